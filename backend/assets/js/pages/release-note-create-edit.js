@@ -7,13 +7,13 @@ document.addEventListener("alpine:init", () => {
       hideCtaIsChecked = false,
       ctaLabelOverrideIsChecked = false,
       ctaUrlOverrideIsChecked = false,
-      mediaType,
     ) => ({
       textWebsiteOverrideIsChecked,
       hideCtaIsChecked,
       ctaLabelOverrideIsChecked,
       ctaUrlOverrideIsChecked,
-      mediaType,
+      // language of the content fields shown, empty for the default language
+      locale: "",
       onSubmitError: function (event) {
         toastError(event.detail.xhr.response);
       },
@@ -22,8 +22,112 @@ document.addEventListener("alpine:init", () => {
       },
     }),
   );
+
+  // audience rules are added and removed on the client and submitted as indexed fields
+  Alpine.data("audienceRules", (rules = []) => ({
+    rules: rules.map((rule, i) => ({ ...rule, key: i })),
+    nextKey: rules.length,
+    // rows are rendered by Alpine, after the page's icons were replaced
+    init: function () {
+      this.$nextTick(() => feather.replace());
+    },
+    add: function () {
+      this.rules.push({ attribute: "", operator: "in", value: "", key: this.nextKey++ });
+      this.$nextTick(() => feather.replace());
+    },
+    remove: function (i) {
+      this.rules.splice(i, 1);
+    },
+    takesValue: function (rule) {
+      return rule.operator !== "exists" && rule.operator !== "not_exists";
+    },
+  }));
+
+  // media are added, reordered and removed on the client and submitted as indexed fields,
+  // new images as files that are attached to a hidden input per item
+  Alpine.data("mediaEditor", (items = []) => ({
+    items: items.map((item, i) => ({ ...item, key: i })),
+    nextKey: items.length,
+    maxItems: 10,
+    get isFull() {
+      return this.items.length >= this.maxItems;
+    },
+    init: function () {
+      this.$nextTick(() => feather.replace());
+    },
+    addImages: function (event) {
+      for (const file of event.target.files) {
+        if (this.isFull) {
+          toastError("A release note can have at most 10 media attachments");
+          break;
+        }
+        this.items.push({
+          kind: "image",
+          file,
+          url: URL.createObjectURL(file),
+          alt_text: "",
+          key: this.nextKey++,
+        });
+      }
+      event.target.value = "";
+      this.$nextTick(() => feather.replace());
+    },
+    addEmbed: function () {
+      this.items.push({ kind: "embed", link: "", alt_text: "", key: this.nextKey++ });
+      this.$nextTick(() => feather.replace());
+    },
+    attachFile: function (input, file) {
+      const transfer = new DataTransfer();
+      transfer.items.add(file);
+      input.files = transfer.files;
+    },
+    move: function (i, offset) {
+      const j = i + offset;
+      if (j < 0 || j >= this.items.length) return;
+      [this.items[i], this.items[j]] = [this.items[j], this.items[i]];
+    },
+    remove: function (i) {
+      const [item] = this.items.splice(i, 1);
+      if (item.file) URL.revokeObjectURL(item.url);
+    },
+    // mirrors util.TransformMediaLink on the server
+    embedUrl: function (link) {
+      if (!link) return "";
+      if (link.includes("youtube.com")) {
+        const videoId = link.split("v=")[1]?.split("&")[0];
+        return videoId ? "https://www.youtube.com/embed/" + videoId : "";
+      }
+      if (link.includes("loom.com")) {
+        return link.replace("share", "embed");
+      }
+      return "";
+    },
+  }));
+
+  // schedule inputs are edited in local time but submitted as UTC timestamps
+  Alpine.data("schedule", (publishAt = "", unpublishAt = "") => ({
+    publishAtLocal: toLocalInputValue(publishAt),
+    unpublishAtLocal: toLocalInputValue(unpublishAt),
+    toIso: function (localValue) {
+      return localValue ? new Date(localValue).toISOString() : "";
+    },
+  }));
+
+  Alpine.data("previewLinks", () => ({
+    onLinkCreated: function () {
+      feather.replace();
+      toastSuccess("Preview link created");
+    },
+  }));
 });
 
+function toLocalInputValue(isoString) {
+  if (!isoString) return "";
+  const date = new Date(isoString);
+  const local = new Date(date.getTime() - date.getTimezoneOffset() * 60000);
+  return local.toISOString().slice(0, 16);
+}
+
 // submit form
 document.getElementById("submit-button").addEventListener("click", () => {
   document.getElementById("form").requestSubmit();
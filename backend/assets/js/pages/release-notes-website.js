@@ -0,0 +1,55 @@
+// media carousels of release notes with more than one attachment
+document.addEventListener("DOMContentLoaded", () => {
+  document.querySelectorAll("[data-carousel]").forEach((carousel) => {
+    const track = carousel.querySelector("[data-carousel-track]");
+    const prev = carousel.querySelector("[data-carousel-prev]");
+    const next = carousel.querySelector("[data-carousel-next]");
+    const dotsContainer = carousel.querySelector("[data-carousel-dots]");
+    const slides = Array.from(track.children);
+    if (!prev || !next || slides.length < 2) {
+      return;
+    }
+
+    const dots = slides.map((_, i) => {
+      const dot = document.createElement("button");
+      dot.type = "button";
+      dot.className = "carousel__dot";
+      dot.setAttribute("aria-label", `Show media ${i + 1} of ${slides.length}`);
+      dot.addEventListener("click", () => scrollTo(i));
+      dotsContainer.appendChild(dot);
+      return dot;
+    });
+
+    const current = () => Math.round(track.scrollLeft / track.clientWidth);
+    const scrollTo = (i) => {
+      track.scrollTo({ left: i * track.clientWidth });
+    };
+    const update = () => {
+      const i = current();
+      prev.disabled = i === 0;
+      next.disabled = i === slides.length - 1;
+      dots.forEach((dot, j) => {
+        dot.classList.toggle("carousel__dot--active", i === j);
+      });
+    };
+
+    prev.addEventListener("click", () => scrollTo(current() - 1));
+    next.addEventListener("click", () => scrollTo(current() + 1));
+    track.addEventListener("scroll", update, { passive: true });
+    update();
+  });
+});
+
+// placeholders are removed once the image has loaded, so that they don't show through transparent images
+document.addEventListener("DOMContentLoaded", () => {
+  document.querySelectorAll("img[data-placeholder]").forEach((img) => {
+    const clear = () => {
+      img.style.backgroundImage = "";
+    };
+    if (img.complete && img.naturalWidth > 0) {
+      clear();
+      return;
+    }
+    img.addEventListener("load", clear, { once: true });
+  });
+});
@@ -34,6 +34,47 @@ document.addEventListener("alpine:init", () => {
     },
   }));
 
+  Alpine.data("defaultLocaleSettings", () => ({
+    onSubmitError: function (event) {
+      toastError(event.detail.xhr.response);
+    },
+    onSubmitSuccess: function () {
+      toastSuccess("Default language updated");
+    },
+  }));
+
+  Alpine.data("reviewSettings", () => ({
+    onSubmitError: function (event) {
+      toastError(event.detail.xhr.response);
+    },
+    onSubmitSuccess: function () {
+      toastSuccess("Review policy updated");
+    },
+  }));
+
+  Alpine.data("identitySettings", () => ({
+    revealed: false,
+    onSubmitError: function (event) {
+      toastError(event.detail.xhr.response);
+    },
+    onSubmitSuccess: function () {
+      toastSuccess("Identity verification updated");
+    },
+  }));
+
+  Alpine.data("apiKeySettings", () => ({
+    onSubmitError: function (event) {
+      toastError(event.detail.xhr.response);
+    },
+    onSubmitSuccess: function () {
+      document.getElementById("api-key-create-form").reset();
+      toastSuccess("API key created");
+    },
+    onKeyCreated: function () {
+      feather.replace();
+    },
+  }));
+
   Alpine.data("pwUpdate", () => ({
     onSubmitError: function (event) {
       toastError(event.detail.xhr.response);
@@ -55,3 +96,27 @@ document
   .addEventListener("click", () => {
     document.getElementById("pw-update-form").requestSubmit();
   });
+
+document
+  .getElementById("api-key-create-submit-button")
+  .addEventListener("click", () => {
+    document.getElementById("api-key-create-form").requestSubmit();
+  });
+
+document
+  .getElementById("identity-submit-button")
+  .addEventListener("click", () => {
+    document.getElementById("identity-form").requestSubmit();
+  });
+
+document
+  .getElementById("default-locale-submit-button")
+  .addEventListener("click", () => {
+    document.getElementById("default-locale-form").requestSubmit();
+  });
+
+document
+  .getElementById("review-submit-button")
+  .addEventListener("click", () => {
+    document.getElementById("review-form").requestSubmit();
+  });
@@ -0,0 +1,29 @@
+document.addEventListener("alpine:init", () => {
+  Alpine.data("webhookEndpoint", () => ({
+    onSubmitError: function (event) {
+      toastError(event.detail.xhr.response);
+    },
+    onSubmitSuccess: function () {
+      toastSuccess("Webhook updated");
+    },
+  }));
+
+  Alpine.data("webhookSecret", () => ({
+    revealed: false,
+    onSubmitError: function (event) {
+      toastError(event.detail.xhr.response);
+    },
+  }));
+
+  Alpine.data("webhookDeliveries", () => ({
+    onSubmitError: function (event) {
+      toastError(event.detail.xhr.response);
+    },
+  }));
+});
+
+document
+  .getElementById("webhook-submit-button")
+  .addEventListener("click", () => {
+    document.getElementById("webhook-form").requestSubmit();
+  });
@@ -59,6 +59,11 @@ type config struct {
 	Email       emailConfig
 	ProductInfo productInfo
 	Axiom       axiomConfig
+
+	// whether the client IP is taken from X-Forwarded-For / X-Real-IP, only set this behind a reverse proxy
+	TrustProxyHeaders bool
+	// whether webhooks may be sent to loopback, link-local and private addresses, e.g. for self-hosted setups
+	WebhookAllowPrivateTargets bool
 }
 
 func New() *config {
@@ -104,6 +109,10 @@ func New() *config {
 			Dataset: getEnvWithDefault("AXIOM_DATASET", ""),
 			Token:   getEnvWithDefault("AXIOM_TOKEN", ""),
 		},
+
+		TrustProxyHeaders: getEnvAsBoolWithDefault("TRUST_PROXY_HEADERS", false),
+
+		WebhookAllowPrivateTargets: getEnvAsBoolWithDefault("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
 	}
 
 	return cfg
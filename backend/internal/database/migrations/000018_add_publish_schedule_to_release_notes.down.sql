@@ -0,0 +1,2 @@
+ALTER TABLE release_notes DROP COLUMN unpublish_at;
+ALTER TABLE release_notes DROP COLUMN publish_at;
@@ -0,0 +1,2 @@
+ALTER TABLE release_notes ADD COLUMN publish_at TIMESTAMPTZ;
+ALTER TABLE release_notes ADD COLUMN unpublish_at TIMESTAMPTZ;
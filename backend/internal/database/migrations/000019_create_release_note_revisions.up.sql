@@ -0,0 +1,29 @@
+CREATE TABLE release_note_revisions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+  action VARCHAR(32) NOT NULL,
+  author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  title VARCHAR(255) NOT NULL,
+  description_short TEXT NOT NULL,
+  description_long TEXT,
+  release_date DATE,
+  image_path VARCHAR(255),
+  media_link VARCHAR(1024),
+  is_published BOOLEAN NOT NULL DEFAULT FALSE,
+  cta_label_override VARCHAR(255),
+  cta_url_override VARCHAR(255),
+  hide_cta BOOLEAN NOT NULL DEFAULT FALSE,
+  attention_mechanism VARCHAR(255),
+  hide_on_widget BOOLEAN NOT NULL DEFAULT FALSE,
+  hide_on_release_page BOOLEAN NOT NULL DEFAULT FALSE,
+  publish_at TIMESTAMPTZ,
+  unpublish_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX release_note_revisions_release_note_id_idx ON release_note_revisions(release_note_id);
+CREATE INDEX release_note_revisions_organisation_id_idx ON release_note_revisions(organisation_id);
+CREATE INDEX release_note_revisions_image_path_idx ON release_note_revisions(image_path);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS release_notes_organisation_id_slug_idx;
+ALTER TABLE release_notes DROP COLUMN slug;
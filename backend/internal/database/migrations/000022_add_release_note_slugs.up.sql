@@ -0,0 +1,29 @@
+ALTER TABLE release_notes ADD COLUMN slug VARCHAR(255);
+
+-- Backfill slugs from titles, suffixing duplicates within an organisation with part of the ID
+WITH slugs AS (
+  SELECT
+    id,
+    COALESCE(
+      NULLIF(TRIM(BOTH '-' FROM LEFT(TRIM(BOTH '-' FROM regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g')), 80)), ''),
+      'release-note'
+    ) AS base
+  FROM release_notes
+),
+ranked AS (
+  SELECT
+    s.id,
+    s.base,
+    ROW_NUMBER() OVER (PARTITION BY rn.organisation_id, s.base ORDER BY rn.created_at, rn.id) AS position
+  FROM slugs s
+  JOIN release_notes rn ON rn.id = s.id
+)
+UPDATE release_notes
+SET slug = CASE
+  WHEN ranked.position = 1 THEN ranked.base
+  ELSE ranked.base || '-' || LEFT(release_notes.id::text, 8)
+END
+FROM ranked
+WHERE ranked.id = release_notes.id;
+
+CREATE UNIQUE INDEX release_notes_organisation_id_slug_idx ON release_notes(organisation_id, slug) WHERE deleted_at IS NULL;
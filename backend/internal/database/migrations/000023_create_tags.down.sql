@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS release_note_tags;
+DROP TABLE IF EXISTS tags;
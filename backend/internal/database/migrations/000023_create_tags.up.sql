@@ -0,0 +1,19 @@
+CREATE TABLE tags (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+  name VARCHAR(64) NOT NULL,
+  color VARCHAR(7) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX tags_organisation_id_name_idx ON tags(organisation_id, lower(name)) WHERE deleted_at IS NULL;
+
+CREATE TABLE release_note_tags (
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (release_note_id, tag_id)
+);
+
+CREATE INDEX release_note_tags_tag_id_idx ON release_note_tags(tag_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS widget_config_translations;
+DROP TABLE IF EXISTS release_note_translations;
+
+ALTER TABLE organisations DROP COLUMN IF EXISTS default_locale;
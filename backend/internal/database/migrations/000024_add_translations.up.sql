@@ -0,0 +1,21 @@
+ALTER TABLE organisations ADD COLUMN default_locale VARCHAR(8) NOT NULL DEFAULT 'en';
+
+CREATE TABLE release_note_translations (
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  locale VARCHAR(8) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description_short TEXT NOT NULL,
+  description_long TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (release_note_id, locale)
+);
+
+CREATE TABLE widget_config_translations (
+  organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+  locale VARCHAR(8) NOT NULL,
+  title VARCHAR(255) NOT NULL DEFAULT '',
+  description TEXT NOT NULL DEFAULT '',
+  release_note_cta_text VARCHAR(255) NOT NULL DEFAULT '',
+  like_button_text VARCHAR(255) NOT NULL DEFAULT '',
+  unlike_button_text VARCHAR(255) NOT NULL DEFAULT '',
+  PRIMARY KEY (organisation_id, locale)
+);
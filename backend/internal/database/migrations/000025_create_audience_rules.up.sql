@@ -0,0 +1,8 @@
+CREATE TABLE audience_rules (
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  attribute VARCHAR(64) NOT NULL,
+  operator VARCHAR(16) NOT NULL,
+  value TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (release_note_id, position)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE identity_configs (
+  organisation_id UUID PRIMARY KEY REFERENCES organisations(id) ON DELETE CASCADE,
+  secret VARCHAR(128) NOT NULL,
+  previous_secret VARCHAR(128) NOT NULL DEFAULT '',
+  previous_secret_expires_at TIMESTAMPTZ,
+  require_signed BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,7 @@
+CREATE TABLE seen_release_notes (
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  client_id TEXT NOT NULL,
+  organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+  seen_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (release_note_id, client_id)
+);
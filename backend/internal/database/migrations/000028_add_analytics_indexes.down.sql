@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS release_note_likes_organisation_id_created_at_idx;
+DROP INDEX IF EXISTS release_note_metrics_release_note_id_metric_type_idx;
+DROP INDEX IF EXISTS release_note_metrics_organisation_id_created_at_idx;
@@ -0,0 +1,4 @@
+-- analytics aggregate metrics and likes of an organisation by date range
+CREATE INDEX release_note_metrics_organisation_id_created_at_idx ON release_note_metrics(organisation_id, created_at);
+CREATE INDEX release_note_metrics_release_note_id_metric_type_idx ON release_note_metrics(release_note_id, metric_type);
+CREATE INDEX release_note_likes_organisation_id_created_at_idx ON release_note_likes(organisation_id, created_at);
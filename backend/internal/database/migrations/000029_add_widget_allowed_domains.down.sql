@@ -0,0 +1 @@
+ALTER TABLE widget_configs DROP COLUMN IF EXISTS allowed_domains;
@@ -0,0 +1,2 @@
+-- newline separated domains the widget API serves data to, empty allows all
+ALTER TABLE widget_configs ADD COLUMN allowed_domains TEXT NOT NULL DEFAULT '';
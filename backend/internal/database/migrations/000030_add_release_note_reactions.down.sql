@@ -0,0 +1,8 @@
+ALTER TABLE widget_configs DROP COLUMN IF EXISTS reactions;
+
+DELETE FROM release_note_likes WHERE reaction <> '';
+
+DROP INDEX IF EXISTS release_note_likes_unique_client_idx;
+CREATE UNIQUE INDEX release_note_likes_unique_client_idx ON release_note_likes(release_note_id, client_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE release_note_likes DROP COLUMN IF EXISTS reaction;
@@ -0,0 +1,8 @@
+-- Likes with a reaction are emoji reactions, an empty reaction is a plain like
+ALTER TABLE release_note_likes ADD COLUMN reaction VARCHAR(32) NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS release_note_likes_unique_client_idx;
+CREATE UNIQUE INDEX release_note_likes_unique_client_idx ON release_note_likes(release_note_id, client_id, reaction) WHERE deleted_at IS NULL;
+
+-- Space separated emoji offered as reactions in the widget
+ALTER TABLE widget_configs ADD COLUMN reactions TEXT NOT NULL DEFAULT '';
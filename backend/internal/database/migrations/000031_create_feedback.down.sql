@@ -0,0 +1,3 @@
+ALTER TABLE widget_configs DROP COLUMN IF EXISTS enable_feedback;
+
+DROP TABLE IF EXISTS feedback;
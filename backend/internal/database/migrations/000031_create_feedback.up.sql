@@ -0,0 +1,19 @@
+CREATE TABLE feedback (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  client_id TEXT NOT NULL DEFAULT '',
+  source VARCHAR(16) NOT NULL,
+  text TEXT NOT NULL,
+  email VARCHAR(254) NOT NULL DEFAULT '',
+  status VARCHAR(16) NOT NULL DEFAULT 'new',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX feedback_organisation_id_idx ON feedback(organisation_id, status, created_at DESC);
+CREATE INDEX feedback_release_note_id_idx ON feedback(release_note_id, created_at DESC);
+
+-- Lets widget and release page visitors leave feedback on release notes
+ALTER TABLE widget_configs ADD COLUMN enable_feedback BOOLEAN NOT NULL DEFAULT FALSE;
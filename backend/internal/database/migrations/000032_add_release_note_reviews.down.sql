@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS release_note_reviews;
+
+ALTER TABLE release_notes DROP COLUMN IF EXISTS review_requested_by;
+ALTER TABLE release_notes DROP COLUMN IF EXISTS reviewer_id;
+ALTER TABLE release_notes DROP COLUMN IF EXISTS review_status;
+
+ALTER TABLE organisations DROP COLUMN IF EXISTS require_review;
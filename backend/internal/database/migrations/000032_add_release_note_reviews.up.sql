@@ -0,0 +1,23 @@
+-- Lets organisations require an approved review before release notes are published
+ALTER TABLE organisations ADD COLUMN require_review BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE release_notes ADD COLUMN review_status VARCHAR(32) NOT NULL DEFAULT 'draft';
+ALTER TABLE release_notes ADD COLUMN reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE release_notes ADD COLUMN review_requested_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- notes published before the policy existed count as approved, so that they can be republished
+UPDATE release_notes SET review_status = 'approved' WHERE is_published;
+
+CREATE TABLE release_note_reviews (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+  author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  action VARCHAR(32) NOT NULL,
+  comment TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX release_note_reviews_release_note_id_idx ON release_note_reviews(release_note_id, created_at DESC);
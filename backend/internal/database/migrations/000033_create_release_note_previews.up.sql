@@ -0,0 +1,15 @@
+CREATE TABLE release_note_previews (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+  prefix VARCHAR(16) NOT NULL,
+  token_hash VARCHAR(64) NOT NULL,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX release_note_previews_token_hash_idx ON release_note_previews(token_hash);
+CREATE INDEX release_note_previews_release_note_id_idx ON release_note_previews(release_note_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS release_note_revision_media;
+DROP TABLE IF EXISTS release_note_media;
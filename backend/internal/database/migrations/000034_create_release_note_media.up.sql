@@ -0,0 +1,45 @@
+CREATE TABLE release_note_media (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  release_note_id UUID NOT NULL REFERENCES release_notes(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  kind VARCHAR(16) NOT NULL,
+  path VARCHAR(255) NOT NULL DEFAULT '',
+  link VARCHAR(1024) NOT NULL DEFAULT '',
+  alt_text VARCHAR(512) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX release_note_media_release_note_id_idx ON release_note_media(release_note_id, position);
+CREATE INDEX release_note_media_path_idx ON release_note_media(path);
+
+CREATE TABLE release_note_revision_media (
+  revision_id UUID NOT NULL REFERENCES release_note_revisions(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  kind VARCHAR(16) NOT NULL,
+  path VARCHAR(255) NOT NULL DEFAULT '',
+  link VARCHAR(1024) NOT NULL DEFAULT '',
+  alt_text VARCHAR(512) NOT NULL DEFAULT '',
+  PRIMARY KEY (revision_id, position)
+);
+
+CREATE INDEX release_note_revision_media_path_idx ON release_note_revision_media(path);
+
+-- the single image and embed of existing notes and revisions become their first attachments
+INSERT INTO release_note_media (release_note_id, position, kind, path)
+SELECT id, 0, 'image', image_path
+FROM release_notes
+WHERE image_path IS NOT NULL AND image_path <> '';
+
+INSERT INTO release_note_media (release_note_id, position, kind, link)
+SELECT id, CASE WHEN image_path IS NOT NULL AND image_path <> '' THEN 1 ELSE 0 END, 'embed', media_link
+FROM release_notes
+WHERE media_link IS NOT NULL AND media_link <> '';
+
+INSERT INTO release_note_revision_media (revision_id, position, kind, path)
+SELECT id, 0, 'image', image_path
+FROM release_note_revisions
+WHERE image_path IS NOT NULL AND image_path <> '';
+
+INSERT INTO release_note_revision_media (revision_id, position, kind, link)
+SELECT id, CASE WHEN image_path IS NOT NULL AND image_path <> '' THEN 1 ELSE 0 END, 'embed', media_link
+FROM release_note_revisions
+WHERE media_link IS NOT NULL AND media_link <> '';
@@ -0,0 +1,11 @@
+ALTER TABLE release_note_revision_media
+  DROP COLUMN placeholder,
+  DROP COLUMN variants,
+  DROP COLUMN height,
+  DROP COLUMN width;
+
+ALTER TABLE release_note_media
+  DROP COLUMN placeholder,
+  DROP COLUMN variants,
+  DROP COLUMN height,
+  DROP COLUMN width;
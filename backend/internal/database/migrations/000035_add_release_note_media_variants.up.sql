@@ -0,0 +1,11 @@
+ALTER TABLE release_note_media
+  ADD COLUMN width INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN height INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN variants TEXT NOT NULL DEFAULT '',
+  ADD COLUMN placeholder TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE release_note_revision_media
+  ADD COLUMN width INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN height INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN variants TEXT NOT NULL DEFAULT '',
+  ADD COLUMN placeholder TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,20 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/google/uuid"
+)
+
+type ApiKey struct {
+	database.BaseModel `gorm:"embedded"`
+	OrganisationID     uuid.UUID `gorm:"type:uuid;not null"`
+	Organisation       organisation.Organisation
+	Name               string     `gorm:"type:varchar(255)"`
+	Prefix             string     `gorm:"type:varchar(16)"` // start of the key, shown to identify it
+	KeyHash            string     `gorm:"type:varchar(64);uniqueIndex"`
+	CreatedBy          uuid.UUID  `gorm:"type:uuid"`
+	LastUsedAt         *time.Time `gorm:"type:timestamptz;default:null"`
+}
@@ -0,0 +1,64 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/google/uuid"
+)
+
+type repository struct {
+	db *database.DB
+}
+
+func NewRepository(db *database.DB) *repository {
+	log.Trace().Msg("NewRepository")
+	return &repository{db: db}
+}
+
+func (r *repository) Create(key *ApiKey) error {
+	log.Trace().Str("orgId", key.OrganisationID.String()).Msg("Create")
+	if err := r.db.Client.Create(key).Error; err != nil {
+		log.Error().Err(err).Msg("Error creating API key")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindAll(orgId uuid.UUID) ([]*ApiKey, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("FindAll")
+	var keys []*ApiKey
+	if err := r.db.Client.Where("organisation_id = ?", orgId).Order("created_at desc").Find(&keys).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding API keys")
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *repository) FindByHash(hash string) (*ApiKey, error) {
+	log.Trace().Msg("FindByHash")
+	var key ApiKey
+	if err := r.db.Client.Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *repository) UpdateLastUsed(id uuid.UUID, at time.Time) error {
+	log.Trace().Str("id", id.String()).Msg("UpdateLastUsed")
+	if err := r.db.Client.Model(&ApiKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error; err != nil {
+		log.Error().Err(err).Msg("Error updating API key usage")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) Delete(id, orgId uuid.UUID) (int64, error) {
+	log.Trace().Str("id", id.String()).Msg("Delete")
+	res := r.db.Client.Where("id = ? AND organisation_id = ?", id, orgId).Delete(&ApiKey{})
+	if res.Error != nil {
+		log.Error().Err(res.Error).Msg("Error deleting API key")
+		return 0, res.Error
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,102 @@
+package apikey
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/random"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	keyPrefix = "ann_"
+	// number of characters of the key that are stored in plain text for display
+	displayPrefixLength = 12
+	// last usage is only persisted once per interval to avoid a write on every request
+	lastUsedResolution = time.Minute
+)
+
+var (
+	ErrInvalidKey  = errors.New("invalid API key")
+	ErrKeyNotFound = errors.New("API key not found")
+)
+
+type service struct {
+	repo repository
+}
+
+func NewService(r repository) *service {
+	log.Trace().Msg("NewService")
+	return &service{repo: r}
+}
+
+// Create generates a new API key for the organisation.
+// The plain key is only returned here; only its hash is stored.
+func (s *service) Create(orgId, userId uuid.UUID, name string) (string, *ApiKey, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("Create")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, errors.New("name is required")
+	}
+	plainKey := createKey()
+	key := &ApiKey{
+		OrganisationID: orgId,
+		Name:           name,
+		Prefix:         plainKey[:displayPrefixLength],
+		KeyHash:        random.EncodeToken(plainKey),
+		CreatedBy:      userId,
+	}
+	if err := s.repo.Create(key); err != nil {
+		log.Error().Err(err).Msg("Error creating API key")
+		return "", nil, err
+	}
+	return plainKey, key, nil
+}
+
+func (s *service) GetAll(orgId uuid.UUID) ([]*ApiKey, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("GetAll")
+	return s.repo.FindAll(orgId)
+}
+
+// Revoke deletes an API key of the organisation so it can no longer be used
+func (s *service) Revoke(id, orgId uuid.UUID) error {
+	log.Trace().Str("id", id.String()).Msg("Revoke")
+	deleted, err := s.repo.Delete(id, orgId)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves a plain API key to the stored key
+func (s *service) Authenticate(plainKey string) (*ApiKey, error) {
+	log.Trace().Msg("Authenticate")
+	if !strings.HasPrefix(plainKey, keyPrefix) {
+		return nil, ErrInvalidKey
+	}
+	key, err := s.repo.FindByHash(random.EncodeToken(plainKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidKey
+		}
+		log.Error().Err(err).Msg("Error finding API key")
+		return nil, err
+	}
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > lastUsedResolution {
+		if err := s.repo.UpdateLastUsed(key.ID, now); err != nil {
+			// usage tracking must not block authenticated requests
+			log.Error().Err(err).Msg("Error updating API key usage")
+		}
+	}
+	return key, nil
+}
+
+func createKey() string {
+	return keyPrefix + strings.ToLower(random.CreateRandomToken())
+}
@@ -0,0 +1,77 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/devbydaniel/announcable/internal/random"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+	require.NoError(t, testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &ApiKey{}))
+
+	org, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	require.NoError(t, testDB.DB.Client.Create(org).Error)
+
+	repo := NewRepository(testDB.DB)
+	s := NewService(*repo)
+	plainKey, key, err := s.Create(org.ID, uuid.New(), "CI")
+	require.NoError(t, err)
+
+	// only the hash of the key is stored
+	assert.True(t, strings.HasPrefix(plainKey, keyPrefix))
+	assert.Equal(t, plainKey[:displayPrefixLength], key.Prefix)
+	assert.NotEqual(t, plainKey, key.KeyHash)
+	var stored ApiKey
+	require.NoError(t, testDB.DB.Client.First(&stored, "id = ?", key.ID).Error)
+	assert.Equal(t, random.EncodeToken(plainKey), stored.KeyHash)
+
+	authenticated, err := s.Authenticate(plainKey)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, authenticated.ID)
+	assert.Equal(t, org.ID, authenticated.OrganisationID)
+
+	for _, invalid := range []string{"", "ann_", plainKey + "x", strings.TrimPrefix(plainKey, keyPrefix), strings.ToUpper(plainKey)} {
+		_, err := s.Authenticate(invalid)
+		assert.ErrorIs(t, err, ErrInvalidKey, "key %q", invalid)
+	}
+
+	t.Run("last usage is throttled", func(t *testing.T) {
+		lastUsed := func() *time.Time {
+			t.Helper()
+			var k ApiKey
+			require.NoError(t, testDB.DB.Client.First(&k, "id = ?", key.ID).Error)
+			return k.LastUsedAt
+		}
+		first := lastUsed()
+		require.NotNil(t, first, "first use is recorded")
+
+		_, err := s.Authenticate(plainKey)
+		require.NoError(t, err)
+		assert.True(t, first.Equal(*lastUsed()), "use within the resolution is not written")
+
+		earlier := time.Now().Add(-2 * lastUsedResolution)
+		require.NoError(t, repo.UpdateLastUsed(key.ID, earlier))
+		_, err = s.Authenticate(plainKey)
+		require.NoError(t, err)
+		assert.True(t, lastUsed().After(earlier.Add(lastUsedResolution)), "use after the resolution is written")
+	})
+
+	t.Run("revoked keys are rejected", func(t *testing.T) {
+		assert.ErrorIs(t, s.Revoke(key.ID, uuid.New()), ErrKeyNotFound, "keys of other organisations can't be revoked")
+		require.NoError(t, s.Revoke(key.ID, org.ID))
+		_, err := s.Authenticate(plainKey)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+}
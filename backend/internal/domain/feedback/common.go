@@ -0,0 +1,5 @@
+package feedback
+
+import "github.com/devbydaniel/announcable/internal/logger"
+
+var log = logger.Get()
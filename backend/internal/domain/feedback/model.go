@@ -0,0 +1,68 @@
+package feedback
+
+import (
+	"github.com/devbydaniel/announcable/internal/database"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/google/uuid"
+)
+
+// Feedback is a short comment a widget or release page visitor left on a release note
+type Feedback struct {
+	database.BaseModel `gorm:"embedded"`
+	OrganisationID     uuid.UUID `gorm:"type:uuid;not null"`
+	ReleaseNoteID      uuid.UUID `gorm:"type:uuid;not null"`
+	ReleaseNote        releasenotes.ReleaseNote
+	ClientID           string `gorm:"type:text;not null;default:''"` // empty for feedback from the release page
+	Source             Source `gorm:"type:varchar(16);not null"`
+	Text               string `gorm:"type:text;not null"`
+	Email              string `gorm:"type:varchar(254);not null;default:''"` // optional, to reply to the visitor
+	Status             Status `gorm:"type:varchar(16);not null;default:'new'"`
+}
+
+func (Feedback) TableName() string {
+	return "feedback"
+}
+
+type Source string
+
+func (s Source) String() string {
+	return string(s)
+}
+
+const (
+	SourceWidget      Source = "widget"
+	SourceReleasePage Source = "release_page"
+)
+
+type Status string
+
+func (s Status) String() string {
+	return string(s)
+}
+
+const (
+	StatusNew      Status = "new"
+	StatusRead     Status = "read"
+	StatusArchived Status = "archived"
+)
+
+// Statuses lists the statuses in the order they are shown in the inbox
+var Statuses = []Status{StatusNew, StatusRead, StatusArchived}
+
+// IsValid reports whether s is a known status
+func (s Status) IsValid() bool {
+	for _, status := range Statuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+type PaginatedFeedback struct {
+	Items      []*Feedback
+	TotalCount int64
+	TotalPages int
+	Page       int
+	PageSize   int
+}
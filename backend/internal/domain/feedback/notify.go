@@ -0,0 +1,61 @@
+package feedback
+
+import (
+	"github.com/devbydaniel/announcable/config"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/devbydaniel/announcable/internal/domain/rbac"
+	"github.com/devbydaniel/announcable/internal/email"
+	"github.com/devbydaniel/announcable/internal/ratelimit"
+	"github.com/devbydaniel/announcable/internal/util"
+	"github.com/google/uuid"
+)
+
+// notifyLimiter allows one notification per organisation every ten minutes, so that a burst of
+// feedback doesn't flood the admins' inboxes. The feedback itself is always stored.
+var notifyLimiter = ratelimit.New(600, 1)
+
+// notifyAdmins emails the organisation's admins about new feedback. It runs after the feedback
+// was stored, errors are only logged.
+func (s *service) notifyAdmins(id, orgId uuid.UUID) {
+	log.Trace().Str("id", id.String()).Msg("notifyAdmins")
+	cfg := config.New()
+	if !cfg.IsEmailEnabled() {
+		return
+	}
+	if err := notifyLimiter.Deduct(orgId.String(), 1); err != nil {
+		log.Debug().Str("orgId", orgId.String()).Msg("Feedback notification throttled")
+		return
+	}
+
+	f, err := s.repo.FindOne(id)
+	if err != nil {
+		return
+	}
+	orgService := organisation.NewService(*organisation.NewRepository(s.repo.db))
+	org, err := orgService.GetOrg(orgId)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding organisation")
+		return
+	}
+	orgUsers, err := orgService.GetOrgUsers(orgId)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding organisation users")
+		return
+	}
+
+	for _, ou := range orgUsers {
+		if ou.Role != rbac.RoleAdmin {
+			continue
+		}
+		if err := email.SendFeedbackNotification(&email.FeedbackNotificationConfig{
+			To:               ou.User.Email,
+			OrganisationName: org.Name,
+			ReleaseNoteTitle: f.ReleaseNote.Title,
+			Text:             f.Text,
+			Email:            f.Email,
+			ActionURL:        util.BuildURL(cfg.BaseURL, "feedback"),
+		}); err != nil {
+			log.Error().Err(err).Str("to", ou.User.Email).Msg("Error sending feedback notification")
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package feedback
+
+import (
+	"math"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *database.DB
+}
+
+func NewRepository(db *database.DB) *repository {
+	log.Trace().Msg("NewRepository")
+	return &repository{db: db}
+}
+
+func (r *repository) Create(f *Feedback) error {
+	log.Trace().Str("releaseNoteId", f.ReleaseNoteID.String()).Msg("Create")
+	if err := r.db.Client.Create(f).Error; err != nil {
+		log.Error().Err(err).Msg("Error creating feedback")
+		return err
+	}
+	return nil
+}
+
+// ExistsSince reports whether the same visitor left the same text on the note since the given time.
+// Visitors are identified by client ID and email, which are both empty for anonymous release page feedback.
+func (r *repository) ExistsSince(f *Feedback, since time.Time) (bool, error) {
+	log.Trace().Str("releaseNoteId", f.ReleaseNoteID.String()).Msg("ExistsSince")
+	var count int64
+	if err := r.db.Client.Model(&Feedback{}).
+		Where("release_note_id = ? AND client_id = ? AND email = ? AND text = ? AND created_at >= ?", f.ReleaseNoteID, f.ClientID, f.Email, f.Text, since).
+		Count(&count).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting feedback")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *repository) FindAll(orgId uuid.UUID, status Status, page, pageSize int) (*PaginatedFeedback, error) {
+	log.Trace().Str("orgId", orgId.String()).Str("status", status.String()).Int("page", page).Int("pageSize", pageSize).Msg("FindAll")
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	query := r.db.Client.Model(&Feedback{}).Where("organisation_id = ? AND status = ?", orgId, status)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting feedback")
+		return nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
+	if page > totalPages && totalPages > 0 {
+		page = totalPages
+	}
+
+	var items []*Feedback
+	offset := (page - 1) * pageSize
+	// unscoped so that feedback on deleted release notes still shows the note's title
+	if err := query.Preload("ReleaseNote", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Offset(offset).Limit(pageSize).Order("created_at desc").Find(&items).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding feedback")
+		return nil, err
+	}
+
+	return &PaginatedFeedback{
+		Items:      items,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+		Page:       page,
+		PageSize:   pageSize,
+	}, nil
+}
+
+func (r *repository) CountByStatus(orgId uuid.UUID) (map[Status]int, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("CountByStatus")
+	var rows []struct {
+		Status Status
+		Count  int
+	}
+	if err := r.db.Client.Model(&Feedback{}).
+		Select("status, COUNT(*) AS count").
+		Where("organisation_id = ?", orgId).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting feedback by status")
+		return nil, err
+	}
+	counts := make(map[Status]int, len(Statuses))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// UpdateStatus sets the status of the organisation's feedback, reporting whether it exists
+func (r *repository) UpdateStatus(id, orgId uuid.UUID, status Status) (bool, error) {
+	log.Trace().Str("id", id.String()).Str("status", status.String()).Msg("UpdateStatus")
+	result := r.db.Client.Model(&Feedback{}).Where("id = ? AND organisation_id = ?", id, orgId).Update("status", status)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("Error updating feedback status")
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete deletes the organisation's feedback, reporting whether it exists
+func (r *repository) Delete(id, orgId uuid.UUID) (bool, error) {
+	log.Trace().Str("id", id.String()).Msg("Delete")
+	result := r.db.Client.Where("id = ? AND organisation_id = ?", id, orgId).Delete(&Feedback{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("Error deleting feedback")
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *repository) FindOne(id uuid.UUID) (*Feedback, error) {
+	log.Trace().Str("id", id.String()).Msg("FindOne")
+	var f Feedback
+	if err := r.db.Client.Preload("ReleaseNote").First(&f, "id = ?", id).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding feedback")
+		return nil, err
+	}
+	return &f, nil
+}
@@ -0,0 +1,125 @@
+package feedback
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFeedbackNotFound = errors.New("feedback not found")
+	ErrInvalidText      = errors.New("feedback must be between 1 and 2000 characters")
+	ErrInvalidEmail     = errors.New("invalid email address")
+	ErrInvalidStatus    = errors.New("invalid feedback status")
+	// ErrSpam is returned for feedback that looks like spam, e.g. because of the number of links
+	ErrSpam = errors.New("feedback looks like spam")
+	// ErrDuplicate is returned for feedback the same visitor already left on the note within a day
+	ErrDuplicate = errors.New("feedback was already submitted")
+)
+
+const (
+	maxTextLength   = 2000
+	maxEmailLength  = 254
+	maxLinks        = 2
+	duplicateWindow = 24 * time.Hour
+)
+
+var linkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+type service struct {
+	repo repository
+}
+
+func NewService(r repository) *service {
+	log.Trace().Msg("NewService")
+	return &service{repo: r}
+}
+
+// Submit validates and stores feedback of a visitor and notifies the organisation's admins.
+// Repeated submissions of the same text by the same visitor return ErrDuplicate.
+func (s *service) Submit(f *Feedback) error {
+	log.Trace().Str("releaseNoteId", f.ReleaseNoteID.String()).Msg("Submit")
+	if err := validate(f); err != nil {
+		return err
+	}
+	exists, err := s.repo.ExistsSince(f, time.Now().Add(-duplicateWindow))
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrDuplicate
+	}
+	f.Status = StatusNew
+	if err := s.repo.Create(f); err != nil {
+		return err
+	}
+	go s.notifyAdmins(f.ID, f.OrganisationID)
+	return nil
+}
+
+// GetInbox returns a page of the organisation's feedback with the given status, newest first
+func (s *service) GetInbox(orgId uuid.UUID, status Status, page, pageSize int) (*PaginatedFeedback, error) {
+	log.Trace().Str("orgId", orgId.String()).Str("status", status.String()).Msg("GetInbox")
+	if !status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+	return s.repo.FindAll(orgId, status, page, pageSize)
+}
+
+// CountByStatus returns the number of the organisation's feedback per status
+func (s *service) CountByStatus(orgId uuid.UUID) (map[Status]int, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("CountByStatus")
+	return s.repo.CountByStatus(orgId)
+}
+
+func (s *service) SetStatus(id, orgId uuid.UUID, status Status) error {
+	log.Trace().Str("id", id.String()).Str("status", status.String()).Msg("SetStatus")
+	if !status.IsValid() {
+		return ErrInvalidStatus
+	}
+	found, err := s.repo.UpdateStatus(id, orgId, status)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrFeedbackNotFound
+	}
+	return nil
+}
+
+func (s *service) Delete(id, orgId uuid.UUID) error {
+	log.Trace().Str("id", id.String()).Msg("Delete")
+	found, err := s.repo.Delete(id, orgId)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrFeedbackNotFound
+	}
+	return nil
+}
+
+// validate trims the text and email of the feedback and checks them
+func validate(f *Feedback) error {
+	f.Text = strings.TrimSpace(f.Text)
+	f.Email = strings.TrimSpace(f.Email)
+	if f.Text == "" || utf8.RuneCountInString(f.Text) > maxTextLength {
+		return ErrInvalidText
+	}
+	if len(linkPattern.FindAllStringIndex(f.Text, -1)) > maxLinks {
+		return ErrSpam
+	}
+	if f.Email != "" {
+		// reject display names and comments, only a bare address can be replied to
+		addr, err := mail.ParseAddress(f.Email)
+		if err != nil || addr.Address != f.Email || len(f.Email) > maxEmailLength {
+			return ErrInvalidEmail
+		}
+	}
+	return nil
+}
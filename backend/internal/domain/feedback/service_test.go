@@ -0,0 +1,47 @@
+package feedback
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		email         string
+		expectedText  string
+		expectedEmail string
+		expectErr     error
+	}{
+		{name: "text only", text: " Love it! ", expectedText: "Love it!"},
+		{name: "text and email", text: "Love it!", email: " jane@example.com ", expectedText: "Love it!", expectedEmail: "jane@example.com"},
+		{name: "two links", text: "See https://example.com and www.example.org", expectedText: "See https://example.com and www.example.org"},
+		{name: "max length", text: strings.Repeat("ä", maxTextLength), expectedText: strings.Repeat("ä", maxTextLength)},
+		{name: "empty text", text: "  ", expectErr: ErrInvalidText},
+		{name: "too long", text: strings.Repeat("a", maxTextLength+1), expectErr: ErrInvalidText},
+		{name: "too many links", text: "http://a.example https://b.example www.c.example", expectErr: ErrSpam},
+		{name: "invalid email", text: "Love it!", email: "jane", expectErr: ErrInvalidEmail},
+		{name: "email with display name", text: "Love it!", email: "Jane <jane@example.com>", expectErr: ErrInvalidEmail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Feedback{Text: tt.text, Email: tt.email}
+			err := validate(f)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("validate() error = %v, want %v", err, tt.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+			if f.Text != tt.expectedText || f.Email != tt.expectedEmail {
+				t.Errorf("validate() = %q, %q, want %q, %q", f.Text, f.Email, tt.expectedText, tt.expectedEmail)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package identity
+
+import "github.com/devbydaniel/announcable/internal/logger"
+
+var log = logger.Get()
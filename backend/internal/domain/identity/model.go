@@ -0,0 +1,52 @@
+package identity
+
+import (
+	"strings"
+	"time"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/google/uuid"
+)
+
+// IdentityConfig holds the secret an organisation signs end-user identities with
+type IdentityConfig struct {
+	OrganisationID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Secret         string    `gorm:"type:varchar(128)"`
+	// the secret before the last rotation stays valid for a grace period
+	PreviousSecret          string     `gorm:"type:varchar(128)"`
+	PreviousSecretExpiresAt *time.Time `gorm:"type:timestamptz;default:null"`
+	RequireSigned           bool       `gorm:"type:bool;default:false"`
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+}
+
+// secrets returns the secrets tokens may be signed with at the given time
+func (cfg *IdentityConfig) secrets(now time.Time) []string {
+	var secrets []string
+	if cfg.Secret != "" {
+		secrets = append(secrets, cfg.Secret)
+	}
+	if cfg.PreviousSecret != "" && cfg.PreviousSecretExpiresAt != nil && now.Before(*cfg.PreviousSecretExpiresAt) {
+		secrets = append(secrets, cfg.PreviousSecret)
+	}
+	return secrets
+}
+
+// Identity is an end user whose ID and attributes were signed by the organisation's backend
+type Identity struct {
+	UserID     string
+	Attributes releasenotes.AudienceAttributes
+}
+
+// ClientID returns the client ID likes, metrics and seen state of the user are stored under.
+// It is prefixed to keep it apart from the client IDs of anonymous visitors, which callers must
+// check with IsSignedClientID before they accept one.
+func (i *Identity) ClientID() string {
+	return clientIdPrefix + i.UserID
+}
+
+// IsSignedClientID reports whether a client ID belongs to a signed identity. An anonymous
+// visitor sending such an ID would act as that user.
+func IsSignedClientID(clientId string) bool {
+	return strings.HasPrefix(clientId, clientIdPrefix)
+}
@@ -0,0 +1,35 @@
+package identity
+
+import (
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *database.DB
+}
+
+func NewRepository(db *database.DB) *repository {
+	log.Trace().Msg("NewRepository")
+	return &repository{db: db}
+}
+
+func (r *repository) FindByOrgId(orgId uuid.UUID) (*IdentityConfig, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("FindByOrgId")
+	var cfg IdentityConfig
+	if err := r.db.Client.Where("organisation_id = ?", orgId).First(&cfg).Error; err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Save creates or replaces the identity config of an organisation
+func (r *repository) Save(cfg *IdentityConfig) error {
+	log.Trace().Str("orgId", cfg.OrganisationID.String()).Msg("Save")
+	if err := r.db.Client.Clauses(clause.OnConflict{UpdateAll: true}).Create(cfg).Error; err != nil {
+		log.Error().Err(err).Msg("Error saving identity config")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package identity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/random"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	secretPrefix = "idsec_"
+	// how long tokens signed with the previous secret are accepted after a rotation
+	rotationGracePeriod = 24 * time.Hour
+)
+
+var (
+	// ErrInvalidToken is returned for tokens that are malformed or not signed with the organisation's secret
+	ErrInvalidToken = errors.New("invalid identity token")
+	// ErrTokenExpired is returned for correctly signed tokens past their expiry
+	ErrTokenExpired = errors.New("identity token expired")
+	// ErrIdentityRequired is returned for requests without a token if the organisation only accepts signed identities
+	ErrIdentityRequired = errors.New("signed identity required")
+	// ErrNoSecret is returned when signed identities are required before a secret was generated
+	ErrNoSecret = errors.New("no identity secret")
+)
+
+type service struct {
+	repo repository
+}
+
+func NewService(r repository) *service {
+	log.Trace().Msg("NewService")
+	return &service{repo: r}
+}
+
+// Get returns the identity config of an organisation, which is empty if no secret was generated yet
+func (s *service) Get(orgId uuid.UUID) (*IdentityConfig, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("Get")
+	cfg, err := s.repo.FindByOrgId(orgId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &IdentityConfig{OrganisationID: orgId}, nil
+		}
+		log.Error().Err(err).Msg("Error finding identity config")
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// RotateSecret generates a new identity secret. Tokens signed with the replaced
+// secret are accepted for another 24 hours so that backends can be updated.
+func (s *service) RotateSecret(orgId uuid.UUID) error {
+	log.Trace().Str("orgId", orgId.String()).Msg("RotateSecret")
+	cfg, err := s.Get(orgId)
+	if err != nil {
+		return err
+	}
+	if cfg.Secret != "" {
+		expiresAt := time.Now().Add(rotationGracePeriod)
+		cfg.PreviousSecret = cfg.Secret
+		cfg.PreviousSecretExpiresAt = &expiresAt
+	}
+	cfg.Secret = createSecret()
+	return s.repo.Save(cfg)
+}
+
+// SetRequireSigned sets whether the widget API only accepts signed identities
+func (s *service) SetRequireSigned(orgId uuid.UUID, requireSigned bool) error {
+	log.Trace().Str("orgId", orgId.String()).Bool("requireSigned", requireSigned).Msg("SetRequireSigned")
+	cfg, err := s.Get(orgId)
+	if err != nil {
+		return err
+	}
+	if requireSigned && cfg.Secret == "" {
+		return ErrNoSecret
+	}
+	cfg.RequireSigned = requireSigned
+	return s.repo.Save(cfg)
+}
+
+// Resolve verifies the identity token of a widget request. Without a token it returns nil,
+// or ErrIdentityRequired if the organisation only accepts signed identities.
+func (s *service) Resolve(orgId uuid.UUID, token string) (*Identity, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("Resolve")
+	cfg, err := s.Get(orgId)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		if cfg.RequireSigned {
+			return nil, ErrIdentityRequired
+		}
+		return nil, nil
+	}
+	return verify(token, cfg.secrets(time.Now()), time.Now())
+}
+
+func createSecret() string {
+	return secretPrefix + strings.ToLower(random.CreateRandomToken())
+}
@@ -0,0 +1,94 @@
+package identity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+)
+
+const (
+	clientIdPrefix = "user:"
+	// maximum length of a user ID, it is stored as client ID with every like and metric
+	maxUserIdLength = 255
+)
+
+// tokenPayload is the JSON object signed by the organisation's backend
+type tokenPayload struct {
+	UserID     string          `json:"user_id"`
+	Attributes json.RawMessage `json:"attributes,omitempty"`
+	// optional expiry as unix timestamp
+	ExpiresAt int64 `json:"exp,omitempty"`
+}
+
+// Sign creates an identity token for a user. Organisation backends create the same token:
+// the base64url encoded (unpadded) JSON payload, a dot and the hex encoded HMAC-SHA256 of
+// the encoded payload, keyed with the organisation's identity secret.
+func Sign(secret, userId string, attributes map[string]interface{}, expiresAt *time.Time) (string, error) {
+	payload := tokenPayload{UserID: userId}
+	if attributes != nil {
+		raw, err := json.Marshal(attributes)
+		if err != nil {
+			return "", err
+		}
+		payload.Attributes = raw
+	}
+	if expiresAt != nil {
+		payload.ExpiresAt = expiresAt.Unix()
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(raw)
+	return encoded + "." + signature(secret, encoded), nil
+}
+
+// verify checks that a token is signed with one of the secrets and not expired
+func verify(token string, secrets []string, now time.Time) (*Identity, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok || len(secrets) == 0 {
+		return nil, ErrInvalidToken
+	}
+	valid := false
+	for _, secret := range secrets {
+		if hmac.Equal([]byte(signature(secret, encoded)), []byte(strings.ToLower(sig))) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var payload tokenPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if payload.UserID == "" || len(payload.UserID) > maxUserIdLength {
+		return nil, ErrInvalidToken
+	}
+	if payload.ExpiresAt != 0 && now.Unix() >= payload.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	attrs, err := releasenotes.ParseAudienceAttributes(string(payload.Attributes))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &Identity{UserID: payload.UserID, Attributes: attrs}, nil
+}
+
+func signature(secret, encodedPayload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encodedPayload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,101 @@
+package identity
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "idsec_test"
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	mustSign := func(secret, userId string, attributes map[string]interface{}, expiresAt *time.Time) string {
+		token, err := Sign(secret, userId, attributes, expiresAt)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return token
+	}
+	valid := mustSign(secret, "user-42", map[string]interface{}{"plan": "pro", "flags": []string{"beta"}}, &future)
+	payload, sig, _ := strings.Cut(valid, ".")
+	otherPayload, _, _ := strings.Cut(mustSign(secret, "user-43", nil, nil), ".")
+
+	tests := []struct {
+		name      string
+		token     string
+		secrets   []string
+		expected  *Identity
+		expectErr error
+	}{
+		{
+			name:     "valid token with attributes",
+			token:    valid,
+			secrets:  []string{secret},
+			expected: &Identity{UserID: "user-42", Attributes: releasenotes.AudienceAttributes{"plan": {"pro"}, "flags": {"beta"}}},
+		},
+		{
+			name:     "signed with previous secret",
+			token:    mustSign("idsec_old", "user-42", nil, nil),
+			secrets:  []string{secret, "idsec_old"},
+			expected: &Identity{UserID: "user-42", Attributes: releasenotes.AudienceAttributes{}},
+		},
+		{name: "uppercase signature", token: payload + "." + strings.ToUpper(sig), secrets: []string{secret}, expected: &Identity{UserID: "user-42", Attributes: releasenotes.AudienceAttributes{"plan": {"pro"}, "flags": {"beta"}}}},
+		{name: "wrong secret", token: mustSign("idsec_other", "user-42", nil, nil), secrets: []string{secret}, expectErr: ErrInvalidToken},
+		{name: "no secret", token: valid, secrets: nil, expectErr: ErrInvalidToken},
+		{name: "tampered payload", token: otherPayload + "." + sig, secrets: []string{secret}, expectErr: ErrInvalidToken},
+		{name: "missing signature", token: payload, secrets: []string{secret}, expectErr: ErrInvalidToken},
+		{name: "expired", token: mustSign(secret, "user-42", nil, &past), secrets: []string{secret}, expectErr: ErrTokenExpired},
+		{name: "missing user id", token: mustSign(secret, "", nil, nil), secrets: []string{secret}, expectErr: ErrInvalidToken},
+		{name: "nested attributes", token: mustSign(secret, "user-42", map[string]interface{}{"company": map[string]string{"plan": "pro"}}, nil), secrets: []string{secret}, expectErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verify(tt.token, tt.secrets, now)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("verify() error = %v, want %v", err, tt.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verify() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("verify() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIdentityConfigSecrets(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Minute)
+	active := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		cfg      IdentityConfig
+		expected []string
+	}{
+		{name: "no secret", cfg: IdentityConfig{}, expected: nil},
+		{name: "current secret", cfg: IdentityConfig{Secret: "a"}, expected: []string{"a"}},
+		{name: "previous secret in grace period", cfg: IdentityConfig{Secret: "a", PreviousSecret: "b", PreviousSecretExpiresAt: &active}, expected: []string{"a", "b"}},
+		{name: "previous secret expired", cfg: IdentityConfig{Secret: "a", PreviousSecret: "b", PreviousSecretExpiresAt: &expired}, expected: []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.secrets(now); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("secrets() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
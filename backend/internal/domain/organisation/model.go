@@ -7,6 +7,7 @@ import (
 	"github.com/devbydaniel/announcable/internal/database"
 	"github.com/devbydaniel/announcable/internal/domain/rbac"
 	"github.com/devbydaniel/announcable/internal/domain/user"
+	"github.com/devbydaniel/announcable/internal/locale"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -15,6 +16,9 @@ type Organisation struct {
 	database.BaseModel `gorm:"embedded"`
 	Name               string
 	ExternalID         uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+	DefaultLocale      string    `gorm:"type:varchar(8);default:'en'"`
+	// release notes need an approved review before they can be published
+	RequireReview bool `gorm:"default:false"`
 }
 
 type OrganisationUser struct {
@@ -47,7 +51,7 @@ func New(name string) (*Organisation, error) {
 	if strings.TrimSpace(name) == "" || len(name) < 3 {
 		return nil, errors.New("Please provide an organisation name with at least 3 characters")
 	}
-	return &Organisation{Name: name}, nil
+	return &Organisation{Name: name, DefaultLocale: locale.Default}, nil
 }
 
 func Connect(org *Organisation, user *user.User, role rbac.Role) *OrganisationUser {
@@ -1,6 +1,8 @@
 package organisation
 
 import (
+	"errors"
+
 	"github.com/devbydaniel/announcable/internal/database"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -45,7 +47,10 @@ func (r *repository) FindOrgByExternalId(externalId uuid.UUID) (*Organisation, e
 	var org Organisation
 
 	if err := r.db.Client.First(&org, "external_id = ?", externalId).Error; err != nil {
-		log.Error().Err(err).Msg("Error finding organisation by external id")
+		// unknown IDs are expected, they come from widget requests
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error().Err(err).Msg("Error finding organisation by external id")
+		}
 		return nil, err
 	}
 	return &org, nil
@@ -56,6 +61,12 @@ func (r *repository) UpdateOrg(orgId uuid.UUID, org *Organisation) error {
 	return r.db.Client.Model(&Organisation{}).Where("id = ?", orgId).Updates(org).Error
 }
 
+func (r *repository) UpdateRequireReview(orgId uuid.UUID, requireReview bool) error {
+	log.Trace().Str("orgId", orgId.String()).Bool("requireReview", requireReview).Msg("UpdateRequireReview")
+	// Updates with a struct skips false, so the column is set explicitly
+	return r.db.Client.Model(&Organisation{}).Where("id = ?", orgId).Update("require_review", requireReview).Error
+}
+
 func (r *repository) SaveOrgUser(ou *OrganisationUser, tx *gorm.DB) error {
 	log.Trace().Msg("Save")
 	var client *gorm.DB
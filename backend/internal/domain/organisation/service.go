@@ -9,9 +9,16 @@ import (
 	"github.com/devbydaniel/announcable/internal/domain/rbac"
 	"github.com/devbydaniel/announcable/internal/domain/user"
 	"github.com/devbydaniel/announcable/internal/email"
+	"github.com/devbydaniel/announcable/internal/locale"
 	"github.com/devbydaniel/announcable/internal/random"
 	"github.com/devbydaniel/announcable/internal/util"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnsupportedLocale = errors.New("language is not supported")
+	ErrOrgNotFound       = errors.New("organisation not found")
 )
 
 type service struct {
@@ -167,7 +174,11 @@ func (s *service) GetOrg(orgId uuid.UUID) (*Organisation, error) {
 
 func (s *service) GetOrgByExternalId(externalId uuid.UUID) (*Organisation, error) {
 	log.Trace().Str("externalId", externalId.String()).Msg("GetOrgByExternalId")
-	return s.repo.FindOrgByExternalId(externalId)
+	org, err := s.repo.FindOrgByExternalId(externalId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrgNotFound
+	}
+	return org, err
 }
 
 func (s *service) UpdateOrg(orgId uuid.UUID, org *Organisation) error {
@@ -175,6 +186,22 @@ func (s *service) UpdateOrg(orgId uuid.UUID, org *Organisation) error {
 	return s.repo.UpdateOrg(orgId, org)
 }
 
+// UpdateDefaultLocale sets the language release notes are written in and fall back to
+func (s *service) UpdateDefaultLocale(orgId uuid.UUID, lang string) error {
+	log.Trace().Str("orgId", orgId.String()).Str("lang", lang).Msg("UpdateDefaultLocale")
+	if !locale.IsSupported(lang) {
+		return ErrUnsupportedLocale
+	}
+	return s.repo.UpdateOrg(orgId, &Organisation{DefaultLocale: lang})
+}
+
+// UpdateRequireReview turns the review policy on or off. While it is on, release notes
+// must be approved by a reviewer before they can be published.
+func (s *service) UpdateRequireReview(orgId uuid.UUID, requireReview bool) error {
+	log.Trace().Str("orgId", orgId.String()).Bool("requireReview", requireReview).Msg("UpdateRequireReview")
+	return s.repo.UpdateRequireReview(orgId, requireReview)
+}
+
 func (s *service) RegenerateExternalId(orgId uuid.UUID) (uuid.UUID, error) {
 	log.Trace().Msg("RegenerateExternalId")
 	externalId, err := uuid.NewRandom()
@@ -14,4 +14,6 @@ type ReleaseNoteLike struct {
 	OrganisationID     uuid.UUID
 	Organisation       organisation.Organisation
 	ClientID           string `gorm:"type:text;not null"`
+	// empty for a like, else the emoji of a reaction
+	Reaction string `gorm:"type:varchar(32);not null;default:''"`
 }
@@ -55,10 +55,10 @@ func (r *repository) FindByOrgID(orgID uuid.UUID) ([]ReleaseNoteLike, error) {
 	return likes, nil
 }
 
-func (r *repository) FindByReleaseNoteAndClientID(releaseNoteID uuid.UUID, clientID string) (*ReleaseNoteLike, error) {
-	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Str("clientID", clientID).Msg("FindByReleaseNoteAndClientID")
+func (r *repository) FindByReleaseNoteAndClientID(releaseNoteID uuid.UUID, clientID, reaction string) (*ReleaseNoteLike, error) {
+	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Str("clientID", clientID).Str("reaction", reaction).Msg("FindByReleaseNoteAndClientID")
 	var like ReleaseNoteLike
-	if err := r.db.Client.Where("release_note_id = ? AND client_id = ?", releaseNoteID, clientID).First(&like).Error; err != nil {
+	if err := r.db.Client.Where("release_note_id = ? AND client_id = ? AND reaction = ?", releaseNoteID, clientID, reaction).First(&like).Error; err != nil {
 		if err.Error() == "record not found" {
 			return nil, nil
 		}
@@ -67,3 +67,49 @@ func (r *repository) FindByReleaseNoteAndClientID(releaseNoteID uuid.UUID, clien
 	}
 	return &like, nil
 }
+
+// Count counts the likes (empty reaction) or the reactions of one kind of a release note
+func (r *repository) Count(releaseNoteID uuid.UUID, reaction string) (int, error) {
+	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Str("reaction", reaction).Msg("Count")
+	var count int64
+	if err := r.db.Client.Model(&ReleaseNoteLike{}).Where("release_note_id = ? AND reaction = ?", releaseNoteID, reaction).Count(&count).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting likes")
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// CountByReaction counts the likes and reactions of a release note by reaction, likes under
+// the empty reaction
+func (r *repository) CountByReaction(releaseNoteID uuid.UUID) (map[string]int, error) {
+	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Msg("CountByReaction")
+	var rows []struct {
+		Reaction string
+		Count    int
+	}
+	if err := r.db.Client.Model(&ReleaseNoteLike{}).
+		Select("reaction, COUNT(*) AS count").
+		Where("release_note_id = ?", releaseNoteID).
+		Group("reaction").
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting likes by reaction")
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Reaction] = row.Count
+	}
+	return counts, nil
+}
+
+// FindReactionsByClientID returns the reactions of a client to a release note, an empty
+// reaction for a like
+func (r *repository) FindReactionsByClientID(releaseNoteID uuid.UUID, clientID string) ([]string, error) {
+	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Str("clientID", clientID).Msg("FindReactionsByClientID")
+	var reactions []string
+	if err := r.db.Client.Model(&ReleaseNoteLike{}).Where("release_note_id = ? AND client_id = ?", releaseNoteID, clientID).Pluck("reaction", &reactions).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding reactions")
+		return nil, err
+	}
+	return reactions, nil
+}
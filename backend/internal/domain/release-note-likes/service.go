@@ -13,11 +13,29 @@ func NewService(r *repository) *service {
 	return &service{repo: r}
 }
 
+// State holds the likes and reactions of a release note as seen by one end user
+type State struct {
+	IsLiked   bool
+	LikeCount int
+	// Reactions counts the reactions by emoji
+	Reactions map[string]int
+	// Reacted holds the reactions of the end user
+	Reacted map[string]bool
+}
+
 func (s *service) ToggleLike(releaseNoteID uuid.UUID, orgID uuid.UUID, clientID string) (bool, error) {
 	log.Trace().Msg("ToggleLike")
+	return s.ToggleReaction(releaseNoteID, orgID, clientID, "")
+}
+
+// ToggleReaction adds the reaction of a client to a release note, or takes it back if the
+// client already reacted with it, and returns whether the client has reacted afterwards.
+// An empty reaction toggles a like.
+func (s *service) ToggleReaction(releaseNoteID uuid.UUID, orgID uuid.UUID, clientID, reaction string) (bool, error) {
+	log.Trace().Str("reaction", reaction).Msg("ToggleReaction")
 
 	// Check if like already exists
-	existingLike, err := s.repo.FindByReleaseNoteAndClientID(releaseNoteID, clientID)
+	existingLike, err := s.repo.FindByReleaseNoteAndClientID(releaseNoteID, clientID, reaction)
 	if err != nil {
 		return false, err
 	}
@@ -35,6 +53,7 @@ func (s *service) ToggleLike(releaseNoteID uuid.UUID, orgID uuid.UUID, clientID
 		ReleaseNoteID:  releaseNoteID,
 		OrganisationID: orgID,
 		ClientID:       clientID,
+		Reaction:       reaction,
 	}
 	if err := s.repo.Create(like); err != nil {
 		return false, err
@@ -44,22 +63,53 @@ func (s *service) ToggleLike(releaseNoteID uuid.UUID, orgID uuid.UUID, clientID
 
 func (s *service) GetLikeCount(releaseNoteID uuid.UUID) (int, error) {
 	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Msg("GetLikeCount")
-	likes, err := s.repo.FindByReleaseNoteID(releaseNoteID)
-	if err != nil {
-		return 0, err
-	}
-	return len(likes), nil
+	return s.repo.Count(releaseNoteID, "")
 }
 
 func (s *service) HasUserLiked(releaseNoteID uuid.UUID, clientID string) (bool, error) {
 	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Str("clientID", clientID).Msg("HasUserLiked")
-	like, err := s.repo.FindByReleaseNoteAndClientID(releaseNoteID, clientID)
+	like, err := s.repo.FindByReleaseNoteAndClientID(releaseNoteID, clientID, "")
 	if err != nil {
 		return false, err
 	}
 	return like != nil, nil
 }
 
+// GetState returns the like and reaction counts of a release note, and what the client liked
+// and reacted with. Without client ID nothing is liked.
+func (s *service) GetState(releaseNoteID uuid.UUID, clientID string) (*State, error) {
+	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Str("clientID", clientID).Msg("GetState")
+	counts, err := s.repo.CountByReaction(releaseNoteID)
+	if err != nil {
+		return nil, err
+	}
+	state := &State{
+		LikeCount: counts[""],
+		Reactions: make(map[string]int, len(counts)),
+		Reacted:   make(map[string]bool),
+	}
+	for reaction, count := range counts {
+		if reaction != "" {
+			state.Reactions[reaction] = count
+		}
+	}
+	if clientID == "" {
+		return state, nil
+	}
+	reactions, err := s.repo.FindReactionsByClientID(releaseNoteID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	for _, reaction := range reactions {
+		if reaction == "" {
+			state.IsLiked = true
+		} else {
+			state.Reacted[reaction] = true
+		}
+	}
+	return state, nil
+}
+
 func (s *service) GetLikesByReleaseNote(releaseNoteID uuid.UUID) ([]ReleaseNoteLike, error) {
 	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Msg("GetLikesByReleaseNote")
 	return s.repo.FindByReleaseNoteID(releaseNoteID)
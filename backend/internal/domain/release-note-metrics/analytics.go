@@ -0,0 +1,168 @@
+package releasenotemetrics
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxAnalyticsDays limits the date range of analytics queries
+const maxAnalyticsDays = 366
+
+// ErrInvalidDateRange is returned for analytics date ranges that end before they start or span more than a year
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// AnalyticsFilter selects the metrics aggregated by GetAnalytics
+type AnalyticsFilter struct {
+	// first and last day (UTC) of the range, both inclusive
+	From time.Time
+	To   time.Time
+	// optional, restricts the daily and total metrics to one release note
+	ReleaseNoteID *uuid.UUID
+}
+
+// Counts are aggregated metrics. Likes are counted on the day they were given and only if
+// they weren't taken back.
+type Counts struct {
+	Views         int
+	UniqueViewers int
+	CtaClicks     int
+	Likes         int
+}
+
+// ClickThroughRate returns the CTA clicks per view in percent
+func (c Counts) ClickThroughRate() float64 {
+	if c.Views == 0 {
+		return 0
+	}
+	return float64(c.CtaClicks) / float64(c.Views) * 100
+}
+
+// DailyMetrics are the metrics of one day (UTC)
+type DailyMetrics struct {
+	Day time.Time
+	Counts
+}
+
+// ReleaseNoteMetrics are the metrics of one release note in the date range
+type ReleaseNoteMetrics struct {
+	ReleaseNoteID uuid.UUID
+	Title         string
+	Counts
+}
+
+// Analytics are the metrics of an organisation in a date range
+type Analytics struct {
+	From time.Time
+	To   time.Time
+	// one entry per day of the range, including days without activity
+	Days []*DailyMetrics
+	// unique viewers are counted over the whole range, not summed up per day
+	Totals Counts
+	// release notes with activity in the range, most viewed first
+	ReleaseNotes []*ReleaseNoteMetrics
+}
+
+// GetAnalytics aggregates the metrics and likes of an organisation per day and per release note
+func (s *service) GetAnalytics(orgId uuid.UUID, filter AnalyticsFilter) (*Analytics, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("GetAnalytics")
+	from := truncateDay(filter.From)
+	to := truncateDay(filter.To)
+	if to.Before(from) || to.Sub(from) >= maxAnalyticsDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+	// the repository takes the end of the range exclusive
+	end := to.AddDate(0, 0, 1)
+
+	daily, err := s.repo.AggregateDaily(orgId, from, end, filter.ReleaseNoteID)
+	if err != nil {
+		return nil, err
+	}
+	dailyLikes, err := s.repo.AggregateDailyLikes(orgId, from, end, filter.ReleaseNoteID)
+	if err != nil {
+		return nil, err
+	}
+	uniqueViewers, err := s.repo.CountUniqueViewers(orgId, from, end, filter.ReleaseNoteID)
+	if err != nil {
+		return nil, err
+	}
+	byReleaseNote, err := s.repo.AggregateByReleaseNote(orgId, from, end)
+	if err != nil {
+		return nil, err
+	}
+	likesByReleaseNote, err := s.repo.AggregateLikesByReleaseNote(orgId, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &Analytics{
+		From:         from,
+		To:           to,
+		Days:         fillDays(from, to, daily, dailyLikes),
+		ReleaseNotes: mergeReleaseNotes(byReleaseNote, likesByReleaseNote),
+	}
+	for _, day := range analytics.Days {
+		analytics.Totals.Views += day.Views
+		analytics.Totals.CtaClicks += day.CtaClicks
+		analytics.Totals.Likes += day.Likes
+	}
+	analytics.Totals.UniqueViewers = uniqueViewers
+	return analytics, nil
+}
+
+// fillDays merges the aggregated metrics and likes into one entry per day from from to to
+func fillDays(from, to time.Time, metrics, likes []*DailyMetrics) []*DailyMetrics {
+	byDay := make(map[time.Time]*DailyMetrics)
+	var days []*DailyMetrics
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		d := &DailyMetrics{Day: day}
+		byDay[day] = d
+		days = append(days, d)
+	}
+	for _, m := range metrics {
+		if d, ok := byDay[truncateDay(m.Day)]; ok {
+			d.Views = m.Views
+			d.UniqueViewers = m.UniqueViewers
+			d.CtaClicks = m.CtaClicks
+		}
+	}
+	for _, l := range likes {
+		if d, ok := byDay[truncateDay(l.Day)]; ok {
+			d.Likes = l.Likes
+		}
+	}
+	return days
+}
+
+// mergeReleaseNotes adds the likes to the metrics of the release notes, adding notes that only
+// have likes, and sorts them by views
+func mergeReleaseNotes(metrics, likes []*ReleaseNoteMetrics) []*ReleaseNoteMetrics {
+	byId := make(map[uuid.UUID]*ReleaseNoteMetrics, len(metrics))
+	merged := make([]*ReleaseNoteMetrics, 0, len(metrics))
+	for _, m := range metrics {
+		byId[m.ReleaseNoteID] = m
+		merged = append(merged, m)
+	}
+	for _, l := range likes {
+		if m, ok := byId[l.ReleaseNoteID]; ok {
+			m.Likes = l.Likes
+			continue
+		}
+		merged = append(merged, l)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Views != merged[j].Views {
+			return merged[i].Views > merged[j].Views
+		}
+		return merged[i].Likes > merged[j].Likes
+	})
+	return merged
+}
+
+// truncateDay returns the start of the day of t in UTC
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
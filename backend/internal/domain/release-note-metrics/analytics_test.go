@@ -0,0 +1,73 @@
+package releasenotemetrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCountsClickThroughRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		counts   Counts
+		expected float64
+	}{
+		{name: "no views", counts: Counts{CtaClicks: 3}, expected: 0},
+		{name: "clicks per view", counts: Counts{Views: 8, CtaClicks: 2}, expected: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.counts.ClickThroughRate(); got != tt.expected {
+				t.Errorf("ClickThroughRate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFillDays(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	metrics := []*DailyMetrics{
+		{Day: day(2), Counts: Counts{Views: 4, UniqueViewers: 3, CtaClicks: 1}},
+		// outside the range
+		{Day: day(9), Counts: Counts{Views: 7}},
+	}
+	likes := []*DailyMetrics{
+		{Day: day(2), Counts: Counts{Likes: 2}},
+		{Day: day(3), Counts: Counts{Likes: 1}},
+	}
+
+	got := fillDays(day(1), day(3), metrics, likes)
+	expected := []*DailyMetrics{
+		{Day: day(1)},
+		{Day: day(2), Counts: Counts{Views: 4, UniqueViewers: 3, CtaClicks: 1, Likes: 2}},
+		{Day: day(3), Counts: Counts{Likes: 1}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("fillDays() = %+v, want %+v", got, expected)
+	}
+}
+
+func TestMergeReleaseNotes(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	metrics := []*ReleaseNoteMetrics{
+		{ReleaseNoteID: a, Title: "A", Counts: Counts{Views: 1}},
+		{ReleaseNoteID: b, Title: "B", Counts: Counts{Views: 5, CtaClicks: 2}},
+	}
+	likes := []*ReleaseNoteMetrics{
+		{ReleaseNoteID: a, Title: "A", Counts: Counts{Likes: 3}},
+		{ReleaseNoteID: c, Title: "C", Counts: Counts{Likes: 1}},
+	}
+
+	got := mergeReleaseNotes(metrics, likes)
+	expected := []*ReleaseNoteMetrics{
+		{ReleaseNoteID: b, Title: "B", Counts: Counts{Views: 5, CtaClicks: 2}},
+		{ReleaseNoteID: a, Title: "A", Counts: Counts{Views: 1, Likes: 3}},
+		{ReleaseNoteID: c, Title: "C", Counts: Counts{Likes: 1}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("mergeReleaseNotes() = %+v, want %+v", got, expected)
+	}
+}
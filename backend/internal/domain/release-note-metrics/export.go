@@ -0,0 +1,121 @@
+package releasenotemetrics
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidMetricType is returned for export filters with an unknown metric type
+var ErrInvalidMetricType = errors.New("invalid metric type")
+
+// ExportFilter selects the rows streamed by ExportMetrics and ExportLikes
+type ExportFilter struct {
+	// first and last day (UTC) of the range, both inclusive
+	From time.Time
+	To   time.Time
+	// optional, restricts the export to one release note
+	ReleaseNoteID *uuid.UUID
+	// optional, restricts a metrics export to one type. Ignored by ExportLikes.
+	MetricType MetricType
+}
+
+// MetricExportColumns are the CSV columns of a metrics export, in the order of MetricExport.Record
+var MetricExportColumns = []string{"id", "release_note_id", "client_id", "metric_type", "created_at"}
+
+// MetricExport is one exported metric
+type MetricExport struct {
+	ID            uuid.UUID  `json:"id"`
+	ReleaseNoteID uuid.UUID  `json:"release_note_id"`
+	ClientID      string     `json:"client_id"`
+	MetricType    MetricType `json:"metric_type"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Record returns the CSV record of the metric
+func (m *MetricExport) Record() []string {
+	return csvRecord(m.ID.String(), m.ReleaseNoteID.String(), m.ClientID, string(m.MetricType), formatExportTime(&m.CreatedAt))
+}
+
+// LikeExportColumns are the CSV columns of a likes export, in the order of LikeExport.Record
+var LikeExportColumns = []string{"id", "release_note_id", "client_id", "reaction", "created_at", "deleted_at"}
+
+// LikeExport is one exported like. Likes that were taken back are exported too, with the
+// time they were taken back as DeletedAt. Reactions are exported with their emoji, likes with
+// an empty Reaction.
+type LikeExport struct {
+	ID            uuid.UUID  `json:"id"`
+	ReleaseNoteID uuid.UUID  `json:"release_note_id"`
+	ClientID      string     `json:"client_id"`
+	Reaction      string     `json:"reaction"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeletedAt     *time.Time `json:"deleted_at"`
+}
+
+// Record returns the CSV record of the like
+func (l *LikeExport) Record() []string {
+	return csvRecord(l.ID.String(), l.ReleaseNoteID.String(), l.ClientID, l.Reaction, formatExportTime(&l.CreatedAt), formatExportTime(l.DeletedAt))
+}
+
+// IsValid reports whether t is a known metric type
+func (t MetricType) IsValid() bool {
+	return t == MetricTypeView || t == MetricTypeCtaClick
+}
+
+// ExportMetrics calls fn for every metric of an organisation matching the filter, oldest
+// first. Rows are read one by one, so exports of any size don't load all metrics into memory.
+// Streaming stops at the first error returned by fn.
+func (s *service) ExportMetrics(orgId uuid.UUID, filter ExportFilter, fn func(*MetricExport) error) error {
+	log.Trace().Str("orgId", orgId.String()).Msg("ExportMetrics")
+	from, end, err := exportRange(filter)
+	if err != nil {
+		return err
+	}
+	if filter.MetricType != "" && !filter.MetricType.IsValid() {
+		return ErrInvalidMetricType
+	}
+	return s.repo.StreamMetrics(orgId, from, end, filter.ReleaseNoteID, filter.MetricType, fn)
+}
+
+// ExportLikes calls fn for every like of an organisation matching the filter, oldest first,
+// including likes that were taken back. Like ExportMetrics, rows are read one by one.
+func (s *service) ExportLikes(orgId uuid.UUID, filter ExportFilter, fn func(*LikeExport) error) error {
+	log.Trace().Str("orgId", orgId.String()).Msg("ExportLikes")
+	from, end, err := exportRange(filter)
+	if err != nil {
+		return err
+	}
+	return s.repo.StreamLikes(orgId, from, end, filter.ReleaseNoteID, fn)
+}
+
+// exportRange returns the start and the exclusive end of the date range of an export.
+// Unlike analytics, exports aren't limited to a year.
+func exportRange(filter ExportFilter) (time.Time, time.Time, error) {
+	from := truncateDay(filter.From)
+	to := truncateDay(filter.To)
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// csvRecord returns the fields as a CSV record. Fields a spreadsheet would evaluate as a
+// formula, such as client IDs sent by end users, are prefixed with a quote to keep them text.
+func csvRecord(fields ...string) []string {
+	for i, f := range fields {
+		if f != "" && strings.ContainsRune("=+-@\t\r", rune(f[0])) {
+			fields[i] = "'" + f
+		}
+	}
+	return fields
+}
+
+// formatExportTime formats t as RFC 3339 in UTC, or returns an empty string for nil
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,108 @@
+package releasenotemetrics
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestExportRecords(t *testing.T) {
+	id := uuid.MustParse("9b2f6a1e-3c4d-4e5f-8a9b-0c1d2e3f4a5b")
+	noteId := uuid.MustParse("1a2b3c4d-5e6f-4a8b-9c0d-1e2f3a4b5c6d")
+	createdAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	deletedAt := time.Date(2024, 3, 2, 12, 0, 0, 500, time.UTC)
+
+	metric := &MetricExport{ID: id, ReleaseNoteID: noteId, ClientID: "client, \"quoted\"", MetricType: MetricTypeCtaClick, CreatedAt: createdAt}
+	expectedMetric := []string{id.String(), noteId.String(), "client, \"quoted\"", "cta_click", "2024-03-01T08:30:00Z"}
+	if got := metric.Record(); !reflect.DeepEqual(got, expectedMetric) {
+		t.Errorf("MetricExport.Record() = %v, want %v", got, expectedMetric)
+	}
+	if len(expectedMetric) != len(MetricExportColumns) {
+		t.Errorf("MetricExport.Record() has %d fields, want %d", len(expectedMetric), len(MetricExportColumns))
+	}
+
+	tests := []struct {
+		name     string
+		like     *LikeExport
+		expected []string
+	}{
+		{
+			name:     "active like",
+			like:     &LikeExport{ID: id, ReleaseNoteID: noteId, ClientID: "user:42", CreatedAt: createdAt},
+			expected: []string{id.String(), noteId.String(), "user:42", "", "2024-03-01T08:30:00Z", ""},
+		},
+		{
+			name:     "taken back",
+			like:     &LikeExport{ID: id, ReleaseNoteID: noteId, ClientID: "user:42", CreatedAt: createdAt, DeletedAt: &deletedAt},
+			expected: []string{id.String(), noteId.String(), "user:42", "", "2024-03-01T08:30:00Z", "2024-03-02T12:00:00.0000005Z"},
+		},
+		{
+			name:     "formula",
+			like:     &LikeExport{ID: id, ReleaseNoteID: noteId, ClientID: "=HYPERLINK(\"http://evil\")", Reaction: "@SUM(A1)", CreatedAt: createdAt},
+			expected: []string{id.String(), noteId.String(), "'=HYPERLINK(\"http://evil\")", "'@SUM(A1)", "2024-03-01T08:30:00Z", ""},
+		},
+		{
+			name:     "reaction",
+			like:     &LikeExport{ID: id, ReleaseNoteID: noteId, ClientID: "user:42", Reaction: "🎉", CreatedAt: createdAt},
+			expected: []string{id.String(), noteId.String(), "user:42", "🎉", "2024-03-01T08:30:00Z", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.like.Record()
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("LikeExport.Record() = %v, want %v", got, tt.expected)
+			}
+			if len(got) != len(LikeExportColumns) {
+				t.Errorf("LikeExport.Record() has %d fields, want %d", len(got), len(LikeExportColumns))
+			}
+		})
+	}
+}
+
+func TestCsvRecord(t *testing.T) {
+	got := csvRecord("=1+1", "+1", "-1", "@a", "\tx", "\rx", "a=b", "", "client-1")
+	expected := []string{"'=1+1", "'+1", "'-1", "'@a", "'\tx", "'\rx", "a=b", "", "client-1"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("csvRecord() = %q, want %q", got, expected)
+	}
+}
+
+func TestExportRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name        string
+		filter      ExportFilter
+		expectFrom  time.Time
+		expectEnd   time.Time
+		expectedErr error
+	}{
+		{name: "single day", filter: ExportFilter{From: day(1), To: day(1)}, expectFrom: day(1), expectEnd: day(2)},
+		{name: "truncates to days", filter: ExportFilter{From: day(1).Add(13 * time.Hour), To: day(5).Add(time.Hour)}, expectFrom: day(1), expectEnd: day(6)},
+		{name: "longer than a year", filter: ExportFilter{From: day(1).AddDate(-2, 0, 0), To: day(1)}, expectFrom: day(1).AddDate(-2, 0, 0), expectEnd: day(2)},
+		{name: "ends before it starts", filter: ExportFilter{From: day(5), To: day(1)}, expectedErr: ErrInvalidDateRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, end, err := exportRange(tt.filter)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("exportRange() error = %v, want %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("exportRange() error = %v", err)
+			}
+			if !from.Equal(tt.expectFrom) || !end.Equal(tt.expectEnd) {
+				t.Errorf("exportRange() = %v, %v, want %v, %v", from, end, tt.expectFrom, tt.expectEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package releasenotemetrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// views of a release note by the same client within this window are counted once
+	ViewDedupWindow = 30 * time.Minute
+	// events held in memory at most; further events are rejected until the next flush
+	maxBufferedEvents = 10000
+	// clients remembered for deduplication at most; beyond it, views are no longer deduplicated
+	maxTrackedViews = 100000
+	// a flush is started early once this many events are buffered
+	ingestBatchSize = 500
+)
+
+var (
+	// ErrBufferFull is returned by Ingester.Add when the buffer has no room for the events
+	ErrBufferFull = errors.New("metrics buffer full")
+	// ErrInvalidEvent is returned by Ingester.Add for events without a release note or with an unknown metric type
+	ErrInvalidEvent = errors.New("invalid metric event")
+)
+
+// Event is a metric reported by the widget
+type Event struct {
+	ReleaseNoteID uuid.UUID
+	MetricType    MetricType
+}
+
+// viewKey identifies the views of a release note by one client
+type viewKey struct {
+	releaseNoteID uuid.UUID
+	clientID      string
+}
+
+// Ingester buffers metric events in memory and writes them in batches. Views of a note by the
+// same client within ViewDedupWindow are dropped. Deduplication is per server instance.
+type Ingester struct {
+	repo     *repository
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	buffer    []*ReleaseNoteMetric
+	seenViews map[viewKey]time.Time
+	// signals Run to flush before the interval is over
+	full chan struct{}
+}
+
+// NewIngester creates an ingester that writes buffered events every interval
+func NewIngester(r *repository, interval time.Duration) *Ingester {
+	log.Trace().Msg("NewIngester")
+	return &Ingester{
+		repo:      r,
+		interval:  interval,
+		now:       time.Now,
+		seenViews: make(map[viewKey]time.Time),
+		full:      make(chan struct{}, 1),
+	}
+}
+
+// Add buffers the events of a client for the next flush and returns how many were accepted,
+// which is fewer than given if views were deduplicated. Either all or none of the events are
+// buffered; if there is no room for all of them, ErrBufferFull is returned.
+func (i *Ingester) Add(orgId uuid.UUID, clientId string, events []Event) (int, error) {
+	log.Trace().Str("orgId", orgId.String()).Int("events", len(events)).Msg("Add")
+	for _, e := range events {
+		if e.ReleaseNoteID == uuid.Nil || !e.MetricType.IsValid() {
+			return 0, ErrInvalidEvent
+		}
+	}
+	now := i.now()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(i.buffer)+len(events) > maxBufferedEvents {
+		return 0, ErrBufferFull
+	}
+	accepted := 0
+	for _, e := range events {
+		if e.MetricType == MetricTypeView && !i.trackView(viewKey{e.ReleaseNoteID, clientId}, now) {
+			continue
+		}
+		metric := &ReleaseNoteMetric{
+			ReleaseNoteID:  e.ReleaseNoteID,
+			OrganisationID: orgId,
+			ClientID:       clientId,
+			MetricType:     e.MetricType,
+		}
+		// the time the event was received, not the time it is written
+		metric.CreatedAt = now
+		i.buffer = append(i.buffer, metric)
+		accepted++
+	}
+	if len(i.buffer) >= ingestBatchSize {
+		select {
+		case i.full <- struct{}{}:
+		default:
+		}
+	}
+	return accepted, nil
+}
+
+// trackView records a view and reports whether it is the first one in the dedup window.
+// Must be called with i.mu held.
+func (i *Ingester) trackView(key viewKey, now time.Time) bool {
+	if seenAt, ok := i.seenViews[key]; ok && now.Sub(seenAt) < ViewDedupWindow {
+		return false
+	}
+	if len(i.seenViews) < maxTrackedViews {
+		i.seenViews[key] = now
+	}
+	return true
+}
+
+// Run flushes the buffer every interval, or earlier when it fills up, until ctx is cancelled.
+// The buffer is flushed a last time before Run returns.
+func (i *Ingester) Run(ctx context.Context) {
+	log.Info().Dur("interval", i.interval).Msg("Metrics ingester started")
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := i.Flush(); err != nil {
+				log.Error().Err(err).Msg("Error flushing metrics on shutdown")
+			}
+			log.Info().Msg("Metrics ingester stopped")
+			return
+		case <-ticker.C:
+		case <-i.full:
+		}
+		if err := i.Flush(); err != nil {
+			log.Error().Err(err).Msg("Error flushing metrics")
+		}
+	}
+}
+
+// Flush writes the buffered events and forgets views older than the dedup window.
+// Events of a batch that can't be written are retried one by one, so that a single event
+// for a deleted release note doesn't drop the whole batch; events failing again are dropped.
+func (i *Ingester) Flush() error {
+	log.Trace().Msg("Flush")
+	i.mu.Lock()
+	metrics := i.buffer
+	i.buffer = nil
+	now := i.now()
+	for key, seenAt := range i.seenViews {
+		if now.Sub(seenAt) >= ViewDedupWindow {
+			delete(i.seenViews, key)
+		}
+	}
+	i.mu.Unlock()
+
+	if len(metrics) == 0 {
+		return nil
+	}
+	if err := i.repo.CreateBatch(metrics, ingestBatchSize); err == nil {
+		return nil
+	}
+	var dropped int
+	var lastErr error
+	for _, m := range metrics {
+		if err := i.repo.Create(m); err != nil {
+			dropped++
+			lastErr = err
+		}
+	}
+	if dropped > 0 {
+		log.Warn().Int("dropped", dropped).Int("total", len(metrics)).Msg("Dropped metrics that could not be written")
+		return lastErr
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package releasenotemetrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestIngesterAdd(t *testing.T) {
+	orgId := uuid.New()
+	noteA := uuid.New()
+	noteB := uuid.New()
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		clientId string
+		after    time.Duration
+		events   []Event
+		expected int
+	}{
+		{name: "first views", clientId: "client-1", events: []Event{{noteA, MetricTypeView}, {noteB, MetricTypeView}}, expected: 2},
+		{name: "repeated view in the same batch", clientId: "client-2", events: []Event{{noteA, MetricTypeView}, {noteA, MetricTypeView}}, expected: 1},
+		{name: "view within the window", clientId: "client-1", after: 10 * time.Minute, events: []Event{{noteA, MetricTypeView}}, expected: 0},
+		{name: "clicks are not deduplicated", clientId: "client-1", after: 10 * time.Minute, events: []Event{{noteA, MetricTypeCtaClick}, {noteA, MetricTypeCtaClick}}, expected: 2},
+		{name: "view after the window", clientId: "client-1", after: ViewDedupWindow, events: []Event{{noteA, MetricTypeView}}, expected: 1},
+		{name: "view of another client", clientId: "client-3", after: ViewDedupWindow, events: []Event{{noteB, MetricTypeView}}, expected: 1},
+	}
+
+	ingester := NewIngester(nil, time.Second)
+	buffered := 0
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingester.now = func() time.Time { return start.Add(tt.after) }
+			got, err := ingester.Add(orgId, tt.clientId, tt.events)
+			if err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Add() = %d, want %d", got, tt.expected)
+			}
+			buffered += tt.expected
+			if len(ingester.buffer) != buffered {
+				t.Errorf("buffered %d events, want %d", len(ingester.buffer), buffered)
+			}
+		})
+	}
+}
+
+func TestIngesterAddRejects(t *testing.T) {
+	orgId := uuid.New()
+	ingester := NewIngester(nil, time.Second)
+
+	if _, err := ingester.Add(orgId, "client-1", []Event{{uuid.New(), MetricTypeView}, {uuid.New(), "like"}}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Add() with unknown metric type error = %v, want ErrInvalidEvent", err)
+	}
+	if _, err := ingester.Add(orgId, "client-1", []Event{{uuid.Nil, MetricTypeView}}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("Add() without release note error = %v, want ErrInvalidEvent", err)
+	}
+	if len(ingester.buffer) != 0 {
+		t.Fatalf("invalid batches buffered %d events", len(ingester.buffer))
+	}
+
+	ingester.buffer = make([]*ReleaseNoteMetric, maxBufferedEvents-1)
+	if _, err := ingester.Add(orgId, "client-1", []Event{{uuid.New(), MetricTypeCtaClick}, {uuid.New(), MetricTypeCtaClick}}); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("Add() beyond the buffer error = %v, want ErrBufferFull", err)
+	}
+	if n, err := ingester.Add(orgId, "client-1", []Event{{uuid.New(), MetricTypeCtaClick}}); err != nil || n != 1 {
+		t.Errorf("Add() of the last free slot = %d, %v, want 1, nil", n, err)
+	}
+}
+
+func TestIngesterFlushForgetsOldViews(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ingester := NewIngester(nil, time.Second)
+	ingester.seenViews[viewKey{uuid.New(), "old"}] = start.Add(-ViewDedupWindow)
+	ingester.seenViews[viewKey{uuid.New(), "recent"}] = start.Add(-time.Minute)
+	ingester.now = func() time.Time { return start }
+
+	if err := ingester.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(ingester.seenViews) != 1 {
+		t.Errorf("Flush() kept %d views, want 1", len(ingester.seenViews))
+	}
+}
@@ -1,6 +1,8 @@
 package releasenotemetrics
 
 import (
+	"time"
+
 	"github.com/devbydaniel/announcable/internal/database"
 	"github.com/devbydaniel/announcable/internal/logger"
 	"github.com/google/uuid"
@@ -26,6 +28,16 @@ func (r *repository) Create(metric *ReleaseNoteMetric) error {
 	return nil
 }
 
+// CreateBatch inserts metrics in batches of batchSize rows, all or none
+func (r *repository) CreateBatch(metrics []*ReleaseNoteMetric, batchSize int) error {
+	log.Trace().Int("count", len(metrics)).Msg("CreateBatch")
+	if err := r.db.Client.CreateInBatches(metrics, batchSize).Error; err != nil {
+		log.Error().Err(err).Msg("Error creating metrics")
+		return err
+	}
+	return nil
+}
+
 func (r *repository) FindByReleaseNoteID(releaseNoteID uuid.UUID) ([]ReleaseNoteMetric, error) {
 	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Msg("FindByReleaseNoteID")
 	var metrics []ReleaseNoteMetric
@@ -45,3 +57,217 @@ func (r *repository) FindByOrgID(orgID uuid.UUID) ([]ReleaseNoteMetric, error) {
 	}
 	return metrics, nil
 }
+
+// CountByType counts the metrics of a type of a release note
+func (r *repository) CountByType(releaseNoteID uuid.UUID, metricType MetricType) (int, error) {
+	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Str("metricType", string(metricType)).Msg("CountByType")
+	var count int64
+	if err := r.db.Client.Model(&ReleaseNoteMetric{}).
+		Where("release_note_id = ? AND metric_type = ?", releaseNoteID, metricType).
+		Count(&count).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting metrics")
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// AggregateDaily sums up views, unique viewers and CTA clicks per day (UTC) from from until
+// before end, optionally of a single release note
+func (r *repository) AggregateDaily(orgID uuid.UUID, from, end time.Time, releaseNoteID *uuid.UUID) ([]*DailyMetrics, error) {
+	log.Trace().Str("orgID", orgID.String()).Msg("AggregateDaily")
+	var rows []struct {
+		Day           time.Time
+		Views         int
+		UniqueViewers int
+		CtaClicks     int
+	}
+	query := r.db.Client.Model(&ReleaseNoteMetric{}).
+		Select(`date_trunc('day', created_at AT TIME ZONE 'UTC') AS day,
+			COUNT(*) FILTER (WHERE metric_type = ?) AS views,
+			COUNT(DISTINCT client_id) FILTER (WHERE metric_type = ?) AS unique_viewers,
+			COUNT(*) FILTER (WHERE metric_type = ?) AS cta_clicks`, MetricTypeView, MetricTypeView, MetricTypeCtaClick).
+		Where("organisation_id = ? AND created_at >= ? AND created_at < ?", orgID, from, end)
+	if releaseNoteID != nil {
+		query = query.Where("release_note_id = ?", *releaseNoteID)
+	}
+	if err := query.Group("day").Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Msg("Error aggregating daily metrics")
+		return nil, err
+	}
+	daily := make([]*DailyMetrics, len(rows))
+	for i, row := range rows {
+		daily[i] = &DailyMetrics{Day: row.Day, Counts: Counts{Views: row.Views, UniqueViewers: row.UniqueViewers, CtaClicks: row.CtaClicks}}
+	}
+	return daily, nil
+}
+
+// AggregateDailyLikes counts the likes given per day (UTC) from from until before end that
+// weren't taken back, optionally of a single release note. Reactions aren't counted.
+func (r *repository) AggregateDailyLikes(orgID uuid.UUID, from, end time.Time, releaseNoteID *uuid.UUID) ([]*DailyMetrics, error) {
+	log.Trace().Str("orgID", orgID.String()).Msg("AggregateDailyLikes")
+	var rows []struct {
+		Day   time.Time
+		Likes int
+	}
+	query := r.db.Client.Table("release_note_likes").
+		Select("date_trunc('day', created_at AT TIME ZONE 'UTC') AS day, COUNT(*) AS likes").
+		Where("organisation_id = ? AND reaction = '' AND created_at >= ? AND created_at < ? AND deleted_at IS NULL", orgID, from, end)
+	if releaseNoteID != nil {
+		query = query.Where("release_note_id = ?", *releaseNoteID)
+	}
+	if err := query.Group("day").Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Msg("Error aggregating daily likes")
+		return nil, err
+	}
+	daily := make([]*DailyMetrics, len(rows))
+	for i, row := range rows {
+		daily[i] = &DailyMetrics{Day: row.Day, Counts: Counts{Likes: row.Likes}}
+	}
+	return daily, nil
+}
+
+// CountUniqueViewers counts the distinct clients that viewed a release note from from until
+// before end, optionally a single release note
+func (r *repository) CountUniqueViewers(orgID uuid.UUID, from, end time.Time, releaseNoteID *uuid.UUID) (int, error) {
+	log.Trace().Str("orgID", orgID.String()).Msg("CountUniqueViewers")
+	var count int64
+	query := r.db.Client.Model(&ReleaseNoteMetric{}).
+		Where("organisation_id = ? AND metric_type = ? AND created_at >= ? AND created_at < ?", orgID, MetricTypeView, from, end)
+	if releaseNoteID != nil {
+		query = query.Where("release_note_id = ?", *releaseNoteID)
+	}
+	if err := query.Distinct("client_id").Count(&count).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting unique viewers")
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// AggregateByReleaseNote sums up views, unique viewers and CTA clicks per release note from
+// from until before end
+func (r *repository) AggregateByReleaseNote(orgID uuid.UUID, from, end time.Time) ([]*ReleaseNoteMetrics, error) {
+	log.Trace().Str("orgID", orgID.String()).Msg("AggregateByReleaseNote")
+	var rows []struct {
+		ReleaseNoteID uuid.UUID
+		Title         string
+		Views         int
+		UniqueViewers int
+		CtaClicks     int
+	}
+	if err := r.db.Client.Model(&ReleaseNoteMetric{}).
+		Select(`release_note_metrics.release_note_id, release_notes.title,
+			COUNT(*) FILTER (WHERE release_note_metrics.metric_type = ?) AS views,
+			COUNT(DISTINCT release_note_metrics.client_id) FILTER (WHERE release_note_metrics.metric_type = ?) AS unique_viewers,
+			COUNT(*) FILTER (WHERE release_note_metrics.metric_type = ?) AS cta_clicks`, MetricTypeView, MetricTypeView, MetricTypeCtaClick).
+		Joins("JOIN release_notes ON release_notes.id = release_note_metrics.release_note_id AND release_notes.deleted_at IS NULL").
+		Where("release_note_metrics.organisation_id = ? AND release_note_metrics.created_at >= ? AND release_note_metrics.created_at < ?", orgID, from, end).
+		Group("release_note_metrics.release_note_id, release_notes.title").
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Msg("Error aggregating metrics by release note")
+		return nil, err
+	}
+	metrics := make([]*ReleaseNoteMetrics, len(rows))
+	for i, row := range rows {
+		metrics[i] = &ReleaseNoteMetrics{
+			ReleaseNoteID: row.ReleaseNoteID,
+			Title:         row.Title,
+			Counts:        Counts{Views: row.Views, UniqueViewers: row.UniqueViewers, CtaClicks: row.CtaClicks},
+		}
+	}
+	return metrics, nil
+}
+
+// AggregateLikesByReleaseNote counts the likes per release note given from from until before
+// end that weren't taken back. Reactions aren't counted.
+func (r *repository) AggregateLikesByReleaseNote(orgID uuid.UUID, from, end time.Time) ([]*ReleaseNoteMetrics, error) {
+	log.Trace().Str("orgID", orgID.String()).Msg("AggregateLikesByReleaseNote")
+	var rows []struct {
+		ReleaseNoteID uuid.UUID
+		Title         string
+		Likes         int
+	}
+	if err := r.db.Client.Table("release_note_likes").
+		Select("release_note_likes.release_note_id, release_notes.title, COUNT(*) AS likes").
+		Joins("JOIN release_notes ON release_notes.id = release_note_likes.release_note_id AND release_notes.deleted_at IS NULL").
+		Where("release_note_likes.organisation_id = ? AND release_note_likes.reaction = '' AND release_note_likes.created_at >= ? AND release_note_likes.created_at < ? AND release_note_likes.deleted_at IS NULL", orgID, from, end).
+		Group("release_note_likes.release_note_id, release_notes.title").
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Msg("Error aggregating likes by release note")
+		return nil, err
+	}
+	likes := make([]*ReleaseNoteMetrics, len(rows))
+	for i, row := range rows {
+		likes[i] = &ReleaseNoteMetrics{ReleaseNoteID: row.ReleaseNoteID, Title: row.Title, Counts: Counts{Likes: row.Likes}}
+	}
+	return likes, nil
+}
+
+// StreamMetrics calls fn for each metric from from until before end, oldest first, optionally
+// of a single release note and metric type. Rows are scanned one at a time.
+func (r *repository) StreamMetrics(orgID uuid.UUID, from, end time.Time, releaseNoteID *uuid.UUID, metricType MetricType, fn func(*MetricExport) error) error {
+	log.Trace().Str("orgID", orgID.String()).Msg("StreamMetrics")
+	query := r.db.Client.Model(&ReleaseNoteMetric{}).
+		Select("id, release_note_id, client_id, metric_type, created_at").
+		Where("organisation_id = ? AND created_at >= ? AND created_at < ?", orgID, from, end)
+	if releaseNoteID != nil {
+		query = query.Where("release_note_id = ?", *releaseNoteID)
+	}
+	if metricType != "" {
+		query = query.Where("metric_type = ?", metricType)
+	}
+	rows, err := query.Order("created_at, id").Rows()
+	if err != nil {
+		log.Error().Err(err).Msg("Error querying metrics")
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m MetricExport
+		if err := rows.Scan(&m.ID, &m.ReleaseNoteID, &m.ClientID, &m.MetricType, &m.CreatedAt); err != nil {
+			log.Error().Err(err).Msg("Error scanning metric")
+			return err
+		}
+		if err := fn(&m); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Msg("Error reading metrics")
+		return err
+	}
+	return nil
+}
+
+// StreamLikes calls fn for each like given from from until before end, oldest first,
+// including likes that were taken back and reactions, optionally of a single release note. Rows are
+// scanned one at a time.
+func (r *repository) StreamLikes(orgID uuid.UUID, from, end time.Time, releaseNoteID *uuid.UUID, fn func(*LikeExport) error) error {
+	log.Trace().Str("orgID", orgID.String()).Msg("StreamLikes")
+	query := r.db.Client.Table("release_note_likes").
+		Select("id, release_note_id, client_id, reaction, created_at, deleted_at").
+		Where("organisation_id = ? AND created_at >= ? AND created_at < ?", orgID, from, end)
+	if releaseNoteID != nil {
+		query = query.Where("release_note_id = ?", *releaseNoteID)
+	}
+	rows, err := query.Order("created_at, id").Rows()
+	if err != nil {
+		log.Error().Err(err).Msg("Error querying likes")
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l LikeExport
+		if err := rows.Scan(&l.ID, &l.ReleaseNoteID, &l.ClientID, &l.Reaction, &l.CreatedAt, &l.DeletedAt); err != nil {
+			log.Error().Err(err).Msg("Error scanning like")
+			return err
+		}
+		if err := fn(&l); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Msg("Error reading likes")
+		return err
+	}
+	return nil
+}
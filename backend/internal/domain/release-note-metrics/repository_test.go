@@ -0,0 +1,206 @@
+package releasenotemetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenotelikes "github.com/devbydaniel/announcable/internal/domain/release-note-likes"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// analyticsFixture holds two organisations with metrics and likes around March 1st to 3rd 2024
+type analyticsFixture struct {
+	db         *database.DB
+	org        *organisation.Organisation
+	first      uuid.UUID
+	second     uuid.UUID
+	otherOrgId uuid.UUID
+}
+
+func setupAnalytics(t *testing.T) *analyticsFixture {
+	t.Helper()
+	cleanup := testutil.SetupTest()
+	t.Cleanup(cleanup)
+
+	testDB := testutil.SetupTestDB(t)
+	t.Cleanup(func() { testDB.Cleanup(t) })
+	db := testDB.DB
+	if err := db.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{}); err != nil {
+		t.Fatal(err)
+	}
+	// created by the migrations, AutoMigrate doesn't create enum types
+	if err := db.Client.Exec("CREATE TYPE release_note_metric_type AS ENUM ('view', 'cta_click')").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Client.AutoMigrate(&ReleaseNoteMetric{}, &releasenotelikes.ReleaseNoteLike{}); err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(value interface{}) {
+		t.Helper()
+		if err := db.Client.Create(value).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	newOrg := func() *organisation.Organisation {
+		org, err := organisation.New("Test Org")
+		if err != nil {
+			t.Fatal(err)
+		}
+		create(org)
+		return org
+	}
+	newNote := func(orgId uuid.UUID, title string) uuid.UUID {
+		rn := &releasenotes.ReleaseNote{OrganisationID: orgId, Title: title}
+		create(rn)
+		return rn.ID
+	}
+	f := &analyticsFixture{db: db, org: newOrg()}
+	otherOrg := newOrg()
+	f.otherOrgId = otherOrg.ID
+	f.first = newNote(f.org.ID, "First")
+	f.second = newNote(f.org.ID, "Second")
+	otherNote := newNote(otherOrg.ID, "Other")
+
+	at := func(day, hour, min, sec int) time.Time { return time.Date(2024, 3, day, hour, min, sec, 0, time.UTC) }
+	metric := func(orgId, noteId uuid.UUID, clientId string, metricType MetricType, createdAt time.Time) {
+		m := &ReleaseNoteMetric{OrganisationID: orgId, ReleaseNoteID: noteId, ClientID: clientId, MetricType: metricType}
+		m.CreatedAt = createdAt
+		create(m)
+	}
+	like := func(orgId, noteId uuid.UUID, clientId, reaction string, createdAt time.Time, takenBack bool) {
+		l := &releasenotelikes.ReleaseNoteLike{OrganisationID: orgId, ReleaseNoteID: noteId, ClientID: clientId, Reaction: reaction}
+		l.CreatedAt = createdAt
+		if takenBack {
+			l.DeletedAt = gorm.DeletedAt{Time: createdAt.Add(time.Minute), Valid: true}
+		}
+		create(l)
+	}
+
+	// right before and after the range
+	metric(f.org.ID, f.first, "c1", MetricTypeView, at(1, 0, 0, 0).Add(-time.Second))
+	metric(f.org.ID, f.first, "c4", MetricTypeView, at(4, 0, 0, 0))
+	like(f.org.ID, f.second, "c2", "", at(4, 0, 0, 0), false)
+	// first day: a client viewing twice, a second client and a click
+	metric(f.org.ID, f.first, "c1", MetricTypeView, at(1, 0, 0, 0))
+	metric(f.org.ID, f.first, "c1", MetricTypeView, at(1, 23, 59, 59))
+	metric(f.org.ID, f.first, "c2", MetricTypeView, at(1, 12, 0, 0))
+	metric(f.org.ID, f.first, "c1", MetricTypeCtaClick, at(1, 12, 0, 5))
+	// second day: only likes count, a taken back like and a reaction don't
+	like(f.org.ID, f.first, "c1", "", at(2, 9, 0, 0), false)
+	like(f.org.ID, f.first, "c2", "", at(2, 9, 0, 0), true)
+	like(f.org.ID, f.first, "c3", "🎉", at(2, 9, 0, 0), false)
+	// third day, the last view is stored with an offset but still falls on that day in UTC
+	metric(f.org.ID, f.second, "c1", MetricTypeView, at(3, 10, 0, 0))
+	metric(f.org.ID, f.second, "c3", MetricTypeView, time.Date(2024, 3, 4, 0, 30, 0, 0, time.FixedZone("CET", 3600)))
+	like(f.org.ID, f.second, "c1", "", at(3, 10, 0, 0), false)
+	// activity of another organisation in the range
+	metric(otherOrg.ID, otherNote, "c1", MetricTypeView, at(2, 12, 0, 0))
+	metric(otherOrg.ID, otherNote, "c5", MetricTypeCtaClick, at(2, 12, 0, 0))
+	like(otherOrg.ID, otherNote, "c1", "", at(2, 12, 0, 0), false)
+	return f
+}
+
+func TestGetAnalyticsAggregation(t *testing.T) {
+	f := setupAnalytics(t)
+	s := NewService(NewRepository(f.db))
+
+	analytics, err := s.GetAnalytics(f.org.ID, AnalyticsFilter{
+		From: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDays := []Counts{
+		{Views: 3, UniqueViewers: 2, CtaClicks: 1},
+		{Likes: 1},
+		{Views: 2, UniqueViewers: 2, Likes: 1},
+	}
+	if len(analytics.Days) != len(expectedDays) {
+		t.Fatalf("got %d days, want %d", len(analytics.Days), len(expectedDays))
+	}
+	for i, day := range analytics.Days {
+		if day.Counts != expectedDays[i] {
+			t.Errorf("day %s = %+v, want %+v", day.Day.Format(time.DateOnly), day.Counts, expectedDays[i])
+		}
+	}
+
+	// c1, c2 and c3 viewed notes, c1 on two days
+	expectedTotals := Counts{Views: 5, UniqueViewers: 3, CtaClicks: 1, Likes: 2}
+	if analytics.Totals != expectedTotals {
+		t.Errorf("Totals = %+v, want %+v", analytics.Totals, expectedTotals)
+	}
+
+	expectedNotes := []ReleaseNoteMetrics{
+		{ReleaseNoteID: f.first, Title: "First", Counts: Counts{Views: 3, UniqueViewers: 2, CtaClicks: 1, Likes: 1}},
+		{ReleaseNoteID: f.second, Title: "Second", Counts: Counts{Views: 2, UniqueViewers: 2, Likes: 1}},
+	}
+	if len(analytics.ReleaseNotes) != len(expectedNotes) {
+		t.Fatalf("got %d release notes, want %d", len(analytics.ReleaseNotes), len(expectedNotes))
+	}
+	for i, rn := range analytics.ReleaseNotes {
+		if *rn != expectedNotes[i] {
+			t.Errorf("release note %d = %+v, want %+v", i, *rn, expectedNotes[i])
+		}
+	}
+}
+
+func TestGetAnalyticsOfOneReleaseNote(t *testing.T) {
+	f := setupAnalytics(t)
+	s := NewService(NewRepository(f.db))
+
+	analytics, err := s.GetAnalytics(f.org.ID, AnalyticsFilter{
+		From:          time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		To:            time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		ReleaseNoteID: &f.second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDays := []Counts{
+		{},
+		{Views: 2, UniqueViewers: 2, Likes: 1},
+		{Likes: 1},
+	}
+	if len(analytics.Days) != len(expectedDays) {
+		t.Fatalf("got %d days, want %d", len(analytics.Days), len(expectedDays))
+	}
+	for i, day := range analytics.Days {
+		if day.Counts != expectedDays[i] {
+			t.Errorf("day %s = %+v, want %+v", day.Day.Format(time.DateOnly), day.Counts, expectedDays[i])
+		}
+	}
+	expectedTotals := Counts{Views: 2, UniqueViewers: 2, Likes: 2}
+	if analytics.Totals != expectedTotals {
+		t.Errorf("Totals = %+v, want %+v", analytics.Totals, expectedTotals)
+	}
+}
+
+func TestGetAnalyticsOfOtherOrganisation(t *testing.T) {
+	f := setupAnalytics(t)
+	s := NewService(NewRepository(f.db))
+
+	analytics, err := s.GetAnalytics(f.otherOrgId, AnalyticsFilter{
+		From: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTotals := Counts{Views: 1, UniqueViewers: 1, CtaClicks: 1, Likes: 1}
+	if analytics.Totals != expectedTotals {
+		t.Errorf("Totals = %+v, want %+v", analytics.Totals, expectedTotals)
+	}
+	if len(analytics.ReleaseNotes) != 1 || analytics.ReleaseNotes[0].Title != "Other" {
+		t.Errorf("ReleaseNotes = %+v, want only the note of the organisation", analytics.ReleaseNotes)
+	}
+}
@@ -26,34 +26,12 @@ func (s *service) CreateMetric(releaseNoteID uuid.UUID, orgID uuid.UUID, clientI
 
 func (s *service) GetViewCount(releaseNoteID uuid.UUID) (int, error) {
 	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Msg("GetViewCount")
-	metrics, err := s.repo.FindByReleaseNoteID(releaseNoteID)
-	if err != nil {
-		return 0, err
-	}
-
-	viewCount := 0
-	for _, metric := range metrics {
-		if metric.MetricType == MetricTypeView {
-			viewCount++
-		}
-	}
-	return viewCount, nil
+	return s.repo.CountByType(releaseNoteID, MetricTypeView)
 }
 
 func (s *service) GetCtaClickCount(releaseNoteID uuid.UUID) (int, error) {
 	log.Trace().Str("releaseNoteID", releaseNoteID.String()).Msg("GetCtaClickCount")
-	metrics, err := s.repo.FindByReleaseNoteID(releaseNoteID)
-	if err != nil {
-		return 0, err
-	}
-
-	clickCount := 0
-	for _, metric := range metrics {
-		if metric.MetricType == MetricTypeCtaClick {
-			clickCount++
-		}
-	}
-	return clickCount, nil
+	return s.repo.CountByType(releaseNoteID, MetricTypeCtaClick)
 }
 
 func (s *service) GetMetricsByReleaseNote(releaseNoteID uuid.UUID) ([]ReleaseNoteMetric, error) {
@@ -0,0 +1,16 @@
+package releasenoteseen
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SeenReleaseNote records when an end user last saw a release note. A note is unread
+// for the user if it was never seen or updated after it was seen.
+type SeenReleaseNote struct {
+	ReleaseNoteID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ClientID       string    `gorm:"type:text;primaryKey"`
+	OrganisationID uuid.UUID `gorm:"type:uuid;not null"`
+	SeenAt         time.Time `gorm:"type:timestamptz;not null"`
+}
@@ -0,0 +1,60 @@
+package releasenoteseen
+
+import (
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+var log = logger.Get()
+
+type repository struct {
+	db *database.DB
+}
+
+func NewRepository(db *database.DB) *repository {
+	log.Trace().Msg("NewRepository")
+	return &repository{db: db}
+}
+
+// Save creates the seen records or moves their seen time forward
+func (r *repository) Save(seen []*SeenReleaseNote) error {
+	log.Trace().Int("count", len(seen)).Msg("Save")
+	if err := r.db.Client.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "release_note_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"seen_at"}),
+	}).Create(&seen).Error; err != nil {
+		log.Error().Err(err).Msg("Error saving seen release notes")
+		return err
+	}
+	return nil
+}
+
+// FindReleaseNoteIds returns the IDs among releaseNoteIds that belong to the organisation
+func (r *repository) FindReleaseNoteIds(orgId uuid.UUID, releaseNoteIds []uuid.UUID) ([]uuid.UUID, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("FindReleaseNoteIds")
+	var ids []uuid.UUID
+	if err := r.db.Client.Table("release_notes").
+		Where("organisation_id = ? AND id IN ? AND deleted_at IS NULL", orgId, releaseNoteIds).
+		Pluck("id", &ids).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note IDs")
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindUnreadIds returns the IDs among releaseNoteIds the client has not seen since their last update
+func (r *repository) FindUnreadIds(clientId string, releaseNoteIds []uuid.UUID) ([]uuid.UUID, error) {
+	log.Trace().Str("clientId", clientId).Msg("FindUnreadIds")
+	var ids []uuid.UUID
+	if err := r.db.Client.Table("release_notes").
+		Joins("LEFT JOIN seen_release_notes ON seen_release_notes.release_note_id = release_notes.id AND seen_release_notes.client_id = ?", clientId).
+		Where("release_notes.id IN ?", releaseNoteIds).
+		Where("seen_release_notes.seen_at IS NULL OR seen_release_notes.seen_at < release_notes.updated_at").
+		Pluck("release_notes.id", &ids).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding unread release notes")
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,61 @@
+package releasenoteseen
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type service struct {
+	repo *repository
+}
+
+func NewService(r *repository) *service {
+	log.Trace().Msg("NewService")
+	return &service{repo: r}
+}
+
+// MarkSeen records that the client saw the given release notes of the organisation now.
+// IDs of notes of other organisations are ignored.
+func (s *service) MarkSeen(orgId uuid.UUID, clientId string, releaseNoteIds []uuid.UUID) error {
+	log.Trace().Str("orgId", orgId.String()).Str("clientId", clientId).Msg("MarkSeen")
+	if len(releaseNoteIds) == 0 {
+		return nil
+	}
+	ids, err := s.repo.FindReleaseNoteIds(orgId, releaseNoteIds)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	now := time.Now()
+	seen := make([]*SeenReleaseNote, len(ids))
+	for i, id := range ids {
+		seen[i] = &SeenReleaseNote{
+			ReleaseNoteID:  id,
+			ClientID:       clientId,
+			OrganisationID: orgId,
+			SeenAt:         now,
+		}
+	}
+	return s.repo.Save(seen)
+}
+
+// GetUnread returns which of the given release notes the client has not seen since
+// their last update
+func (s *service) GetUnread(clientId string, releaseNoteIds []uuid.UUID) (map[uuid.UUID]bool, error) {
+	log.Trace().Str("clientId", clientId).Msg("GetUnread")
+	unread := make(map[uuid.UUID]bool)
+	if len(releaseNoteIds) == 0 {
+		return unread, nil
+	}
+	ids, err := s.repo.FindUnreadIds(clientId, releaseNoteIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		unread[id] = true
+	}
+	return unread, nil
+}
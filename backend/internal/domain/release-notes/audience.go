@@ -0,0 +1,313 @@
+package releasenotes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidAudienceRule is returned for audience rules with an unknown operator, a missing
+	// attribute or value, or a non-numeric value for a numeric comparison
+	ErrInvalidAudienceRule = errors.New("invalid audience rule")
+	// ErrInvalidAudienceAttributes is returned for end-user attributes that are not a flat JSON object
+	ErrInvalidAudienceAttributes = errors.New("invalid audience attributes")
+)
+
+// FilterAudience is a FindAll and GetStatus filter key matching release notes whose audience
+// rules match the given end-user attributes (AudienceAttributes). It is resolved by the service.
+const FilterAudience = "audience"
+
+// FilterUntargeted is a FindAll and GetStatus filter key (bool) leaving out release notes with
+// audience rules. Public surfaces like the release page and feeds have no end user to match
+// the rules against, so they only show notes meant for everyone.
+const FilterUntargeted = "untargeted"
+
+// untargetedCondition matches release notes without audience rules
+const untargetedCondition = "NOT EXISTS (SELECT 1 FROM audience_rules WHERE audience_rules.release_note_id = release_notes.id)"
+
+// filterExcludedIds is the repository filter key FilterAudience is resolved into ([]uuid.UUID)
+const filterExcludedIds = "excluded_ids"
+
+type AudienceOperator string
+
+func (op AudienceOperator) String() string {
+	return string(op)
+}
+
+const (
+	AudienceOperatorIn        AudienceOperator = "in"
+	AudienceOperatorNotIn     AudienceOperator = "not_in"
+	AudienceOperatorGreater   AudienceOperator = "gt"
+	AudienceOperatorLess      AudienceOperator = "lt"
+	AudienceOperatorExists    AudienceOperator = "exists"
+	AudienceOperatorNotExists AudienceOperator = "not_exists"
+)
+
+// AudienceOperators lists the operators in the order they are offered in the editor
+var AudienceOperators = []AudienceOperator{
+	AudienceOperatorIn,
+	AudienceOperatorNotIn,
+	AudienceOperatorGreater,
+	AudienceOperatorLess,
+	AudienceOperatorExists,
+	AudienceOperatorNotExists,
+}
+
+// Label returns a human readable name of the operator
+func (op AudienceOperator) Label() string {
+	switch op {
+	case AudienceOperatorIn:
+		return "is one of"
+	case AudienceOperatorNotIn:
+		return "is none of"
+	case AudienceOperatorGreater:
+		return "is greater than"
+	case AudienceOperatorLess:
+		return "is less than"
+	case AudienceOperatorExists:
+		return "is set"
+	case AudienceOperatorNotExists:
+		return "is not set"
+	}
+	return op.String()
+}
+
+// IsValid reports whether op is a known operator
+func (op AudienceOperator) IsValid() bool {
+	for _, o := range AudienceOperators {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// takesValue reports whether rules with this operator need a value
+func (op AudienceOperator) takesValue() bool {
+	return op != AudienceOperatorExists && op != AudienceOperatorNotExists
+}
+
+// isNumeric reports whether rules with this operator compare numbers
+func (op AudienceOperator) isNumeric() bool {
+	return op == AudienceOperatorGreater || op == AudienceOperatorLess
+}
+
+// AudienceRule restricts a release note to end users whose attribute matches the rule.
+// A note is shown only to end users matching all of its rules.
+type AudienceRule struct {
+	ReleaseNoteID uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	Position      int              `gorm:"primaryKey"`
+	Attribute     string           `gorm:"type:varchar(64)"`
+	Operator      AudienceOperator `gorm:"type:varchar(16)"`
+	Value         string           `gorm:"type:text"` // comma separated list for in and not_in
+}
+
+// IsEmpty reports whether neither an attribute nor a value is filled in
+func (rule *AudienceRule) IsEmpty() bool {
+	return rule.Attribute == "" && rule.Value == ""
+}
+
+// Values returns the trimmed, non-empty values of the rule
+func (rule *AudienceRule) Values() []string {
+	var values []string
+	for _, v := range strings.Split(rule.Value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Matches reports whether end users with the given attributes are in the audience of the rule.
+// A missing attribute only matches not_exists, so that notes restricted to e.g. a plan are
+// never shown to end users whose plan is unknown.
+func (rule *AudienceRule) Matches(attrs AudienceAttributes) bool {
+	values, ok := attrs[rule.Attribute]
+	if rule.Operator == AudienceOperatorNotExists {
+		return !ok
+	}
+	if !ok {
+		return false
+	}
+	switch rule.Operator {
+	case AudienceOperatorExists:
+		return true
+	case AudienceOperatorIn:
+		return containsAny(values, rule.Values())
+	case AudienceOperatorNotIn:
+		return !containsAny(values, rule.Values())
+	case AudienceOperatorGreater, AudienceOperatorLess:
+		limit, err := strconv.ParseFloat(strings.TrimSpace(rule.Value), 64)
+		if err != nil || len(values) != 1 {
+			return false
+		}
+		n, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return false
+		}
+		if rule.Operator == AudienceOperatorGreater {
+			return n > limit
+		}
+		return n < limit
+	}
+	return false
+}
+
+// validate checks that a trimmed rule is complete
+func (rule *AudienceRule) validate() error {
+	if rule.Attribute == "" || len(rule.Attribute) > 64 || !rule.Operator.IsValid() {
+		return ErrInvalidAudienceRule
+	}
+	if !rule.Operator.takesValue() {
+		rule.Value = ""
+		return nil
+	}
+	if len(rule.Values()) == 0 {
+		return ErrInvalidAudienceRule
+	}
+	if rule.Operator.isNumeric() {
+		if _, err := strconv.ParseFloat(rule.Value, 64); err != nil {
+			return ErrInvalidAudienceRule
+		}
+	}
+	return nil
+}
+
+// MatchesAudience reports whether end users with the given attributes match all rules.
+// Notes without rules are shown to everyone.
+func MatchesAudience(rules []*AudienceRule, attrs AudienceAttributes) bool {
+	for _, rule := range rules {
+		if !rule.Matches(attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+// AudienceAttributes are the attributes of an end user, e.g. plan, role or feature flags.
+// Every attribute holds a list of values so that list attributes like feature flags can be
+// matched by in and not_in.
+type AudienceAttributes map[string][]string
+
+// ParseAudienceAttributes decodes end-user attributes from a flat JSON object whose values
+// are strings, numbers, booleans or lists of those. Null values are ignored, an empty input
+// yields no attributes.
+func ParseAudienceAttributes(raw string) (AudienceAttributes, error) {
+	attrs := AudienceAttributes{}
+	if strings.TrimSpace(raw) == "" {
+		return attrs, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, ErrInvalidAudienceAttributes
+	}
+	for key, value := range obj {
+		if value == nil {
+			continue
+		}
+		list, isList := value.([]interface{})
+		if !isList {
+			list = []interface{}{value}
+		}
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := attributeString(item)
+			if !ok {
+				return nil, ErrInvalidAudienceAttributes
+			}
+			values = append(values, s)
+		}
+		attrs[key] = values
+	}
+	return attrs, nil
+}
+
+// attributeString formats a scalar JSON value, reporting false for objects, lists and null
+func attributeString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// containsAny reports whether any of the values equals one of the wanted values, ignoring case
+func containsAny(values, wanted []string) bool {
+	for _, v := range values {
+		for _, w := range wanted {
+			if strings.EqualFold(v, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateAudienceRules trims the audience rules of a note, drops empty ones, checks the rest
+// and numbers them in order. Nil rules stay nil.
+func validateAudienceRules(rn *ReleaseNote) error {
+	if rn.AudienceRules == nil {
+		return nil
+	}
+	filled := []*AudienceRule{}
+	for _, rule := range rn.AudienceRules {
+		rule.Attribute = strings.TrimSpace(rule.Attribute)
+		rule.Value = strings.TrimSpace(rule.Value)
+		if rule.IsEmpty() {
+			continue
+		}
+		if err := rule.validate(); err != nil {
+			return err
+		}
+		rule.Position = len(filled)
+		filled = append(filled, rule)
+	}
+	rn.AudienceRules = filled
+	return nil
+}
+
+// resolveAudience returns a copy of filters in which a FilterAudience entry is replaced with the
+// IDs of the organisation's release notes whose audience rules don't match the attributes
+func (s *service) resolveAudience(orgId string, filters map[string]interface{}) (map[string]interface{}, error) {
+	value, ok := filters[FilterAudience]
+	if !ok {
+		return filters, nil
+	}
+	attrs, _ := value.(AudienceAttributes)
+	rules, err := s.repo.FindAudienceRules(orgId)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding audience rules")
+		return nil, err
+	}
+	byNote := make(map[uuid.UUID][]*AudienceRule)
+	for _, rule := range rules {
+		byNote[rule.ReleaseNoteID] = append(byNote[rule.ReleaseNoteID], rule)
+	}
+	var excluded []uuid.UUID
+	for id, noteRules := range byNote {
+		if !MatchesAudience(noteRules, attrs) {
+			excluded = append(excluded, id)
+		}
+	}
+	resolved := make(map[string]interface{}, len(filters))
+	for key, value := range filters {
+		if key != FilterAudience {
+			resolved[key] = value
+		}
+	}
+	if len(excluded) > 0 {
+		resolved[filterExcludedIds] = excluded
+	}
+	return resolved, nil
+}
@@ -0,0 +1,161 @@
+package releasenotes
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAudienceRuleMatches(t *testing.T) {
+	attrs := AudienceAttributes{
+		"plan":             {"Pro"},
+		"role":             {"admin"},
+		"account_age_days": {"42"},
+		"flags":            {"beta", "dark_mode"},
+	}
+
+	tests := []struct {
+		name     string
+		rule     AudienceRule
+		expected bool
+	}{
+		{name: "in matches", rule: AudienceRule{Attribute: "plan", Operator: AudienceOperatorIn, Value: "pro, enterprise"}, expected: true},
+		{name: "in ignores case", rule: AudienceRule{Attribute: "plan", Operator: AudienceOperatorIn, Value: "PRO"}, expected: true},
+		{name: "in does not match", rule: AudienceRule{Attribute: "plan", Operator: AudienceOperatorIn, Value: "free"}, expected: false},
+		{name: "in matches any list value", rule: AudienceRule{Attribute: "flags", Operator: AudienceOperatorIn, Value: "dark_mode"}, expected: true},
+		{name: "in with missing attribute", rule: AudienceRule{Attribute: "country", Operator: AudienceOperatorIn, Value: "de"}, expected: false},
+		{name: "not in matches", rule: AudienceRule{Attribute: "plan", Operator: AudienceOperatorNotIn, Value: "free,trial"}, expected: true},
+		{name: "not in does not match", rule: AudienceRule{Attribute: "flags", Operator: AudienceOperatorNotIn, Value: "beta"}, expected: false},
+		{name: "not in with missing attribute", rule: AudienceRule{Attribute: "country", Operator: AudienceOperatorNotIn, Value: "de"}, expected: false},
+		{name: "greater than", rule: AudienceRule{Attribute: "account_age_days", Operator: AudienceOperatorGreater, Value: "30"}, expected: true},
+		{name: "not greater than", rule: AudienceRule{Attribute: "account_age_days", Operator: AudienceOperatorGreater, Value: "42"}, expected: false},
+		{name: "less than", rule: AudienceRule{Attribute: "account_age_days", Operator: AudienceOperatorLess, Value: "90.5"}, expected: true},
+		{name: "less than with non-numeric attribute", rule: AudienceRule{Attribute: "plan", Operator: AudienceOperatorLess, Value: "90"}, expected: false},
+		{name: "greater than with list attribute", rule: AudienceRule{Attribute: "flags", Operator: AudienceOperatorGreater, Value: "0"}, expected: false},
+		{name: "exists", rule: AudienceRule{Attribute: "role", Operator: AudienceOperatorExists}, expected: true},
+		{name: "exists with missing attribute", rule: AudienceRule{Attribute: "country", Operator: AudienceOperatorExists}, expected: false},
+		{name: "not exists", rule: AudienceRule{Attribute: "country", Operator: AudienceOperatorNotExists}, expected: true},
+		{name: "not exists with attribute", rule: AudienceRule{Attribute: "role", Operator: AudienceOperatorNotExists}, expected: false},
+		{name: "unknown operator", rule: AudienceRule{Attribute: "plan", Operator: "contains", Value: "pro"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(attrs); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchesAudience(t *testing.T) {
+	rules := []*AudienceRule{
+		{Attribute: "plan", Operator: AudienceOperatorIn, Value: "pro,enterprise"},
+		{Attribute: "role", Operator: AudienceOperatorIn, Value: "admin"},
+	}
+
+	tests := []struct {
+		name     string
+		rules    []*AudienceRule
+		attrs    AudienceAttributes
+		expected bool
+	}{
+		{name: "no rules", rules: nil, attrs: AudienceAttributes{}, expected: true},
+		{name: "all rules match", rules: rules, attrs: AudienceAttributes{"plan": {"enterprise"}, "role": {"admin"}}, expected: true},
+		{name: "one rule fails", rules: rules, attrs: AudienceAttributes{"plan": {"enterprise"}, "role": {"member"}}, expected: false},
+		{name: "no attributes", rules: rules, attrs: AudienceAttributes{}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesAudience(tt.rules, tt.attrs); got != tt.expected {
+				t.Errorf("MatchesAudience() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseAudienceAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected AudienceAttributes
+		wantErr  bool
+	}{
+		{name: "empty", input: "", expected: AudienceAttributes{}},
+		{
+			name:     "scalars and lists",
+			input:    `{"plan":"pro","account_age_days":42,"trial":false,"flags":["beta",2],"country":null}`,
+			expected: AudienceAttributes{"plan": {"pro"}, "account_age_days": {"42"}, "trial": {"false"}, "flags": {"beta", "2"}},
+		},
+		{name: "large number keeps precision", input: `{"seats":12345678901234567890}`, expected: AudienceAttributes{"seats": {"12345678901234567890"}}},
+		{name: "nested object", input: `{"company":{"plan":"pro"}}`, wantErr: true},
+		{name: "nested list", input: `{"flags":[["beta"]]}`, wantErr: true},
+		{name: "not an object", input: `["pro"]`, wantErr: true},
+		{name: "malformed", input: `{"plan":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAudienceAttributes(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAudienceAttributes) {
+					t.Fatalf("ParseAudienceAttributes(%q) error = %v, want ErrInvalidAudienceAttributes", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAudienceAttributes(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseAudienceAttributes(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateAudienceRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []*AudienceRule
+		expected []*AudienceRule
+		wantErr  bool
+	}{
+		{name: "nil stays nil", rules: nil, expected: nil},
+		{
+			name: "trims, drops empty rules and numbers the rest",
+			rules: []*AudienceRule{
+				{Attribute: " ", Operator: AudienceOperatorIn, Value: ""},
+				{Attribute: " plan ", Operator: AudienceOperatorIn, Value: " pro, enterprise "},
+				{Attribute: "beta_tester", Operator: AudienceOperatorExists, Value: "ignored"},
+			},
+			expected: []*AudienceRule{
+				{Attribute: "plan", Operator: AudienceOperatorIn, Value: "pro, enterprise", Position: 0},
+				{Attribute: "beta_tester", Operator: AudienceOperatorExists, Value: "", Position: 1},
+			},
+		},
+		{name: "missing attribute", rules: []*AudienceRule{{Operator: AudienceOperatorIn, Value: "pro"}}, wantErr: true},
+		{name: "missing value", rules: []*AudienceRule{{Attribute: "plan", Operator: AudienceOperatorIn, Value: " , "}}, wantErr: true},
+		{name: "unknown operator", rules: []*AudienceRule{{Attribute: "plan", Operator: "contains", Value: "pro"}}, wantErr: true},
+		{name: "non-numeric comparison", rules: []*AudienceRule{{Attribute: "account_age_days", Operator: AudienceOperatorGreater, Value: "a month"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rn := &ReleaseNote{AudienceRules: tt.rules}
+			err := validateAudienceRules(rn)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAudienceRule) {
+					t.Fatalf("validateAudienceRules() error = %v, want ErrInvalidAudienceRule", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateAudienceRules() error = %v", err)
+			}
+			if !reflect.DeepEqual(rn.AudienceRules, tt.expected) {
+				t.Errorf("validateAudienceRules() rules = %v, want %v", rn.AudienceRules, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package releasenotes
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/devbydaniel/announcable/internal/widgetcache"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTooManySubscribers is returned when an organisation has reached its limit of event subscribers
+	ErrTooManySubscribers = errors.New("too many event subscribers")
+	// ErrBrokerClosed is returned when subscribing after the broker was closed on shutdown
+	ErrBrokerClosed = errors.New("event broker closed")
+)
+
+// maxEventSubscribersPerOrg limits the open event streams of an organisation
+const maxEventSubscribersPerOrg = 500
+
+// eventBufferSize is the number of events buffered per subscriber before events are dropped
+const eventBufferSize = 16
+
+// EventType tells what happened to a release note visible to end users
+type EventType string
+
+func (t EventType) String() string {
+	return string(t)
+}
+
+const (
+	EventPublished   EventType = "published"
+	EventUpdated     EventType = "updated"
+	EventUnpublished EventType = "unpublished"
+	EventDeleted     EventType = "deleted"
+)
+
+// Event notifies subscribers of an organisation that one of its release notes changed
+type Event struct {
+	Type           EventType
+	OrganisationID uuid.UUID
+	ReleaseNoteID  uuid.UUID
+}
+
+// Events is the in-process broker the service publishes release note changes to
+var Events = NewBroker(maxEventSubscribersPerOrg)
+
+// Broker fans out release note events to the subscribers of an organisation.
+// Publishing never blocks: subscribers that don't keep up miss events.
+type Broker struct {
+	mu          sync.Mutex
+	maxPerOrg   int
+	subscribers map[uuid.UUID]map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBroker creates a broker allowing at most maxPerOrg subscribers per organisation
+func NewBroker(maxPerOrg int) *Broker {
+	log.Trace().Msg("NewBroker")
+	return &Broker{
+		maxPerOrg:   maxPerOrg,
+		subscribers: make(map[uuid.UUID]map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives the events of one organisation on C until it is closed.
+// C is closed when the subscription or the broker is closed.
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	orgId  uuid.UUID
+	broker *Broker
+	once   sync.Once
+}
+
+// Subscribe registers a subscriber for the events of the organisation
+func (b *Broker) Subscribe(orgId uuid.UUID) (*Subscription, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("Subscribe")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+	if len(b.subscribers[orgId]) >= b.maxPerOrg {
+		log.Warn().Str("orgId", orgId.String()).Msg("Event subscriber limit reached")
+		return nil, ErrTooManySubscribers
+	}
+	ch := make(chan Event, eventBufferSize)
+	sub := &Subscription{C: ch, ch: ch, orgId: orgId, broker: b}
+	if b.subscribers[orgId] == nil {
+		b.subscribers[orgId] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[orgId][sub] = struct{}{}
+	return sub, nil
+}
+
+// Close unregisters the subscription and closes C. It is safe to call more than once.
+func (sub *Subscription) Close() {
+	sub.broker.mu.Lock()
+	defer sub.broker.mu.Unlock()
+	sub.close()
+}
+
+// close requires the broker's lock to be held
+func (sub *Subscription) close() {
+	sub.once.Do(func() {
+		subs := sub.broker.subscribers[sub.orgId]
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(sub.broker.subscribers, sub.orgId)
+		}
+		close(sub.ch)
+	})
+}
+
+// Publish sends the event to all subscribers of its organisation
+func (b *Broker) Publish(e Event) {
+	log.Trace().Str("type", e.Type.String()).Msg("Publish")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers[e.OrganisationID] {
+		select {
+		case sub.ch <- e:
+		default:
+			log.Warn().Str("orgId", e.OrganisationID.String()).Msg("Dropping release note event for slow subscriber")
+		}
+	}
+}
+
+// Close closes all subscriptions and rejects new ones, so that open event streams end on shutdown
+func (b *Broker) Close() {
+	log.Trace().Msg("Close")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			sub.close()
+		}
+	}
+}
+
+// SubscriberCount returns the number of open subscriptions of the organisation
+func (b *Broker) SubscriberCount(orgId uuid.UUID) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers[orgId])
+}
+
+// eventOf returns the event end users are notified of after a revision was saved.
+// Changes to notes that are not and were not visible are not announced.
+func eventOf(rn *ReleaseNote, action RevisionAction) (Event, bool) {
+	e := Event{OrganisationID: rn.OrganisationID, ReleaseNoteID: rn.ID}
+	switch {
+	case action == RevisionActionUnpublished:
+		e.Type = EventUnpublished
+	case !rn.IsPublished:
+		return e, false
+	case action == RevisionActionPublished || action == RevisionActionCreated:
+		e.Type = EventPublished
+	default:
+		e.Type = EventUpdated
+	}
+	return e, true
+}
+
+// announce notifies end users of a committed revision, if it changed what they see
+func announce(rn *ReleaseNote, action RevisionAction) {
+	if e, ok := eventOf(rn, action); ok {
+		notify(e)
+	}
+}
+
+// notify drops the cached widget data of the organisation and publishes the event.
+// The cache is invalidated first, so that widgets refetching on the event get fresh data.
+func notify(e Event) {
+	widgetcache.Invalidate(e.OrganisationID)
+	Events.Publish(e)
+}
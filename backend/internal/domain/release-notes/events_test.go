@@ -0,0 +1,99 @@
+package releasenotes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBroker(t *testing.T) {
+	orgId, otherOrgId := uuid.New(), uuid.New()
+	b := NewBroker(2)
+
+	first, err := b.Subscribe(orgId)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	second, err := b.Subscribe(orgId)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if _, err := b.Subscribe(orgId); !errors.Is(err, ErrTooManySubscribers) {
+		t.Fatalf("Subscribe() over the limit error = %v, want ErrTooManySubscribers", err)
+	}
+	other, err := b.Subscribe(otherOrgId)
+	if err != nil {
+		t.Fatalf("Subscribe() for another organisation error = %v", err)
+	}
+
+	e := Event{Type: EventPublished, OrganisationID: orgId, ReleaseNoteID: uuid.New()}
+	b.Publish(e)
+	for _, sub := range []*Subscription{first, second} {
+		if got := <-sub.C; got != e {
+			t.Errorf("received %+v, want %+v", got, e)
+		}
+	}
+	if len(other.C) != 0 {
+		t.Errorf("subscriber of another organisation received an event")
+	}
+
+	// a closed subscription frees its slot
+	first.Close()
+	first.Close()
+	if _, ok := <-first.C; ok {
+		t.Errorf("channel of closed subscription is open")
+	}
+	if got := b.SubscriberCount(orgId); got != 1 {
+		t.Errorf("SubscriberCount() = %d, want 1", got)
+	}
+
+	// slow subscribers miss events instead of blocking the publisher
+	for i := 0; i < eventBufferSize+1; i++ {
+		b.Publish(e)
+	}
+	if got := len(second.C); got != eventBufferSize {
+		t.Errorf("buffered events = %d, want %d", got, eventBufferSize)
+	}
+
+	b.Close()
+	for range second.C {
+	}
+	if _, ok := <-other.C; ok {
+		t.Errorf("channel is open after the broker was closed")
+	}
+	if _, err := b.Subscribe(orgId); !errors.Is(err, ErrBrokerClosed) {
+		t.Errorf("Subscribe() after Close() error = %v, want ErrBrokerClosed", err)
+	}
+	second.Close()
+}
+
+func TestEventOf(t *testing.T) {
+	published := &ReleaseNote{IsPublished: true}
+	draft := &ReleaseNote{IsPublished: false}
+
+	tests := []struct {
+		name     string
+		rn       *ReleaseNote
+		action   RevisionAction
+		expected EventType
+		ok       bool
+	}{
+		{name: "created published", rn: published, action: RevisionActionCreated, expected: EventPublished, ok: true},
+		{name: "created draft", rn: draft, action: RevisionActionCreated},
+		{name: "published", rn: published, action: RevisionActionPublished, expected: EventPublished, ok: true},
+		{name: "unpublished", rn: draft, action: RevisionActionUnpublished, expected: EventUnpublished, ok: true},
+		{name: "updated published", rn: published, action: RevisionActionUpdated, expected: EventUpdated, ok: true},
+		{name: "updated draft", rn: draft, action: RevisionActionUpdated},
+		{name: "restored published", rn: published, action: RevisionActionRestored, expected: EventUpdated, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := eventOf(tt.rn, tt.action)
+			if ok != tt.ok || (ok && e.Type != tt.expected) {
+				t.Errorf("eventOf() = %q, %v, want %q, %v", e.Type, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package releasenotes
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/devbydaniel/announcable/internal/imgUtil"
+	"github.com/devbydaniel/announcable/internal/util"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTooManyMedia is returned for notes with more than maxMedia attachments
+	ErrTooManyMedia = errors.New("a release note can have at most 10 media attachments")
+	// ErrInvalidMedia is returned for attachments of an unknown kind, without content or that belong to another note
+	ErrInvalidMedia = errors.New("invalid media attachment")
+	// ErrInvalidMediaLink is returned for video links that can't be embedded
+	ErrInvalidMediaLink = errors.New("video links must point to YouTube or Loom")
+	// ErrAltTextTooLong is returned for alt texts longer than maxAltTextLength characters
+	ErrAltTextTooLong = errors.New("alt texts can be at most 512 characters long")
+)
+
+const (
+	maxMedia          = 10
+	maxAltTextLength  = 512
+	placeholderPrefix = "data:image/webp;base64,"
+)
+
+type MediaKind string
+
+func (mk MediaKind) String() string {
+	return string(mk)
+}
+
+const (
+	// MediaKindImage is an uploaded image or GIF stored in the release notes bucket
+	MediaKindImage MediaKind = "image"
+	// MediaKindEmbed is a YouTube or Loom video
+	MediaKindEmbed MediaKind = "embed"
+)
+
+// ReleaseNoteMedia is an image or video embed attached to a release note, shown in the order of Position
+type ReleaseNoteMedia struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ReleaseNoteID uuid.UUID `gorm:"type:uuid;not null"`
+	Position      int       `gorm:"not null"`
+	Kind          MediaKind `gorm:"type:varchar(16)"`
+	Path          string    `gorm:"type:varchar(255)"`  // object path of images
+	Link          string    `gorm:"type:varchar(1024)"` // share link of embeds as entered
+	AltText       string    `gorm:"type:varchar(512)"`
+	// Width and Height of the image at Path, 0 for images uploaded before they were recorded
+	Width  int
+	Height int
+	// Variants are the widths of the resized copies stored next to the image, see variantPath
+	Variants []int `gorm:"serializer:json;type:text"`
+	// Placeholder is a tiny version of the image as data URI, shown while it loads
+	Placeholder string `gorm:"type:text"`
+	// Url is the signed image URL or the embed URL, set by the service
+	Url string `gorm:"-"`
+	// SrcSet lists the signed URLs of the image and its variants for the srcset attribute, set by the service
+	SrcSet string `gorm:"-"`
+}
+
+func (ReleaseNoteMedia) TableName() string {
+	return "release_note_media"
+}
+
+// IsImage reports whether the attachment is an uploaded image
+func (m *ReleaseNoteMedia) IsImage() bool {
+	return m.Kind == MediaKindImage
+}
+
+// PlaceholderStyle is the inline style that shows the placeholder behind the image until it has loaded
+func (m *ReleaseNoteMedia) PlaceholderStyle() template.CSS {
+	// placeholders are generated by imgUtil, anything else is not passed on unescaped
+	data, ok := strings.CutPrefix(m.Placeholder, placeholderPrefix)
+	if !ok || strings.ContainsAny(data, "()'\" \\;") {
+		return ""
+	}
+	return template.CSS("background-image: url(" + m.Placeholder + ")")
+}
+
+// ReleaseNoteRevisionMedia is an attachment of a release note as it was when a revision was taken
+type ReleaseNoteRevisionMedia struct {
+	RevisionID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Position    int       `gorm:"primaryKey"`
+	Kind        MediaKind `gorm:"type:varchar(16)"`
+	Path        string    `gorm:"type:varchar(255)"`
+	Link        string    `gorm:"type:varchar(1024)"`
+	AltText     string    `gorm:"type:varchar(512)"`
+	Width       int
+	Height      int
+	Variants    []int  `gorm:"serializer:json;type:text"`
+	Placeholder string `gorm:"type:text"`
+}
+
+func (ReleaseNoteRevisionMedia) TableName() string {
+	return "release_note_revision_media"
+}
+
+// MediaInput is an attachment of a release note as submitted by the editor or the API, in display order
+type MediaInput struct {
+	// ID of an existing attachment to keep, uuid.Nil for new attachments
+	ID      uuid.UUID
+	Kind    MediaKind
+	ImgData io.Reader // new images only
+	Link    string    // embeds only
+	AltText string
+}
+
+// validateMedia checks the submitted attachments before anything is uploaded
+func validateMedia(media []*MediaInput) error {
+	if len(media) > maxMedia {
+		return ErrTooManyMedia
+	}
+	for _, m := range media {
+		m.AltText = strings.TrimSpace(m.AltText)
+		m.Link = strings.TrimSpace(m.Link)
+		if utf8.RuneCountInString(m.AltText) > maxAltTextLength {
+			return ErrAltTextTooLong
+		}
+		switch m.Kind {
+		case MediaKindImage:
+			if m.ID == uuid.Nil && m.ImgData == nil {
+				return ErrInvalidMedia
+			}
+		case MediaKindEmbed:
+			if m.ID == uuid.Nil && m.Link == "" {
+				return ErrInvalidMedia
+			}
+			if m.Link != "" && !isEmbeddable(m.Link) {
+				return ErrInvalidMediaLink
+			}
+		default:
+			return ErrInvalidMedia
+		}
+	}
+	return nil
+}
+
+// isEmbeddable reports whether a video link can be turned into an embed URL
+func isEmbeddable(link string) bool {
+	if !strings.HasPrefix(link, "https://") && !strings.HasPrefix(link, "http://") {
+		return false
+	}
+	return util.TransformMediaLink(link) != ""
+}
+
+// coverOf returns the first image and the first embed link, which are kept in ImagePath and
+// MediaLink for API v1 clients, webhooks and widgets that don't know about multiple attachments yet
+func coverOf(media []*ReleaseNoteMedia) (imagePath, mediaLink string) {
+	for _, m := range media {
+		if m.Kind == MediaKindImage && imagePath == "" {
+			imagePath = m.Path
+		}
+		if m.Kind == MediaKindEmbed && mediaLink == "" {
+			mediaLink = m.Link
+		}
+	}
+	return imagePath, mediaLink
+}
+
+// imagePaths returns the object paths of the image attachments
+func imagePaths(media []*ReleaseNoteMedia) []string {
+	var paths []string
+	for _, m := range media {
+		if m.Kind == MediaKindImage && m.Path != "" {
+			paths = append(paths, m.Path)
+		}
+	}
+	return paths
+}
+
+// saveMedia replaces the attachments of a note with the submitted ones. New images are processed and
+// uploaded; their paths are returned so that they can be removed again if the transaction fails.
+// The paths of images that are no longer attached are returned as removed.
+func (s *service) saveMedia(id, orgId uuid.UUID, media []*MediaInput, tx *gorm.DB) (uploaded, removed []string, err error) {
+	log.Trace().Str("id", id.String()).Int("count", len(media)).Msg("saveMedia")
+	current, err := s.repo.FindMedia(id, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byId := make(map[uuid.UUID]*ReleaseNoteMedia, len(current))
+	for _, m := range current {
+		byId[m.ID] = m
+	}
+
+	rows := make([]*ReleaseNoteMedia, 0, len(media))
+	kept := map[uuid.UUID]bool{}
+	for i, input := range media {
+		row := &ReleaseNoteMedia{ReleaseNoteID: id, Position: i, Kind: input.Kind, AltText: input.AltText}
+		switch {
+		case input.ID != uuid.Nil:
+			existing, ok := byId[input.ID]
+			if !ok || existing.Kind != input.Kind || kept[input.ID] {
+				log.Warn().Str("mediaId", input.ID.String()).Msg("Media does not belong to release note")
+				return uploaded, nil, ErrInvalidMedia
+			}
+			kept[input.ID] = true
+			row.ID = existing.ID
+			row.Path = existing.Path
+			row.Link = existing.Link
+			row.Width = existing.Width
+			row.Height = existing.Height
+			row.Variants = existing.Variants
+			row.Placeholder = existing.Placeholder
+			if input.Kind == MediaKindEmbed && input.Link != "" {
+				row.Link = input.Link
+			}
+		case input.Kind == MediaKindImage:
+			processed, err := imgUtil.Process(input.ImgData, &imgProcessConfig)
+			if err != nil {
+				log.Error().Err(err).Msg("Error processing image")
+				return uploaded, nil, err
+			}
+			randomId, err := uuid.NewRandom()
+			if err != nil {
+				log.Error().Err(err).Msg("Error generating random UUID")
+				return uploaded, nil, err
+			}
+			row.Path = createImgPath(orgId.String(), randomId.String(), processed.Format.String())
+			row.Width = int(processed.Width)
+			row.Height = int(processed.Height)
+			row.Placeholder = processed.Placeholder
+			log.Debug().Str("path", row.Path).Int("variants", len(processed.Variants)).Msg("Uploading image")
+			if err := s.repo.UploadImage(row.Path, processed.Data); err != nil {
+				return uploaded, nil, err
+			}
+			uploaded = append(uploaded, row.Path)
+			for _, v := range processed.Variants {
+				vPath := variantPath(row.Path, int(v.Width))
+				if err := s.repo.UploadImage(vPath, v.Data); err != nil {
+					return uploaded, nil, err
+				}
+				uploaded = append(uploaded, vPath)
+				row.Variants = append(row.Variants, int(v.Width))
+			}
+		default:
+			row.Link = input.Link
+		}
+		rows = append(rows, row)
+	}
+
+	if err := s.repo.ReplaceMedia(id, rows, tx); err != nil {
+		return uploaded, nil, err
+	}
+	imagePath, mediaLink := coverOf(rows)
+	if err := s.repo.UpdateWithNil(id, map[string]interface{}{"ImagePath": imagePath, "MediaLink": mediaLink}, tx); err != nil {
+		return uploaded, nil, err
+	}
+	for _, m := range current {
+		if m.Kind == MediaKindImage && !kept[m.ID] {
+			removed = append(removed, m.Path)
+		}
+	}
+	return uploaded, removed, nil
+}
+
+// restoreMedia sets the attachments of a note back to those of a revision and returns the paths
+// of images that are no longer attached
+func (s *service) restoreMedia(id uuid.UUID, rev *ReleaseNoteRevision, tx *gorm.DB) ([]string, error) {
+	log.Trace().Str("revisionId", rev.ID.String()).Msg("restoreMedia")
+	current, err := s.repo.FindMedia(id, tx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*ReleaseNoteMedia, len(rev.Media))
+	restored := map[string]bool{}
+	for i, m := range rev.Media {
+		rows[i] = &ReleaseNoteMedia{
+			ReleaseNoteID: id,
+			Position:      i,
+			Kind:          m.Kind,
+			Path:          m.Path,
+			Link:          m.Link,
+			AltText:       m.AltText,
+			Width:         m.Width,
+			Height:        m.Height,
+			Variants:      m.Variants,
+			Placeholder:   m.Placeholder,
+		}
+		restored[m.Path] = true
+	}
+	if err := s.repo.ReplaceMedia(id, rows, tx); err != nil {
+		return nil, err
+	}
+	imagePath, mediaLink := coverOf(rows)
+	if err := s.repo.UpdateWithNil(id, map[string]interface{}{"ImagePath": imagePath, "MediaLink": mediaLink}, tx); err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, path := range imagePaths(current) {
+		if !restored[path] {
+			removed = append(removed, path)
+		}
+	}
+	return removed, nil
+}
+
+// variantPath returns the object path of the variant of an image with the given width
+func variantPath(imagePath string, width int) string {
+	ext := path.Ext(imagePath)
+	return strings.TrimSuffix(imagePath, ext) + "_" + strconv.Itoa(width) + "w" + ext
+}
+
+// deleteUnusedImages removes the given images and their variants from object storage unless a note
+// or the revision history of a note that isn't deleted still references them. Failures are only
+// logged, as the change that made the images unused has already been committed.
+func (s *service) deleteUnusedImages(paths []string) {
+	log.Trace().Int("count", len(paths)).Msg("deleteUnusedImages")
+	for _, path := range paths {
+		count, err := s.repo.CountImageReferences(path)
+		if err != nil {
+			continue
+		}
+		if count > 0 {
+			log.Debug().Str("path", path).Int64("references", count).Msg("Keeping image")
+			continue
+		}
+		if err := s.repo.DeleteImageObject(path); err != nil {
+			log.Error().Err(err).Str("path", path).Msg("Error deleting unused image")
+		}
+		// the widths of the image aren't known anymore once its rows are gone; removing
+		// objects that don't exist is not an error
+		for _, width := range imgProcessConfig.VariantWidths {
+			if err := s.repo.DeleteImageObject(variantPath(path, int(width))); err != nil {
+				log.Error().Err(err).Str("path", path).Msg("Error deleting unused image variant")
+			}
+		}
+	}
+}
+
+// discardUploads removes images uploaded by a change that was rolled back
+func (s *service) discardUploads(paths []string) {
+	for _, path := range paths {
+		if err := s.repo.DeleteImageObject(path); err != nil {
+			log.Error().Err(err).Str("path", path).Msg("Error deleting uploaded image")
+		}
+	}
+}
+
+// GetPublicImageUrl returns a freshly signed URL of an image shown on the release page of the
+// organisation. Feeds link to the images through it, as signed URLs expire.
+func (s *service) GetPublicImageUrl(orgId, mediaId uuid.UUID) (string, error) {
+	log.Trace().Str("mediaId", mediaId.String()).Msg("GetPublicImageUrl")
+	m, err := s.repo.FindPublicImage(orgId, mediaId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrReleaseNoteNotFound
+		}
+		log.Error().Err(err).Msg("Error finding image")
+		return "", err
+	}
+	imgUrl, err := s.repo.GetImageUrl(m.Path)
+	if err != nil {
+		log.Error().Err(err).Msg("Error getting image URL")
+		return "", err
+	}
+	if imgUrl == "" {
+		return "", ErrReleaseNoteNotFound
+	}
+	return imgUrl, nil
+}
+
+// setMediaUrls fills in the image URL of the cover image and the URLs and srcsets of all attachments
+func (s *service) setMediaUrls(rn *ReleaseNote) {
+	if rn.ImagePath != "" {
+		imgUrl, err := s.repo.GetImageUrl(rn.ImagePath)
+		if err != nil {
+			log.Error().Err(err).Msg("Error getting image URL")
+		} else {
+			rn.ImageUrl = imgUrl
+		}
+	}
+	for _, m := range rn.Media {
+		if m.Kind == MediaKindEmbed {
+			m.Url = util.TransformMediaLink(m.Link)
+			continue
+		}
+		imgUrl, err := s.repo.GetImageUrl(m.Path)
+		if err != nil {
+			log.Error().Err(err).Msg("Error getting image URL")
+			continue
+		}
+		m.Url = imgUrl
+		m.SrcSet = s.srcSet(m)
+	}
+}
+
+// srcSet lists the image and its variants with their widths in ascending order, or is empty
+// for images without variants
+func (s *service) srcSet(m *ReleaseNoteMedia) string {
+	if len(m.Variants) == 0 || m.Width == 0 {
+		return ""
+	}
+	candidates := make([]string, 0, len(m.Variants)+1)
+	mainAdded := false
+	for _, width := range m.Variants {
+		if !mainAdded && m.Width < width {
+			candidates = append(candidates, m.Url+" "+strconv.Itoa(m.Width)+"w")
+			mainAdded = true
+		}
+		imgUrl, err := s.repo.GetImageUrl(variantPath(m.Path, width))
+		if err != nil || imgUrl == "" {
+			log.Warn().Err(err).Str("path", m.Path).Int("width", width).Msg("Variant not found")
+			continue
+		}
+		candidates = append(candidates, imgUrl+" "+strconv.Itoa(width)+"w")
+	}
+	if !mainAdded {
+		candidates = append(candidates, m.Url+" "+strconv.Itoa(m.Width)+"w")
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// describeMedia summarises the attachments of a revision for the change history
+func describeMedia(media []*ReleaseNoteRevisionMedia) string {
+	parts := make([]string, len(media))
+	for i, m := range media {
+		desc := m.Link
+		if m.Kind == MediaKindImage {
+			desc = "Image " + path.Base(m.Path)
+		}
+		if m.AltText != "" {
+			desc += ` ("` + m.AltText + `")`
+		}
+		parts[i] = desc
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,123 @@
+package releasenotes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateMedia(t *testing.T) {
+	tests := []struct {
+		name  string
+		media []*MediaInput
+		want  error
+	}{
+		{name: "none", media: nil, want: nil},
+		{
+			name: "new image and embed",
+			media: []*MediaInput{
+				{Kind: MediaKindImage, ImgData: strings.NewReader("img"), AltText: "Screenshot"},
+				{Kind: MediaKindEmbed, Link: " https://www.youtube.com/watch?v=abc "},
+			},
+			want: nil,
+		},
+		{name: "existing image", media: []*MediaInput{{ID: uuid.New(), Kind: MediaKindImage}}, want: nil},
+		{name: "existing embed", media: []*MediaInput{{ID: uuid.New(), Kind: MediaKindEmbed}}, want: nil},
+		{name: "unknown kind", media: []*MediaInput{{Kind: "gif", Link: "https://example.com"}}, want: ErrInvalidMedia},
+		{name: "new image without file", media: []*MediaInput{{Kind: MediaKindImage}}, want: ErrInvalidMedia},
+		{name: "new embed without link", media: []*MediaInput{{Kind: MediaKindEmbed, Link: "  "}}, want: ErrInvalidMedia},
+		{name: "other video host", media: []*MediaInput{{Kind: MediaKindEmbed, Link: "https://vimeo.com/123"}}, want: ErrInvalidMediaLink},
+		{name: "script link", media: []*MediaInput{{Kind: MediaKindEmbed, Link: "javascript:alert('youtube.com?v=1')"}}, want: ErrInvalidMediaLink},
+		{
+			name:  "alt text too long",
+			media: []*MediaInput{{ID: uuid.New(), Kind: MediaKindImage, AltText: strings.Repeat("ä", maxAltTextLength+1)}},
+			want:  ErrAltTextTooLong,
+		},
+		{
+			name: "too many",
+			media: func() []*MediaInput {
+				media := make([]*MediaInput, maxMedia+1)
+				for i := range media {
+					media[i] = &MediaInput{ID: uuid.New(), Kind: MediaKindImage}
+				}
+				return media
+			}(),
+			want: ErrTooManyMedia,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMedia(tt.media); !errors.Is(err, tt.want) {
+				t.Errorf("validateMedia() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMediaTrims(t *testing.T) {
+	input := &MediaInput{Kind: MediaKindEmbed, Link: " https://www.loom.com/share/abc\n", AltText: "  Demo "}
+	if err := validateMedia([]*MediaInput{input}); err != nil {
+		t.Fatalf("validateMedia() = %v", err)
+	}
+	if input.Link != "https://www.loom.com/share/abc" || input.AltText != "Demo" {
+		t.Errorf("got link %q and alt text %q, want them trimmed", input.Link, input.AltText)
+	}
+}
+
+func TestCoverOf(t *testing.T) {
+	media := []*ReleaseNoteMedia{
+		{Kind: MediaKindEmbed, Link: "https://www.loom.com/share/first"},
+		{Kind: MediaKindImage, Path: "org/first.png"},
+		{Kind: MediaKindImage, Path: "org/second.png"},
+		{Kind: MediaKindEmbed, Link: "https://www.loom.com/share/second"},
+	}
+	imagePath, mediaLink := coverOf(media)
+	if imagePath != "org/first.png" || mediaLink != "https://www.loom.com/share/first" {
+		t.Errorf("coverOf() = %q, %q, want the first image and the first embed", imagePath, mediaLink)
+	}
+
+	if imagePath, mediaLink := coverOf(nil); imagePath != "" || mediaLink != "" {
+		t.Errorf("coverOf(nil) = %q, %q, want empty", imagePath, mediaLink)
+	}
+}
+
+func TestDescribeMedia(t *testing.T) {
+	media := []*ReleaseNoteRevisionMedia{
+		{Kind: MediaKindImage, Path: "org/abc.png", AltText: "Dashboard"},
+		{Kind: MediaKindEmbed, Link: "https://www.youtube.com/watch?v=abc"},
+	}
+	want := `Image abc.png ("Dashboard"), https://www.youtube.com/watch?v=abc`
+	if got := describeMedia(media); got != want {
+		t.Errorf("describeMedia() = %q, want %q", got, want)
+	}
+	if got := describeMedia(nil); got != "" {
+		t.Errorf("describeMedia(nil) = %q, want empty", got)
+	}
+}
+
+func TestVariantPath(t *testing.T) {
+	if got := variantPath("org/abc.webp", 320); got != "org/abc_320w.webp" {
+		t.Errorf("variantPath() = %q, want org/abc_320w.webp", got)
+	}
+}
+
+func TestPlaceholderStyle(t *testing.T) {
+	tests := []struct {
+		placeholder string
+		want        string
+	}{
+		{placeholder: "", want: ""},
+		{placeholder: "data:image/webp;base64,UklGRg+/=", want: "background-image: url(data:image/webp;base64,UklGRg+/=)"},
+		{placeholder: "https://example.com/x.webp", want: ""},
+		{placeholder: "data:image/webp;base64,x); background: red", want: ""},
+	}
+	for _, tt := range tests {
+		m := &ReleaseNoteMedia{Kind: MediaKindImage, Placeholder: tt.placeholder}
+		if got := string(m.PlaceholderStyle()); got != tt.want {
+			t.Errorf("PlaceholderStyle(%q) = %q, want %q", tt.placeholder, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package releasenotes
 
 import (
-	"io"
+	"html/template"
+	"time"
 
 	"github.com/devbydaniel/announcable/internal/database"
 	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	"github.com/devbydaniel/announcable/internal/markdown"
+	"github.com/devbydaniel/announcable/internal/util"
 	"github.com/google/uuid"
 )
 
@@ -13,12 +17,13 @@ type ReleaseNote struct {
 	OrganisationID     uuid.UUID `gorm:"type:uuid;primaryKey"`
 	Organisation       organisation.Organisation
 	Title              string             `gorm:"type:varchar(255)"`
-	ImageUrl           string             `gorm:"-"` // only returned in GetReleaseNoteByID
+	Slug               string             `gorm:"type:varchar(255)"`
+	ImageUrl           string             `gorm:"-"` // URL of the cover image, set by the service
 	DescriptionShort   string             `gorm:"type:text"`
 	DescriptionLong    string             `gorm:"type:text"`
 	ReleaseDate        *string            `gorm:"type:date;default:null"`
-	ImagePath          string             `gorm:"type:varchar(255)"`
-	MediaLink          string             `gorm:"type:varchar(1024)"`
+	ImagePath          string             `gorm:"type:varchar(255)"`  // first image of Media
+	MediaLink          string             `gorm:"type:varchar(1024)"` // first embed of Media
 	IsPublished        bool               `gorm:"type:bool;default:false"`
 	CtaLabelOverride   string             `gorm:"type:varchar(255)"`
 	CtaUrlOverride     string             `gorm:"type:varchar(255)"`
@@ -28,8 +33,86 @@ type ReleaseNote struct {
 	LastUpdatedBy      uuid.UUID          `gorm:"type:uuid"`
 	HideOnWidget       bool               `gorm:"type:bool;default:false"`
 	HideOnReleasePage  bool               `gorm:"type:bool;default:false"`
+	PublishAt          *time.Time         `gorm:"type:timestamptz;default:null"`
+	UnpublishAt        *time.Time         `gorm:"type:timestamptz;default:null"`
+	Tags               []*tag.Tag         `gorm:"-"` // loaded from ReleaseNoteTag by the repository
+	// content in other languages than the organisation's default, only loaded by FindOne
+	Translations []*ReleaseNoteTranslation `gorm:"-"`
+	// end users the note is shown to in the widget, only loaded by FindOne
+	AudienceRules []*AudienceRule `gorm:"-"`
+	// images and video embeds in display order, loaded from ReleaseNoteMedia by the repository
+	Media []*ReleaseNoteMedia `gorm:"-"`
+	// review workflow, only enforced for organisations that require reviews
+	ReviewStatus      ReviewStatus `gorm:"type:varchar(32);default:'draft'"`
+	ReviewerID        *uuid.UUID   `gorm:"type:uuid"`
+	ReviewRequestedBy *uuid.UUID   `gorm:"type:uuid"`
+}
+
+// IsScheduledForPublish reports whether an unpublished note is waiting for its publish time
+func (rn *ReleaseNote) IsScheduledForPublish() bool {
+	return !rn.IsPublished && rn.PublishAt != nil
+}
+
+// ScheduleAwaitsApproval reports whether a scheduled note is held back by the review gate.
+// The scheduler only publishes it once the review is approved.
+func (rn *ReleaseNote) ScheduleAwaitsApproval(requireReview bool) bool {
+	return requireReview && rn.IsScheduledForPublish() && rn.ReviewStatus != ReviewStatusApproved
+}
+
+// IsScheduledForUnpublish reports whether a published note is waiting for its unpublish time
+func (rn *ReleaseNote) IsScheduledForUnpublish() bool {
+	return rn.IsPublished && rn.UnpublishAt != nil
+}
+
+// Permalink returns the URL of the note's own page below the given release page URL
+func (rn *ReleaseNote) Permalink(releasePageUrl string) string {
+	if rn.Slug == "" || releasePageUrl == "" {
+		return ""
+	}
+	return util.BuildURL(releasePageUrl, rn.Slug)
+}
+
+// DescriptionHTML renders the website description, or the description if there's none, from
+// Markdown to sanitized HTML
+func (rn *ReleaseNote) DescriptionHTML() template.HTML {
+	description := rn.DescriptionLong
+	if description == "" {
+		description = rn.DescriptionShort
+	}
+	return template.HTML(markdown.ToHTML(description))
 }
 
+// ReleaseNoteTag links a release note to one of its tags
+type ReleaseNoteTag struct {
+	ReleaseNoteID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TagID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+// ReleaseNoteTranslation holds the content of a release note in another language
+type ReleaseNoteTranslation struct {
+	ReleaseNoteID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Locale           string    `gorm:"type:varchar(8);primaryKey"`
+	Title            string    `gorm:"type:varchar(255)"`
+	DescriptionShort string    `gorm:"type:text"`
+	DescriptionLong  string    `gorm:"type:text"`
+}
+
+// IsEmpty reports whether none of the translated fields are filled in
+func (t *ReleaseNoteTranslation) IsEmpty() bool {
+	return t.Title == "" && t.DescriptionShort == "" && t.DescriptionLong == ""
+}
+
+// translate replaces the content of the note with the given translation
+func (rn *ReleaseNote) translate(t *ReleaseNoteTranslation) {
+	rn.Title = t.Title
+	rn.DescriptionShort = t.DescriptionShort
+	// an untranslated website description must not show up in the original language
+	rn.DescriptionLong = t.DescriptionLong
+}
+
+// FilterTags is a FindAll filter key matching release notes that have any of the given tag names ([]string, case-insensitive)
+const FilterTags = "tags"
+
 type PaginatedReleaseNotes struct {
 	Items      []*ReleaseNote
 	TotalCount int64
@@ -39,6 +122,7 @@ type PaginatedReleaseNotes struct {
 }
 
 type ReleaseNoteStatus struct {
+	ID                 uuid.UUID
 	UpdatedAt          string
 	AttentionMechanism string
 }
@@ -54,8 +138,96 @@ const (
 	AttentionMechanismInstantOpen AttentionMechanism = "instant_open"
 )
 
-type ImageInput struct {
-	ShouldDeleteImage bool
-	ImgData           io.Reader
-	Format            string
+type RevisionAction string
+
+func (ra RevisionAction) String() string {
+	return string(ra)
+}
+
+const (
+	RevisionActionCreated     RevisionAction = "created"
+	RevisionActionUpdated     RevisionAction = "updated"
+	RevisionActionPublished   RevisionAction = "published"
+	RevisionActionUnpublished RevisionAction = "unpublished"
+	RevisionActionRestored    RevisionAction = "restored"
+)
+
+// ReleaseNoteRevision is an immutable snapshot of a release note, taken after every change
+type ReleaseNoteRevision struct {
+	database.BaseModel `gorm:"embedded"`
+	ReleaseNoteID      uuid.UUID          `gorm:"type:uuid;not null"`
+	OrganisationID     uuid.UUID          `gorm:"type:uuid;not null"`
+	Action             RevisionAction     `gorm:"type:varchar(32)"`
+	AuthorID           *uuid.UUID         `gorm:"type:uuid"` // nil when changed by the scheduler
+	Title              string             `gorm:"type:varchar(255)"`
+	DescriptionShort   string             `gorm:"type:text"`
+	DescriptionLong    string             `gorm:"type:text"`
+	ReleaseDate        *string            `gorm:"type:date;default:null"`
+	ImagePath          string             `gorm:"type:varchar(255)"`
+	MediaLink          string             `gorm:"type:varchar(1024)"`
+	IsPublished        bool               `gorm:"type:bool;default:false"`
+	CtaLabelOverride   string             `gorm:"type:varchar(255)"`
+	CtaUrlOverride     string             `gorm:"type:varchar(255)"`
+	HideCta            bool               `gorm:"type:bool;default:false"`
+	AttentionMechanism AttentionMechanism `gorm:"type:varchar(255)"`
+	HideOnWidget       bool               `gorm:"type:bool;default:false"`
+	HideOnReleasePage  bool               `gorm:"type:bool;default:false"`
+	PublishAt          *time.Time         `gorm:"type:timestamptz;default:null"`
+	UnpublishAt        *time.Time         `gorm:"type:timestamptz;default:null"`
+	// attachments at the time of the revision, loaded by FindRevision and FindPreviousRevision
+	Media []*ReleaseNoteRevisionMedia `gorm:"-"`
+}
+
+// newRevision snapshots the current state of a release note
+func newRevision(rn *ReleaseNote, action RevisionAction, authorId *uuid.UUID) *ReleaseNoteRevision {
+	return &ReleaseNoteRevision{
+		ReleaseNoteID:      rn.ID,
+		OrganisationID:     rn.OrganisationID,
+		Action:             action,
+		AuthorID:           authorId,
+		Title:              rn.Title,
+		DescriptionShort:   rn.DescriptionShort,
+		DescriptionLong:    rn.DescriptionLong,
+		ReleaseDate:        rn.ReleaseDate,
+		ImagePath:          rn.ImagePath,
+		MediaLink:          rn.MediaLink,
+		IsPublished:        rn.IsPublished,
+		CtaLabelOverride:   rn.CtaLabelOverride,
+		CtaUrlOverride:     rn.CtaUrlOverride,
+		HideCta:            rn.HideCta,
+		AttentionMechanism: rn.AttentionMechanism,
+		HideOnWidget:       rn.HideOnWidget,
+		HideOnReleasePage:  rn.HideOnReleasePage,
+		PublishAt:          rn.PublishAt,
+		UnpublishAt:        rn.UnpublishAt,
+		Media:              revisionMedia(rn.Media),
+	}
+}
+
+// revisionMedia copies attachments into a revision snapshot
+func revisionMedia(media []*ReleaseNoteMedia) []*ReleaseNoteRevisionMedia {
+	snapshot := make([]*ReleaseNoteRevisionMedia, len(media))
+	for i, m := range media {
+		snapshot[i] = &ReleaseNoteRevisionMedia{
+			Position:    i,
+			Kind:        m.Kind,
+			Path:        m.Path,
+			Link:        m.Link,
+			AltText:     m.AltText,
+			Width:       m.Width,
+			Height:      m.Height,
+			Variants:    m.Variants,
+			Placeholder: m.Placeholder,
+		}
+	}
+	return snapshot
+}
+
+// RevisionChange describes how a single field differs between two revisions
+type RevisionChange struct {
+	Field    string
+	OldValue string
+	NewValue string
+	// TextDiff is set for free-text fields and holds a word-level diff
+	TextDiff []util.DiffSegment
 }
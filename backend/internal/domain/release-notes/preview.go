@@ -0,0 +1,110 @@
+package releasenotes
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/random"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPreviewNotFound is returned for unknown, revoked and expired preview tokens
+	ErrPreviewNotFound = errors.New("preview link not found or expired")
+	// ErrInvalidPreviewLifetime is returned for preview links that would expire too early or too late
+	ErrInvalidPreviewLifetime = errors.New("preview links can be valid for one hour up to 30 days")
+)
+
+const (
+	minPreviewLifetime = time.Hour
+	maxPreviewLifetime = 30 * 24 * time.Hour
+	// number of characters of the token that are stored in plain text to tell links apart
+	previewPrefixLength = 6
+)
+
+// ReleaseNotePreview grants access to a single release note, published or not, to anyone
+// with the token until it expires or is revoked
+type ReleaseNotePreview struct {
+	database.BaseModel `gorm:"embedded"`
+	ReleaseNoteID      uuid.UUID  `gorm:"type:uuid;not null"`
+	OrganisationID     uuid.UUID  `gorm:"type:uuid;not null"`
+	Prefix             string     `gorm:"type:varchar(16)"`
+	TokenHash          string     `gorm:"type:varchar(64);uniqueIndex"`
+	CreatedBy          *uuid.UUID `gorm:"type:uuid"`
+	ExpiresAt          time.Time  `gorm:"type:timestamptz"`
+}
+
+// CreatePreview creates a preview link for the note that is valid for the given lifetime.
+// The plain token is only returned here; only its hash is stored.
+func (s *service) CreatePreview(id, orgId, userId uuid.UUID, lifetime time.Duration) (string, *ReleaseNotePreview, error) {
+	log.Trace().Str("id", id.String()).Dur("lifetime", lifetime).Msg("CreatePreview")
+	if lifetime < minPreviewLifetime || lifetime > maxPreviewLifetime {
+		return "", nil, ErrInvalidPreviewLifetime
+	}
+	if _, err := s.GetOne(id.String(), orgId.String()); err != nil {
+		return "", nil, err
+	}
+	token := strings.ToLower(random.CreateRandomToken())
+	preview := &ReleaseNotePreview{
+		ReleaseNoteID:  id,
+		OrganisationID: orgId,
+		Prefix:         token[:previewPrefixLength],
+		TokenHash:      random.EncodeToken(token),
+		CreatedBy:      &userId,
+		ExpiresAt:      time.Now().Add(lifetime),
+	}
+	if err := s.repo.CreatePreview(preview); err != nil {
+		return "", nil, err
+	}
+	return token, preview, nil
+}
+
+// GetPreviews returns the preview links of a note that haven't expired yet
+func (s *service) GetPreviews(id, orgId uuid.UUID) ([]*ReleaseNotePreview, error) {
+	log.Trace().Str("id", id.String()).Msg("GetPreviews")
+	return s.repo.FindPreviews(id, orgId, time.Now())
+}
+
+// RevokePreview deletes a preview link of the note so that it stops working
+func (s *service) RevokePreview(previewId, id, orgId uuid.UUID) error {
+	log.Trace().Str("previewId", previewId.String()).Msg("RevokePreview")
+	deleted, err := s.repo.DeletePreview(previewId, id, orgId)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrPreviewNotFound
+	}
+	return nil
+}
+
+// GetByPreviewToken returns the note a valid preview token of the organisation grants access
+// to, regardless of its published state, together with the preview
+func (s *service) GetByPreviewToken(orgId uuid.UUID, token string) (*ReleaseNote, *ReleaseNotePreview, error) {
+	log.Trace().Msg("GetByPreviewToken")
+	if token == "" {
+		return nil, nil, ErrPreviewNotFound
+	}
+	preview, err := s.repo.FindPreviewByHash(random.EncodeToken(strings.ToLower(token)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrPreviewNotFound
+		}
+		log.Error().Err(err).Msg("Error finding preview")
+		return nil, nil, err
+	}
+	if preview.OrganisationID != orgId || !preview.ExpiresAt.After(time.Now()) {
+		return nil, nil, ErrPreviewNotFound
+	}
+	rn, err := s.GetOne(preview.ReleaseNoteID.String(), orgId.String())
+	if err != nil {
+		if errors.Is(err, ErrReleaseNoteNotFound) {
+			return nil, nil, ErrPreviewNotFound
+		}
+		return nil, nil, err
+	}
+	return rn, preview, nil
+}
@@ -0,0 +1,32 @@
+package releasenotes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCreatePreviewLifetime(t *testing.T) {
+	tests := []struct {
+		name     string
+		lifetime time.Duration
+	}{
+		{name: "zero", lifetime: 0},
+		{name: "negative", lifetime: -24 * time.Hour},
+		{name: "below an hour", lifetime: 59 * time.Minute},
+		{name: "above 30 days", lifetime: 31 * 24 * time.Hour},
+	}
+
+	// the lifetime is validated before the note is looked up
+	s := &service{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, preview, err := s.CreatePreview(uuid.New(), uuid.New(), uuid.New(), tt.lifetime)
+			if !errors.Is(err, ErrInvalidPreviewLifetime) || token != "" || preview != nil {
+				t.Errorf("CreatePreview(%s) = %q, %v, %v, want ErrInvalidPreviewLifetime", tt.lifetime, token, preview, err)
+			}
+		})
+	}
+}
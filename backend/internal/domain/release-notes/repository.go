@@ -3,8 +3,11 @@ package releasenotes
 import (
 	"io"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
 	"github.com/devbydaniel/announcable/internal/objstore"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -13,10 +16,17 @@ import (
 type repository struct {
 	db       *database.DB
 	tx       *database.Transaction
-	objStore *objstore.ObjStore
+	objStore imageStore
 	bucket   string
 }
 
+// imageStore is the part of objstore.ObjStore the repository uses
+type imageStore interface {
+	GetImageUrl(bucket, path string) (string, error)
+	UpdateImage(bucket, path string, img *io.Reader) error
+	DeleteImage(bucket, path string) error
+}
+
 func (r *repository) StartTransaction() {
 	r.tx = r.db.StartTransaction()
 }
@@ -29,11 +39,89 @@ func (r *repository) Rollback() {
 	r.tx.Rollback()
 }
 
-func NewRepository(db *database.DB, objStore *objstore.ObjStore) *repository {
+func NewRepository(db *database.DB, objStore imageStore) *repository {
 	log.Trace().Msg("NewRepository")
 	return &repository{db: db, objStore: objStore, bucket: objstore.ReleaseNotesBucket.String()}
 }
 
+// applyFilters adds equality conditions for column filters, a tag condition for FilterTags and
+// excludes the release notes an audience filter was resolved into and, for FilterUntargeted,
+// those with audience rules
+func (r *repository) applyFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	for key, value := range filters {
+		if key == FilterUntargeted {
+			if untargeted, ok := value.(bool); ok && untargeted {
+				query = query.Where(untargetedCondition)
+			}
+			continue
+		}
+		if key == filterExcludedIds {
+			if ids, ok := value.([]uuid.UUID); ok && len(ids) > 0 {
+				query = query.Where("id NOT IN ?", ids)
+			}
+			continue
+		}
+		if key == FilterTags {
+			names, ok := value.([]string)
+			if !ok || len(names) == 0 {
+				continue
+			}
+			lowered := make([]string, len(names))
+			for i, name := range names {
+				lowered[i] = strings.ToLower(name)
+			}
+			tagged := r.db.Client.Table("release_note_tags").
+				Select("release_note_tags.release_note_id").
+				Joins("JOIN tags ON tags.id = release_note_tags.tag_id AND tags.deleted_at IS NULL").
+				Where("lower(tags.name) IN ?", lowered)
+			query = query.Where("id IN (?)", tagged)
+			continue
+		}
+		query = query.Where(key+" = ?", value)
+	}
+	return query
+}
+
+// loadTags populates the tags of the given release notes, ordered by name
+func (r *repository) loadTags(rns []*ReleaseNote, tx *gorm.DB) error {
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if len(rns) == 0 {
+		return nil
+	}
+	byId := make(map[uuid.UUID]*ReleaseNote, len(rns))
+	ids := make([]uuid.UUID, len(rns))
+	for i, rn := range rns {
+		byId[rn.ID] = rn
+		ids[i] = rn.ID
+	}
+
+	var rows []struct {
+		tag.Tag
+		ReleaseNoteID uuid.UUID
+	}
+	if err := client.Model(&tag.Tag{}).
+		Select("tags.*, release_note_tags.release_note_id").
+		Joins("JOIN release_note_tags ON release_note_tags.tag_id = tags.id").
+		Where("release_note_tags.release_note_id IN ?", ids).
+		Order("lower(tags.name) asc").
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Msg("Error loading release note tags")
+		return err
+	}
+	for i := range rows {
+		if rn, ok := byId[rows[i].ReleaseNoteID]; ok {
+			t := rows[i].Tag
+			rn.Tags = append(rn.Tags, &t)
+		}
+	}
+	return nil
+}
+
 func (r *repository) Create(rn *ReleaseNote, tx *gorm.DB) (uuid.UUID, error) {
 	log.Trace().Msg("Create")
 	var client *gorm.DB
@@ -69,7 +157,8 @@ func (r *repository) Update(id uuid.UUID, rn *ReleaseNote, tx *gorm.DB) error {
 		"AttentionMechanism",
 		"HideOnWidget",
 		"HideOnReleasePage",
-		"MediaLink",
+		"PublishAt",
+		"UnpublishAt",
 	).Updates(rn).Error; err != nil {
 		log.Error().Err(err).Msg("Error updating release note")
 		return err
@@ -100,15 +189,11 @@ func (r *repository) GetStatus(orgId string, filters map[string]interface{}) ([]
 
 	// Base query conditions
 	query := r.db.Client.Model(&ReleaseNote{}).
-		Select("updated_at, attention_mechanism").
+		Select("id, updated_at, attention_mechanism").
 		Where("organisation_id = ?", orgId).Where("is_published = ?", true)
 
 	// Apply additional filters if any
-	if len(filters) > 0 {
-		for key, value := range filters {
-			query = query.Where(key+" = ?", value)
-		}
-	}
+	query = r.applyFilters(query, filters)
 
 	if err := query.Find(&statuses).Error; err != nil {
 		log.Error().Err(err).Msg("Error getting release note statuses")
@@ -132,12 +217,8 @@ func (r *repository) FindAll(orgId string, page, pageSize int, filters map[strin
 	// Base query conditions
 	query := r.db.Client.Model(&ReleaseNote{}).Where("organisation_id = ?", orgId)
 
-	if len(filters) > 0 {
-		// Apply additional filters
-		for key, value := range filters {
-			query = query.Where(key+" = ?", value)
-		}
-	}
+	// Apply additional filters
+	query = r.applyFilters(query, filters)
 
 	var totalCount int64
 	if err := query.Count(&totalCount).Error; err != nil {
@@ -156,6 +237,12 @@ func (r *repository) FindAll(orgId string, page, pageSize int, filters map[strin
 		log.Error().Err(err).Msg("Error finding release notes by organisation id")
 		return nil, err
 	}
+	if err := r.loadTags(rns, nil); err != nil {
+		return nil, err
+	}
+	if err := r.loadMedia(rns, nil); err != nil {
+		return nil, err
+	}
 
 	return &PaginatedReleaseNotes{
 		Items:      rns,
@@ -166,16 +253,229 @@ func (r *repository) FindAll(orgId string, page, pageSize int, filters map[strin
 	}, nil
 }
 
-func (r *repository) FindOne(id uuid.UUID) (*ReleaseNote, error) {
+func (r *repository) FindOne(id uuid.UUID, tx *gorm.DB) (*ReleaseNote, error) {
 	log.Trace().Msg("FindById")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
 	rn := &ReleaseNote{}
-	if err := r.db.Client.First(rn, id).Error; err != nil {
+	if err := client.First(rn, id).Error; err != nil {
 		log.Error().Err(err).Msg("Error finding release note by id")
 		return nil, err
 	}
+	if err := r.loadTags([]*ReleaseNote{rn}, tx); err != nil {
+		return nil, err
+	}
+	if err := r.loadMedia([]*ReleaseNote{rn}, tx); err != nil {
+		return nil, err
+	}
+	if err := client.Where("release_note_id = ?", rn.ID).Order("locale asc").Find(&rn.Translations).Error; err != nil {
+		log.Error().Err(err).Msg("Error loading release note translations")
+		return nil, err
+	}
+	if err := client.Where("release_note_id = ?", rn.ID).Order("position asc").Find(&rn.AudienceRules).Error; err != nil {
+		log.Error().Err(err).Msg("Error loading release note audience rules")
+		return nil, err
+	}
 	return rn, nil
 }
 
+// ReplaceTags sets the tags of a release note to the given tags
+func (r *repository) ReplaceTags(id uuid.UUID, tagIds []uuid.UUID, tx *gorm.DB) error {
+	log.Trace().Str("id", id.String()).Int("count", len(tagIds)).Msg("ReplaceTags")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Where("release_note_id = ?", id).Delete(&ReleaseNoteTag{}).Error; err != nil {
+		log.Error().Err(err).Msg("Error removing release note tags")
+		return err
+	}
+	if len(tagIds) == 0 {
+		return nil
+	}
+	rows := make([]*ReleaseNoteTag, len(tagIds))
+	for i, tagId := range tagIds {
+		rows[i] = &ReleaseNoteTag{ReleaseNoteID: id, TagID: tagId}
+	}
+	if err := client.Create(rows).Error; err != nil {
+		log.Error().Err(err).Msg("Error adding release note tags")
+		return err
+	}
+	return nil
+}
+
+// ReplaceTranslations sets the translations of a release note to the given translations
+func (r *repository) ReplaceTranslations(id uuid.UUID, translations []*ReleaseNoteTranslation, tx *gorm.DB) error {
+	log.Trace().Str("id", id.String()).Int("count", len(translations)).Msg("ReplaceTranslations")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Where("release_note_id = ?", id).Delete(&ReleaseNoteTranslation{}).Error; err != nil {
+		log.Error().Err(err).Msg("Error removing release note translations")
+		return err
+	}
+	if len(translations) == 0 {
+		return nil
+	}
+	for _, t := range translations {
+		t.ReleaseNoteID = id
+	}
+	if err := client.Create(translations).Error; err != nil {
+		log.Error().Err(err).Msg("Error adding release note translations")
+		return err
+	}
+	return nil
+}
+
+// FindTranslations returns the translations of the given release notes into one language
+func (r *repository) FindTranslations(ids []uuid.UUID, locale string) ([]*ReleaseNoteTranslation, error) {
+	log.Trace().Str("locale", locale).Int("count", len(ids)).Msg("FindTranslations")
+	var translations []*ReleaseNoteTranslation
+	if len(ids) == 0 {
+		return translations, nil
+	}
+	if err := r.db.Client.Where("release_note_id IN ? AND locale = ?", ids, locale).Find(&translations).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note translations")
+		return nil, err
+	}
+	return translations, nil
+}
+
+// FindLocales returns the languages that published release notes of an organisation are translated into
+func (r *repository) FindLocales(orgId uuid.UUID) ([]string, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("FindLocales")
+	var locales []string
+	if err := r.db.Client.Model(&ReleaseNoteTranslation{}).
+		Distinct("release_note_translations.locale").
+		Joins("JOIN release_notes ON release_notes.id = release_note_translations.release_note_id").
+		Where("release_notes.organisation_id = ? AND release_notes.is_published = ? AND release_notes.deleted_at IS NULL", orgId, true).
+		Pluck("release_note_translations.locale", &locales).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note locales")
+		return nil, err
+	}
+	return locales, nil
+}
+
+// ReplaceAudienceRules sets the audience rules of a release note to the given rules
+func (r *repository) ReplaceAudienceRules(id uuid.UUID, rules []*AudienceRule, tx *gorm.DB) error {
+	log.Trace().Str("id", id.String()).Int("count", len(rules)).Msg("ReplaceAudienceRules")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Where("release_note_id = ?", id).Delete(&AudienceRule{}).Error; err != nil {
+		log.Error().Err(err).Msg("Error removing audience rules")
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	for _, rule := range rules {
+		rule.ReleaseNoteID = id
+	}
+	if err := client.Create(rules).Error; err != nil {
+		log.Error().Err(err).Msg("Error adding audience rules")
+		return err
+	}
+	return nil
+}
+
+// HasAudienceRules reports whether a release note is targeted at a part of the end users
+func (r *repository) HasAudienceRules(id uuid.UUID) (bool, error) {
+	log.Trace().Str("id", id.String()).Msg("HasAudienceRules")
+	var count int64
+	if err := r.db.Client.Model(&AudienceRule{}).Where("release_note_id = ?", id).Count(&count).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting audience rules")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindAudienceRules returns the audience rules of all release notes of an organisation
+func (r *repository) FindAudienceRules(orgId string) ([]*AudienceRule, error) {
+	log.Trace().Str("orgId", orgId).Msg("FindAudienceRules")
+	var rules []*AudienceRule
+	if err := r.db.Client.
+		Joins("JOIN release_notes ON release_notes.id = audience_rules.release_note_id").
+		Where("release_notes.organisation_id = ? AND release_notes.deleted_at IS NULL", orgId).
+		Order("audience_rules.release_note_id, audience_rules.position").
+		Find(&rules).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding audience rules")
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *repository) FindBySlug(orgId uuid.UUID, slug string) (*ReleaseNote, error) {
+	log.Trace().Str("slug", slug).Msg("FindBySlug")
+	rn := &ReleaseNote{}
+	if err := r.db.Client.Where("organisation_id = ? AND slug = ?", orgId, slug).First(rn).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note by slug")
+		return nil, err
+	}
+	if err := r.loadTags([]*ReleaseNote{rn}, nil); err != nil {
+		return nil, err
+	}
+	if err := r.loadMedia([]*ReleaseNote{rn}, nil); err != nil {
+		return nil, err
+	}
+	return rn, nil
+}
+
+func (r *repository) SlugExists(orgId uuid.UUID, slug string, tx *gorm.DB) (bool, error) {
+	log.Trace().Str("slug", slug).Msg("SlugExists")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	var count int64
+	if err := client.Model(&ReleaseNote{}).Where("organisation_id = ? AND slug = ?", orgId, slug).Count(&count).Error; err != nil {
+		log.Error().Err(err).Msg("Error checking release note slug")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindDueForPublish skips notes that still need an approved review, they are published
+// once approved if their publish time has passed by then
+func (r *repository) FindDueForPublish(now time.Time) ([]*ReleaseNote, error) {
+	log.Trace().Time("now", now).Msg("FindDueForPublish")
+	var rns []*ReleaseNote
+	if err := r.db.Client.
+		Where("publish_at IS NOT NULL AND publish_at <= ?", now).
+		Where("review_status = ? OR organisation_id NOT IN (SELECT id FROM organisations WHERE require_review)", ReviewStatusApproved).
+		Find(&rns).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release notes due for publishing")
+		return nil, err
+	}
+	return rns, nil
+}
+
+func (r *repository) FindDueForUnpublish(now time.Time) ([]*ReleaseNote, error) {
+	log.Trace().Time("now", now).Msg("FindDueForUnpublish")
+	var rns []*ReleaseNote
+	if err := r.db.Client.
+		Where("unpublish_at IS NOT NULL AND unpublish_at <= ?", now).
+		Find(&rns).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release notes due for unpublishing")
+		return nil, err
+	}
+	return rns, nil
+}
+
 func (r *repository) Delete(id uuid.UUID, tx *gorm.DB) error {
 	log.Trace().Msg("Delete")
 	var client *gorm.DB
@@ -196,37 +496,23 @@ func (r *repository) GetImageUrl(path string) (string, error) {
 	return r.objStore.GetImageUrl(r.bucket, path)
 }
 
-func (r *repository) UpdateImage(id uuid.UUID, img *io.Reader, path string, tx *gorm.DB) error {
-	log.Trace().Msg("UpdateImage")
-	if err := r.UpdateWithNil(id, map[string]interface{}{"ImagePath": path}, tx); err != nil {
-		log.Error().Err(err).Msg("Error updating release note")
-		return err
-	}
+// UploadImage stores an image in the release notes bucket
+func (r *repository) UploadImage(path string, img *io.Reader) error {
+	log.Trace().Str("path", path).Msg("UploadImage")
 	if err := r.objStore.UpdateImage(r.bucket, path, img); err != nil {
-		log.Error().Err(err).Msg("Error updating image")
+		log.Error().Err(err).Msg("Error uploading image")
 		return err
 	}
 	return nil
 }
 
-func (r *repository) DeleteImage(id uuid.UUID, tx *gorm.DB) error {
-	log.Trace().Msg("DeleteImage")
-	rn, err := r.FindOne(id)
-	if err != nil {
-		log.Error().Err(err).Msg("Error finding release note")
+// DeleteImageObject removes an image from the release notes bucket
+func (r *repository) DeleteImageObject(path string) error {
+	log.Trace().Str("path", path).Msg("DeleteImageObject")
+	if err := r.objStore.DeleteImage(r.bucket, path); err != nil {
+		log.Error().Err(err).Msg("Error deleting image")
 		return err
 	}
-	if rn.ImagePath != "" {
-		if err := r.objStore.DeleteImage(r.bucket, rn.ImagePath); err != nil {
-			log.Error().Err(err).Msg("Error deleting image")
-			return err
-		}
-		if err := r.UpdateWithNil(id, map[string]interface{}{"ImagePath": nil}, tx); err != nil {
-			log.Error().Err(err).Msg("Error updating release note")
-			return err
-		}
-	}
-
 	return nil
 }
 
@@ -239,3 +525,323 @@ func (r *repository) GetCount(orgID uuid.UUID) (int64, error) {
 	}
 	return count, nil
 }
+
+func (r *repository) CreateRevision(rev *ReleaseNoteRevision, tx *gorm.DB) error {
+	log.Trace().Msg("CreateRevision")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Create(rev).Error; err != nil {
+		log.Error().Err(err).Msg("Error saving release note revision")
+		return err
+	}
+	if len(rev.Media) == 0 {
+		return nil
+	}
+	for _, m := range rev.Media {
+		m.RevisionID = rev.ID
+	}
+	if err := client.Create(rev.Media).Error; err != nil {
+		log.Error().Err(err).Msg("Error saving release note revision media")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindRevisions(releaseNoteId uuid.UUID) ([]*ReleaseNoteRevision, error) {
+	log.Trace().Str("releaseNoteId", releaseNoteId.String()).Msg("FindRevisions")
+	var revs []*ReleaseNoteRevision
+	if err := r.db.Client.
+		Where("release_note_id = ?", releaseNoteId).
+		Order("created_at desc").
+		Find(&revs).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note revisions")
+		return nil, err
+	}
+	return revs, nil
+}
+
+func (r *repository) FindRevision(id uuid.UUID) (*ReleaseNoteRevision, error) {
+	log.Trace().Str("id", id.String()).Msg("FindRevision")
+	rev := &ReleaseNoteRevision{}
+	if err := r.db.Client.First(rev, id).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note revision")
+		return nil, err
+	}
+	if err := r.loadRevisionMedia(rev); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// FindPreviousRevision returns the revision taken right before the given one, or nil if it is the first
+func (r *repository) FindPreviousRevision(rev *ReleaseNoteRevision) (*ReleaseNoteRevision, error) {
+	log.Trace().Str("id", rev.ID.String()).Msg("FindPreviousRevision")
+	var revs []*ReleaseNoteRevision
+	if err := r.db.Client.
+		Where("release_note_id = ? AND created_at < ?", rev.ReleaseNoteID, rev.CreatedAt).
+		Order("created_at desc").
+		Limit(1).
+		Find(&revs).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding previous release note revision")
+		return nil, err
+	}
+	if len(revs) == 0 {
+		return nil, nil
+	}
+	if err := r.loadRevisionMedia(revs[0]); err != nil {
+		return nil, err
+	}
+	return revs[0], nil
+}
+
+func (r *repository) CreateReview(review *ReleaseNoteReview, tx *gorm.DB) error {
+	log.Trace().Msg("CreateReview")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Create(review).Error; err != nil {
+		log.Error().Err(err).Msg("Error saving release note review")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindReviews(releaseNoteId, orgId uuid.UUID) ([]*ReleaseNoteReview, error) {
+	log.Trace().Str("releaseNoteId", releaseNoteId.String()).Msg("FindReviews")
+	var reviews []*ReleaseNoteReview
+	if err := r.db.Client.
+		Where("release_note_id = ? AND organisation_id = ?", releaseNoteId, orgId).
+		Order("created_at desc").
+		Find(&reviews).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note reviews")
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ResetApproval moves an approved note back to draft, as the approval covers the content
+// that was reviewed. Notes in review stay there, so the reviewer sees the latest content.
+func (r *repository) ResetApproval(id uuid.UUID, tx *gorm.DB) error {
+	log.Trace().Str("id", id.String()).Msg("ResetApproval")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Model(&ReleaseNote{}).
+		Where("id = ? AND review_status = ?", id, ReviewStatusApproved).
+		Update("review_status", ReviewStatusDraft).Error; err != nil {
+		log.Error().Err(err).Msg("Error resetting approval")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) CreatePreview(preview *ReleaseNotePreview) error {
+	log.Trace().Str("releaseNoteId", preview.ReleaseNoteID.String()).Msg("CreatePreview")
+	if err := r.db.Client.Create(preview).Error; err != nil {
+		log.Error().Err(err).Msg("Error creating release note preview")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindPreviews(releaseNoteId, orgId uuid.UUID, now time.Time) ([]*ReleaseNotePreview, error) {
+	log.Trace().Str("releaseNoteId", releaseNoteId.String()).Msg("FindPreviews")
+	var previews []*ReleaseNotePreview
+	if err := r.db.Client.
+		Where("release_note_id = ? AND organisation_id = ? AND expires_at > ?", releaseNoteId, orgId, now).
+		Order("created_at desc").
+		Find(&previews).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note previews")
+		return nil, err
+	}
+	return previews, nil
+}
+
+func (r *repository) FindPreviewByHash(hash string) (*ReleaseNotePreview, error) {
+	log.Trace().Msg("FindPreviewByHash")
+	var preview ReleaseNotePreview
+	if err := r.db.Client.Where("token_hash = ?", hash).First(&preview).Error; err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+func (r *repository) DeletePreview(id, releaseNoteId, orgId uuid.UUID) (int64, error) {
+	log.Trace().Str("id", id.String()).Msg("DeletePreview")
+	res := r.db.Client.
+		Where("id = ? AND release_note_id = ? AND organisation_id = ?", id, releaseNoteId, orgId).
+		Delete(&ReleaseNotePreview{})
+	if res.Error != nil {
+		log.Error().Err(res.Error).Msg("Error deleting release note preview")
+		return 0, res.Error
+	}
+	return res.RowsAffected, nil
+}
+
+// CountImageReferences counts the attachments and revision snapshots of notes that aren't deleted
+// which reference the image at path
+func (r *repository) CountImageReferences(path string) (int64, error) {
+	log.Trace().Str("path", path).Msg("CountImageReferences")
+	var attached, inHistory int64
+	if err := r.db.Client.Model(&ReleaseNoteMedia{}).
+		Joins("JOIN release_notes ON release_notes.id = release_note_media.release_note_id").
+		Where("release_note_media.path = ? AND release_notes.deleted_at IS NULL", path).
+		Count(&attached).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting release note media")
+		return 0, err
+	}
+	if err := r.db.Client.Model(&ReleaseNoteRevisionMedia{}).
+		Joins("JOIN release_note_revisions ON release_note_revisions.id = release_note_revision_media.revision_id").
+		Joins("JOIN release_notes ON release_notes.id = release_note_revisions.release_note_id").
+		Where("release_note_revision_media.path = ? AND release_notes.deleted_at IS NULL", path).
+		Count(&inHistory).Error; err != nil {
+		log.Error().Err(err).Msg("Error counting release note revision media")
+		return 0, err
+	}
+	return attached + inHistory, nil
+}
+
+// FindImagePaths returns the paths of all images a note and its revisions have referenced
+func (r *repository) FindImagePaths(releaseNoteId uuid.UUID, tx *gorm.DB) ([]string, error) {
+	log.Trace().Str("releaseNoteId", releaseNoteId.String()).Msg("FindImagePaths")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	var attached, inHistory []string
+	if err := client.Model(&ReleaseNoteMedia{}).
+		Where("release_note_id = ? AND kind = ?", releaseNoteId, MediaKindImage).
+		Pluck("path", &attached).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note media")
+		return nil, err
+	}
+	if err := client.Model(&ReleaseNoteRevisionMedia{}).
+		Distinct("release_note_revision_media.path").
+		Joins("JOIN release_note_revisions ON release_note_revisions.id = release_note_revision_media.revision_id").
+		Where("release_note_revisions.release_note_id = ? AND release_note_revision_media.kind = ?", releaseNoteId, MediaKindImage).
+		Pluck("release_note_revision_media.path", &inHistory).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note revision media")
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var paths []string
+	for _, path := range append(attached, inHistory...) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// FindMedia returns the attachments of a release note in display order
+func (r *repository) FindMedia(releaseNoteId uuid.UUID, tx *gorm.DB) ([]*ReleaseNoteMedia, error) {
+	log.Trace().Str("releaseNoteId", releaseNoteId.String()).Msg("FindMedia")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	var media []*ReleaseNoteMedia
+	if err := client.Where("release_note_id = ?", releaseNoteId).Order("position asc").Find(&media).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding release note media")
+		return nil, err
+	}
+	return media, nil
+}
+
+// FindPublicImage returns an image attachment of a published release note of the organisation
+// that is shown on its release page
+func (r *repository) FindPublicImage(orgId, mediaId uuid.UUID) (*ReleaseNoteMedia, error) {
+	log.Trace().Str("mediaId", mediaId.String()).Msg("FindPublicImage")
+	var m ReleaseNoteMedia
+	if err := r.db.Client.
+		Joins("JOIN release_notes ON release_notes.id = release_note_media.release_note_id").
+		Where("release_note_media.id = ? AND release_note_media.kind = ?", mediaId, MediaKindImage).
+		Where("release_notes.organisation_id = ? AND release_notes.deleted_at IS NULL", orgId).
+		Where("release_notes.is_published AND NOT release_notes.hide_on_release_page").
+		Where(untargetedCondition).
+		First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ReplaceMedia sets the attachments of a release note to the given ones.
+// Attachments that are kept retain their ID.
+func (r *repository) ReplaceMedia(id uuid.UUID, media []*ReleaseNoteMedia, tx *gorm.DB) error {
+	log.Trace().Str("id", id.String()).Int("count", len(media)).Msg("ReplaceMedia")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Where("release_note_id = ?", id).Delete(&ReleaseNoteMedia{}).Error; err != nil {
+		log.Error().Err(err).Msg("Error removing release note media")
+		return err
+	}
+	if len(media) == 0 {
+		return nil
+	}
+	for _, m := range media {
+		m.ReleaseNoteID = id
+	}
+	if err := client.Create(media).Error; err != nil {
+		log.Error().Err(err).Msg("Error adding release note media")
+		return err
+	}
+	return nil
+}
+
+// loadMedia populates the attachments of the given release notes
+func (r *repository) loadMedia(rns []*ReleaseNote, tx *gorm.DB) error {
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if len(rns) == 0 {
+		return nil
+	}
+	byId := make(map[uuid.UUID]*ReleaseNote, len(rns))
+	ids := make([]uuid.UUID, len(rns))
+	for i, rn := range rns {
+		byId[rn.ID] = rn
+		ids[i] = rn.ID
+	}
+	var media []*ReleaseNoteMedia
+	if err := client.Where("release_note_id IN ?", ids).Order("release_note_id, position asc").Find(&media).Error; err != nil {
+		log.Error().Err(err).Msg("Error loading release note media")
+		return err
+	}
+	for _, m := range media {
+		if rn, ok := byId[m.ReleaseNoteID]; ok {
+			rn.Media = append(rn.Media, m)
+		}
+	}
+	return nil
+}
+
+// loadRevisionMedia populates the attachments of a revision snapshot
+func (r *repository) loadRevisionMedia(rev *ReleaseNoteRevision) error {
+	if err := r.db.Client.Where("revision_id = ?", rev.ID).Order("position asc").Find(&rev.Media).Error; err != nil {
+		log.Error().Err(err).Msg("Error loading release note revision media")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package releasenotes
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devbydaniel/announcable/config"
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/devbydaniel/announcable/internal/email"
+	"github.com/devbydaniel/announcable/internal/util"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrReviewRequired is returned when publishing a note that wasn't approved while the organisation requires reviews
+	ErrReviewRequired = errors.New("release note needs an approved review before it can be published")
+	// ErrInvalidReviewTransition is returned for review actions that don't apply to the current review status
+	ErrInvalidReviewTransition = errors.New("review action is not possible in the current review status")
+	// ErrNotReviewer is returned when someone else than the assigned reviewer approves or requests changes
+	ErrNotReviewer = errors.New("only the assigned reviewer can review this release note")
+	// ErrInvalidReviewer is returned for reviewers outside the organisation and for reviewing one's own request
+	ErrInvalidReviewer = errors.New("please choose another member of the organisation as reviewer")
+	// ErrCommentRequired is returned when requesting changes without saying which
+	ErrCommentRequired = errors.New("please describe the changes you request")
+	// ErrCommentTooLong is returned for review comments over maxReviewCommentLength characters
+	ErrCommentTooLong = errors.New("review comments can have at most 2000 characters")
+)
+
+// maxReviewCommentLength caps review comments, which are also sent by email
+const maxReviewCommentLength = 2000
+
+type ReviewStatus string
+
+func (rs ReviewStatus) String() string {
+	return string(rs)
+}
+
+const (
+	ReviewStatusDraft    ReviewStatus = "draft"
+	ReviewStatusInReview ReviewStatus = "in_review"
+	ReviewStatusApproved ReviewStatus = "approved"
+)
+
+type ReviewAction string
+
+func (ra ReviewAction) String() string {
+	return string(ra)
+}
+
+const (
+	ReviewActionSubmitted        ReviewAction = "submitted"
+	ReviewActionApproved         ReviewAction = "approved"
+	ReviewActionChangesRequested ReviewAction = "changes_requested"
+)
+
+// ReleaseNoteReview is an entry in the review log of a release note
+type ReleaseNoteReview struct {
+	database.BaseModel `gorm:"embedded"`
+	ReleaseNoteID      uuid.UUID    `gorm:"type:uuid;not null"`
+	OrganisationID     uuid.UUID    `gorm:"type:uuid;not null"`
+	AuthorID           *uuid.UUID   `gorm:"type:uuid"` // nil once the author was deleted
+	Action             ReviewAction `gorm:"type:varchar(32)"`
+	Comment            string       `gorm:"type:text"`
+}
+
+// nextReviewStatus returns the review status a note moves to by the given action
+func nextReviewStatus(current ReviewStatus, action ReviewAction) (ReviewStatus, error) {
+	switch {
+	case action == ReviewActionSubmitted && current == ReviewStatusDraft:
+		return ReviewStatusInReview, nil
+	case action == ReviewActionApproved && current == ReviewStatusInReview:
+		return ReviewStatusApproved, nil
+	case action == ReviewActionChangesRequested && current == ReviewStatusInReview:
+		return ReviewStatusDraft, nil
+	}
+	return "", ErrInvalidReviewTransition
+}
+
+// SubmitForReview asks reviewerId to review the note. Only drafts can be submitted.
+func (s *service) SubmitForReview(id, orgId, userId, reviewerId uuid.UUID, comment string) error {
+	log.Trace().Str("id", id.String()).Str("reviewerId", reviewerId.String()).Msg("SubmitForReview")
+	if reviewerId == userId {
+		return ErrInvalidReviewer
+	}
+	orgService := organisation.NewService(*organisation.NewRepository(s.repo.db))
+	orgUsers, err := orgService.GetOrgUsers(orgId)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding organisation users")
+		return err
+	}
+	isMember := false
+	for _, ou := range orgUsers {
+		if ou.UserID == reviewerId {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		return ErrInvalidReviewer
+	}
+	return s.review(id, orgId, userId, ReviewActionSubmitted, comment, map[string]interface{}{
+		"ReviewerID":        reviewerId,
+		"ReviewRequestedBy": userId,
+	})
+}
+
+// Approve marks a note in review as ready to be published
+func (s *service) Approve(id, orgId, userId uuid.UUID, comment string) error {
+	log.Trace().Str("id", id.String()).Msg("Approve")
+	return s.review(id, orgId, userId, ReviewActionApproved, comment, nil)
+}
+
+// RequestChanges sends a note in review back to draft, comment says what to change
+func (s *service) RequestChanges(id, orgId, userId uuid.UUID, comment string) error {
+	log.Trace().Str("id", id.String()).Msg("RequestChanges")
+	if strings.TrimSpace(comment) == "" {
+		return ErrCommentRequired
+	}
+	return s.review(id, orgId, userId, ReviewActionChangesRequested, comment, nil)
+}
+
+// GetReviews returns the review log of a release note, newest first
+func (s *service) GetReviews(releaseNoteId, orgId uuid.UUID) ([]*ReleaseNoteReview, error) {
+	log.Trace().Str("releaseNoteId", releaseNoteId.String()).Msg("GetReviews")
+	return s.repo.FindReviews(releaseNoteId, orgId)
+}
+
+// review applies a review action to the note, logs it and notifies the other party.
+// data holds additional fields to store along with the new review status.
+func (s *service) review(id, orgId, userId uuid.UUID, action ReviewAction, comment string, data map[string]interface{}) error {
+	log.Trace().Str("id", id.String()).Str("action", action.String()).Msg("review")
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > maxReviewCommentLength {
+		return ErrCommentTooLong
+	}
+
+	tx := s.repo.db.StartTransaction()
+	rn, err := s.repo.FindOne(id, tx.Tx)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReleaseNoteNotFound
+		}
+		log.Error().Err(err).Msg("Error finding release note")
+		return err
+	}
+	if rn.OrganisationID != orgId {
+		tx.Rollback()
+		return ErrReleaseNoteNotFound
+	}
+	next, err := nextReviewStatus(rn.ReviewStatus, action)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if action != ReviewActionSubmitted && (rn.ReviewerID == nil || *rn.ReviewerID != userId) {
+		tx.Rollback()
+		return ErrNotReviewer
+	}
+
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	data["ReviewStatus"] = next
+	if err := s.repo.UpdateWithNil(id, data, tx.Tx); err != nil {
+		log.Error().Err(err).Msg("Error updating review status")
+		tx.Rollback()
+		return err
+	}
+	if err := s.repo.CreateReview(&ReleaseNoteReview{
+		ReleaseNoteID:  id,
+		OrganisationID: orgId,
+		AuthorID:       &userId,
+		Action:         action,
+		Comment:        comment,
+	}, tx.Tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	saved, err := s.repo.FindOne(id, tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding release note")
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+
+	go s.notifyReview(saved, action, userId, comment)
+	return nil
+}
+
+// notifyReview emails the reviewer about a new request and the requester about the outcome.
+// It runs after the review was stored, errors are only logged.
+func (s *service) notifyReview(rn *ReleaseNote, action ReviewAction, actorId uuid.UUID, comment string) {
+	log.Trace().Str("id", rn.ID.String()).Str("action", action.String()).Msg("notifyReview")
+	cfg := config.New()
+	if !cfg.IsEmailEnabled() {
+		return
+	}
+	recipientId := rn.ReviewRequestedBy
+	if action == ReviewActionSubmitted {
+		recipientId = rn.ReviewerID
+	}
+	if recipientId == nil {
+		return
+	}
+
+	orgService := organisation.NewService(*organisation.NewRepository(s.repo.db))
+	org, err := orgService.GetOrg(rn.OrganisationID)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding organisation")
+		return
+	}
+	orgUsers, err := orgService.GetOrgUsers(rn.OrganisationID)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding organisation users")
+		return
+	}
+	var to, actorEmail string
+	for _, ou := range orgUsers {
+		if ou.UserID == *recipientId {
+			to = ou.User.Email
+		}
+		if ou.UserID == actorId {
+			actorEmail = ou.User.Email
+		}
+	}
+	// the recipient might have left the organisation in the meantime
+	if to == "" {
+		return
+	}
+
+	if err := email.SendReviewNotification(&email.ReviewNotificationConfig{
+		To:               to,
+		OrganisationName: org.Name,
+		ReleaseNoteTitle: rn.Title,
+		Action:           action.String(),
+		ActorEmail:       actorEmail,
+		Comment:          comment,
+		ActionURL:        util.BuildURL(cfg.BaseURL, "release-notes", rn.ID.String()),
+	}); err != nil {
+		log.Error().Err(err).Str("to", to).Msg("Error sending review notification")
+	}
+}
+
+// checkPublishAllowed returns ErrReviewRequired if the organisation of the note requires
+// reviews and the note wasn't approved
+func (s *service) checkPublishAllowed(rn *ReleaseNote) error {
+	log.Trace().Str("id", rn.ID.String()).Msg("checkPublishAllowed")
+	if rn.ReviewStatus == ReviewStatusApproved {
+		return nil
+	}
+	orgService := organisation.NewService(*organisation.NewRepository(s.repo.db))
+	org, err := orgService.GetOrg(rn.OrganisationID)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding organisation")
+		return err
+	}
+	if org.RequireReview {
+		return ErrReviewRequired
+	}
+	return nil
+}
+
+// checkScheduleAllowed returns ErrReviewRequired if publishAt schedules a note that couldn't be
+// published right now. Keeping the current publish time is always allowed.
+func (s *service) checkScheduleAllowed(current *ReleaseNote, publishAt *time.Time) error {
+	log.Trace().Msg("checkScheduleAllowed")
+	if publishAt == nil || (current.PublishAt != nil && current.PublishAt.Equal(*publishAt)) {
+		return nil
+	}
+	return s.checkPublishAllowed(current)
+}
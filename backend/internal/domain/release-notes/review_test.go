@@ -0,0 +1,35 @@
+package releasenotes
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNextReviewStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  ReviewStatus
+		action   ReviewAction
+		expected ReviewStatus
+		err      error
+	}{
+		{name: "submit draft", current: ReviewStatusDraft, action: ReviewActionSubmitted, expected: ReviewStatusInReview},
+		{name: "approve in review", current: ReviewStatusInReview, action: ReviewActionApproved, expected: ReviewStatusApproved},
+		{name: "request changes in review", current: ReviewStatusInReview, action: ReviewActionChangesRequested, expected: ReviewStatusDraft},
+		{name: "submit in review", current: ReviewStatusInReview, action: ReviewActionSubmitted, err: ErrInvalidReviewTransition},
+		{name: "submit approved", current: ReviewStatusApproved, action: ReviewActionSubmitted, err: ErrInvalidReviewTransition},
+		{name: "approve draft", current: ReviewStatusDraft, action: ReviewActionApproved, err: ErrInvalidReviewTransition},
+		{name: "approve approved", current: ReviewStatusApproved, action: ReviewActionApproved, err: ErrInvalidReviewTransition},
+		{name: "request changes draft", current: ReviewStatusDraft, action: ReviewActionChangesRequested, err: ErrInvalidReviewTransition},
+		{name: "request changes approved", current: ReviewStatusApproved, action: ReviewActionChangesRequested, err: ErrInvalidReviewTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextReviewStatus(tt.current, tt.action)
+			if !errors.Is(err, tt.err) || got != tt.expected {
+				t.Errorf("nextReviewStatus() = %q, %v, want %q, %v", got, err, tt.expected, tt.err)
+			}
+		})
+	}
+}
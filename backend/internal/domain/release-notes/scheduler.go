@@ -0,0 +1,77 @@
+package releasenotes
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("unpublish time must be after publish time")
+
+// ParseSchedule parses the RFC 3339 publish and unpublish times submitted by the editor.
+// Empty values mean that no schedule is set.
+func ParseSchedule(publishAt, unpublishAt string) (*time.Time, *time.Time, error) {
+	log.Trace().Msg("ParseSchedule")
+	parse := func(value string) (*time.Time, error) {
+		if value == "" {
+			return nil, nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, err
+		}
+		t = t.UTC()
+		return &t, nil
+	}
+	publish, err := parse(publishAt)
+	if err != nil {
+		log.Error().Err(err).Msg("Error parsing publish time")
+		return nil, nil, err
+	}
+	unpublish, err := parse(unpublishAt)
+	if err != nil {
+		log.Error().Err(err).Msg("Error parsing unpublish time")
+		return nil, nil, err
+	}
+	if publish != nil && unpublish != nil && !unpublish.After(*publish) {
+		return nil, nil, ErrInvalidSchedule
+	}
+	return publish, unpublish, nil
+}
+
+// Scheduler periodically applies the publish and unpublish schedules of release notes.
+type Scheduler struct {
+	service  *service
+	interval time.Duration
+}
+
+// NewScheduler creates a scheduler that checks for due schedules every interval
+func NewScheduler(s *service, interval time.Duration) *Scheduler {
+	log.Trace().Msg("NewScheduler")
+	return &Scheduler{service: s, interval: interval}
+}
+
+// Run applies due schedules until ctx is cancelled
+func (sch *Scheduler) Run(ctx context.Context) {
+	log.Info().Dur("interval", sch.interval).Msg("Release note scheduler started")
+	ticker := time.NewTicker(sch.interval)
+	defer ticker.Stop()
+
+	sch.tick()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Release note scheduler stopped")
+			return
+		case <-ticker.C:
+			sch.tick()
+		}
+	}
+}
+
+func (sch *Scheduler) tick() {
+	log.Trace().Msg("tick")
+	if _, err := sch.service.ApplySchedules(time.Now()); err != nil {
+		log.Error().Err(err).Msg("Error applying release note schedules")
+	}
+}
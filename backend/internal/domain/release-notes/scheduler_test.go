@@ -0,0 +1,146 @@
+package releasenotes
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/widgetcache"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	publish := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	unpublish := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		publishAt     string
+		unpublishAt   string
+		wantPublish   *time.Time
+		wantUnpublish *time.Time
+		wantErr       error
+		wantAnyErr    bool
+	}{
+		{name: "no schedule"},
+		{name: "publish only", publishAt: "2024-05-01T10:00:00Z", wantPublish: &publish},
+		{name: "unpublish only", unpublishAt: "2024-05-02T10:00:00Z", wantUnpublish: &unpublish},
+		{name: "window", publishAt: "2024-05-01T10:00:00Z", unpublishAt: "2024-05-02T10:00:00Z", wantPublish: &publish, wantUnpublish: &unpublish},
+		{name: "converted to UTC", publishAt: "2024-05-01T12:00:00+02:00", wantPublish: &publish},
+		{name: "unpublish before publish", publishAt: "2024-05-02T10:00:00Z", unpublishAt: "2024-05-01T10:00:00Z", wantErr: ErrInvalidSchedule},
+		{name: "unpublish at publish", publishAt: "2024-05-01T10:00:00Z", unpublishAt: "2024-05-01T10:00:00Z", wantErr: ErrInvalidSchedule},
+		{name: "publish without zone", publishAt: "2024-05-01T10:00", wantAnyErr: true},
+		{name: "unpublish date only", unpublishAt: "2024-05-02", wantAnyErr: true},
+		{name: "garbage", publishAt: "tomorrow", wantAnyErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPublish, gotUnpublish, err := ParseSchedule(tt.publishAt, tt.unpublishAt)
+			if tt.wantErr != nil || tt.wantAnyErr {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("ParseSchedule() error = %v, want %v", err, tt.wantErr)
+				}
+				if gotPublish != nil || gotUnpublish != nil {
+					t.Errorf("ParseSchedule() returned times with an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+			if !equalTime(gotPublish, tt.wantPublish) {
+				t.Errorf("publish = %v, want %v", gotPublish, tt.wantPublish)
+			}
+			if !equalTime(gotUnpublish, tt.wantUnpublish) {
+				t.Errorf("unpublish = %v, want %v", gotUnpublish, tt.wantUnpublish)
+			}
+			if gotPublish != nil && gotPublish.Location() != time.UTC {
+				t.Errorf("publish location = %v, want UTC", gotPublish.Location())
+			}
+		})
+	}
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestApplySchedules(t *testing.T) {
+	s, _, db, org := setupService(t)
+	userId := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+	past, longAgo, future := now.Add(-time.Hour), now.Add(-2*time.Hour), now.Add(time.Hour)
+
+	create := func(title string, published bool, publishAt, unpublishAt *time.Time) uuid.UUID {
+		t.Helper()
+		id, err := s.Create(&ReleaseNote{
+			OrganisationID:   org.ID,
+			Title:            title,
+			DescriptionShort: "Description",
+			IsPublished:      published,
+			PublishAt:        publishAt,
+			UnpublishAt:      unpublishAt,
+			CreatedBy:        userId,
+			LastUpdatedBy:    userId,
+		}, nil)
+		require.NoError(t, err)
+		return id
+	}
+	duePublish := create("Due for publishing", false, &past, nil)
+	dueUnpublish := create("Due for unpublishing", true, nil, &past)
+	passedWindow := create("Window passed", false, &longAgo, &past)
+	upcoming := create("Upcoming", false, &future, nil)
+	running := create("Window running", false, &past, &future)
+
+	// cached widget data of the organisation is dropped once schedules are applied
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+	_, err := widgetcache.GetOrLoad(org.ID, "notes", load)
+	require.NoError(t, err)
+
+	orgIds, err := s.ApplySchedules(now)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{org.ID}, orgIds)
+
+	entry, err := widgetcache.GetOrLoad(org.ID, "notes", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, entry.Value, "widget cache of the organisation was not invalidated")
+
+	get := func(id uuid.UUID) *ReleaseNote {
+		t.Helper()
+		rn, err := s.repo.FindOne(id, db.Client)
+		require.NoError(t, err)
+		return rn
+	}
+	tests := []struct {
+		name            string
+		id              uuid.UUID
+		wantPublished   bool
+		wantPublishAt   bool
+		wantUnpublishAt bool
+	}{
+		{name: "due publish", id: duePublish, wantPublished: true},
+		{name: "due unpublish", id: dueUnpublish, wantPublished: false},
+		{name: "passed window", id: passedWindow, wantPublished: false},
+		{name: "upcoming", id: upcoming, wantPublished: false, wantPublishAt: true},
+		{name: "running window", id: running, wantPublished: true, wantUnpublishAt: true},
+	}
+	for _, tt := range tests {
+		rn := get(tt.id)
+		assert.Equal(t, tt.wantPublished, rn.IsPublished, "%s: published", tt.name)
+		assert.Equal(t, tt.wantPublishAt, rn.PublishAt != nil, "%s: publish schedule kept", tt.name)
+		assert.Equal(t, tt.wantUnpublishAt, rn.UnpublishAt != nil, "%s: unpublish schedule kept", tt.name)
+	}
+
+	// applied schedules are consumed, running again changes nothing
+	orgIds, err = s.ApplySchedules(now)
+	require.NoError(t, err)
+	assert.Empty(t, orgIds)
+}
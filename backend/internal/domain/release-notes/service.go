@@ -1,8 +1,38 @@
 package releasenotes
 
 import (
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
 	"github.com/devbydaniel/announcable/internal/imgUtil"
+	"github.com/devbydaniel/announcable/internal/locale"
+	"github.com/devbydaniel/announcable/internal/util"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReleaseNoteNotFound = errors.New("release note not found")
+	ErrRevisionNotFound    = errors.New("revision not found")
+	// ErrInvalidLocale is returned for translations into an unsupported language or into the same language twice
+	ErrInvalidLocale = errors.New("translation language is not supported")
+	// ErrIncompleteTranslation is returned for translations without a title or description
+	ErrIncompleteTranslation = errors.New("translations need a title and a description")
+)
+
+const (
+	// slugIndex is the unique index on the slugs of an organisation
+	slugIndex     = "release_notes_organisation_id_slug_idx"
+	slugSavePoint = "release_note_slug"
+	// inserts losing the race for a slug are retried this often
+	maxSlugAttempts = 5
 )
 
 type service struct {
@@ -10,8 +40,10 @@ type service struct {
 }
 
 var imgProcessConfig = imgUtil.ImgProcessConfig{
-	MaxWidth: 1000,
-	Quality:  80,
+	MaxWidth:      1000,
+	Quality:       80,
+	VariantWidths: []uint{320, 640, 2000},
+	Placeholder:   true,
 }
 
 func createImgPath(orgId, randomId, format string) string {
@@ -23,47 +55,88 @@ func NewService(r repository) *service {
 	return &service{repo: r}
 }
 
-func (s *service) Create(rn *ReleaseNote, imgInput *ImageInput) (uuid.UUID, error) {
+// Create stores a new release note with the given attachments
+func (s *service) Create(rn *ReleaseNote, media []*MediaInput) (uuid.UUID, error) {
 	log.Trace().Msg("Create")
-	// Start a transaction
+	if err := validateTranslations(rn); err != nil {
+		return uuid.Nil, err
+	}
+	if err := validateAudienceRules(rn); err != nil {
+		return uuid.Nil, err
+	}
+	if err := validateMedia(media); err != nil {
+		return uuid.Nil, err
+	}
+	// new notes aren't approved yet, so they can only be scheduled without a required review
+	if err := s.checkScheduleAllowed(rn, rn.PublishAt); err != nil {
+		return uuid.Nil, err
+	}
+
+	// Start a transaction, images uploaded within it are removed again on rollback
 	tx := s.repo.db.StartTransaction()
+	var uploaded []string
+	rollback := func() {
+		tx.Rollback()
+		s.discardUploads(uploaded)
+	}
 
-	// Create release note
-	id, err := s.repo.Create(rn, tx.Tx)
+	// Create release note with a permalink slug, the cover fields are derived from the attachments
+	rn.ImagePath, rn.MediaLink = "", ""
+	id, err := s.createWithUniqueSlug(rn, tx.Tx)
 	if err != nil {
 		log.Error().Err(err).Msg("Error creating release note")
 		tx.Rollback()
 		return uuid.Nil, err
 	}
 
-	// Create image
-	if imgInput != nil {
-		if imgInput.ImgData != nil {
-			processedImg, format, err := imgUtil.DecodeProcessEncode(imgInput.ImgData, &imgProcessConfig)
-			if err != nil {
-				log.Error().Err(err).Msg("Error processing image")
-				tx.Rollback()
-				return uuid.Nil, err
-			}
-			randomId, err := uuid.NewRandom()
-			if err != nil {
-				log.Error().Err(err).Msg("Error generating random UUID")
-				tx.Rollback()
-				return uuid.Nil, err
-			}
-			imgPath := createImgPath(rn.OrganisationID.String(), randomId.String(), format.String())
-			log.Debug().Str("path", imgPath).Msg("Creating image")
-			if err := s.repo.UpdateImage(id, processedImg, imgPath, tx.Tx); err != nil {
-				log.Error().Err(err).Msg("Error creating image")
-				tx.Rollback()
-				return uuid.Nil, err
-			}
-			rn.ImagePath = imgPath
+	// Attach tags
+	if rn.Tags != nil {
+		if err := s.repo.ReplaceTags(id, tagIds(rn.Tags), tx.Tx); err != nil {
+			log.Error().Err(err).Msg("Error saving tags")
+			tx.Rollback()
+			return uuid.Nil, err
 		}
 	}
 
+	// Attach translations
+	if rn.Translations != nil {
+		if err := s.repo.ReplaceTranslations(id, rn.Translations, tx.Tx); err != nil {
+			log.Error().Err(err).Msg("Error saving translations")
+			tx.Rollback()
+			return uuid.Nil, err
+		}
+	}
+
+	// Attach audience rules
+	if rn.AudienceRules != nil {
+		if err := s.repo.ReplaceAudienceRules(id, rn.AudienceRules, tx.Tx); err != nil {
+			log.Error().Err(err).Msg("Error saving audience rules")
+			tx.Rollback()
+			return uuid.Nil, err
+		}
+	}
+
+	// Attach media
+	if media != nil {
+		uploaded, _, err = s.saveMedia(id, rn.OrganisationID, media, tx.Tx)
+		if err != nil {
+			log.Error().Err(err).Msg("Error saving media")
+			rollback()
+			return uuid.Nil, err
+		}
+	}
+
+	// Record the initial revision
+	saved, err := s.saveRevision(id, RevisionActionCreated, authorOf(rn.LastUpdatedBy), tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error saving revision")
+		rollback()
+		return uuid.Nil, err
+	}
+
 	// Commit the transaction
 	tx.Commit()
+	announce(saved, RevisionActionCreated)
 	return id, nil
 }
 
@@ -86,31 +159,32 @@ func (s *service) GetAll(orgId string, page, pageSize int) (*PaginatedReleaseNot
 
 func (s *service) GetAllWithImgUrl(orgId string, page, pageSize int, filters map[string]interface{}) (*PaginatedReleaseNotes, error) {
 	log.Trace().Str("orgId", orgId).Msg("GetAllWithImgUrl")
+	filters, err := s.resolveAudience(orgId, filters)
+	if err != nil {
+		return nil, err
+	}
 	rns, err := s.repo.FindAll(orgId, page, pageSize, filters)
 	if err != nil {
 		log.Error().Err(err).Msg("Error finding release notes by organisation ID")
 		return nil, err
 	}
-	// adjust release date format and get image
+	// adjust release date format and get media URLs
 	for _, rn := range rns.Items {
 		if rn.ReleaseDate != nil {
 			rd := (*rn.ReleaseDate)[:10]
 			rn.ReleaseDate = &rd
 		}
-		if rn.ImagePath != "" {
-			imgUrl, err := s.repo.GetImageUrl(rn.ImagePath)
-			if err != nil {
-				log.Error().Err(err).Msg("Error getting image URL")
-			} else {
-				rn.ImageUrl = imgUrl
-			}
-		}
+		s.setMediaUrls(rn)
 	}
 	return rns, nil
 }
 
 func (s *service) GetStatus(orgId string, filters map[string]interface{}) ([]*ReleaseNoteStatus, error) {
 	log.Trace().Str("orgId", orgId).Msg("GetStatus")
+	filters, err := s.resolveAudience(orgId, filters)
+	if err != nil {
+		return nil, err
+	}
 	return s.repo.GetStatus(orgId, filters)
 }
 
@@ -123,11 +197,18 @@ func (s *service) GetOne(id, orgId string) (*ReleaseNote, error) {
 		return nil, err
 	}
 
-	rn, err := s.repo.FindOne(uuid)
+	rn, err := s.repo.FindOne(uuid, nil)
 	if err != nil {
 		log.Error().Err(err).Msg("Error finding release note by ID")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReleaseNoteNotFound
+		}
 		return nil, err
 	}
+	if rn.OrganisationID.String() != orgId {
+		log.Warn().Str("id", id).Str("orgId", orgId).Msg("Release note belongs to another organisation")
+		return nil, ErrReleaseNoteNotFound
+	}
 
 	// ReleaseDate includes time, so we need to format it
 	if rn.ReleaseDate != nil {
@@ -135,84 +216,615 @@ func (s *service) GetOne(id, orgId string) (*ReleaseNote, error) {
 		rn.ReleaseDate = &rd
 	}
 
-	// Get image URL from path if it exists
-	if rn.ImagePath != "" {
-		imgUrl, err := s.repo.GetImageUrl(rn.ImagePath)
-		if err != nil {
-			log.Error().Err(err).Msg("Error getting image URL")
-		} else {
-			rn.ImageUrl = imgUrl
+	s.setMediaUrls(rn)
+	return rn, nil
+}
+
+// GetPublicBySlug returns a published release note that is visible on the release page.
+// Notes targeted at an audience are not, see FilterUntargeted.
+func (s *service) GetPublicBySlug(orgId uuid.UUID, slug string) (*ReleaseNote, error) {
+	log.Trace().Str("slug", slug).Msg("GetPublicBySlug")
+	rn, err := s.repo.FindBySlug(orgId, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReleaseNoteNotFound
 		}
+		return nil, err
+	}
+	if !rn.IsPublished || rn.HideOnReleasePage {
+		return nil, ErrReleaseNoteNotFound
+	}
+	targeted, err := s.repo.HasAudienceRules(rn.ID)
+	if err != nil {
+		return nil, err
+	}
+	if targeted {
+		return nil, ErrReleaseNoteNotFound
 	}
+
+	if rn.ReleaseDate != nil {
+		rd := (*rn.ReleaseDate)[:10]
+		rn.ReleaseDate = &rd
+	}
+	s.setMediaUrls(rn)
 	return rn, nil
 }
 
-func (s *service) Update(id uuid.UUID, rn *ReleaseNote, imgInput *ImageInput) error {
-	log.Trace().Msg("UpdateWithImg")
-	// Start a transaction
+// Update saves the content of a release note. Non-nil media replace the attachments of the note.
+func (s *service) Update(id uuid.UUID, rn *ReleaseNote, media []*MediaInput) error {
+	log.Trace().Msg("Update")
+	if err := validateTranslations(rn); err != nil {
+		return err
+	}
+	if err := validateAudienceRules(rn); err != nil {
+		return err
+	}
+	if err := validateMedia(media); err != nil {
+		return err
+	}
+
+	// Start a transaction, images uploaded within it are removed again on rollback
 	tx := s.repo.db.StartTransaction()
+	var uploaded, removed []string
+	rollback := func() {
+		tx.Rollback()
+		s.discardUploads(uploaded)
+	}
 
-	var path string
-	// Update image
-	if imgInput != nil {
-		if imgInput.ShouldDeleteImage {
-			if err := s.repo.DeleteImage(id, tx.Tx); err != nil {
-				log.Error().Err(err).Msg("Error deleting image")
-				tx.Rollback()
-				return err
-			}
-		} else if imgInput.ImgData != nil {
-			processedImg, format, err := imgUtil.DecodeProcessEncode(imgInput.ImgData, &imgProcessConfig)
-			if err != nil {
-				log.Error().Err(err).Msg("Error processing image")
-				tx.Rollback()
-				return err
-			}
-			randId, err := uuid.NewRandom()
-			if err != nil {
-				log.Error().Err(err).Msg("Error generating random UUID")
-				tx.Rollback()
-				return err
-			}
-			path = createImgPath(rn.OrganisationID.String(), randId.String(), format.String())
-			log.Debug().Str("path", path).Msg("Updating image")
-			if err := s.repo.UpdateImage(id, processedImg, path, tx.Tx); err != nil {
-				log.Error().Err(err).Msg("Error updating image")
-				tx.Rollback()
-				return err
-			}
-		}
+	// Setting a publish time is a publish in advance, so it is subject to the review gate
+	current, err := s.repo.FindOne(id, tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding release note")
+		tx.Rollback()
+		return err
+	}
+	if err := s.checkScheduleAllowed(current, rn.PublishAt); err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	// Update release note data
 	log.Debug().Interface("rn", rn).Msg("Updating release note")
 	if err := s.repo.Update(id, rn, tx.Tx); err != nil {
 		log.Error().Err(err).Msg("Error updating release note")
-		if imgInput != nil && imgInput.ImgData != nil {
-			s.repo.DeleteImage(id, tx.Tx)
-		}
 		tx.Rollback()
 		return err
 	}
+	// nil tags leave the current tags untouched, an empty slice removes them
+	if rn.Tags != nil {
+		if err := s.repo.ReplaceTags(id, tagIds(rn.Tags), tx.Tx); err != nil {
+			log.Error().Err(err).Msg("Error saving tags")
+			tx.Rollback()
+			return err
+		}
+	}
+	// same for translations
+	if rn.Translations != nil {
+		if err := s.repo.ReplaceTranslations(id, rn.Translations, tx.Tx); err != nil {
+			log.Error().Err(err).Msg("Error saving translations")
+			tx.Rollback()
+			return err
+		}
+	}
+	// and audience rules
+	if rn.AudienceRules != nil {
+		if err := s.repo.ReplaceAudienceRules(id, rn.AudienceRules, tx.Tx); err != nil {
+			log.Error().Err(err).Msg("Error saving audience rules")
+			tx.Rollback()
+			return err
+		}
+	}
+	// and attachments
+	if media != nil {
+		var err error
+		uploaded, removed, err = s.saveMedia(id, rn.OrganisationID, media, tx.Tx)
+		if err != nil {
+			log.Error().Err(err).Msg("Error saving media")
+			rollback()
+			return err
+		}
+	}
+	// an approval covers the content, changing only the schedule keeps it
+	updated, err := s.repo.FindOne(id, tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding release note")
+		rollback()
+		return err
+	}
+	if contentChanged(current, updated) {
+		if err := s.repo.ResetApproval(id, tx.Tx); err != nil {
+			rollback()
+			return err
+		}
+	}
+	saved, err := s.saveRevision(id, RevisionActionUpdated, authorOf(rn.LastUpdatedBy), tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error saving revision")
+		rollback()
+		return err
+	}
 	tx.Commit()
+	s.deleteUnusedImages(removed)
+	announce(saved, RevisionActionUpdated)
 	return nil
 }
 
-func (s *service) ChangePublishedStatus(id uuid.UUID, published bool) error {
+func (s *service) ChangePublishedStatus(id uuid.UUID, published bool, userId uuid.UUID) error {
 	log.Trace().Bool("published", published).Msg("ChangePublishedStatus")
-	if err := s.repo.UpdateWithNil(id, map[string]interface{}{"IsPublished": published}, nil); err != nil {
+	tx := s.repo.db.StartTransaction()
+	if published {
+		rn, err := s.repo.FindOne(id, tx.Tx)
+		if err != nil {
+			log.Error().Err(err).Msg("Error finding release note")
+			tx.Rollback()
+			return err
+		}
+		if err := s.checkPublishAllowed(rn); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	// a manual change supersedes the pending schedule for the same transition
+	data := map[string]interface{}{"IsPublished": published, "LastUpdatedBy": userId}
+	if published {
+		data["PublishAt"] = nil
+	} else {
+		data["UnpublishAt"] = nil
+	}
+	if err := s.repo.UpdateWithNil(id, data, tx.Tx); err != nil {
 		log.Error().Err(err).Msg("Error updating published status")
+		tx.Rollback()
 		return err
 	}
+	action := RevisionActionUnpublished
+	if published {
+		action = RevisionActionPublished
+	}
+	rn, err := s.saveRevision(id, action, authorOf(userId), tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error saving revision")
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	announce(rn, action)
 	return nil
 }
 
+// ApplySchedules publishes and unpublishes all release notes whose scheduled time
+// has passed and returns the IDs of the affected organisations.
+// Schedules are consumed once applied so that manual changes afterwards stick.
+func (s *service) ApplySchedules(now time.Time) ([]uuid.UUID, error) {
+	log.Trace().Time("now", now).Msg("ApplySchedules")
+	toPublish, err := s.repo.FindDueForPublish(now)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding release notes due for publishing")
+		return nil, err
+	}
+	toUnpublish, err := s.repo.FindDueForUnpublish(now)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding release notes due for unpublishing")
+		return nil, err
+	}
+	if len(toPublish) == 0 && len(toUnpublish) == 0 {
+		return nil, nil
+	}
+
+	tx := s.repo.db.StartTransaction()
+	affected := map[uuid.UUID]bool{}
+	var events []Event
+	// publish first, so a note whose whole window has passed ends up unpublished
+	for _, rn := range toPublish {
+		// the query already leaves out unapproved notes, this guards against a review reset in between
+		if err := s.checkPublishAllowed(rn); err != nil {
+			if errors.Is(err, ErrReviewRequired) {
+				log.Warn().Str("id", rn.ID.String()).Msg("Skipping scheduled publish of unapproved release note")
+				continue
+			}
+			tx.Rollback()
+			return nil, err
+		}
+		if err := s.repo.UpdateWithNil(rn.ID, map[string]interface{}{"IsPublished": true, "PublishAt": nil}, tx.Tx); err != nil {
+			log.Error().Err(err).Str("id", rn.ID.String()).Msg("Error publishing scheduled release note")
+			tx.Rollback()
+			return nil, err
+		}
+		saved, err := s.saveRevision(rn.ID, RevisionActionPublished, nil, tx.Tx)
+		if err != nil {
+			log.Error().Err(err).Str("id", rn.ID.String()).Msg("Error saving revision")
+			tx.Rollback()
+			return nil, err
+		}
+		if e, ok := eventOf(saved, RevisionActionPublished); ok {
+			events = append(events, e)
+		}
+		affected[rn.OrganisationID] = true
+	}
+	for _, rn := range toUnpublish {
+		if err := s.repo.UpdateWithNil(rn.ID, map[string]interface{}{"IsPublished": false, "UnpublishAt": nil}, tx.Tx); err != nil {
+			log.Error().Err(err).Str("id", rn.ID.String()).Msg("Error unpublishing scheduled release note")
+			tx.Rollback()
+			return nil, err
+		}
+		saved, err := s.saveRevision(rn.ID, RevisionActionUnpublished, nil, tx.Tx)
+		if err != nil {
+			log.Error().Err(err).Str("id", rn.ID.String()).Msg("Error saving revision")
+			tx.Rollback()
+			return nil, err
+		}
+		if e, ok := eventOf(saved, RevisionActionUnpublished); ok {
+			events = append(events, e)
+		}
+		affected[rn.OrganisationID] = true
+	}
+	tx.Commit()
+	for _, e := range events {
+		notify(e)
+	}
+
+	orgIds := make([]uuid.UUID, 0, len(affected))
+	for orgId := range affected {
+		orgIds = append(orgIds, orgId)
+	}
+	log.Info().Int("published", len(toPublish)).Int("unpublished", len(toUnpublish)).Msg("Applied release note schedules")
+	return orgIds, nil
+}
+
 func (s *service) Delete(id uuid.UUID) error {
 	log.Trace().Msg("Delete")
-	return s.repo.Delete(id, nil)
+	tx := s.repo.db.StartTransaction()
+	rn, err := s.repo.FindOne(id, tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding release note")
+		tx.Rollback()
+		return err
+	}
+	// images of deleted notes are only kept as long as no other note references them
+	paths, err := s.repo.FindImagePaths(id, tx.Tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.repo.Delete(id, tx.Tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.enqueueWebhook(rn, webhook.EventReleaseNoteDeleted, tx.Tx); err != nil {
+		log.Error().Err(err).Msg("Error queueing webhook event")
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	s.deleteUnusedImages(paths)
+	if rn.IsPublished {
+		notify(Event{Type: EventDeleted, OrganisationID: rn.OrganisationID, ReleaseNoteID: rn.ID})
+	}
+	return nil
 }
 
 func (s *service) GetCount(orgID uuid.UUID) (int64, error) {
 	log.Trace().Str("orgID", orgID.String()).Msg("GetCount")
 	return s.repo.GetCount(orgID)
 }
+
+func (s *service) GetRevisions(releaseNoteId, orgId uuid.UUID) ([]*ReleaseNoteRevision, error) {
+	log.Trace().Str("releaseNoteId", releaseNoteId.String()).Msg("GetRevisions")
+	revs, err := s.repo.FindRevisions(releaseNoteId)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding revisions")
+		return nil, err
+	}
+	scoped := make([]*ReleaseNoteRevision, 0, len(revs))
+	for _, rev := range revs {
+		if rev.OrganisationID == orgId {
+			scoped = append(scoped, rev)
+		}
+	}
+	return scoped, nil
+}
+
+// GetRevisionChanges returns a revision together with the changes it introduced
+// compared to the revision before it.
+func (s *service) GetRevisionChanges(releaseNoteId, revisionId, orgId uuid.UUID) (*ReleaseNoteRevision, []*RevisionChange, error) {
+	log.Trace().Str("revisionId", revisionId.String()).Msg("GetRevisionChanges")
+	rev, err := s.getRevision(releaseNoteId, revisionId, orgId)
+	if err != nil {
+		return nil, nil, err
+	}
+	prev, err := s.repo.FindPreviousRevision(rev)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding previous revision")
+		return nil, nil, err
+	}
+	if prev == nil {
+		prev = &ReleaseNoteRevision{}
+	}
+	return rev, compareRevisions(prev, rev), nil
+}
+
+// RestoreRevision sets the content of a release note back to the state of the given revision.
+// The published state and schedule are left untouched.
+func (s *service) RestoreRevision(releaseNoteId, revisionId, orgId, userId uuid.UUID) error {
+	log.Trace().Str("revisionId", revisionId.String()).Msg("RestoreRevision")
+	rev, err := s.getRevision(releaseNoteId, revisionId, orgId)
+	if err != nil {
+		return err
+	}
+
+	tx := s.repo.db.StartTransaction()
+	data := map[string]interface{}{
+		"Title":              rev.Title,
+		"DescriptionShort":   rev.DescriptionShort,
+		"DescriptionLong":    rev.DescriptionLong,
+		"ReleaseDate":        rev.ReleaseDate,
+		"CtaLabelOverride":   rev.CtaLabelOverride,
+		"CtaUrlOverride":     rev.CtaUrlOverride,
+		"HideCta":            rev.HideCta,
+		"AttentionMechanism": rev.AttentionMechanism,
+		"HideOnWidget":       rev.HideOnWidget,
+		"HideOnReleasePage":  rev.HideOnReleasePage,
+		"LastUpdatedBy":      userId,
+	}
+	if err := s.repo.UpdateWithNil(releaseNoteId, data, tx.Tx); err != nil {
+		log.Error().Err(err).Msg("Error restoring revision")
+		tx.Rollback()
+		return err
+	}
+	removed, err := s.restoreMedia(releaseNoteId, rev, tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error restoring media")
+		tx.Rollback()
+		return err
+	}
+	if err := s.repo.ResetApproval(releaseNoteId, tx.Tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	rn, err := s.saveRevision(releaseNoteId, RevisionActionRestored, authorOf(userId), tx.Tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error saving revision")
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	s.deleteUnusedImages(removed)
+	announce(rn, RevisionActionRestored)
+	return nil
+}
+
+func (s *service) getRevision(releaseNoteId, revisionId, orgId uuid.UUID) (*ReleaseNoteRevision, error) {
+	log.Trace().Str("revisionId", revisionId.String()).Msg("getRevision")
+	rev, err := s.repo.FindRevision(revisionId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	if rev.ReleaseNoteID != releaseNoteId || rev.OrganisationID != orgId {
+		log.Warn().Str("revisionId", revisionId.String()).Msg("Revision does not belong to release note")
+		return nil, ErrRevisionNotFound
+	}
+	return rev, nil
+}
+
+// saveRevision snapshots the release note as currently stored within tx,
+// queues the webhook event of the action and returns the stored note
+func (s *service) saveRevision(id uuid.UUID, action RevisionAction, authorId *uuid.UUID, tx *gorm.DB) (*ReleaseNote, error) {
+	log.Trace().Str("action", action.String()).Msg("saveRevision")
+	rn, err := s.repo.FindOne(id, tx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error finding release note")
+		return nil, err
+	}
+	if err := s.repo.CreateRevision(newRevision(rn, action, authorId), tx); err != nil {
+		return nil, err
+	}
+	if err := s.enqueueWebhook(rn, webhookEvents[action], tx); err != nil {
+		log.Error().Err(err).Msg("Error queueing webhook event")
+		return nil, err
+	}
+	return rn, nil
+}
+
+// createWithUniqueSlug inserts the release note with a slug that is not yet taken within the
+// organisation. A note created at the same time can claim the same slug first, the insert is
+// then retried with the next free one.
+func (s *service) createWithUniqueSlug(rn *ReleaseNote, tx *gorm.DB) (uuid.UUID, error) {
+	for attempt := 1; ; attempt++ {
+		slug, err := s.uniqueSlug(rn.OrganisationID, rn.Title, tx)
+		if err != nil {
+			log.Error().Err(err).Msg("Error generating slug")
+			return uuid.Nil, err
+		}
+		rn.Slug = slug
+		// a failed insert aborts the transaction, the savepoint keeps it usable for the retry
+		if err := tx.SavePoint(slugSavePoint).Error; err != nil {
+			return uuid.Nil, err
+		}
+		id, err := s.repo.Create(rn, tx)
+		if err == nil || !isSlugConflict(err) || attempt == maxSlugAttempts {
+			return id, err
+		}
+		log.Debug().Str("slug", slug).Msg("Slug was taken concurrently, retrying")
+		if err := tx.RollbackTo(slugSavePoint).Error; err != nil {
+			return uuid.Nil, err
+		}
+	}
+}
+
+// isSlugConflict reports whether err is a violation of the unique index on the slugs of an organisation
+func isSlugConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == slugIndex
+}
+
+// uniqueSlug derives a slug from the title that is not yet taken within the organisation
+func (s *service) uniqueSlug(orgId uuid.UUID, title string, tx *gorm.DB) (string, error) {
+	base := util.Slugify(title)
+	if base == "" {
+		base = "release-note"
+	}
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := s.repo.SlugExists(orgId, slug, tx)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+}
+
+// Localize replaces the content of the given release notes with their translation into
+// lang. Notes without a translation keep their content in the default language.
+func (s *service) Localize(rns []*ReleaseNote, lang string) error {
+	log.Trace().Str("lang", lang).Int("count", len(rns)).Msg("Localize")
+	ids := make([]uuid.UUID, len(rns))
+	for i, rn := range rns {
+		ids[i] = rn.ID
+	}
+	translations, err := s.repo.FindTranslations(ids, lang)
+	if err != nil {
+		return err
+	}
+	byNote := make(map[uuid.UUID]*ReleaseNoteTranslation, len(translations))
+	for _, t := range translations {
+		byNote[t.ReleaseNoteID] = t
+	}
+	for _, rn := range rns {
+		if t, ok := byNote[rn.ID]; ok {
+			rn.translate(t)
+		}
+	}
+	return nil
+}
+
+// GetLocales returns the languages published release notes of an organisation are translated into
+func (s *service) GetLocales(orgId uuid.UUID) ([]string, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("GetLocales")
+	return s.repo.FindLocales(orgId)
+}
+
+// validateTranslations trims the translations of a note, drops empty ones and checks that the
+// rest name a supported language once and are complete. Nil translations stay nil.
+func validateTranslations(rn *ReleaseNote) error {
+	if rn.Translations == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(rn.Translations))
+	filled := []*ReleaseNoteTranslation{}
+	for _, t := range rn.Translations {
+		t.Title = strings.TrimSpace(t.Title)
+		t.DescriptionShort = strings.TrimSpace(t.DescriptionShort)
+		t.DescriptionLong = strings.TrimSpace(t.DescriptionLong)
+		if !locale.IsSupported(t.Locale) || seen[t.Locale] {
+			return ErrInvalidLocale
+		}
+		seen[t.Locale] = true
+		if t.IsEmpty() {
+			continue
+		}
+		if t.Title == "" || t.DescriptionShort == "" {
+			return ErrIncompleteTranslation
+		}
+		filled = append(filled, t)
+	}
+	rn.Translations = filled
+	return nil
+}
+
+// tagIds returns the IDs of the given tags
+func tagIds(tags []*tag.Tag) []uuid.UUID {
+	ids := make([]uuid.UUID, len(tags))
+	for i, t := range tags {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// authorOf returns nil for the zero UUID so that no author is referenced
+func authorOf(userId uuid.UUID) *uuid.UUID {
+	if userId == uuid.Nil {
+		return nil
+	}
+	return &userId
+}
+
+// contentChanged reports whether anything but the schedule differs between two states of a note
+func contentChanged(prev, curr *ReleaseNote) bool {
+	for _, change := range compareRevisions(newRevision(prev, "", nil), newRevision(curr, "", nil)) {
+		if change.Field != "Publish at" && change.Field != "Unpublish at" {
+			return true
+		}
+	}
+	prevTags, currTags := make([]string, len(prev.Tags)), make([]string, len(curr.Tags))
+	for i, t := range prev.Tags {
+		prevTags[i] = t.ID.String()
+	}
+	for i, t := range curr.Tags {
+		currTags[i] = t.ID.String()
+	}
+	sort.Strings(prevTags)
+	sort.Strings(currTags)
+	return !slices.Equal(prevTags, currTags) ||
+		!slices.EqualFunc(prev.Translations, curr.Translations, func(a, b *ReleaseNoteTranslation) bool {
+			return a.Locale == b.Locale && a.Title == b.Title && a.DescriptionShort == b.DescriptionShort && a.DescriptionLong == b.DescriptionLong
+		}) ||
+		!slices.EqualFunc(prev.AudienceRules, curr.AudienceRules, func(a, b *AudienceRule) bool {
+			return a.Attribute == b.Attribute && a.Operator == b.Operator && a.Value == b.Value
+		})
+}
+
+// compareRevisions lists the fields that differ between two revisions
+func compareRevisions(prev, curr *ReleaseNoteRevision) []*RevisionChange {
+	formatDate := func(d *string) string {
+		if d == nil || len(*d) < 10 {
+			return ""
+		}
+		return (*d)[:10]
+	}
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format("02.01.2006 15:04") + " UTC"
+	}
+	formatBool := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+
+	var changes []*RevisionChange
+	addText := func(field, old, new string) {
+		if old != new {
+			changes = append(changes, &RevisionChange{Field: field, OldValue: old, NewValue: new, TextDiff: util.DiffWords(old, new)})
+		}
+	}
+	add := func(field, old, new string) {
+		if old != new {
+			changes = append(changes, &RevisionChange{Field: field, OldValue: old, NewValue: new})
+		}
+	}
+
+	addText("Title", prev.Title, curr.Title)
+	addText("Description", prev.DescriptionShort, curr.DescriptionShort)
+	addText("Description (Website)", prev.DescriptionLong, curr.DescriptionLong)
+	add("Release date", formatDate(prev.ReleaseDate), formatDate(curr.ReleaseDate))
+	add("Media", describeMedia(prev.Media), describeMedia(curr.Media))
+	add("Published", formatBool(prev.IsPublished), formatBool(curr.IsPublished))
+	add("Call to action label", prev.CtaLabelOverride, curr.CtaLabelOverride)
+	add("Call to action link", prev.CtaUrlOverride, curr.CtaUrlOverride)
+	add("Hide call to action", formatBool(prev.HideCta), formatBool(curr.HideCta))
+	add("Attention mechanism", prev.AttentionMechanism.String(), curr.AttentionMechanism.String())
+	add("Hide on widget", formatBool(prev.HideOnWidget), formatBool(curr.HideOnWidget))
+	add("Hide on release page", formatBool(prev.HideOnReleasePage), formatBool(curr.HideOnReleasePage))
+	add("Publish at", formatTime(prev.PublishAt), formatTime(curr.PublishAt))
+	add("Unpublish at", formatTime(prev.UnpublishAt), formatTime(curr.UnpublishAt))
+	log.Debug().Int("changes", len(changes)).Msg("Compared revisions")
+	return changes
+}
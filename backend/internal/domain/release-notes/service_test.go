@@ -0,0 +1,256 @@
+package releasenotes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrateReleaseNoteTables creates the tables the service writes to when notes are created,
+// updated and published
+func migrateReleaseNoteTables(t *testing.T, db *database.DB) {
+	t.Helper()
+	require.NoError(t, db.Client.AutoMigrate(
+		&organisation.Organisation{},
+		&tag.Tag{},
+		&ReleaseNote{},
+		&ReleaseNoteTag{},
+		&ReleaseNoteTranslation{},
+		&AudienceRule{},
+		&ReleaseNoteRevision{},
+		&ReleaseNoteMedia{},
+		&ReleaseNoteRevisionMedia{},
+		&ReleaseNotePreview{},
+		&ReleaseNoteReview{},
+		&webhook.WebhookEndpoint{},
+		&webhook.WebhookDelivery{},
+	))
+}
+
+// setupService starts a database with the release note tables and returns a service on it,
+// its object store mock and an organisation
+func setupService(t *testing.T) (*service, *testutil.MockObjStore, *database.DB, *organisation.Organisation) {
+	t.Helper()
+	cleanup := testutil.SetupTest()
+	t.Cleanup(cleanup)
+
+	testDB := testutil.SetupTestDB(t)
+	t.Cleanup(func() { testDB.Cleanup(t) })
+	migrateReleaseNoteTables(t, testDB.DB)
+
+	org, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	require.NoError(t, testDB.DB.Client.Create(org).Error)
+
+	objStore := testutil.NewMockObjStore()
+	return NewService(*NewRepository(testDB.DB, objStore)), objStore, testDB.DB, org
+}
+
+func testPNG(t *testing.T, width, height int) *bytes.Buffer {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return &buf
+}
+
+func TestUpdateKeepsImageVariants(t *testing.T) {
+	s, objStore, db, org := setupService(t)
+	userId := uuid.New()
+
+	id, err := s.Create(&ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "With image",
+		DescriptionShort: "Description",
+		CreatedBy:        userId,
+		LastUpdatedBy:    userId,
+	}, []*MediaInput{{Kind: MediaKindImage, ImgData: testPNG(t, 1200, 600)}})
+	require.NoError(t, err)
+
+	created, err := s.repo.FindMedia(id, db.Client)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, []int{320, 640}, created[0].Variants)
+	assert.Equal(t, 1000, created[0].Width)
+	assert.NotEmpty(t, created[0].Placeholder)
+	uploads := len(objStore.UpdateImageCalls)
+
+	// re-save the note keeping the image, only its alt text changes
+	err = s.Update(id, &ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "With image",
+		DescriptionShort: "Description",
+		LastUpdatedBy:    userId,
+	}, []*MediaInput{{ID: created[0].ID, Kind: MediaKindImage, AltText: "Screenshot"}})
+	require.NoError(t, err)
+
+	updated, err := s.repo.FindMedia(id, db.Client)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, created[0].Path, updated[0].Path)
+	assert.Equal(t, created[0].Variants, updated[0].Variants)
+	assert.Equal(t, created[0].Width, updated[0].Width)
+	assert.Equal(t, created[0].Height, updated[0].Height)
+	assert.Equal(t, created[0].Placeholder, updated[0].Placeholder)
+	assert.Equal(t, "Screenshot", updated[0].AltText)
+	assert.Len(t, objStore.UpdateImageCalls, uploads, "kept images must not be uploaded again")
+	assert.Empty(t, objStore.DeleteImageCalls)
+
+	rn, err := s.GetOne(id.String(), org.ID.String())
+	require.NoError(t, err)
+	require.Len(t, rn.Media, 1)
+	assert.Contains(t, rn.Media[0].SrcSet, " 320w, ")
+	assert.NotEmpty(t, rn.Media[0].PlaceholderStyle())
+}
+
+func TestScheduledPublishRequiresReview(t *testing.T) {
+	s, _, db, org := setupService(t)
+	userId := uuid.New()
+	require.NoError(t, db.Client.Model(org).Update("require_review", true).Error)
+
+	past := time.Now().Add(-time.Hour).UTC()
+	_, err := s.Create(&ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "Scheduled on create",
+		DescriptionShort: "Description",
+		PublishAt:        &past,
+		CreatedBy:        userId,
+		LastUpdatedBy:    userId,
+	}, nil)
+	assert.ErrorIs(t, err, ErrReviewRequired)
+
+	id, err := s.Create(&ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "Unapproved",
+		DescriptionShort: "Description",
+		CreatedBy:        userId,
+		LastUpdatedBy:    userId,
+	}, nil)
+	require.NoError(t, err)
+
+	err = s.Update(id, &ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "Unapproved",
+		DescriptionShort: "Description",
+		PublishAt:        &past,
+		LastUpdatedBy:    userId,
+	}, nil)
+	assert.ErrorIs(t, err, ErrReviewRequired)
+
+	// a schedule set before reviews were required must not publish the note either
+	require.NoError(t, db.Client.Model(&ReleaseNote{}).Where("id = ?", id).Update("publish_at", past).Error)
+	_, err = s.ApplySchedules(time.Now())
+	require.NoError(t, err)
+
+	rn, err := s.repo.FindOne(id, nil)
+	require.NoError(t, err)
+	assert.False(t, rn.IsPublished)
+	assert.NotNil(t, rn.PublishAt, "the schedule stays pending until the note is approved")
+}
+
+func TestScheduledPublishKeepsApproval(t *testing.T) {
+	s, _, db, org := setupService(t)
+	userId := uuid.New()
+	require.NoError(t, db.Client.Model(org).Update("require_review", true).Error)
+
+	id, err := s.Create(&ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "Approved",
+		DescriptionShort: "Description",
+		CreatedBy:        userId,
+		LastUpdatedBy:    userId,
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Client.Model(&ReleaseNote{}).Where("id = ?", id).Update("review_status", ReviewStatusApproved).Error)
+
+	// scheduling an approved note leaves its content and so its approval untouched
+	past := time.Now().Add(-time.Hour).UTC()
+	require.NoError(t, s.Update(id, &ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "Approved",
+		DescriptionShort: "Description",
+		PublishAt:        &past,
+		LastUpdatedBy:    userId,
+	}, nil))
+	rn, err := s.repo.FindOne(id, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ReviewStatusApproved, rn.ReviewStatus)
+
+	orgIds, err := s.ApplySchedules(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{org.ID}, orgIds)
+	rn, err = s.repo.FindOne(id, nil)
+	require.NoError(t, err)
+	assert.True(t, rn.IsPublished)
+
+	// changing the content needs a new review
+	require.NoError(t, s.Update(id, &ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "Changed after approval",
+		DescriptionShort: "Description",
+		LastUpdatedBy:    userId,
+	}, nil))
+	rn, err = s.repo.FindOne(id, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ReviewStatusDraft, rn.ReviewStatus)
+}
+
+func TestCreateRetriesSlugTakenConcurrently(t *testing.T) {
+	s, _, db, org := setupService(t)
+	userId := uuid.New()
+	require.NoError(t, db.Client.Exec("CREATE UNIQUE INDEX "+slugIndex+" ON release_notes(organisation_id, slug) WHERE deleted_at IS NULL").Error)
+
+	// another request inserted a note with the same slug but hasn't committed yet, so the
+	// slug looks free and the insert waits for the other transaction
+	other := db.Client.Begin()
+	require.NoError(t, other.Create(&ReleaseNote{
+		OrganisationID: org.ID,
+		Title:          "Same title",
+		Slug:           "same-title",
+		CreatedBy:      userId,
+		LastUpdatedBy:  userId,
+	}).Error)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		other.Commit()
+	}()
+
+	id, err := s.Create(&ReleaseNote{
+		OrganisationID:   org.ID,
+		Title:            "Same title",
+		DescriptionShort: "Description",
+		CreatedBy:        userId,
+		LastUpdatedBy:    userId,
+	}, nil)
+	require.NoError(t, err)
+	rn, err := s.repo.FindOne(id, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "same-title-2", rn.Slug)
+}
+
+func TestIsSlugConflict(t *testing.T) {
+	assert.True(t, isSlugConflict(fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: slugIndex})))
+	assert.False(t, isSlugConflict(&pgconn.PgError{Code: "23505", ConstraintName: "release_notes_pkey"}))
+	assert.False(t, isSlugConflict(&pgconn.PgError{Code: "23503", ConstraintName: slugIndex}))
+	assert.False(t, isSlugConflict(errors.New("connection reset")))
+}
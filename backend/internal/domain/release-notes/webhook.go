@@ -0,0 +1,83 @@
+package releasenotes
+
+import (
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// webhookEvents maps revision actions to the webhook event they trigger
+var webhookEvents = map[RevisionAction]webhook.Event{
+	RevisionActionCreated:     webhook.EventReleaseNoteCreated,
+	RevisionActionUpdated:     webhook.EventReleaseNoteUpdated,
+	RevisionActionRestored:    webhook.EventReleaseNoteUpdated,
+	RevisionActionPublished:   webhook.EventReleaseNotePublished,
+	RevisionActionUnpublished: webhook.EventReleaseNoteUnpublished,
+}
+
+// webhookData is the release note as sent in webhook payloads
+type webhookData struct {
+	ID                 uuid.UUID  `json:"id"`
+	OrganisationID     uuid.UUID  `json:"organisation_id"`
+	Title              string     `json:"title"`
+	Slug               string     `json:"slug"`
+	Tags               []string   `json:"tags"`
+	DescriptionShort   string     `json:"description_short"`
+	DescriptionLong    string     `json:"description_long"`
+	ReleaseDate        *string    `json:"release_date"`
+	MediaLink          string     `json:"media_link"`
+	CtaLabelOverride   string     `json:"cta_label_override"`
+	CtaUrlOverride     string     `json:"cta_url_override"`
+	HideCta            bool       `json:"hide_cta"`
+	AttentionMechanism string     `json:"attention_mechanism"`
+	IsPublished        bool       `json:"is_published"`
+	HideOnWidget       bool       `json:"hide_on_widget"`
+	HideOnReleasePage  bool       `json:"hide_on_release_page"`
+	PublishAt          *time.Time `json:"publish_at"`
+	UnpublishAt        *time.Time `json:"unpublish_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+func newWebhookData(rn *ReleaseNote) *webhookData {
+	var releaseDate *string
+	if rn.ReleaseDate != nil && len(*rn.ReleaseDate) >= 10 {
+		rd := (*rn.ReleaseDate)[:10]
+		releaseDate = &rd
+	}
+	tags := make([]string, len(rn.Tags))
+	for i, t := range rn.Tags {
+		tags[i] = t.Name
+	}
+	return &webhookData{
+		ID:                 rn.ID,
+		OrganisationID:     rn.OrganisationID,
+		Title:              rn.Title,
+		Slug:               rn.Slug,
+		Tags:               tags,
+		DescriptionShort:   rn.DescriptionShort,
+		DescriptionLong:    rn.DescriptionLong,
+		ReleaseDate:        releaseDate,
+		MediaLink:          rn.MediaLink,
+		CtaLabelOverride:   rn.CtaLabelOverride,
+		CtaUrlOverride:     rn.CtaUrlOverride,
+		HideCta:            rn.HideCta,
+		AttentionMechanism: rn.AttentionMechanism.String(),
+		IsPublished:        rn.IsPublished,
+		HideOnWidget:       rn.HideOnWidget,
+		HideOnReleasePage:  rn.HideOnReleasePage,
+		PublishAt:          rn.PublishAt,
+		UnpublishAt:        rn.UnpublishAt,
+		CreatedAt:          rn.CreatedAt,
+		UpdatedAt:          rn.UpdatedAt,
+	}
+}
+
+// enqueueWebhook queues the event for the organisation's webhook endpoints within tx
+func (s *service) enqueueWebhook(rn *ReleaseNote, event webhook.Event, tx *gorm.DB) error {
+	log.Trace().Str("event", event.String()).Msg("enqueueWebhook")
+	webhookService := webhook.NewService(*webhook.NewRepository(s.repo.db))
+	return webhookService.Enqueue(rn.OrganisationID, event, newWebhookData(rn), tx)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/devbydaniel/announcable/config"
 	"github.com/devbydaniel/announcable/internal/imgUtil"
 	"github.com/devbydaniel/announcable/internal/util"
+	"github.com/devbydaniel/announcable/internal/widgetcache"
 	"github.com/google/uuid"
 )
 
@@ -162,13 +163,14 @@ func (s *service) Update(orgId uuid.UUID, cfg *ReleasePageConfig, imgInput *Imag
 		return err
 	}
 	tx.Commit()
+	widgetcache.Invalidate(orgId)
 	return nil
 }
 
 func (s *service) UpdateSlug(orgId uuid.UUID, orgName string) error {
 	log.Trace().Str("orgId", orgId.String()).Str("orgName", orgName).Msg("UpdateSlug")
 	slug := s.formatSlug(orgName)
-	return s.repo.UpdateWithNil(orgId, map[string]interface{}{"Slug": slug}, nil)
+	return s.updateWithNil(orgId, map[string]interface{}{"Slug": slug})
 }
 
 func (s *service) EditSlugAsAdmin(orgId uuid.UUID, slug string) error {
@@ -193,12 +195,22 @@ func (s *service) EditSlugAsAdmin(orgId uuid.UUID, slug string) error {
 		return errors.New("Slug is already in use by another organization")
 	}
 
-	return s.repo.UpdateWithNil(orgId, map[string]interface{}{"Slug": slug}, nil)
+	return s.updateWithNil(orgId, map[string]interface{}{"Slug": slug})
 }
 
 func (s *service) UpdateDisableReleasePage(orgId uuid.UUID, disabled bool) error {
 	log.Trace().Str("orgId", orgId.String()).Bool("disabled", disabled).Msg("UpdateDisableReleasePage")
-	return s.repo.UpdateWithNil(orgId, map[string]interface{}{"DisableReleasePage": disabled}, nil)
+	return s.updateWithNil(orgId, map[string]interface{}{"DisableReleasePage": disabled})
+}
+
+// updateWithNil updates the given fields and drops the cached widget data, whose
+// permalinks and release page URL depend on the config
+func (s *service) updateWithNil(orgId uuid.UUID, data map[string]interface{}) error {
+	if err := s.repo.UpdateWithNil(orgId, data, nil); err != nil {
+		return err
+	}
+	widgetcache.Invalidate(orgId)
+	return nil
 }
 
 func (s *service) formatSlug(orgName string) string {
@@ -0,0 +1,21 @@
+package tag
+
+import (
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	// maximum length of a tag name
+	maxNameLength = 32
+	// colour of tags created without one
+	DefaultColor = "#6b7280"
+)
+
+// Tag is an organisation-defined label for classifying release notes, e.g. "New" or "Fix"
+type Tag struct {
+	database.BaseModel `gorm:"embedded"`
+	OrganisationID     uuid.UUID `gorm:"type:uuid;not null"`
+	Name               string    `gorm:"type:varchar(64);not null"`
+	Color              string    `gorm:"type:varchar(7);not null"`
+}
@@ -0,0 +1,111 @@
+package tag
+
+import (
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *database.DB
+}
+
+func NewRepository(db *database.DB) *repository {
+	log.Trace().Msg("NewRepository")
+	return &repository{db: db}
+}
+
+func (r *repository) Create(t *Tag) error {
+	log.Trace().Str("orgId", t.OrganisationID.String()).Msg("Create")
+	if err := r.db.Client.Create(t).Error; err != nil {
+		log.Error().Err(err).Msg("Error creating tag")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindAll(orgId uuid.UUID) ([]*Tag, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("FindAll")
+	var tags []*Tag
+	if err := r.db.Client.Where("organisation_id = ?", orgId).Order("lower(name) asc").Find(&tags).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding tags")
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *repository) FindByIds(ids []uuid.UUID, orgId uuid.UUID) ([]*Tag, error) {
+	log.Trace().Int("count", len(ids)).Msg("FindByIds")
+	var tags []*Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	if err := r.db.Client.Where("id IN ? AND organisation_id = ?", ids, orgId).Find(&tags).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding tags by id")
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *repository) FindOne(id, orgId uuid.UUID) (*Tag, error) {
+	log.Trace().Str("id", id.String()).Msg("FindOne")
+	var t Tag
+	if err := r.db.Client.Where("id = ? AND organisation_id = ?", id, orgId).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// NameExists reports whether another tag of the organisation has the name, ignoring case
+func (r *repository) NameExists(orgId uuid.UUID, name string, excludeId uuid.UUID) (bool, error) {
+	log.Trace().Str("name", name).Msg("NameExists")
+	var count int64
+	if err := r.db.Client.Model(&Tag{}).
+		Where("organisation_id = ? AND lower(name) = lower(?) AND id <> ?", orgId, name, excludeId).
+		Count(&count).Error; err != nil {
+		log.Error().Err(err).Msg("Error checking tag name")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *repository) Update(id uuid.UUID, data map[string]interface{}) error {
+	log.Trace().Str("id", id.String()).Msg("Update")
+	if err := r.db.Client.Model(&Tag{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		log.Error().Err(err).Msg("Error updating tag")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) Delete(id, orgId uuid.UUID, tx *gorm.DB) (int64, error) {
+	log.Trace().Str("id", id.String()).Msg("Delete")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	res := client.Where("id = ? AND organisation_id = ?", id, orgId).Delete(&Tag{})
+	if res.Error != nil {
+		log.Error().Err(res.Error).Msg("Error deleting tag")
+		return 0, res.Error
+	}
+	return res.RowsAffected, nil
+}
+
+// DetachFromReleaseNotes removes the tag from all release notes
+func (r *repository) DetachFromReleaseNotes(id uuid.UUID, tx *gorm.DB) error {
+	log.Trace().Str("id", id.String()).Msg("DetachFromReleaseNotes")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Exec("DELETE FROM release_note_tags WHERE tag_id = ?", id).Error; err != nil {
+		log.Error().Err(err).Msg("Error detaching tag from release notes")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package tag
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/devbydaniel/announcable/internal/widgetcache"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTagNotFound  = errors.New("tag not found")
+	ErrInvalidName  = errors.New("tag name must be between 1 and 32 characters")
+	ErrInvalidColor = errors.New("tag color must be a hex color like #1f2937")
+	ErrNameTaken    = errors.New("a tag with this name already exists")
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type service struct {
+	repo repository
+}
+
+func NewService(r repository) *service {
+	log.Trace().Msg("NewService")
+	return &service{repo: r}
+}
+
+func (s *service) Create(orgId uuid.UUID, name, color string) (*Tag, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("Create")
+	name, color, err := s.validate(orgId, uuid.Nil, name, color)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tag{OrganisationID: orgId, Name: name, Color: color}
+	if err := s.repo.Create(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *service) GetAll(orgId uuid.UUID) ([]*Tag, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("GetAll")
+	return s.repo.FindAll(orgId)
+}
+
+// Resolve returns the tags of the organisation for IDs submitted in a form, skipping unknown or malformed IDs.
+// The result is never nil, so it can be assigned to a release note to replace its tags.
+func (s *service) Resolve(orgId uuid.UUID, ids []string) ([]*Tag, error) {
+	log.Trace().Int("count", len(ids)).Msg("Resolve")
+	parsed := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		tagId, err := uuid.Parse(id)
+		if err != nil {
+			log.Debug().Str("id", id).Msg("Skipping malformed tag ID")
+			continue
+		}
+		parsed = append(parsed, tagId)
+	}
+	tags, err := s.repo.FindByIds(parsed, orgId)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []*Tag{}
+	}
+	return tags, nil
+}
+
+func (s *service) Update(id, orgId uuid.UUID, name, color string) error {
+	log.Trace().Str("id", id.String()).Msg("Update")
+	if _, err := s.repo.FindOne(id, orgId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTagNotFound
+		}
+		log.Error().Err(err).Msg("Error finding tag")
+		return err
+	}
+	name, color, err := s.validate(orgId, id, name, color)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Update(id, map[string]interface{}{
+		"Name":  name,
+		"Color": color,
+	}); err != nil {
+		return err
+	}
+	// tags are shown on the release notes served to the widget
+	widgetcache.Invalidate(orgId)
+	return nil
+}
+
+func (s *service) Delete(id, orgId uuid.UUID) error {
+	log.Trace().Str("id", id.String()).Msg("Delete")
+	tx := s.repo.db.StartTransaction()
+
+	deleted, err := s.repo.Delete(id, orgId, tx.Tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if deleted == 0 {
+		tx.Rollback()
+		return ErrTagNotFound
+	}
+	if err := s.repo.DetachFromReleaseNotes(id, tx.Tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	tx.Commit()
+	widgetcache.Invalidate(orgId)
+	return nil
+}
+
+// validate normalises name and color and checks that the name is free within the organisation
+func (s *service) validate(orgId, id uuid.UUID, name, color string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return "", "", ErrInvalidName
+	}
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "" {
+		color = DefaultColor
+	}
+	if !colorPattern.MatchString(color) {
+		return "", "", ErrInvalidColor
+	}
+	exists, err := s.repo.NameExists(orgId, name, id)
+	if err != nil {
+		return "", "", err
+	}
+	if exists {
+		return "", "", ErrNameTaken
+	}
+	return name, color, nil
+}
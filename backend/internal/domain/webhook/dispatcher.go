@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// a delivery is given up after this many failed attempts
+	maxAttempts    = 8
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = 6 * time.Hour
+	// time a claimed delivery is hidden from other dispatchers while it is sent
+	claimLease      = 2 * time.Minute
+	claimBatchSize  = 20
+	deliveryTimeout = 10 * time.Second
+	// only the start of the response body is kept for the delivery log
+	maxResponseBodyLength = 1024
+)
+
+const (
+	HeaderEvent     = "X-Announcable-Event"
+	HeaderDelivery  = "X-Announcable-Delivery"
+	HeaderTimestamp = "X-Announcable-Timestamp"
+	HeaderSignature = "X-Announcable-Signature"
+)
+
+// Dispatcher periodically sends due webhook deliveries.
+type Dispatcher struct {
+	service  *service
+	client   *http.Client
+	interval time.Duration
+}
+
+// NewDispatcher creates a dispatcher that checks for due deliveries every interval.
+func NewDispatcher(s *service, interval time.Duration) *Dispatcher {
+	log.Trace().Msg("NewDispatcher")
+	return &Dispatcher{
+		service:  s,
+		client:   newDeliveryClient(),
+		interval: interval,
+	}
+}
+
+// Run sends due deliveries until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	log.Info().Dur("interval", d.interval).Msg("Webhook dispatcher started")
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	d.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+			d.tick(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) tick(ctx context.Context) {
+	log.Trace().Msg("tick")
+	now := time.Now()
+	deliveries, err := d.service.repo.ClaimDueDeliveries(now, now.Add(claimLease), claimBatchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Error claiming webhook deliveries")
+		return
+	}
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			// unsent deliveries are picked up again after the lease
+			return
+		}
+		var res *attemptResult
+		if delivery.Endpoint.ID == uuid.Nil || !delivery.Endpoint.IsActive {
+			res = &attemptResult{Error: "endpoint was removed or disabled", Permanent: true}
+		} else {
+			res = deliver(ctx, d.client, delivery, time.Now())
+		}
+		if err := d.service.recordAttempt(delivery, res, time.Now()); err != nil {
+			log.Error().Err(err).Str("id", delivery.ID.String()).Msg("Error recording webhook delivery attempt")
+		}
+	}
+}
+
+type attemptResult struct {
+	StatusCode *int
+	Body       string
+	Error      string
+	// Permanent failures are not retried
+	Permanent bool
+}
+
+func (r *attemptResult) succeeded() bool {
+	return r.StatusCode != nil && *r.StatusCode >= 200 && *r.StatusCode < 300
+}
+
+// deliver sends a single delivery to its endpoint
+func deliver(ctx context.Context, client *http.Client, d *WebhookDelivery, now time.Time) *attemptResult {
+	log.Trace().Str("id", d.ID.String()).Msg("deliver")
+	body := []byte(d.Payload)
+	timestamp := now.Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return &attemptResult{Error: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Announcable-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, d.Event.String())
+	req.Header.Set(HeaderDelivery, d.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Endpoint.Secret, timestamp, body))
+
+	res, err := client.Do(req)
+	if err != nil {
+		log.Warn().Err(err).Str("id", d.ID.String()).Msg("Webhook delivery failed")
+		return &attemptResult{Error: err.Error()}
+	}
+	defer res.Body.Close()
+	resBody, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBodyLength))
+
+	result := &attemptResult{StatusCode: &res.StatusCode, Body: string(resBody)}
+	if !result.succeeded() {
+		result.Error = "endpoint responded with " + res.Status
+		log.Warn().Int("status", res.StatusCode).Str("id", d.ID.String()).Msg("Webhook delivery rejected")
+	}
+	return result
+}
+
+// Sign computes the signature header value of a payload.
+// Receivers recompute the HMAC-SHA256 of "<timestamp>.<body>" with the endpoint
+// secret and compare it to the X-Announcable-Signature header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryDelay returns the exponential backoff before the next attempt
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestDeliver tests a delivery against a local receiver that verifies the signature
+func TestDeliver(t *testing.T) {
+	const secret = "whsec_test"
+	endpoint := &WebhookEndpoint{URL: "", Secret: secret, IsActive: true}
+	delivery, err := newDelivery(endpoint, EventReleaseNotePublished, map[string]string{"id": "rn"})
+	if err != nil {
+		t.Fatalf("newDelivery() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		status      int
+		wantSuccess bool
+	}{
+		{name: "accepted", status: http.StatusOK, wantSuccess: true},
+		{name: "no content", status: http.StatusNoContent, wantSuccess: true},
+		{name: "server error", status: http.StatusInternalServerError, wantSuccess: false},
+		{name: "gone", status: http.StatusGone, wantSuccess: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSignatureValid bool
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+				gotSignatureValid = r.Header.Get(HeaderSignature) == Sign(secret, timestamp, body) &&
+					r.Header.Get(HeaderEvent) == EventReleaseNotePublished.String() &&
+					r.Header.Get(HeaderDelivery) == delivery.ID.String() &&
+					string(body) == delivery.Payload
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			endpoint.URL = receiver.URL
+			delivery.Endpoint = *endpoint
+			res := deliver(context.Background(), receiver.Client(), delivery, time.Now())
+
+			if !gotSignatureValid {
+				t.Error("receiver got an invalid signature or headers")
+			}
+			if res.succeeded() != tt.wantSuccess {
+				t.Errorf("succeeded() = %v, want %v (error %q)", res.succeeded(), tt.wantSuccess, res.Error)
+			}
+			if res.StatusCode == nil || *res.StatusCode != tt.status {
+				t.Errorf("StatusCode = %v, want %d", res.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+// TestDeliver_Unreachable tests that connection errors are reported as failed attempts
+func TestDeliver_Unreachable(t *testing.T) {
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	url := receiver.URL
+	receiver.Close()
+
+	delivery := &WebhookDelivery{Endpoint: WebhookEndpoint{URL: url}, Payload: "{}"}
+	delivery.ID = uuid.New()
+	res := deliver(context.Background(), http.DefaultClient, delivery, time.Now())
+	if res.succeeded() || res.Error == "" || res.StatusCode != nil {
+		t.Errorf("deliver() = %+v, want a failed attempt without status", res)
+	}
+}
+
+// TestSign tests that signatures depend on secret, timestamp and body
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"ping"}`)
+	sig := Sign("secret", 1700000000, body)
+	if sig != Sign("secret", 1700000000, body) {
+		t.Error("Sign() is not deterministic")
+	}
+	if sig == Sign("other", 1700000000, body) {
+		t.Error("Sign() ignores the secret")
+	}
+	if sig == Sign("secret", 1700000001, body) {
+		t.Error("Sign() ignores the timestamp")
+	}
+	if sig == Sign("secret", 1700000000, []byte(`{"event":"pong"}`)) {
+		t.Error("Sign() ignores the body")
+	}
+}
+
+// TestRetryDelay tests the exponential backoff between attempts
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: 30 * time.Second},
+		{attempts: 2, expected: time.Minute},
+		{attempts: 3, expected: 2 * time.Minute},
+		{attempts: 7, expected: 32 * time.Minute},
+		{attempts: 20, expected: maxRetryDelay},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.expected {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.expected)
+		}
+	}
+}
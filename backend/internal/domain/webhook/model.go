@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/google/uuid"
+)
+
+type Event string
+
+const (
+	EventReleaseNoteCreated     Event = "release_note.created"
+	EventReleaseNoteUpdated     Event = "release_note.updated"
+	EventReleaseNotePublished   Event = "release_note.published"
+	EventReleaseNoteUnpublished Event = "release_note.unpublished"
+	EventReleaseNoteDeleted     Event = "release_note.deleted"
+	// EventPing is only sent when a test delivery is requested
+	EventPing Event = "ping"
+)
+
+// Events lists the events an endpoint can subscribe to
+var Events = []Event{
+	EventReleaseNoteCreated,
+	EventReleaseNoteUpdated,
+	EventReleaseNotePublished,
+	EventReleaseNoteUnpublished,
+	EventReleaseNoteDeleted,
+}
+
+func (e Event) String() string {
+	return string(e)
+}
+
+func (e Event) IsValid() bool {
+	return slices.Contains(Events, e)
+}
+
+type WebhookEndpoint struct {
+	database.BaseModel `gorm:"embedded"`
+	OrganisationID     uuid.UUID `gorm:"type:uuid;not null"`
+	Organisation       organisation.Organisation
+	URL                string `gorm:"type:varchar(2048)"`
+	Secret             string `gorm:"type:varchar(128)"`
+	Events             string `gorm:"type:varchar(255)"` // comma separated list of events
+	IsActive           bool   `gorm:"default:true"`
+}
+
+// EventList returns the events the endpoint is subscribed to
+func (e *WebhookEndpoint) EventList() []Event {
+	var events []Event
+	for _, ev := range strings.Split(e.Events, ",") {
+		if ev != "" {
+			events = append(events, Event(ev))
+		}
+	}
+	return events
+}
+
+// Subscribes reports whether the endpoint should receive the event
+func (e *WebhookEndpoint) Subscribes(event Event) bool {
+	return slices.Contains(e.EventList(), event)
+}
+
+func joinEvents(events []Event) string {
+	s := make([]string, len(events))
+	for i, ev := range events {
+		s[i] = ev.String()
+	}
+	return strings.Join(s, ",")
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+func (s DeliveryStatus) String() string {
+	return string(s)
+}
+
+type WebhookDelivery struct {
+	database.BaseModel `gorm:"embedded"`
+	EndpointID         uuid.UUID       `gorm:"type:uuid;not null"`
+	Endpoint           WebhookEndpoint `gorm:"foreignKey:EndpointID"`
+	OrganisationID     uuid.UUID       `gorm:"type:uuid;not null"`
+	Event              Event           `gorm:"type:varchar(64)"`
+	Payload            string          `gorm:"type:text"`
+	Status             DeliveryStatus  `gorm:"type:varchar(16);default:pending"`
+	Attempts           int
+	NextAttemptAt      *time.Time `gorm:"type:timestamptz;default:null"`
+	LastAttemptAt      *time.Time `gorm:"type:timestamptz;default:null"`
+	ResponseStatus     *int
+	ResponseBody       string `gorm:"type:text"`
+	LastError          string `gorm:"type:text"`
+}
+
+// payload is the JSON body sent to endpoints
+type payload struct {
+	ID        uuid.UUID   `json:"id"`
+	Event     Event       `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *database.DB
+}
+
+func NewRepository(db *database.DB) *repository {
+	log.Trace().Msg("NewRepository")
+	return &repository{db: db}
+}
+
+func (r *repository) CreateEndpoint(endpoint *WebhookEndpoint) error {
+	log.Trace().Str("orgId", endpoint.OrganisationID.String()).Msg("CreateEndpoint")
+	if err := r.db.Client.Create(endpoint).Error; err != nil {
+		log.Error().Err(err).Msg("Error creating webhook endpoint")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindEndpoints(orgId uuid.UUID, tx *gorm.DB) ([]*WebhookEndpoint, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("FindEndpoints")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	var endpoints []*WebhookEndpoint
+	if err := client.Where("organisation_id = ?", orgId).Order("created_at asc").Find(&endpoints).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding webhook endpoints")
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (r *repository) FindEndpoint(id, orgId uuid.UUID) (*WebhookEndpoint, error) {
+	log.Trace().Str("id", id.String()).Msg("FindEndpoint")
+	var endpoint WebhookEndpoint
+	if err := r.db.Client.Where("id = ? AND organisation_id = ?", id, orgId).First(&endpoint).Error; err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (r *repository) UpdateEndpoint(id uuid.UUID, data map[string]interface{}) error {
+	log.Trace().Str("id", id.String()).Msg("UpdateEndpoint")
+	if err := r.db.Client.Model(&WebhookEndpoint{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		log.Error().Err(err).Msg("Error updating webhook endpoint")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) DeleteEndpoint(id, orgId uuid.UUID) (int64, error) {
+	log.Trace().Str("id", id.String()).Msg("DeleteEndpoint")
+	res := r.db.Client.Where("id = ? AND organisation_id = ?", id, orgId).Delete(&WebhookEndpoint{})
+	if res.Error != nil {
+		log.Error().Err(res.Error).Msg("Error deleting webhook endpoint")
+		return 0, res.Error
+	}
+	return res.RowsAffected, nil
+}
+
+func (r *repository) CreateDeliveries(deliveries []*WebhookDelivery, tx *gorm.DB) error {
+	log.Trace().Int("count", len(deliveries)).Msg("CreateDeliveries")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Omit("Endpoint").Create(deliveries).Error; err != nil {
+		log.Error().Err(err).Msg("Error creating webhook deliveries")
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindDeliveries(endpointId uuid.UUID, limit int) ([]*WebhookDelivery, error) {
+	log.Trace().Str("endpointId", endpointId.String()).Msg("FindDeliveries")
+	var deliveries []*WebhookDelivery
+	if err := r.db.Client.
+		Where("endpoint_id = ?", endpointId).
+		Order("created_at desc").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding webhook deliveries")
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *repository) FindDelivery(id, orgId uuid.UUID) (*WebhookDelivery, error) {
+	log.Trace().Str("id", id.String()).Msg("FindDelivery")
+	var delivery WebhookDelivery
+	if err := r.db.Client.Where("id = ? AND organisation_id = ?", id, orgId).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ClaimDueDeliveries locks pending deliveries that are due and pushes their next attempt
+// to leaseUntil, so that other dispatchers skip them while they are being sent.
+// Deliveries whose dispatcher dies are picked up again once the lease has expired.
+func (r *repository) ClaimDueDeliveries(now, leaseUntil time.Time, limit int) ([]*WebhookDelivery, error) {
+	log.Trace().Time("now", now).Msg("ClaimDueDeliveries")
+	var deliveries []*WebhookDelivery
+	tx := r.db.StartTransaction()
+	if err := tx.Tx.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", DeliveryStatusPending, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		log.Error().Err(err).Msg("Error claiming webhook deliveries")
+		tx.Rollback()
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		tx.Rollback()
+		return nil, nil
+	}
+	ids := make([]uuid.UUID, len(deliveries))
+	for i, d := range deliveries {
+		ids[i] = d.ID
+	}
+	if err := tx.Tx.Model(&WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", leaseUntil).Error; err != nil {
+		log.Error().Err(err).Msg("Error claiming webhook deliveries")
+		tx.Rollback()
+		return nil, err
+	}
+	tx.Commit()
+
+	// load the endpoints outside of the locking transaction
+	var claimed []*WebhookDelivery
+	if err := r.db.Client.Preload("Endpoint").Where("id IN ?", ids).Find(&claimed).Error; err != nil {
+		log.Error().Err(err).Msg("Error loading claimed webhook deliveries")
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (r *repository) UpdateDelivery(id uuid.UUID, data map[string]interface{}) error {
+	log.Trace().Str("id", id.String()).Msg("UpdateDelivery")
+	if err := r.db.Client.Model(&WebhookDelivery{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		log.Error().Err(err).Msg("Error updating webhook delivery")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/random"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	secretPrefix = "whsec_"
+	// number of deliveries shown in the delivery log of an endpoint
+	deliveryLogSize = 50
+)
+
+var (
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidURL       = errors.New("webhook URL must be an absolute http or https URL")
+	ErrPrivateTarget    = errors.New("webhook URL must not point to a local or private network address")
+	ErrInvalidEvents    = errors.New("select at least one valid event")
+)
+
+type service struct {
+	repo repository
+}
+
+func NewService(r repository) *service {
+	log.Trace().Msg("NewService")
+	return &service{repo: r}
+}
+
+func (s *service) CreateEndpoint(orgId uuid.UUID, endpointUrl string, events []Event) (*WebhookEndpoint, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("CreateEndpoint")
+	endpointUrl, err := validateURL(endpointUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateEvents(events); err != nil {
+		return nil, err
+	}
+	endpoint := &WebhookEndpoint{
+		OrganisationID: orgId,
+		URL:            endpointUrl,
+		Secret:         createSecret(),
+		Events:         joinEvents(events),
+		IsActive:       true,
+	}
+	if err := s.repo.CreateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *service) GetEndpoints(orgId uuid.UUID) ([]*WebhookEndpoint, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("GetEndpoints")
+	return s.repo.FindEndpoints(orgId, nil)
+}
+
+func (s *service) GetEndpoint(id, orgId uuid.UUID) (*WebhookEndpoint, error) {
+	log.Trace().Str("id", id.String()).Msg("GetEndpoint")
+	endpoint, err := s.repo.FindEndpoint(id, orgId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEndpointNotFound
+		}
+		log.Error().Err(err).Msg("Error finding webhook endpoint")
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *service) UpdateEndpoint(id, orgId uuid.UUID, endpointUrl string, events []Event, isActive bool) error {
+	log.Trace().Str("id", id.String()).Msg("UpdateEndpoint")
+	if _, err := s.GetEndpoint(id, orgId); err != nil {
+		return err
+	}
+	endpointUrl, err := validateURL(endpointUrl)
+	if err != nil {
+		return err
+	}
+	if err := validateEvents(events); err != nil {
+		return err
+	}
+	return s.repo.UpdateEndpoint(id, map[string]interface{}{
+		"URL":      endpointUrl,
+		"Events":   joinEvents(events),
+		"IsActive": isActive,
+	})
+}
+
+// RotateSecret replaces the signing secret of an endpoint
+func (s *service) RotateSecret(id, orgId uuid.UUID) error {
+	log.Trace().Str("id", id.String()).Msg("RotateSecret")
+	if _, err := s.GetEndpoint(id, orgId); err != nil {
+		return err
+	}
+	return s.repo.UpdateEndpoint(id, map[string]interface{}{"Secret": createSecret()})
+}
+
+func (s *service) DeleteEndpoint(id, orgId uuid.UUID) error {
+	log.Trace().Str("id", id.String()).Msg("DeleteEndpoint")
+	deleted, err := s.repo.DeleteEndpoint(id, orgId)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrEndpointNotFound
+	}
+	return nil
+}
+
+// GetDeliveries returns the most recent deliveries of an endpoint
+func (s *service) GetDeliveries(endpointId, orgId uuid.UUID) ([]*WebhookDelivery, error) {
+	log.Trace().Str("endpointId", endpointId.String()).Msg("GetDeliveries")
+	if _, err := s.GetEndpoint(endpointId, orgId); err != nil {
+		return nil, err
+	}
+	return s.repo.FindDeliveries(endpointId, deliveryLogSize)
+}
+
+// Enqueue queues a delivery of the event to every active endpoint of the organisation
+// that subscribes to it. Passing the transaction of the change that caused the event
+// makes sure that deliveries are only queued if the change is committed.
+func (s *service) Enqueue(orgId uuid.UUID, event Event, data interface{}, tx *gorm.DB) error {
+	log.Trace().Str("orgId", orgId.String()).Str("event", event.String()).Msg("Enqueue")
+	endpoints, err := s.repo.FindEndpoints(orgId, tx)
+	if err != nil {
+		return err
+	}
+	var deliveries []*WebhookDelivery
+	for _, endpoint := range endpoints {
+		if !endpoint.IsActive || !endpoint.Subscribes(event) {
+			continue
+		}
+		delivery, err := newDelivery(endpoint, event, data)
+		if err != nil {
+			log.Error().Err(err).Msg("Error creating webhook payload")
+			return err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return s.repo.CreateDeliveries(deliveries, tx)
+}
+
+// SendTest queues a ping delivery to the endpoint, regardless of its event filter
+func (s *service) SendTest(id, orgId uuid.UUID) error {
+	log.Trace().Str("id", id.String()).Msg("SendTest")
+	endpoint, err := s.GetEndpoint(id, orgId)
+	if err != nil {
+		return err
+	}
+	delivery, err := newDelivery(endpoint, EventPing, map[string]string{"endpoint_id": endpoint.ID.String()})
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating webhook payload")
+		return err
+	}
+	return s.repo.CreateDeliveries([]*WebhookDelivery{delivery}, nil)
+}
+
+// Redeliver queues a delivery again with a fresh set of attempts
+func (s *service) Redeliver(id, orgId uuid.UUID) error {
+	log.Trace().Str("id", id.String()).Msg("Redeliver")
+	if _, err := s.repo.FindDelivery(id, orgId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDeliveryNotFound
+		}
+		log.Error().Err(err).Msg("Error finding webhook delivery")
+		return err
+	}
+	return s.repo.UpdateDelivery(id, map[string]interface{}{
+		"Status":        DeliveryStatusPending,
+		"Attempts":      0,
+		"NextAttemptAt": time.Now(),
+	})
+}
+
+// recordAttempt stores the outcome of a delivery attempt and schedules a retry if needed
+func (s *service) recordAttempt(d *WebhookDelivery, res *attemptResult, now time.Time) error {
+	log.Trace().Str("id", d.ID.String()).Msg("recordAttempt")
+	attempts := d.Attempts + 1
+	data := map[string]interface{}{
+		"Attempts":       attempts,
+		"LastAttemptAt":  now,
+		"ResponseStatus": res.StatusCode,
+		"ResponseBody":   res.Body,
+		"LastError":      res.Error,
+	}
+	switch {
+	case res.succeeded():
+		data["Status"] = DeliveryStatusSucceeded
+		data["NextAttemptAt"] = nil
+	case res.Permanent || attempts >= maxAttempts:
+		data["Status"] = DeliveryStatusFailed
+		data["NextAttemptAt"] = nil
+	default:
+		data["NextAttemptAt"] = now.Add(retryDelay(attempts))
+	}
+	return s.repo.UpdateDelivery(d.ID, data)
+}
+
+func newDelivery(endpoint *WebhookEndpoint, event Event, data interface{}) (*WebhookDelivery, error) {
+	id := uuid.New()
+	now := time.Now().UTC()
+	body, err := json.Marshal(payload{ID: id, Event: event, CreatedAt: now, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	d := &WebhookDelivery{
+		EndpointID:     endpoint.ID,
+		OrganisationID: endpoint.OrganisationID,
+		Event:          event,
+		Payload:        string(body),
+		Status:         DeliveryStatusPending,
+		NextAttemptAt:  &now,
+	}
+	d.ID = id
+	return d, nil
+}
+
+func createSecret() string {
+	return secretPrefix + strings.ToLower(random.CreateRandomToken())
+}
+
+func validateURL(endpointUrl string) (string, error) {
+	endpointUrl = strings.TrimSpace(endpointUrl)
+	u, err := url.Parse(endpointUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", ErrInvalidURL
+	}
+	if !privateTargetsAllowed() {
+		if err := checkHost(u.Hostname()); err != nil {
+			return "", err
+		}
+	}
+	return endpointUrl, nil
+}
+
+func validateEvents(events []Event) error {
+	if len(events) == 0 {
+		return ErrInvalidEvents
+	}
+	for _, ev := range events {
+		if !ev.IsValid() {
+			return ErrInvalidEvents
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/devbydaniel/announcable/config"
+)
+
+// privateTargetsAllowed reports whether endpoints may point to loopback, link-local and private
+// addresses. Self-hosted instances can allow it to send webhooks to services on their network.
+func privateTargetsAllowed() bool {
+	return config.New().WebhookAllowPrivateTargets
+}
+
+// isPublicIP reports whether ip may be the target of a webhook on the public internet
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast()
+}
+
+// checkHost resolves the host of an endpoint URL and returns ErrPrivateTarget if any of its
+// addresses is not public
+func checkHost(host string) error {
+	ips, err := net.LookupIP(host)
+	if err != nil || len(ips) == 0 {
+		log.Debug().Err(err).Str("host", host).Msg("Error resolving webhook host")
+		return ErrInvalidURL
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return ErrPrivateTarget
+		}
+	}
+	return nil
+}
+
+// checkDialAddress rejects connections to addresses that are not public. It runs after DNS
+// resolution, so hosts resolving to other addresses than when the endpoint was saved and
+// redirects are covered as well.
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return ErrPrivateTarget
+	}
+	return nil
+}
+
+// newDeliveryClient returns the HTTP client deliveries are sent with
+func newDeliveryClient() *http.Client {
+	if privateTargetsAllowed() {
+		return &http.Client{Timeout: deliveryTimeout}
+	}
+	dialer := &net.Dialer{Timeout: deliveryTimeout, Control: checkDialAddress}
+	return &http.Client{
+		Timeout: deliveryTimeout,
+		// no proxy, the check must see the address of the endpoint
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: deliveryTimeout,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
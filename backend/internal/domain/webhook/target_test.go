@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.216.34", want: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{ip: "127.0.0.1"},
+		{ip: "::1"},
+		{ip: "10.1.2.3"},
+		{ip: "172.16.0.1"},
+		{ip: "192.168.1.1"},
+		{ip: "fd00::1"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "0.0.0.0"},
+		{ip: "::"},
+		{ip: "::ffff:127.0.0.1"},
+		{ip: "224.0.0.1"},
+	}
+	for _, tt := range tests {
+		if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false")
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{url: "https://93.184.216.34/hooks"},
+		{url: "  https://93.184.216.34:8443/hooks  "},
+		{url: "ftp://93.184.216.34/hooks", wantErr: ErrInvalidURL},
+		{url: "/hooks", wantErr: ErrInvalidURL},
+		{url: "http://:8080/hooks", wantErr: ErrInvalidURL},
+		{url: "http://127.0.0.1:8080/hooks", wantErr: ErrPrivateTarget},
+		{url: "http://localhost/hooks", wantErr: ErrPrivateTarget},
+		{url: "http://[::1]/hooks", wantErr: ErrPrivateTarget},
+		{url: "http://10.0.0.5/hooks", wantErr: ErrPrivateTarget},
+		{url: "http://169.254.169.254/latest/meta-data", wantErr: ErrPrivateTarget},
+		{url: "http://0.0.0.0/hooks", wantErr: ErrPrivateTarget},
+	}
+	for _, tt := range tests {
+		_, err := validateURL(tt.url)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("validateURL(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+		}
+	}
+
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "true")
+	if _, err := validateURL("http://127.0.0.1:8080/hooks"); err != nil {
+		t.Errorf("validateURL() with private targets allowed error = %v", err)
+	}
+}
+
+// TestDeliveryClientRejectsPrivateTargets checks the dial-time check, which also covers
+// hosts that resolve to a private address only after the endpoint was saved
+func TestDeliveryClientRejectsPrivateTargets(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false")
+	if _, err := newDeliveryClient().Get(receiver.URL); !errors.Is(err, ErrPrivateTarget) {
+		t.Errorf("Get() error = %v, want %v", err, ErrPrivateTarget)
+	}
+
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "true")
+	res, err := newDeliveryClient().Get(receiver.URL)
+	if err != nil {
+		t.Fatalf("Get() with private targets allowed error = %v", err)
+	}
+	res.Body.Close()
+}
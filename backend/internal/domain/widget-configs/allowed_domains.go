@@ -0,0 +1,95 @@
+package widgetconfigs
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxAllowedDomains limits the number of allowed domains of a widget config
+const maxAllowedDomains = 50
+
+// ErrInvalidDomain is returned for allowed domains that are not a host name, or for too many of them
+var ErrInvalidDomain = errors.New("invalid domain")
+
+// domainPattern matches lowercase host names, optionally starting with a "*." wildcard
+var domainPattern = regexp.MustCompile(`^(\*\.)?[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// ParseAllowedDomains normalises a list of domains separated by newlines, commas or spaces.
+// Entries may be given as URLs, of which only the host name is kept, so ports and paths are
+// ignored. A leading "*." allows all subdomains. Duplicates are dropped.
+func ParseAllowedDomains(raw string) ([]string, error) {
+	domains := []string{}
+	seen := make(map[string]bool)
+	for _, entry := range strings.FieldsFunc(raw, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ',' || r == ' ' || r == '\t'
+	}) {
+		domain := hostname(entry)
+		if !domainPattern.MatchString(domain) {
+			return nil, ErrInvalidDomain
+		}
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+	if len(domains) > maxAllowedDomains {
+		return nil, ErrInvalidDomain
+	}
+	return domains, nil
+}
+
+// AllowedDomainList returns the domains the widget API serves data of the organisation to.
+// An empty list allows all domains.
+func (cfg *WidgetConfig) AllowedDomainList() []string {
+	var domains []string
+	for _, d := range strings.Split(cfg.AllowedDomains, "\n") {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// IsOriginAllowed reports whether a request from origin, an Origin header value or a URL like
+// the Referer, may be served given the allowed domains. Any origin is allowed if there are no
+// allowed domains. "*.example.com" matches the subdomains of example.com, not example.com itself.
+func IsOriginAllowed(domains []string, origin string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	host := hostname(origin)
+	if host == "" || host == "null" {
+		return false
+	}
+	for _, d := range domains {
+		if suffix, ok := strings.CutPrefix(d, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == d {
+			return true
+		}
+	}
+	return false
+}
+
+// hostname returns the lowercase host name of a URL or of a bare host with optional port and path
+func hostname(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ""
+	}
+	if !strings.Contains(s, "://") {
+		s = "//" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Hostname(), ".")
+}
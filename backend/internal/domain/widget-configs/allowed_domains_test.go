@@ -0,0 +1,100 @@
+package widgetconfigs
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAllowedDomains(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "empty", input: " \n ", expected: []string{}},
+		{name: "one per line", input: "example.com\napp.example.com\r\n", expected: []string{"example.com", "app.example.com"}},
+		{name: "commas and spaces", input: "example.com, example.org  example.net", expected: []string{"example.com", "example.org", "example.net"}},
+		{name: "urls keep the host", input: "https://App.Example.com/changelog?x=1\nhttp://localhost:3000", expected: []string{"app.example.com", "localhost"}},
+		{name: "wildcard", input: "*.example.com", expected: []string{"*.example.com"}},
+		{name: "duplicates", input: "example.com\nEXAMPLE.com\nhttps://example.com/", expected: []string{"example.com"}},
+		{name: "trailing dot", input: "example.com.", expected: []string{"example.com"}},
+		{name: "wildcard in the middle", input: "app.*.example.com", wantErr: true},
+		{name: "bare wildcard", input: "*", wantErr: true},
+		{name: "invalid characters", input: "exa_mple.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAllowedDomains(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDomain) {
+					t.Fatalf("ParseAllowedDomains(%q) error = %v, want ErrInvalidDomain", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAllowedDomains(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseAllowedDomains(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	var many []string
+	for i := 0; i <= maxAllowedDomains; i++ {
+		many = append(many, strings.Repeat("a", i+1)+".example.com")
+	}
+	if _, err := ParseAllowedDomains(strings.Join(many, "\n")); !errors.Is(err, ErrInvalidDomain) {
+		t.Errorf("ParseAllowedDomains() with %d domains error = %v, want ErrInvalidDomain", len(many), err)
+	}
+}
+
+func TestIsOriginAllowed(t *testing.T) {
+	domains := []string{"example.com", "*.example.org", "localhost"}
+
+	tests := []struct {
+		name     string
+		domains  []string
+		origin   string
+		expected bool
+	}{
+		{name: "no restriction", domains: nil, origin: "https://anywhere.test", expected: true},
+		{name: "no restriction without origin", domains: nil, origin: "", expected: true},
+		{name: "exact match", domains: domains, origin: "https://example.com", expected: true},
+		{name: "match ignores case and port", domains: domains, origin: "http://EXAMPLE.com:8080", expected: true},
+		{name: "referer url", domains: domains, origin: "https://example.com/pricing?plan=pro", expected: true},
+		{name: "subdomain without wildcard", domains: domains, origin: "https://app.example.com", expected: false},
+		{name: "wildcard subdomain", domains: domains, origin: "https://app.example.org", expected: true},
+		{name: "wildcard nested subdomain", domains: domains, origin: "https://eu.app.example.org", expected: true},
+		{name: "wildcard excludes apex", domains: domains, origin: "https://example.org", expected: false},
+		{name: "suffix of another domain", domains: domains, origin: "https://notexample.com", expected: false},
+		{name: "wildcard suffix of another domain", domains: domains, origin: "https://evilexample.org", expected: false},
+		{name: "localhost with port", domains: domains, origin: "http://localhost:5173", expected: true},
+		{name: "other domain", domains: domains, origin: "https://example.net", expected: false},
+		{name: "null origin", domains: domains, origin: "null", expected: false},
+		{name: "missing origin", domains: domains, origin: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOriginAllowed(tt.domains, tt.origin); got != tt.expected {
+				t.Errorf("IsOriginAllowed(%v, %q) = %v, want %v", tt.domains, tt.origin, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAllowedDomainList(t *testing.T) {
+	cfg := &WidgetConfig{AllowedDomains: "example.com\n\n *.example.org \n"}
+	expected := []string{"example.com", "*.example.org"}
+	if got := cfg.AllowedDomainList(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("AllowedDomainList() = %v, want %v", got, expected)
+	}
+	if got := (&WidgetConfig{}).AllowedDomainList(); len(got) != 0 {
+		t.Errorf("AllowedDomainList() of an empty config = %v, want none", got)
+	}
+}
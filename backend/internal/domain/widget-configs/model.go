@@ -28,6 +28,47 @@ type WidgetConfig struct {
 	EnableLikes             bool       `gorm:"type:boolean;default:true"`
 	LikeButtonText          string     `gorm:"type:varchar(255);default:'Like'"`
 	UnlikeButtonText        string     `gorm:"type:varchar(255);default:'Unlike'"`
+	// newline separated domains the widget API serves data to, empty allows all (see AllowedDomainList)
+	AllowedDomains string `gorm:"type:text;not null;default:''"`
+	// space separated emoji end users can react with besides liking, empty offers none (see ReactionList)
+	Reactions string `gorm:"type:text;not null;default:''"`
+	// lets widget and release page visitors leave feedback on release notes
+	EnableFeedback bool `gorm:"type:boolean;not null;default:false"`
+}
+
+// WidgetConfigTranslation holds the widget texts in another language than the organisation's
+// default. Empty fields fall back to the default language.
+type WidgetConfigTranslation struct {
+	OrganisationID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Locale             string    `gorm:"type:varchar(8);primaryKey"`
+	Title              string    `gorm:"type:varchar(255)"`
+	Description        string    `gorm:"type:text"`
+	ReleaseNoteCtaText string    `gorm:"type:varchar(255)"`
+	LikeButtonText     string    `gorm:"type:varchar(255)"`
+	UnlikeButtonText   string    `gorm:"type:varchar(255)"`
+}
+
+// IsEmpty reports whether none of the texts are translated
+func (t *WidgetConfigTranslation) IsEmpty() bool {
+	return t.Title == "" && t.Description == "" && t.ReleaseNoteCtaText == "" && t.LikeButtonText == "" && t.UnlikeButtonText == ""
+}
+
+// translate replaces the texts of the config with the translated ones
+func (cfg *WidgetConfig) translate(t *WidgetConfigTranslation) {
+	for _, field := range []struct {
+		dst *string
+		src string
+	}{
+		{&cfg.Title, t.Title},
+		{&cfg.Description, t.Description},
+		{&cfg.ReleaseNoteCtaText, t.ReleaseNoteCtaText},
+		{&cfg.LikeButtonText, t.LikeButtonText},
+		{&cfg.UnlikeButtonText, t.UnlikeButtonText},
+	} {
+		if field.src != "" {
+			*field.dst = field.src
+		}
+	}
 }
 
 type WidgetType string
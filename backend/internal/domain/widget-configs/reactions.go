@@ -0,0 +1,70 @@
+package widgetconfigs
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxReactions limits the number of reactions offered by a widget config
+	maxReactions = 8
+	// maxReactionLength is the maximum length of a reaction in bytes, long enough for emoji
+	// sequences like flags or skin tones
+	maxReactionLength = 32
+)
+
+// ErrInvalidReaction is returned for reactions that are not emoji, or for too many of them
+var ErrInvalidReaction = errors.New("invalid reaction")
+
+// ParseReactions splits a space separated list of emoji into the reactions offered in the
+// widget. Letters, digits and punctuation are rejected, duplicates are dropped.
+func ParseReactions(raw string) ([]string, error) {
+	reactions := []string{}
+	seen := make(map[string]bool)
+	for _, reaction := range strings.FieldsFunc(raw, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	}) {
+		if !isEmoji(reaction) {
+			return nil, ErrInvalidReaction
+		}
+		if seen[reaction] {
+			continue
+		}
+		seen[reaction] = true
+		reactions = append(reactions, reaction)
+	}
+	if len(reactions) > maxReactions {
+		return nil, ErrInvalidReaction
+	}
+	return reactions, nil
+}
+
+// ReactionList returns the reactions offered in the widget, in the configured order
+func (cfg *WidgetConfig) ReactionList() []string {
+	return strings.Fields(cfg.Reactions)
+}
+
+// HasReaction reports whether end users can react with reaction
+func (cfg *WidgetConfig) HasReaction(reaction string) bool {
+	for _, r := range cfg.ReactionList() {
+		if r == reaction {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmoji roughly checks that s is a single emoji: short and without ASCII characters, letters
+// or digits. Emoji sequences with joiners and variation selectors are accepted.
+func isEmoji(s string) bool {
+	if s == "" || len(s) > maxReactionLength {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x80 || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || r == unicode.ReplacementChar {
+			return false
+		}
+	}
+	return true
+}
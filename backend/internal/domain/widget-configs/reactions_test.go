@@ -0,0 +1,57 @@
+package widgetconfigs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseReactions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "empty", input: "  ", expected: []string{}},
+		{name: "spaces and commas", input: "🎉 👍,👀\n", expected: []string{"🎉", "👍", "👀"}},
+		{name: "duplicates", input: "🎉 🎉 👍", expected: []string{"🎉", "👍"}},
+		{name: "sequences", input: "👍🏽 ❤️ 🇩🇪 👩‍💻", expected: []string{"👍🏽", "❤️", "🇩🇪", "👩‍💻"}},
+		{name: "text", input: "🎉 yay", wantErr: true},
+		{name: "emoji with text", input: "🎉yay", wantErr: true},
+		{name: "non-latin letters", input: "ä", wantErr: true},
+		{name: "too long", input: "🎉🎉🎉🎉🎉🎉🎉🎉🎉", wantErr: true},
+		{name: "too many", input: "😀 😁 😂 😃 😄 😅 😆 😇 😈", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReactions(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidReaction) {
+					t.Fatalf("ParseReactions(%q) error = %v, want ErrInvalidReaction", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReactions(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseReactions(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasReaction(t *testing.T) {
+	cfg := &WidgetConfig{Reactions: "🎉 👍"}
+	if !cfg.HasReaction("👍") {
+		t.Error("HasReaction(👍) = false, want true")
+	}
+	if cfg.HasReaction("👀") {
+		t.Error("HasReaction(👀) = true, want false")
+	}
+	if cfg.HasReaction("") {
+		t.Error("HasReaction(\"\") = true, want false")
+	}
+}
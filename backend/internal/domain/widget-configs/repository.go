@@ -3,6 +3,7 @@ package widgetconfigs
 import (
 	"github.com/devbydaniel/announcable/internal/database"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type repository struct {
@@ -46,6 +47,9 @@ func (r *repository) Update(orgId uuid.UUID, cfg *WidgetConfig) error {
 		"EnableLikes",
 		"LikeButtonText",
 		"UnlikeButtonText",
+		"AllowedDomains",
+		"Reactions",
+		"EnableFeedback",
 	).Where("organisation_id = ?", orgId).Updates(cfg).Error; err != nil {
 		log.Error().Err(err).Msg("Error updating widget config")
 		return err
@@ -73,3 +77,48 @@ func (r *repository) Get(orgId uuid.UUID) (*WidgetConfig, error) {
 	}
 	return &cfg, nil
 }
+
+func (r *repository) FindTranslations(orgId uuid.UUID) ([]*WidgetConfigTranslation, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("FindTranslations")
+	var translations []*WidgetConfigTranslation
+	if err := r.db.Client.Where("organisation_id = ?", orgId).Order("locale asc").Find(&translations).Error; err != nil {
+		log.Error().Err(err).Msg("Error finding widget config translations")
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *repository) FindTranslation(orgId uuid.UUID, locale string) (*WidgetConfigTranslation, error) {
+	log.Trace().Str("orgId", orgId.String()).Str("locale", locale).Msg("FindTranslation")
+	var t WidgetConfigTranslation
+	if err := r.db.Client.Where("organisation_id = ? AND locale = ?", orgId, locale).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ReplaceTranslations sets the translations of an organisation's widget config to the given translations
+func (r *repository) ReplaceTranslations(orgId uuid.UUID, translations []*WidgetConfigTranslation, tx *gorm.DB) error {
+	log.Trace().Str("orgId", orgId.String()).Int("count", len(translations)).Msg("ReplaceTranslations")
+	var client *gorm.DB
+	if tx != nil {
+		client = tx
+	} else {
+		client = r.db.Client
+	}
+	if err := client.Where("organisation_id = ?", orgId).Delete(&WidgetConfigTranslation{}).Error; err != nil {
+		log.Error().Err(err).Msg("Error removing widget config translations")
+		return err
+	}
+	if len(translations) == 0 {
+		return nil
+	}
+	for _, t := range translations {
+		t.OrganisationID = orgId
+	}
+	if err := client.Create(translations).Error; err != nil {
+		log.Error().Err(err).Msg("Error adding widget config translations")
+		return err
+	}
+	return nil
+}
@@ -1,9 +1,18 @@
 package widgetconfigs
 
 import (
+	"errors"
+	"strings"
+
+	"github.com/devbydaniel/announcable/internal/locale"
+	"github.com/devbydaniel/announcable/internal/widgetcache"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// ErrInvalidLocale is returned for translations into an unsupported language or into the same language twice
+var ErrInvalidLocale = errors.New("translation language is not supported")
+
 type service struct {
 	repo repository
 }
@@ -65,6 +74,7 @@ func (s *service) UpdateBaseUrl(orgId uuid.UUID, baseUrl *string) error {
 		log.Error().Err(err).Msg("Error updating base URL")
 		return err
 	}
+	widgetcache.Invalidate(orgId)
 	return nil
 }
 
@@ -84,5 +94,60 @@ func (s *service) Update(orgId uuid.UUID, cfg *WidgetConfig) error {
 		log.Error().Err(err).Msg("Error updating widget config")
 		return err
 	}
+	widgetcache.Invalidate(orgId)
+	return nil
+}
+
+func (s *service) GetTranslations(orgId uuid.UUID) ([]*WidgetConfigTranslation, error) {
+	log.Trace().Str("orgId", orgId.String()).Msg("GetTranslations")
+	return s.repo.FindTranslations(orgId)
+}
+
+// GetLocalized returns the widget config with its texts translated into lang where a
+// translation exists
+func (s *service) GetLocalized(orgId uuid.UUID, lang string) (*WidgetConfig, error) {
+	log.Trace().Str("orgId", orgId.String()).Str("lang", lang).Msg("GetLocalized")
+	cfg, err := s.Get(orgId)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.repo.FindTranslation(orgId, lang)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return cfg, nil
+		}
+		log.Error().Err(err).Msg("Error finding widget config translation")
+		return nil, err
+	}
+	cfg.translate(t)
+	return cfg, nil
+}
+
+// UpdateTranslations replaces the translations of the widget texts. Translations without any
+// text are dropped.
+func (s *service) UpdateTranslations(orgId uuid.UUID, translations []*WidgetConfigTranslation) error {
+	log.Trace().Str("orgId", orgId.String()).Int("count", len(translations)).Msg("UpdateTranslations")
+	seen := make(map[string]bool, len(translations))
+	var filled []*WidgetConfigTranslation
+	for _, t := range translations {
+		if !locale.IsSupported(t.Locale) || seen[t.Locale] {
+			return ErrInvalidLocale
+		}
+		seen[t.Locale] = true
+		for _, field := range []*string{&t.Title, &t.Description, &t.ReleaseNoteCtaText, &t.LikeButtonText, &t.UnlikeButtonText} {
+			*field = strings.TrimSpace(*field)
+		}
+		if !t.IsEmpty() {
+			filled = append(filled, t)
+		}
+	}
+
+	tx := s.repo.db.StartTransaction()
+	if err := s.repo.ReplaceTranslations(orgId, filled, tx.Tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	widgetcache.Invalidate(orgId)
 	return nil
 }
@@ -27,6 +27,27 @@ type UserInviteConfig struct {
 	ActionURL        string
 }
 
+type FeedbackNotificationConfig struct {
+	To               string
+	OrganisationName string
+	ReleaseNoteTitle string
+	Text             string
+	Email            string
+	ActionURL        string
+}
+
+// ReviewNotificationConfig describes a step of a release note review. Action is one of
+// "submitted", "approved" and "changes_requested".
+type ReviewNotificationConfig struct {
+	To               string
+	OrganisationName string
+	ReleaseNoteTitle string
+	Action           string
+	ActorEmail       string
+	Comment          string
+	ActionURL        string
+}
+
 var cfg = config.New()
 
 func SendPasswordReset(c *PasswordResetConfig) error {
@@ -66,6 +87,48 @@ func SendUserInvite(c *UserInviteConfig) error {
 	return sendEmail(c.To, "You're Invited to "+c.OrganisationName, userInviteTmpl, data)
 }
 
+func SendFeedbackNotification(c *FeedbackNotificationConfig) error {
+	data := map[string]string{
+		"action_url":         c.ActionURL,
+		"organisation_name":  c.OrganisationName,
+		"release_note_title": c.ReleaseNoteTitle,
+		"text":               c.Text,
+		"email":              c.Email,
+		"product_url":        cfg.BaseURL,
+		"product_name":       cfg.ProductInfo.ProductName,
+		"support_email":      cfg.ProductInfo.SupportEmail,
+		"company_name":       cfg.ProductInfo.CompanyName,
+		"company_address":    cfg.ProductInfo.CompanyAddress,
+	}
+	return sendEmail(c.To, "New Feedback on "+c.ReleaseNoteTitle, feedbackTmpl, data)
+}
+
+func SendReviewNotification(c *ReviewNotificationConfig) error {
+	data := map[string]string{
+		"action_url":         c.ActionURL,
+		"organisation_name":  c.OrganisationName,
+		"release_note_title": c.ReleaseNoteTitle,
+		"action":             c.Action,
+		"actor_email":        c.ActorEmail,
+		"comment":            c.Comment,
+		"product_url":        cfg.BaseURL,
+		"product_name":       cfg.ProductInfo.ProductName,
+		"support_email":      cfg.ProductInfo.SupportEmail,
+		"company_name":       cfg.ProductInfo.CompanyName,
+		"company_address":    cfg.ProductInfo.CompanyAddress,
+	}
+	var subject string
+	switch c.Action {
+	case "approved":
+		subject = "Approved: " + c.ReleaseNoteTitle
+	case "changes_requested":
+		subject = "Changes Requested on " + c.ReleaseNoteTitle
+	default:
+		subject = "Review Requested: " + c.ReleaseNoteTitle
+	}
+	return sendEmail(c.To, subject, reviewTmpl, data)
+}
+
 func sendEmail(to, subject string, tmpl *template.Template, data map[string]string) error {
 	var body bytes.Buffer
 	if err := tmpl.ExecuteTemplate(&body, "base", data); err != nil {
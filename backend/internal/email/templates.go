@@ -12,6 +12,8 @@ var (
 	welcomeTmpl       *template.Template
 	passwordResetTmpl *template.Template
 	userInviteTmpl    *template.Template
+	feedbackTmpl      *template.Template
+	reviewTmpl        *template.Template
 )
 
 func init() {
@@ -26,4 +28,10 @@ func init() {
 	userInviteTmpl = template.Must(
 		template.ParseFS(emailTemplates, base, "templates/user-invitation.html"),
 	)
+	feedbackTmpl = template.Must(
+		template.ParseFS(emailTemplates, base, "templates/feedback-notification.html"),
+	)
+	reviewTmpl = template.Must(
+		template.ParseFS(emailTemplates, base, "templates/review-notification.html"),
+	)
 }
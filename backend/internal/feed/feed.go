@@ -0,0 +1,37 @@
+// Package feed renders release notes as RSS 2.0, Atom 1.0 and JSON Feed 1.1 documents.
+package feed
+
+import "time"
+
+// Feed is the format independent description of a feed
+type Feed struct {
+	Title       string
+	Description string
+	// Link is the HTML page the feed belongs to
+	Link     string
+	ImageURL string
+	Updated  time.Time
+	Items    []*Item
+}
+
+// Item is a single entry of a feed
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Summary     string
+	ContentHTML string
+	ImageURL    string
+	// MediaURL links to an external video or other media of the entry
+	MediaURL  string
+	Published time.Time
+	Updated   time.Time
+}
+
+const generator = "Announcable"
+
+const (
+	ContentTypeRSS  = "application/rss+xml; charset=utf-8"
+	ContentTypeAtom = "application/atom+xml; charset=utf-8"
+	ContentTypeJSON = "application/feed+json; charset=utf-8"
+)
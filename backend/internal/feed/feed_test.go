@@ -0,0 +1,172 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+const selfURL = "https://app.example.com/s/acme/feed"
+
+func testFeed() *Feed {
+	published := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	return &Feed{
+		Title:       "Acme & Co <Changelog>",
+		Description: "What's new",
+		Link:        "https://app.example.com/s/acme",
+		Updated:     published.Add(time.Hour),
+		Items: []*Item{
+			{
+				ID:          "urn:uuid:1",
+				Title:       "Dark mode",
+				Link:        "https://app.example.com/s/acme#1",
+				Summary:     "Dark mode is here",
+				ContentHTML: `<p>Dark mode &amp; more</p><img src="https://img.example.com/1.webp" alt="">`,
+				ImageURL:    "https://img.example.com/1.webp",
+				MediaURL:    "https://youtube.com/watch?v=1",
+				Published:   published,
+				Updated:     published.Add(time.Hour),
+			},
+			{
+				ID:        "urn:uuid:2",
+				Title:     "Faster exports",
+				Summary:   "Exports are twice as fast",
+				Published: published.Add(-24 * time.Hour),
+			},
+		},
+	}
+}
+
+// TestRSS tests that the RSS output is well-formed and carries the item fields
+func TestRSS(t *testing.T) {
+	out, err := RSS(testFeed(), selfURL+".rss")
+	if err != nil {
+		t.Fatalf("RSS() error = %v", err)
+	}
+	var doc struct {
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title   string `xml:"title"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+				Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				Media   struct {
+					URL string `xml:"url,attr"`
+				} `xml:"http://search.yahoo.com/mrss/ content"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("RSS output is not valid XML: %v\n%s", err, out)
+	}
+	if doc.Channel.Title != "Acme & Co <Changelog>" {
+		t.Errorf("channel title = %q", doc.Channel.Title)
+	}
+	if len(doc.Channel.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(doc.Channel.Items))
+	}
+	item := doc.Channel.Items[0]
+	if item.GUID != "urn:uuid:1" || item.PubDate != "Sat, 01 Mar 2025 00:00:00 +0000" {
+		t.Errorf("item = %+v", item)
+	}
+	if !strings.Contains(item.Content, "<img src=") {
+		t.Errorf("content = %q, want HTML with image", item.Content)
+	}
+	if item.Media.URL != "https://img.example.com/1.webp" {
+		t.Errorf("media url = %q", item.Media.URL)
+	}
+	if !strings.Contains(string(out), `href="`+selfURL+`.rss" rel="self"`) {
+		t.Error("RSS output misses the self link")
+	}
+}
+
+// TestAtom tests that the Atom output is well-formed and carries the item fields
+func TestAtom(t *testing.T) {
+	out, err := Atom(testFeed(), selfURL+".atom")
+	if err != nil {
+		t.Fatalf("Atom() error = %v", err)
+	}
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Links   []struct {
+				Href string `xml:"href,attr"`
+				Rel  string `xml:"rel,attr"`
+			} `xml:"link"`
+			Content struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("Atom output is not valid XML: %v\n%s", err, out)
+	}
+	if doc.ID != selfURL+".atom" || len(doc.Entries) != 2 {
+		t.Fatalf("feed = %+v", doc)
+	}
+	entry := doc.Entries[0]
+	if entry.Updated != "2025-03-01T01:00:00Z" {
+		t.Errorf("updated = %q", entry.Updated)
+	}
+	if entry.Content.Type != "html" || !strings.HasPrefix(entry.Content.Value, "<p>Dark mode &amp; more</p>") {
+		t.Errorf("content = %+v", entry.Content)
+	}
+	if len(entry.Links) != 2 || entry.Links[1].Rel != "related" {
+		t.Errorf("links = %+v, want alternate and related media link", entry.Links)
+	}
+}
+
+// TestJSON tests the JSON Feed output
+func TestJSON(t *testing.T) {
+	out, err := JSON(testFeed(), selfURL+".json")
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var doc struct {
+		Version string `json:"version"`
+		FeedURL string `json:"feed_url"`
+		Items   []struct {
+			ID           string  `json:"id"`
+			Image        string  `json:"image"`
+			ExternalURL  string  `json:"external_url"`
+			DateModified *string `json:"date_modified"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("JSON output is not valid: %v", err)
+	}
+	if doc.Version != "https://jsonfeed.org/version/1.1" || doc.FeedURL != selfURL+".json" {
+		t.Errorf("feed = %+v", doc)
+	}
+	if len(doc.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(doc.Items))
+	}
+	if doc.Items[0].Image == "" || doc.Items[0].ExternalURL != "https://youtube.com/watch?v=1" {
+		t.Errorf("item = %+v", doc.Items[0])
+	}
+	if doc.Items[1].DateModified != nil {
+		t.Errorf("date_modified = %v, want omitted", *doc.Items[1].DateModified)
+	}
+}
+
+// TestEmptyFeed tests that feeds without items are still valid
+func TestEmptyFeed(t *testing.T) {
+	f := &Feed{Title: "Empty"}
+	if out, err := RSS(f, selfURL); err != nil || xml.Unmarshal(out, &struct{}{}) != nil {
+		t.Errorf("RSS() = %s, %v", out, err)
+	}
+	if out, err := Atom(f, selfURL); err != nil || xml.Unmarshal(out, &struct{}{}) != nil {
+		t.Errorf("Atom() = %s, %v", out, err)
+	}
+	out, err := JSON(f, selfURL)
+	if err != nil || !strings.Contains(string(out), `"items": []`) {
+		t.Errorf("JSON() = %s, %v", out, err)
+	}
+}
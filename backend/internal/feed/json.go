@@ -0,0 +1,59 @@
+package feed
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type jsonDocument struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url,omitempty"`
+	FeedURL     string     `json:"feed_url"`
+	Description string     `json:"description,omitempty"`
+	Icon        string     `json:"icon,omitempty"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string     `json:"id"`
+	URL           string     `json:"url,omitempty"`
+	ExternalURL   string     `json:"external_url,omitempty"`
+	Title         string     `json:"title"`
+	ContentHTML   string     `json:"content_html,omitempty"`
+	Summary       string     `json:"summary,omitempty"`
+	Image         string     `json:"image,omitempty"`
+	DatePublished time.Time  `json:"date_published"`
+	DateModified  *time.Time `json:"date_modified,omitempty"`
+}
+
+// JSON renders the feed as JSON Feed 1.1. selfURL is the URL the feed is served from.
+func JSON(f *Feed, selfURL string) ([]byte, error) {
+	doc := jsonDocument{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     selfURL,
+		Description: f.Description,
+		Icon:        f.ImageURL,
+		Items:       make([]jsonItem, 0, len(f.Items)),
+	}
+	for _, item := range f.Items {
+		ji := jsonItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			ExternalURL:   item.MediaURL,
+			Title:         item.Title,
+			ContentHTML:   item.ContentHTML,
+			Summary:       item.Summary,
+			Image:         item.ImageURL,
+			DatePublished: item.Published.UTC(),
+		}
+		if !item.Updated.IsZero() {
+			updated := item.Updated.UTC()
+			ji.DateModified = &updated
+		}
+		doc.Items = append(doc.Items, ji)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
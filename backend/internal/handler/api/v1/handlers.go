@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/handler/shared"
+)
+
+// Handlers provides the handlers of the authenticated JSON API
+type Handlers struct {
+	*shared.Dependencies
+}
+
+// New creates a new API v1 handlers instance
+func New(deps *shared.Dependencies) *Handlers {
+	return &Handlers{Dependencies: deps}
+}
+
+type errorResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func (h *Handlers) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.Log.Error().Err(err).Msg("Error encoding response")
+	}
+}
+
+func (h *Handlers) writeError(w http.ResponseWriter, status int, message string) {
+	h.writeJSON(w, status, errorResponse{Status: status, Message: message})
+}
@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// HandleReleaseNoteCreate handles POST /api/v1/release-notes
+func (h *Handlers) HandleReleaseNoteCreate(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNoteCreate")
+	ctx := r.Context()
+	orgId := ctx.Value(mw.OrgIDKey).(string)
+	userId := ctx.Value(mw.UserIDKey).(string)
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	req, img, err := h.decodeReleaseNoteRequest(w, r)
+	if err != nil {
+		h.Log.Debug().Err(err).Msg("Invalid request body")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	releaseNote := &releasenotes.ReleaseNote{
+		OrganisationID: uuid.MustParse(orgId),
+		CreatedBy:      uuid.MustParse(userId),
+		LastUpdatedBy:  uuid.MustParse(userId),
+	}
+	if err := req.applyTo(releaseNote); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	media, err := req.mediaInputs(releaseNote, img)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := releaseNotesService.Create(releaseNote, media)
+	if err != nil {
+		if isMediaError(err) {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, releasenotes.ErrReviewRequired) {
+			h.writeError(w, http.StatusConflict, "Release note needs an approved review before it can be scheduled")
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error creating release note")
+		h.writeError(w, http.StatusInternalServerError, "Error creating release note")
+		return
+	}
+
+	h.respondWithReleaseNote(w, id.String(), orgId, http.StatusCreated)
+}
@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+)
+
+// HandleReleaseNoteDelete handles DELETE /api/v1/release-notes/{id}
+func (h *Handlers) HandleReleaseNoteDelete(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNoteDelete")
+	orgId := r.Context().Value(mw.OrgIDKey).(string)
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	id, err := parseReleaseNoteId(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid release note ID")
+		return
+	}
+	if _, err := releaseNotesService.GetOne(id.String(), orgId); err != nil {
+		if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+			h.writeError(w, http.StatusNotFound, "Release note not found")
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error getting release note")
+		h.writeError(w, http.StatusInternalServerError, "Error getting release note")
+		return
+	}
+
+	if err := releaseNotesService.Delete(id); err != nil {
+		h.Log.Error().Err(err).Msg("Error deleting release note")
+		h.writeError(w, http.StatusInternalServerError, "Error deleting release note")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
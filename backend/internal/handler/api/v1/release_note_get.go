@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+)
+
+// HandleReleaseNoteGet handles GET /api/v1/release-notes/{id}
+func (h *Handlers) HandleReleaseNoteGet(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNoteGet")
+	orgId := r.Context().Value(mw.OrgIDKey).(string)
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	id, err := parseReleaseNoteId(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid release note ID")
+		return
+	}
+
+	rn, err := releaseNotesService.GetOne(id.String(), orgId)
+	if err != nil {
+		if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+			h.writeError(w, http.StatusNotFound, "Release note not found")
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error getting release note")
+		h.writeError(w, http.StatusInternalServerError, "Error getting release note")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, releaseNoteResponseBody{Data: toReleaseNoteResponse(rn)})
+}
+
+// respondWithReleaseNote loads the current state of a release note and writes it
+func (h *Handlers) respondWithReleaseNote(w http.ResponseWriter, id, orgId string, status int) {
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+	rn, err := releaseNotesService.GetOne(id, orgId)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting release note")
+		h.writeError(w, http.StatusInternalServerError, "Error getting release note")
+		return
+	}
+	h.writeJSON(w, status, releaseNoteResponseBody{Data: toReleaseNoteResponse(rn)})
+}
@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// HandleReleaseNotePublish handles POST /api/v1/release-notes/{id}/publish
+func (h *Handlers) HandleReleaseNotePublish(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNotePublish")
+	h.changePublishedStatus(w, r, true)
+}
+
+// HandleReleaseNoteUnpublish handles POST /api/v1/release-notes/{id}/unpublish
+func (h *Handlers) HandleReleaseNoteUnpublish(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNoteUnpublish")
+	h.changePublishedStatus(w, r, false)
+}
+
+func (h *Handlers) changePublishedStatus(w http.ResponseWriter, r *http.Request, publish bool) {
+	ctx := r.Context()
+	orgId := ctx.Value(mw.OrgIDKey).(string)
+	userId := ctx.Value(mw.UserIDKey).(string)
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	id, err := parseReleaseNoteId(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid release note ID")
+		return
+	}
+	if _, err := releaseNotesService.GetOne(id.String(), orgId); err != nil {
+		if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+			h.writeError(w, http.StatusNotFound, "Release note not found")
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error getting release note")
+		h.writeError(w, http.StatusInternalServerError, "Error getting release note")
+		return
+	}
+
+	if err := releaseNotesService.ChangePublishedStatus(id, publish, uuid.MustParse(userId)); err != nil {
+		if errors.Is(err, releasenotes.ErrReviewRequired) {
+			h.writeError(w, http.StatusConflict, "Release note needs an approved review before it can be published")
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error updating published status")
+		h.writeError(w, http.StatusInternalServerError, "Error updating release note")
+		return
+	}
+
+	h.respondWithReleaseNote(w, id.String(), orgId, http.StatusOK)
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// HandleReleaseNoteUpdate handles PATCH /api/v1/release-notes/{id}
+func (h *Handlers) HandleReleaseNoteUpdate(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNoteUpdate")
+	ctx := r.Context()
+	orgId := ctx.Value(mw.OrgIDKey).(string)
+	userId := ctx.Value(mw.UserIDKey).(string)
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	id, err := parseReleaseNoteId(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid release note ID")
+		return
+	}
+
+	releaseNote, err := releaseNotesService.GetOne(id.String(), orgId)
+	if err != nil {
+		if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+			h.writeError(w, http.StatusNotFound, "Release note not found")
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error getting release note")
+		h.writeError(w, http.StatusInternalServerError, "Error getting release note")
+		return
+	}
+
+	req, img, err := h.decodeReleaseNoteRequest(w, r)
+	if err != nil {
+		h.Log.Debug().Err(err).Msg("Invalid request body")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.applyTo(releaseNote); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	media, err := req.mediaInputs(releaseNote, img)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	releaseNote.LastUpdatedBy = uuid.MustParse(userId)
+
+	if err := releaseNotesService.Update(id, releaseNote, media); err != nil {
+		if isMediaError(err) {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, releasenotes.ErrReviewRequired) {
+			h.writeError(w, http.StatusConflict, "Release note needs an approved review before it can be scheduled")
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error updating release note")
+		h.writeError(w, http.StatusInternalServerError, "Error updating release note")
+		return
+	}
+
+	h.respondWithReleaseNote(w, id.String(), orgId, http.StatusOK)
+}
@@ -0,0 +1,288 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/imgUtil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const maxRequestSize = 10 << 20
+
+type releaseNoteResponse struct {
+	ID                 string          `json:"id"`
+	Title              string          `json:"title"`
+	Slug               string          `json:"slug"`
+	Tags               []string        `json:"tags"`
+	DescriptionShort   string          `json:"description_short"`
+	DescriptionLong    string          `json:"description_long"`
+	ReleaseDate        *string         `json:"release_date"`
+	ImageUrl           string          `json:"image_url"`
+	MediaLink          string          `json:"media_link"`
+	Media              []mediaResponse `json:"media"`
+	CtaLabelOverride   string          `json:"cta_label_override"`
+	CtaUrlOverride     string          `json:"cta_url_override"`
+	HideCta            bool            `json:"hide_cta"`
+	AttentionMechanism string          `json:"attention_mechanism"`
+	IsPublished        bool            `json:"is_published"`
+	HideOnWidget       bool            `json:"hide_on_widget"`
+	HideOnReleasePage  bool            `json:"hide_on_release_page"`
+	PublishAt          *time.Time      `json:"publish_at"`
+	UnpublishAt        *time.Time      `json:"unpublish_at"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// mediaResponse is an attachment of a release note. Url is the image or embed URL, Link the
+// video link as entered. SrcSet lists the resized copies of images for the srcset attribute.
+type mediaResponse struct {
+	Kind    string `json:"kind"`
+	Url     string `json:"url"`
+	Link    string `json:"link,omitempty"`
+	AltText string `json:"alt_text"`
+	SrcSet  string `json:"srcset,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+}
+
+type releaseNoteResponseBody struct {
+	Data releaseNoteResponse `json:"data"`
+}
+
+func toReleaseNoteResponse(rn *releasenotes.ReleaseNote) releaseNoteResponse {
+	tags := make([]string, len(rn.Tags))
+	for i, t := range rn.Tags {
+		tags[i] = t.Name
+	}
+	media := make([]mediaResponse, len(rn.Media))
+	for i, m := range rn.Media {
+		media[i] = mediaResponse{
+			Kind:    m.Kind.String(),
+			Url:     m.Url,
+			Link:    m.Link,
+			AltText: m.AltText,
+			SrcSet:  m.SrcSet,
+			Width:   m.Width,
+			Height:  m.Height,
+		}
+	}
+	return releaseNoteResponse{
+		ID:                 rn.ID.String(),
+		Title:              rn.Title,
+		Slug:               rn.Slug,
+		Tags:               tags,
+		DescriptionShort:   rn.DescriptionShort,
+		DescriptionLong:    rn.DescriptionLong,
+		ReleaseDate:        rn.ReleaseDate,
+		ImageUrl:           rn.ImageUrl,
+		MediaLink:          rn.MediaLink,
+		Media:              media,
+		CtaLabelOverride:   rn.CtaLabelOverride,
+		CtaUrlOverride:     rn.CtaUrlOverride,
+		HideCta:            rn.HideCta,
+		AttentionMechanism: rn.AttentionMechanism.String(),
+		IsPublished:        rn.IsPublished,
+		HideOnWidget:       rn.HideOnWidget,
+		HideOnReleasePage:  rn.HideOnReleasePage,
+		PublishAt:          rn.PublishAt,
+		UnpublishAt:        rn.UnpublishAt,
+		CreatedAt:          rn.CreatedAt,
+		UpdatedAt:          rn.UpdatedAt,
+	}
+}
+
+// releaseNoteRequest is the body of create and update requests.
+// Fields that are not set are left unchanged on update.
+type releaseNoteRequest struct {
+	Title              *string `json:"title" schema:"title"`
+	DescriptionShort   *string `json:"description_short" schema:"description_short"`
+	DescriptionLong    *string `json:"description_long" schema:"description_long"`
+	ReleaseDate        *string `json:"release_date" schema:"release_date"`
+	MediaLink          *string `json:"media_link" schema:"media_link"`
+	CtaLabelOverride   *string `json:"cta_label_override" schema:"cta_label_override"`
+	CtaUrlOverride     *string `json:"cta_url_override" schema:"cta_url_override"`
+	HideCta            *bool   `json:"hide_cta" schema:"hide_cta"`
+	AttentionMechanism *string `json:"attention_mechanism" schema:"attention_mechanism"`
+	HideOnWidget       *bool   `json:"hide_on_widget" schema:"hide_on_widget"`
+	HideOnReleasePage  *bool   `json:"hide_on_release_page" schema:"hide_on_release_page"`
+	PublishAt          *string `json:"publish_at" schema:"publish_at"`
+	UnpublishAt        *string `json:"unpublish_at" schema:"unpublish_at"`
+	DeleteImage        bool    `json:"delete_image" schema:"delete_image"`
+}
+
+// decodeReleaseNoteRequest reads a JSON or multipart body.
+// Multipart bodies may carry an image in the "image" field, which is returned if present.
+func (h *Handlers) decodeReleaseNoteRequest(w http.ResponseWriter, r *http.Request) (*releaseNoteRequest, io.Reader, error) {
+	var req releaseNoteRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return nil, nil, errors.New("invalid JSON body: " + err.Error())
+		}
+		return &req, nil, nil
+	}
+
+	if err := r.ParseMultipartForm(maxRequestSize); err != nil {
+		return nil, nil, errors.New("invalid multipart body")
+	}
+	if err := h.Decoder.Decode(&req, r.PostForm); err != nil {
+		return nil, nil, errors.New("invalid form fields: " + err.Error())
+	}
+	if req.DeleteImage {
+		return &req, nil, nil
+	}
+	img, _, err := r.FormFile("image")
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return &req, nil, nil
+		}
+		return nil, nil, errors.New("invalid image")
+	}
+	if ok := imgUtil.VerifyImageType(img); !ok {
+		return nil, nil, errors.New("unsupported image type")
+	}
+	return &req, img, nil
+}
+
+// applyTo copies the set fields of the request onto rn and validates the result
+func (req *releaseNoteRequest) applyTo(rn *releasenotes.ReleaseNote) error {
+	if req.Title != nil {
+		rn.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.DescriptionShort != nil {
+		rn.DescriptionShort = *req.DescriptionShort
+	}
+	if req.DescriptionLong != nil {
+		rn.DescriptionLong = *req.DescriptionLong
+	}
+	if req.ReleaseDate != nil {
+		if *req.ReleaseDate == "" {
+			rn.ReleaseDate = nil
+		} else {
+			if _, err := time.Parse("2006-01-02", *req.ReleaseDate); err != nil {
+				return errors.New("release_date must have the format YYYY-MM-DD")
+			}
+			releaseDate := *req.ReleaseDate
+			rn.ReleaseDate = &releaseDate
+		}
+	}
+	if req.CtaLabelOverride != nil {
+		rn.CtaLabelOverride = *req.CtaLabelOverride
+	}
+	if req.CtaUrlOverride != nil {
+		rn.CtaUrlOverride = *req.CtaUrlOverride
+	}
+	if req.HideCta != nil {
+		rn.HideCta = *req.HideCta
+	}
+	if req.AttentionMechanism != nil {
+		rn.AttentionMechanism = releasenotes.AttentionMechanism(*req.AttentionMechanism)
+	}
+	if req.HideOnWidget != nil {
+		rn.HideOnWidget = *req.HideOnWidget
+	}
+	if req.HideOnReleasePage != nil {
+		rn.HideOnReleasePage = *req.HideOnReleasePage
+	}
+	if req.PublishAt != nil || req.UnpublishAt != nil {
+		publishAt, unpublishAt := formatSchedule(rn.PublishAt), formatSchedule(rn.UnpublishAt)
+		if req.PublishAt != nil {
+			publishAt = *req.PublishAt
+		}
+		if req.UnpublishAt != nil {
+			unpublishAt = *req.UnpublishAt
+		}
+		publish, unpublish, err := releasenotes.ParseSchedule(publishAt, unpublishAt)
+		if err != nil {
+			if errors.Is(err, releasenotes.ErrInvalidSchedule) {
+				return err
+			}
+			return errors.New("publish_at and unpublish_at must be RFC 3339 timestamps")
+		}
+		rn.PublishAt, rn.UnpublishAt = publish, unpublish
+	}
+
+	if rn.Title == "" {
+		return errors.New("title is required")
+	}
+	if rn.DescriptionShort == "" {
+		return errors.New("description_short is required")
+	}
+	switch rn.AttentionMechanism {
+	case "":
+		rn.AttentionMechanism = releasenotes.AttentionMechanismIndicator
+	case releasenotes.AttentionMechanismIndicator, releasenotes.AttentionMechanismInstantOpen:
+	default:
+		return errors.New("attention_mechanism must be show_indicator or instant_open")
+	}
+	return nil
+}
+
+// mediaInputs maps the image and media_link of the request onto the attachments of rn. The API
+// treats them as a single attachment: a new image or media link replaces all attachments,
+// delete_image and an empty media_link remove the images or embeds. Returns nil if the
+// request leaves the attachments untouched.
+func (req *releaseNoteRequest) mediaInputs(rn *releasenotes.ReleaseNote, img io.Reader) ([]*releasenotes.MediaInput, error) {
+	mediaLink := ""
+	if req.MediaLink != nil {
+		mediaLink = strings.TrimSpace(*req.MediaLink)
+	}
+	switch {
+	case img != nil:
+		if mediaLink != "" {
+			return nil, errors.New("a release note can have either an image or a media_link")
+		}
+		return []*releasenotes.MediaInput{{Kind: releasenotes.MediaKindImage, ImgData: img}}, nil
+	case req.MediaLink != nil && mediaLink != "":
+		if mediaLink == rn.MediaLink && !req.DeleteImage {
+			return nil, nil
+		}
+		return []*releasenotes.MediaInput{{Kind: releasenotes.MediaKindEmbed, Link: mediaLink}}, nil
+	}
+
+	// remove the images, the embeds or both, keeping the rest as they are
+	removeKind := map[releasenotes.MediaKind]bool{
+		releasenotes.MediaKindImage: req.DeleteImage,
+		releasenotes.MediaKindEmbed: req.MediaLink != nil,
+	}
+	if !removeKind[releasenotes.MediaKindImage] && !removeKind[releasenotes.MediaKindEmbed] {
+		return nil, nil
+	}
+	media := []*releasenotes.MediaInput{}
+	for _, m := range rn.Media {
+		if !removeKind[m.Kind] {
+			media = append(media, &releasenotes.MediaInput{ID: m.ID, Kind: m.Kind, AltText: m.AltText})
+		}
+	}
+	return media, nil
+}
+
+func formatSchedule(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseReleaseNoteId reads the release note ID from the URL
+func parseReleaseNoteId(r *http.Request) (uuid.UUID, error) {
+	return uuid.Parse(chi.URLParam(r, "id"))
+}
+
+// isMediaError reports whether the attachments of a request were rejected
+func isMediaError(err error) bool {
+	return errors.Is(err, releasenotes.ErrTooManyMedia) ||
+		errors.Is(err, releasenotes.ErrInvalidMedia) ||
+		errors.Is(err, releasenotes.ErrInvalidMediaLink) ||
+		errors.Is(err, releasenotes.ErrAltTextTooLong)
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+)
+
+type paginationResponse struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	TotalCount int64 `json:"total_count"`
+	TotalPages int   `json:"total_pages"`
+}
+
+type releaseNotesListResponseBody struct {
+	Data       []releaseNoteResponse `json:"data"`
+	Pagination paginationResponse    `json:"pagination"`
+}
+
+// HandleReleaseNotesList handles GET /api/v1/release-notes
+func (h *Handlers) HandleReleaseNotesList(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNotesList")
+	orgId := r.Context().Value(mw.OrgIDKey).(string)
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	query := r.URL.Query()
+	page, pageSize := 1, 10
+	if v := query.Get("page"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "page must be a number")
+			return
+		}
+		page = p
+	}
+	if v := query.Get("page_size"); v != "" {
+		ps, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "page_size must be a number")
+			return
+		}
+		pageSize = ps
+	}
+	filters := map[string]interface{}{}
+	if v := query.Get("published"); v != "" {
+		published, err := strconv.ParseBool(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "published must be true or false")
+			return
+		}
+		filters["is_published"] = published
+	}
+	if tags := query["tag"]; len(tags) > 0 {
+		filters[releasenotes.FilterTags] = tags
+	}
+
+	releaseNotes, err := releaseNotesService.GetAllWithImgUrl(orgId, page, pageSize, filters)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting release notes")
+		h.writeError(w, http.StatusInternalServerError, "Error getting release notes")
+		return
+	}
+
+	res := releaseNotesListResponseBody{
+		Data: make([]releaseNoteResponse, 0, len(releaseNotes.Items)),
+		Pagination: paginationResponse{
+			Page:       releaseNotes.Page,
+			PageSize:   releaseNotes.PageSize,
+			TotalCount: releaseNotes.TotalCount,
+			TotalPages: releaseNotes.TotalPages,
+		},
+	}
+	for _, rn := range releaseNotes.Items {
+		res.Data = append(res.Data, toReleaseNoteResponse(rn))
+	}
+	h.writeJSON(w, http.StatusOK, res)
+}
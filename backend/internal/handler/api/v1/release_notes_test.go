@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apikey "github.com/devbydaniel/announcable/internal/domain/api-key"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReleaseNotesOfOtherOrganisations checks that an API key can't read or change the release
+// notes of another organisation
+func TestReleaseNotesOfOtherOrganisations(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+	deps := testutil.NewMockDependencies(testDB.DB)
+	require.NoError(t, testDB.DB.Client.AutoMigrate(
+		&organisation.Organisation{},
+		&apikey.ApiKey{},
+		&tag.Tag{},
+		&releasenotes.ReleaseNote{},
+		&releasenotes.ReleaseNoteTag{},
+		&releasenotes.ReleaseNoteTranslation{},
+		&releasenotes.AudienceRule{},
+		&releasenotes.ReleaseNoteRevision{},
+		&releasenotes.ReleaseNoteMedia{},
+		&releasenotes.ReleaseNoteRevisionMedia{},
+		&releasenotes.ReleaseNotePreview{},
+		&releasenotes.ReleaseNoteReview{},
+		&webhook.WebhookEndpoint{},
+		&webhook.WebhookDelivery{},
+	))
+
+	ownOrg, err := organisation.New("Own Org")
+	require.NoError(t, err)
+	require.NoError(t, testDB.DB.Client.Create(ownOrg).Error)
+	otherOrg, err := organisation.New("Other Org")
+	require.NoError(t, err)
+	require.NoError(t, testDB.DB.Client.Create(otherOrg).Error)
+
+	userId := uuid.New()
+	plainKey, _, err := apikey.NewService(*apikey.NewRepository(testDB.DB)).Create(ownOrg.ID, userId, "CI")
+	require.NoError(t, err)
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	otherId, err := releaseNotesService.Create(&releasenotes.ReleaseNote{
+		OrganisationID:   otherOrg.ID,
+		Title:            "Other",
+		DescriptionShort: "Description",
+		CreatedBy:        userId,
+		LastUpdatedBy:    userId,
+	}, nil)
+	require.NoError(t, err)
+
+	handlers := New(deps.ToSharedDependencies())
+	r := chi.NewRouter()
+	r.With(mw.NewHandler(testDB.DB).AuthenticateApiKey).Route("/api/v1/release-notes", func(r chi.Router) {
+		r.Get("/{id}", handlers.HandleReleaseNoteGet)
+		r.Patch("/{id}", handlers.HandleReleaseNoteUpdate)
+		r.Delete("/{id}", handlers.HandleReleaseNoteDelete)
+		r.Post("/{id}/publish", handlers.HandleReleaseNotePublish)
+		r.Post("/{id}/unpublish", handlers.HandleReleaseNoteUnpublish)
+	})
+
+	target := "/api/v1/release-notes/" + otherId.String()
+	tests := []struct {
+		method string
+		target string
+		body   string
+	}{
+		{method: http.MethodGet, target: target},
+		{method: http.MethodPatch, target: target, body: `{"title":"Changed"}`},
+		{method: http.MethodDelete, target: target},
+		{method: http.MethodPost, target: target + "/publish"},
+		{method: http.MethodPost, target: target + "/unpublish"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+strings.TrimPrefix(tt.target, target), func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+plainKey)
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusNotFound, rr.Code)
+		})
+	}
+
+	rn, err := releaseNotesService.GetOne(otherId.String(), otherOrg.ID.String())
+	require.NoError(t, err, "note of the other organisation was deleted")
+	assert.Equal(t, "Other", rn.Title)
+	assert.False(t, rn.IsPublished)
+}
@@ -0,0 +1,72 @@
+package widget
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"time"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+)
+
+// cacheKey identifies the cached data of a widget API request by its path, the query
+// parameters that change the data and the negotiated language. attrs are the resolved
+// audience attributes, which may come from a signed identity instead of the query; nil if
+// they don't apply. Unknown parameters are left out so they can't be used to fill the cache.
+func cacheKey(r *http.Request, lang string, attrs releasenotes.AudienceAttributes) string {
+	query := r.URL.Query()
+	key := url.Values{}
+	for _, param := range []string{"for", "page", "pageSize"} {
+		if value := query.Get(param); value != "" {
+			key.Set(param, value)
+		}
+	}
+	if tags := query["tag"]; len(tags) > 0 {
+		tags = append([]string(nil), tags...)
+		sort.Strings(tags)
+		key["tag"] = slices.Compact(tags)
+	}
+	if attrs != nil {
+		key.Set("attributes", attributesHash(attrs))
+	}
+	return r.URL.Path + "?" + key.Encode() + "|lang=" + lang
+}
+
+// attributesHash returns a hash of the audience attributes independent of the order of their values
+func attributesHash(attrs releasenotes.AudienceAttributes) string {
+	normalized := make(releasenotes.AudienceAttributes, len(attrs))
+	for name, values := range attrs {
+		values = append([]string(nil), values...)
+		sort.Strings(values)
+		normalized[name] = values
+	}
+	// maps are encoded with sorted keys
+	encoded, _ := json.Marshal(normalized)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:16])
+}
+
+// writeJSON encodes res and writes it with an ETag derived from the body, answering
+// conditional requests with 304 Not Modified. lastModified is omitted if zero, e.g. for
+// responses with per-user data whose changes the cached data doesn't track.
+func (h *Handlers) writeJSON(w http.ResponseWriter, r *http.Request, res interface{}, lastModified time.Time) {
+	body, err := json.Marshal(res)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error encoding response")
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+	sum := sha256.Sum256(body)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	// browsers may store responses but revalidate them on every use
+	w.Header().Set("Cache-Control", "no-cache")
+	// handles If-None-Match and If-Modified-Since
+	http.ServeContent(w, r, "", lastModified, bytes.NewReader(body))
+}
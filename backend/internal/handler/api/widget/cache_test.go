@@ -0,0 +1,28 @@
+package widget
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheKey(t *testing.T) {
+	key := func(target string, attrs releasenotes.AudienceAttributes) string {
+		return cacheKey(httptest.NewRequest("GET", target, nil), "en", attrs)
+	}
+	base := key("/api/release-notes/org?for=widget&page=1&tag=b&tag=a", nil)
+
+	assert.Equal(t, base, key("/api/release-notes/org?tag=a&page=1&for=widget&tag=b&tag=a", nil), "parameter and tag order")
+	assert.Equal(t, base, key("/api/release-notes/org?for=widget&page=1&tag=a&tag=b&clientId=c1&preview=tok&cb=123", nil), "parameters that don't change the data")
+	assert.NotEqual(t, base, key("/api/release-notes/org?for=website&page=1&tag=a&tag=b", nil))
+	assert.NotEqual(t, base, key("/api/release-notes/org?for=widget&page=2&tag=a&tag=b", nil))
+	assert.NotEqual(t, base, cacheKey(httptest.NewRequest("GET", "/api/release-notes/org?for=widget&page=1&tag=a&tag=b", nil), "de", nil))
+
+	attrs := releasenotes.AudienceAttributes{"plan": {"pro", "beta"}, "role": {"admin"}}
+	withAttrs := key("/api/release-notes/org", attrs)
+	assert.Equal(t, withAttrs, key("/api/release-notes/org", releasenotes.AudienceAttributes{"role": {"admin"}, "plan": {"beta", "pro"}}))
+	assert.NotEqual(t, withAttrs, key("/api/release-notes/org", releasenotes.AudienceAttributes{"plan": {"pro"}}))
+	assert.NotContains(t, withAttrs, "admin", "attributes are hashed")
+}
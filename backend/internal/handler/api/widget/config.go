@@ -1,14 +1,13 @@
 package widget
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
 	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
-	"github.com/go-chi/chi/v5"
-	"github.com/google/uuid"
+	"github.com/devbydaniel/announcable/internal/locale"
+	"github.com/devbydaniel/announcable/internal/widgetcache"
 )
 
 type serviceWidgetConfigResponseBodyWidgetConfig struct {
@@ -32,6 +31,9 @@ type serviceWidgetConfigResponseBodyWidgetConfig struct {
 	ReleaseNoteFontColor    string `json:"release_note_font_color"`
 	ReleasePageBaseUrl      string `json:"release_page_baseurl"`
 	DisableReleasePage      bool   `json:"disable_release_page"`
+	// emoji end users can react with besides liking, in the order they are shown
+	Reactions      []string `json:"reactions"`
+	EnableFeedback bool     `json:"enable_feedback"`
 }
 
 type serveWidgetConfigResponseBody struct {
@@ -41,37 +43,42 @@ type serveWidgetConfigResponseBody struct {
 // HandleWidgetConfigServe serves widget configuration
 func (h *Handlers) HandleWidgetConfigServe(w http.ResponseWriter, r *http.Request) {
 	h.Log.Trace().Msg("HandleWidgetConfigServe")
-	widgetConfigService := widgetconfigs.NewService(*widgetconfigs.NewRepository(h.DB))
-	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.DB, h.ObjStore))
-	organisationService := organisation.NewService(*organisation.NewRepository(h.DB))
 
-	externalOrgId := chi.URLParam(r, "orgId")
-	if externalOrgId == "" {
-		h.Log.Error().Msg("Org ID not found in URL")
-		http.Error(w, "Error getting widget config", http.StatusBadRequest)
+	org, ok := h.getOrg(w, r)
+	if !ok {
 		return
 	}
 
-	org, err := organisationService.GetOrgByExternalId(uuid.MustParse(externalOrgId))
+	lang := locale.Negotiate(r.URL.Query().Get("locale"), r.Header.Get("Accept-Language"), org.DefaultLocale)
+	entry, err := widgetcache.GetOrLoad(org.ID, cacheKey(r, lang, nil), func() (interface{}, error) {
+		return h.loadWidgetConfig(org, lang)
+	})
 	if err != nil {
-		h.Log.Error().Err(err).Msg("Error getting org ID")
 		http.Error(w, "Error getting widget config", http.StatusInternalServerError)
 		return
 	}
 
-	widgetConfig, err := widgetConfigService.Get(org.ID)
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	h.writeJSON(w, r, entry.Value, entry.LoadedAt)
+}
+
+// loadWidgetConfig builds the widget config response of the organisation in the given language
+func (h *Handlers) loadWidgetConfig(org *organisation.Organisation, lang string) (*serveWidgetConfigResponseBody, error) {
+	widgetConfigService := widgetconfigs.NewService(*widgetconfigs.NewRepository(h.DB))
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.DB, h.ObjStore))
+
+	widgetConfig, err := widgetConfigService.GetLocalized(org.ID, lang)
 	if err != nil {
 		h.Log.Error().Err(err).Msg("Error getting widget config")
-		http.Error(w, "Error getting widget config", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
 	var releasePageUrl string
 	releasePageUrl, err = releasePageConfigService.GetUrl(org.ID)
 	if err != nil {
 		h.Log.Error().Err(err).Msg("Error getting release page URL")
-		http.Error(w, "Error getting widget config", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 	if widgetConfig.ReleasePageBaseUrl != nil {
 		releasePageUrl = *widgetConfig.ReleasePageBaseUrl
@@ -80,12 +87,11 @@ func (h *Handlers) HandleWidgetConfigServe(w http.ResponseWriter, r *http.Reques
 	releasePageConfig, err := releasePageConfigService.Get(org.ID)
 	if err != nil {
 		h.Log.Error().Err(err).Msg("Error getting release page config")
-		http.Error(w, "Error getting widget config", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
 	conf := serviceWidgetConfigResponseBodyWidgetConfig{
-		OrgId:                   externalOrgId,
+		OrgId:                   org.ExternalID.String(),
 		Title:                   widgetConfig.Title,
 		Description:             widgetConfig.Description,
 		CtaText:                 widgetConfig.ReleaseNoteCtaText,
@@ -105,19 +111,11 @@ func (h *Handlers) HandleWidgetConfigServe(w http.ResponseWriter, r *http.Reques
 		ReleaseNoteFontColor:    widgetConfig.ReleaseNoteTextColor,
 		ReleasePageBaseUrl:      releasePageUrl,
 		DisableReleasePage:      releasePageConfig.DisableReleasePage,
+		Reactions:               widgetConfig.ReactionList(),
+		EnableFeedback:          widgetConfig.EnableFeedback,
 	}
 
-	res := serveWidgetConfigResponseBody{
+	return &serveWidgetConfigResponseBody{
 		Data: conf,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(res); err != nil {
-		h.Log.Error().Err(err).Msg("Error encoding response")
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
+	}, nil
 }
@@ -0,0 +1,91 @@
+package widget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+)
+
+// heartbeatInterval keeps idle event streams from being closed by proxies
+const heartbeatInterval = 25 * time.Second
+
+// reconnectDelay is how long browsers wait before reconnecting a dropped event stream
+const reconnectDelay = 10 * time.Second
+
+// releaseNoteEventData is the payload of release note events. It doesn't contain the note,
+// as the note might be hidden from the end user, who refetches the status instead.
+type releaseNoteEventData struct {
+	Type string `json:"type"`
+}
+
+// HandleReleaseNotesEvents streams Server-Sent Events whenever a release note of the
+// organisation is published, updated, unpublished or deleted. The stream ends when the
+// client disconnects or the server shuts down.
+func (h *Handlers) HandleReleaseNotesEvents(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNotesEvents")
+	org, ok := h.getOrg(w, r)
+	if !ok {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.Log.Error().Msg("Response writer does not support flushing")
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub, err := releasenotes.Events.Subscribe(org.ID)
+	if err != nil {
+		if errors.Is(err, releasenotes.ErrTooManySubscribers) || errors.Is(err, releasenotes.ErrBrokerClosed) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(reconnectDelay.Seconds())))
+			http.Error(w, "Too many open event streams", http.StatusServiceUnavailable)
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error subscribing to release note events")
+		http.Error(w, "Error subscribing to release note events", http.StatusInternalServerError)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// disable response buffering in nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, ok := <-sub.C:
+			if !ok {
+				// the server is shutting down
+				return
+			}
+			data, err := json.Marshal(releaseNoteEventData{Type: e.Type.String()})
+			if err != nil {
+				h.Log.Error().Err(err).Msg("Error encoding release note event")
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package widget
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/feedback"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type feedbackRequest struct {
+	ClientID string `json:"client_id"`
+	Text     string `json:"text"`
+	Email    string `json:"email"`
+	// honeypot, a hidden field only bots fill in
+	Website string `json:"website"`
+}
+
+// HandleReleaseNoteFeedbackCreate stores feedback of an end user on a release note. Duplicate
+// submissions and bots are answered like successful ones, so that they learn nothing.
+func (h *Handlers) HandleReleaseNoteFeedbackCreate(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNoteFeedbackCreate")
+
+	org, ok := h.getOrg(w, r)
+	if !ok {
+		return
+	}
+
+	releaseNoteUUID, err := uuid.Parse(chi.URLParam(r, "releaseNoteId"))
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Invalid release note ID")
+		http.Error(w, "Invalid release note ID", http.StatusBadRequest)
+		return
+	}
+
+	var req feedbackRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+		h.Log.Error().Err(err).Msg("Error decoding request body")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	clientId, err := h.resolveClientId(r, org.ID, req.ClientID)
+	if err != nil {
+		h.writeIdentityError(w, err)
+		return
+	}
+	if clientId == "" {
+		h.Log.Error().Msg("Missing required fields")
+		http.Error(w, "Missing required fields", http.StatusBadRequest)
+		return
+	}
+
+	widgetConfigService := widgetconfigs.NewService(*widgetconfigs.NewRepository(h.DB))
+	cfg, err := widgetConfigService.Get(org.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		h.Log.Error().Err(err).Msg("Error getting widget config")
+		http.Error(w, "Error saving feedback", http.StatusInternalServerError)
+		return
+	}
+	if cfg == nil || !cfg.EnableFeedback {
+		http.Error(w, "Feedback is disabled", http.StatusForbidden)
+		return
+	}
+
+	// Only published notes of the organisation can receive feedback
+	if !h.checkPublishedReleaseNote(w, org, releaseNoteUUID) {
+		return
+	}
+
+	if req.Website != "" {
+		h.Log.Debug().Msg("Feedback honeypot filled in")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	feedbackService := feedback.NewService(*feedback.NewRepository(h.DB))
+	err = feedbackService.Submit(&feedback.Feedback{
+		OrganisationID: org.ID,
+		ReleaseNoteID:  releaseNoteUUID,
+		ClientID:       clientId,
+		Source:         feedback.SourceWidget,
+		Text:           req.Text,
+		Email:          req.Email,
+	})
+	switch {
+	case errors.Is(err, feedback.ErrInvalidText), errors.Is(err, feedback.ErrInvalidEmail), errors.Is(err, feedback.ErrSpam):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil && !errors.Is(err, feedback.ErrDuplicate):
+		h.Log.Error().Err(err).Msg("Error saving feedback")
+		http.Error(w, "Error saving feedback", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
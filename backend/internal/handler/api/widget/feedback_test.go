@@ -0,0 +1,105 @@
+package widget
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devbydaniel/announcable/internal/domain/feedback"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleReleaseNoteFeedbackCreate(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB, &feedback.Feedback{})
+
+	// Create test organization with a published note, feedback is disabled by default
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+	cfg := widgetconfigs.DefaultConfig(testOrg.ID)
+	err = testDB.DB.Client.Create(cfg).Error
+	require.NoError(t, err)
+
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+	rnID, err := releaseNotesService.Create(&releasenotes.ReleaseNote{
+		OrganisationID:   testOrg.ID,
+		Title:            "Feedback",
+		DescriptionShort: "Description",
+		CreatedBy:        testUserID,
+		LastUpdatedBy:    testUserID,
+	}, nil)
+	require.NoError(t, err)
+	err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+	require.NoError(t, err)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+	submit := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/release-notes/"+testOrg.ExternalID.String()+"/"+rnID.String()+"/feedback", strings.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+		rctx.URLParams.Add("releaseNoteId", rnID.String())
+		rr := httptest.NewRecorder()
+		handlers.HandleReleaseNoteFeedbackCreate(rr, req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)))
+		return rr.Code
+	}
+
+	// Rejected while the organisation doesn't accept feedback
+	assert.Equal(t, http.StatusForbidden, submit(`{"client_id":"client-1","text":"Love it"}`))
+	err = testDB.DB.Client.Model(&widgetconfigs.WidgetConfig{}).Where("organisation_id = ?", testOrg.ID).Update("enable_feedback", true).Error
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{name: "valid", body: `{"client_id":"client-1","text":" Love it ","email":"jane@example.com"}`, expected: http.StatusNoContent},
+		{name: "duplicate", body: `{"client_id":"client-1","text":"Love it","email":"jane@example.com"}`, expected: http.StatusNoContent},
+		{name: "same text by another client", body: `{"client_id":"client-2","text":"Love it"}`, expected: http.StatusNoContent},
+		{name: "honeypot", body: `{"client_id":"client-3","text":"Buy now","website":"https://spam.example"}`, expected: http.StatusNoContent},
+		{name: "empty text", body: `{"client_id":"client-1","text":"  "}`, expected: http.StatusBadRequest},
+		{name: "invalid email", body: `{"client_id":"client-1","text":"Nice","email":"jane"}`, expected: http.StatusBadRequest},
+		{name: "too many links", body: `{"client_id":"client-1","text":"http://a.example http://b.example http://c.example"}`, expected: http.StatusBadRequest},
+		{name: "missing client ID", body: `{"text":"Nice"}`, expected: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, submit(tt.body))
+		})
+	}
+
+	// Only the valid feedback and the one of the other client are stored
+	var stored []feedback.Feedback
+	err = testDB.DB.Client.Order("client_id").Find(&stored, "organisation_id = ?", testOrg.ID).Error
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, "Love it", stored[0].Text)
+	assert.Equal(t, "jane@example.com", stored[0].Email)
+	assert.Equal(t, feedback.SourceWidget, stored[0].Source)
+	assert.Equal(t, feedback.StatusNew, stored[0].Status)
+	assert.Equal(t, "client-2", stored[1].ClientID)
+}
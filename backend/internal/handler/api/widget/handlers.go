@@ -1,15 +1,32 @@
 package widget
 
 import (
+	"context"
+	"time"
+
+	releasenotemetrics "github.com/devbydaniel/announcable/internal/domain/release-note-metrics"
 	"github.com/devbydaniel/announcable/internal/handler/shared"
 )
 
+// how often metrics buffered by the metrics endpoint are written
+const metricsFlushInterval = 2 * time.Second
+
 // Handlers provides widget API handlers
 type Handlers struct {
 	*shared.Dependencies
+	metrics *releasenotemetrics.Ingester
 }
 
 // New creates a new widget API handlers instance
 func New(deps *shared.Dependencies) *Handlers {
-	return &Handlers{Dependencies: deps}
+	return &Handlers{
+		Dependencies: deps,
+		metrics:      releasenotemetrics.NewIngester(releasenotemetrics.NewRepository(deps.DB), metricsFlushInterval),
+	}
+}
+
+// RunMetricsIngester writes the metrics buffered by the metrics endpoint until ctx is
+// cancelled, and flushes them a last time before it returns
+func (h *Handlers) RunMetricsIngester(ctx context.Context) {
+	h.metrics.Run(ctx)
 }
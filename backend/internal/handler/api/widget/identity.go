@@ -0,0 +1,91 @@
+package widget
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/identity"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/google/uuid"
+)
+
+// HeaderUserToken carries the signed identity token of the end user
+const HeaderUserToken = "X-User-Token"
+
+// resolveIdentity verifies the identity token sent with a widget request. It returns nil
+// without a token, unless the organisation only accepts signed identities.
+func (h *Handlers) resolveIdentity(r *http.Request, orgId uuid.UUID) (*identity.Identity, error) {
+	identityService := identity.NewService(*identity.NewRepository(h.DB))
+	return identityService.Resolve(orgId, r.Header.Get(HeaderUserToken))
+}
+
+// errInvalidClientId is returned for client IDs the widget can't have generated
+var errInvalidClientId = errors.New("invalid client ID")
+
+// the widget generates UUIDs as client IDs, this leaves room for other clients
+const maxClientIdLength = 64
+
+// validClientId reports whether an anonymous client ID is short and made of letters, digits,
+// dots, dashes and underscores only. The empty client ID of unknown visitors is valid.
+func validClientId(clientId string) bool {
+	if len(clientId) > maxClientIdLength {
+		return false
+	}
+	for _, c := range clientId {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveClientId returns the ID events of the end user are attributed to: the user ID of a
+// signed identity or the client ID generated by the widget. Unsigned client IDs in the format
+// of signed identities are rejected, they would let anyone act as a signed user.
+func (h *Handlers) resolveClientId(r *http.Request, orgId uuid.UUID, clientId string) (string, error) {
+	ident, err := h.resolveIdentity(r, orgId)
+	if err != nil {
+		return "", err
+	}
+	if ident != nil {
+		return ident.ClientID(), nil
+	}
+	if identity.IsSignedClientID(clientId) || !validClientId(clientId) {
+		return "", errInvalidClientId
+	}
+	return clientId, nil
+}
+
+// audienceAttributes returns the attributes release notes are targeted by: those of a signed
+// identity, or the unsigned attributes parameter unless the organisation only accepts signed
+// identities, in which case anonymous visitors have no attributes
+func (h *Handlers) audienceAttributes(r *http.Request, orgId uuid.UUID) (releasenotes.AudienceAttributes, error) {
+	ident, err := h.resolveIdentity(r, orgId)
+	if errors.Is(err, identity.ErrIdentityRequired) {
+		return releasenotes.AudienceAttributes{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ident != nil {
+		return ident.Attributes, nil
+	}
+	return releasenotes.ParseAudienceAttributes(r.URL.Query().Get("attributes"))
+}
+
+// writeIdentityError responds to a failed identity resolution
+func (h *Handlers) writeIdentityError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, identity.ErrIdentityRequired):
+		http.Error(w, "Signed user identity required", http.StatusUnauthorized)
+	case errors.Is(err, identity.ErrInvalidToken), errors.Is(err, identity.ErrTokenExpired):
+		http.Error(w, "Invalid user identity", http.StatusUnauthorized)
+	case errors.Is(err, releasenotes.ErrInvalidAudienceAttributes):
+		http.Error(w, "Invalid attributes", http.StatusBadRequest)
+	case errors.Is(err, errInvalidClientId):
+		http.Error(w, "Invalid client ID", http.StatusBadRequest)
+	default:
+		h.Log.Error().Err(err).Msg("Error resolving user identity")
+		http.Error(w, "Error resolving user identity", http.StatusInternalServerError)
+	}
+}
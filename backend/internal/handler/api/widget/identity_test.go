@@ -0,0 +1,30 @@
+package widget
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidClientId(t *testing.T) {
+	tests := []struct {
+		clientId string
+		valid    bool
+	}{
+		{clientId: "", valid: true},
+		{clientId: "8f14e45f-ceea-467f-a0e6-2b3c4d5e6f70", valid: true},
+		{clientId: "1718000000000", valid: true},
+		{clientId: "client_1.a", valid: true},
+		{clientId: strings.Repeat("a", maxClientIdLength), valid: true},
+		{clientId: strings.Repeat("a", maxClientIdLength+1), valid: false},
+		{clientId: "user:someone", valid: false},
+		{clientId: "client 1", valid: false},
+		{clientId: "<script>", valid: false},
+		{clientId: "clïent", valid: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, validClientId(tt.clientId), tt.clientId)
+	}
+}
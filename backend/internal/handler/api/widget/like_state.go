@@ -2,29 +2,37 @@ package widget
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/devbydaniel/announcable/internal/domain/identity"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotelikes "github.com/devbydaniel/announcable/internal/domain/release-note-likes"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-type getLikeStateResponse struct {
+type likeStateResponse struct {
 	IsLiked bool `json:"is_liked"`
+	Count   int  `json:"count"`
+	// the reactions offered by the organisation, in the configured order
+	Reactions []reactionStateResponse `json:"reactions"`
 }
 
-// HandleGetReleaseNoteLikeState gets the like state for a release note
+type reactionStateResponse struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
+
+// HandleGetReleaseNoteLikeState gets the like and reaction counts of a release note, and
+// whether the end user liked it and reacted to it
 func (h *Handlers) HandleGetReleaseNoteLikeState(w http.ResponseWriter, r *http.Request) {
 	h.Log.Trace().Msg("HandleGetReleaseNoteLikeState")
 
-	// Get external org ID from URL params
-	externalOrgId := chi.URLParam(r, "orgId")
-	if externalOrgId == "" {
-		h.Log.Error().Msg("Organisation ID not found in URL")
-		http.Error(w, "Organisation ID required", http.StatusBadRequest)
-		return
-	}
-
 	// Get release note ID from URL params
 	releaseNoteId := chi.URLParam(r, "releaseNoteId")
 	if releaseNoteId == "" {
@@ -33,20 +41,21 @@ func (h *Handlers) HandleGetReleaseNoteLikeState(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Get client ID from URL params
-	clientId := r.URL.Query().Get("clientId")
-	if clientId == "" {
-		// don't error, just return false
-		fallback := getLikeStateResponse{
-			IsLiked: false,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(fallback); err != nil {
-			h.Log.Error().Err(err).Msg("Error encoding response")
-			http.Error(w, "Error encoding response", http.StatusInternalServerError)
-		}
+	org, ok := h.getOrg(w, r)
+	if !ok {
 		return
 	}
+
+	// Get client ID from the signed user identity or from URL params,
+	// anonymous visitors haven't liked anything if only signed identities are accepted
+	clientId, err := h.resolveClientId(r, org.ID, r.URL.Query().Get("clientId"))
+	if errors.Is(err, identity.ErrIdentityRequired) {
+		clientId = ""
+	} else if err != nil {
+		h.writeIdentityError(w, err)
+		return
+	}
+
 	// Parse UUIDs
 	releaseNoteUUID, err := uuid.Parse(releaseNoteId)
 	if err != nil {
@@ -54,19 +63,62 @@ func (h *Handlers) HandleGetReleaseNoteLikeState(w http.ResponseWriter, r *http.
 		http.Error(w, "Invalid release note ID", http.StatusBadRequest)
 		return
 	}
+	if !h.checkPublishedReleaseNote(w, org, releaseNoteUUID) {
+		return
+	}
 
-	// Get like state
+	h.writeLikeState(w, org, releaseNoteUUID, clientId)
+}
+
+// checkPublishedReleaseNote writes a 404 response and returns false unless the release note
+// is a published note of the organisation
+func (h *Handlers) checkPublishedReleaseNote(w http.ResponseWriter, org *organisation.Organisation, releaseNoteId uuid.UUID) bool {
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+	rn, err := releaseNotesService.GetOne(releaseNoteId.String(), org.ID.String())
+	if err != nil && !errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+		h.Log.Error().Err(err).Msg("Error getting release note")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return false
+	}
+	if err != nil || !rn.IsPublished {
+		http.Error(w, "Release note not found", http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+// writeLikeState writes the like and reaction counts of a release note and what the client
+// liked and reacted with. Reactions the organisation no longer offers are left out.
+func (h *Handlers) writeLikeState(w http.ResponseWriter, org *organisation.Organisation, releaseNoteId uuid.UUID, clientId string) {
 	likesService := releasenotelikes.NewService(releasenotelikes.NewRepository(h.DB))
-	isLiked, err := likesService.HasUserLiked(releaseNoteUUID, clientId)
+	widgetConfigService := widgetconfigs.NewService(*widgetconfigs.NewRepository(h.DB))
+
+	state, err := likesService.GetState(releaseNoteId, clientId)
 	if err != nil {
 		h.Log.Error().Err(err).Msg("Error getting like state")
 		http.Error(w, "Error getting like state", http.StatusInternalServerError)
 		return
 	}
+	cfg, err := widgetConfigService.Get(org.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		h.Log.Error().Err(err).Msg("Error getting widget config")
+		http.Error(w, "Error getting like state", http.StatusInternalServerError)
+		return
+	}
 
-	// Return response
-	response := getLikeStateResponse{
-		IsLiked: isLiked,
+	response := likeStateResponse{
+		IsLiked:   state.IsLiked,
+		Count:     state.LikeCount,
+		Reactions: []reactionStateResponse{},
+	}
+	if cfg != nil {
+		for _, emoji := range cfg.ReactionList() {
+			response.Reactions = append(response.Reactions, reactionStateResponse{
+				Emoji:   emoji,
+				Count:   state.Reactions[emoji],
+				Reacted: state.Reacted[emoji],
+			})
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
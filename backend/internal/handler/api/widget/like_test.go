@@ -0,0 +1,139 @@
+package widget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenotelikes "github.com/devbydaniel/announcable/internal/domain/release-note-likes"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleReleaseNoteToggleLike_Reactions(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB, &releasenotelikes.ReleaseNoteLike{})
+
+	// Create test organization offering two reactions, with a published note
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+	cfg := widgetconfigs.DefaultConfig(testOrg.ID)
+	cfg.Reactions = "🎉 👀"
+	err = testDB.DB.Client.Create(cfg).Error
+	require.NoError(t, err)
+
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+	rnID, err := releaseNotesService.Create(&releasenotes.ReleaseNote{
+		OrganisationID:   testOrg.ID,
+		Title:            "Reactions",
+		DescriptionShort: "Description",
+		CreatedBy:        testUserID,
+		LastUpdatedBy:    testUserID,
+	}, nil)
+	require.NoError(t, err)
+	err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+	require.NoError(t, err)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+	likeUrl := "/api/release-notes/" + testOrg.ExternalID.String() + "/" + rnID.String() + "/like"
+	newRequest := func(method, target, body string) *http.Request {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+		rctx.URLParams.Add("releaseNoteId", rnID.String())
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+	toggle := func(clientId, reaction string) (int, likeStateResponse) {
+		rr := httptest.NewRecorder()
+		handlers.HandleReleaseNoteToggleLike(rr, newRequest(http.MethodPost, likeUrl, `{"release_note_id":"`+rnID.String()+`","client_id":"`+clientId+`","reaction":"`+reaction+`"}`))
+		var response likeStateResponse
+		if rr.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+		}
+		return rr.Code, response
+	}
+
+	// Likes and reactions of two clients
+	code, state := toggle("client-1", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, state.IsLiked)
+	assert.Equal(t, 1, state.Count)
+	toggle("client-2", "")
+	toggle("client-1", "🎉")
+	code, state = toggle("client-2", "🎉")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, likeStateResponse{
+		IsLiked:   true,
+		Count:     2,
+		Reactions: []reactionStateResponse{{Emoji: "🎉", Count: 2, Reacted: true}, {Emoji: "👀", Count: 0, Reacted: false}},
+	}, state)
+
+	// Toggling again takes a reaction back without touching the like
+	code, state = toggle("client-2", "🎉")
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, state.IsLiked)
+	assert.Equal(t, 2, state.Count)
+	assert.Equal(t, reactionStateResponse{Emoji: "🎉", Count: 1, Reacted: false}, state.Reactions[0])
+
+	// Reactions the organisation doesn't offer are rejected
+	code, _ = toggle("client-1", "👍")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// The state of the first client, and of an anonymous visitor
+	tests := []struct {
+		name     string
+		query    string
+		expected likeStateResponse
+	}{
+		{
+			name:  "client",
+			query: "?clientId=client-1",
+			expected: likeStateResponse{IsLiked: true, Count: 2, Reactions: []reactionStateResponse{
+				{Emoji: "🎉", Count: 1, Reacted: true}, {Emoji: "👀", Count: 0, Reacted: false},
+			}},
+		},
+		{
+			name:  "anonymous",
+			query: "",
+			expected: likeStateResponse{IsLiked: false, Count: 2, Reactions: []reactionStateResponse{
+				{Emoji: "🎉", Count: 1, Reacted: false}, {Emoji: "👀", Count: 0, Reacted: false},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handlers.HandleGetReleaseNoteLikeState(rr, newRequest(http.MethodGet, likeUrl+tt.query, ""))
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			var response likeStateResponse
+			err := json.NewDecoder(rr.Body).Decode(&response)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, response)
+		})
+	}
+}
@@ -2,86 +2,121 @@ package widget
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
-	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotemetrics "github.com/devbydaniel/announcable/internal/domain/release-note-metrics"
-	"github.com/go-chi/chi/v5"
+	"github.com/devbydaniel/announcable/internal/ratelimit"
 	"github.com/google/uuid"
 )
 
+const (
+	// events accepted in one request at most
+	maxMetricEvents = 50
+	// request bodies are small, larger ones are rejected
+	maxMetricsBodyBytes = 16 << 10
+)
+
+// limits every client to 120 events per minute
+var metricsRateLimiter = ratelimit.New(60, 120)
+
+type metricEvent struct {
+	ReleaseNoteID string `json:"release_note_id"`
+	MetricType    string `json:"metric_type"`
+}
+
 type createMetricRequest struct {
+	ClientID string        `json:"client_id"`
+	Events   []metricEvent `json:"events"`
+	// a single event, as sent by widget versions without batching
 	ReleaseNoteID string `json:"release_note_id"`
 	MetricType    string `json:"metric_type"`
-	ClientID      string `json:"client_id"`
 }
 
-// HandleReleaseNoteMetricCreate creates a metric for a release note
+type createMetricResponse struct {
+	// events that will be stored, views already counted in the dedup window are not
+	Accepted int `json:"accepted"`
+}
+
+// HandleReleaseNoteMetricCreate buffers a batch of metrics of a client. The metrics are written
+// asynchronously, views of a note by the same client are counted once per dedup window.
 func (h *Handlers) HandleReleaseNoteMetricCreate(w http.ResponseWriter, r *http.Request) {
 	h.Log.Trace().Msg("HandleReleaseNoteMetricCreate")
-	orgService := organisation.NewService(*organisation.NewRepository(h.DB))
-
-	// Get external org ID from URL params
-	externalOrgId := chi.URLParam(r, "orgId")
-	if externalOrgId == "" {
-		h.Log.Error().Msg("Organisation ID not found in URL")
-		http.Error(w, "Organisation ID required", http.StatusBadRequest)
-		return
-	}
 
-	// Get org by external ID
-	org, err := orgService.GetOrgByExternalId(uuid.MustParse(externalOrgId))
-	if err != nil {
-		h.Log.Error().Err(err).Msg("Error getting organisation")
-		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
+	org, ok := h.getOrg(w, r)
+	if !ok {
 		return
 	}
 
 	// Parse request body
 	var req createMetricRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMetricsBodyBytes)).Decode(&req); err != nil {
 		h.Log.Error().Err(err).Msg("Error decoding request body")
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if len(req.Events) == 0 && req.ReleaseNoteID != "" {
+		req.Events = []metricEvent{{ReleaseNoteID: req.ReleaseNoteID, MetricType: req.MetricType}}
+	}
+
+	// Attribute the events to the signed user identity if there is one
+	clientId, err := h.resolveClientId(r, org.ID, req.ClientID)
+	if err != nil {
+		h.writeIdentityError(w, err)
+		return
+	}
 
 	// Validate required fields
-	if req.ReleaseNoteID == "" || req.MetricType == "" || req.ClientID == "" {
+	if len(req.Events) == 0 || clientId == "" {
 		h.Log.Error().Msg("Missing required fields")
 		http.Error(w, "Missing required fields", http.StatusBadRequest)
 		return
 	}
-
-	// Parse UUIDs
-	releaseNoteUUID, err := uuid.Parse(req.ReleaseNoteID)
-	if err != nil {
-		h.Log.Error().Err(err).Msg("Invalid release note ID")
-		http.Error(w, "Invalid release note ID", http.StatusBadRequest)
+	if len(req.Events) > maxMetricEvents {
+		http.Error(w, "Too many events", http.StatusRequestEntityTooLarge)
 		return
 	}
 
-	orgUUID, err := uuid.Parse(org.ID.String())
-	if err != nil {
-		h.Log.Error().Err(err).Msg("Invalid organisation ID")
-		http.Error(w, "Invalid organisation ID", http.StatusBadRequest)
-		return
+	events := make([]releasenotemetrics.Event, len(req.Events))
+	for i, e := range req.Events {
+		releaseNoteUUID, err := uuid.Parse(e.ReleaseNoteID)
+		if err != nil {
+			h.Log.Error().Err(err).Msg("Invalid release note ID")
+			http.Error(w, "Invalid release note ID", http.StatusBadRequest)
+			return
+		}
+		metricType := releasenotemetrics.MetricType(e.MetricType)
+		if !metricType.IsValid() {
+			h.Log.Error().Str("metricType", e.MetricType).Msg("Invalid metric type")
+			http.Error(w, "Invalid metric type", http.StatusBadRequest)
+			return
+		}
+		events[i] = releasenotemetrics.Event{ReleaseNoteID: releaseNoteUUID, MetricType: metricType}
 	}
 
-	// Validate metric type
-	metricType := releasenotemetrics.MetricType(req.MetricType)
-	if metricType != releasenotemetrics.MetricTypeView && metricType != releasenotemetrics.MetricTypeCtaClick {
-		h.Log.Error().Str("metricType", string(metricType)).Msg("Invalid metric type")
-		http.Error(w, "Invalid metric type", http.StatusBadRequest)
+	if err := metricsRateLimiter.Deduct(org.ID.String()+":"+clientId, float64(len(events))); err != nil {
+		h.Log.Warn().Str("clientId", clientId).Err(err).Msg("Metrics rate limit reached")
+		http.Error(w, "Rate limit reached", http.StatusTooManyRequests)
 		return
 	}
 
-	// Create metric
-	metricsService := releasenotemetrics.NewService(releasenotemetrics.NewRepository(h.DB))
-	if err := metricsService.CreateMetric(releaseNoteUUID, orgUUID, req.ClientID, metricType); err != nil {
-		h.Log.Error().Err(err).Msg("Error creating metric")
-		http.Error(w, "Error creating metric", http.StatusInternalServerError)
+	// Buffer metrics
+	accepted, err := h.metrics.Add(org.ID, clientId, events)
+	if err != nil {
+		if errors.Is(err, releasenotemetrics.ErrBufferFull) {
+			h.Log.Warn().Msg("Metrics buffer full")
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, "Too many metrics, try again later", http.StatusServiceUnavailable)
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error buffering metrics")
+		http.Error(w, "Invalid metric event", http.StatusBadRequest)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(createMetricResponse{Accepted: accepted}); err != nil {
+		h.Log.Error().Err(err).Msg("Error encoding response")
+	}
 }
@@ -0,0 +1,37 @@
+package widget
+
+import (
+	"testing"
+
+	"github.com/devbydaniel/announcable/internal/database"
+	"github.com/devbydaniel/announcable/internal/domain/identity"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	"github.com/stretchr/testify/require"
+)
+
+// migrateReleaseNoteTables creates the tables the release notes service writes to when notes are
+// created, updated and published, plus the given models of the test
+func migrateReleaseNoteTables(t testing.TB, db *database.DB, models ...interface{}) {
+	t.Helper()
+	tables := []interface{}{
+		&organisation.Organisation{},
+		&widgetconfigs.WidgetConfig{},
+		&identity.IdentityConfig{},
+		&tag.Tag{},
+		&releasenotes.ReleaseNote{},
+		&releasenotes.ReleaseNoteTag{},
+		&releasenotes.ReleaseNoteTranslation{},
+		&releasenotes.AudienceRule{},
+		&releasenotes.ReleaseNoteRevision{},
+		&releasenotes.ReleaseNoteMedia{},
+		&releasenotes.ReleaseNoteRevisionMedia{},
+		&releasenotes.ReleaseNotePreview{},
+		&webhook.WebhookEndpoint{},
+		&webhook.WebhookDelivery{},
+	}
+	require.NoError(t, db.Client.AutoMigrate(append(tables, models...)...))
+}
@@ -0,0 +1,36 @@
+package widget
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// getOrg returns the organisation of the orgId URL param. It writes a 400 response for missing
+// or malformed IDs, a 404 response for unknown organisations and a 403 response for requests
+// from sites outside the organisation's allowed domains, and returns false.
+func (h *Handlers) getOrg(w http.ResponseWriter, r *http.Request) (*organisation.Organisation, bool) {
+	externalOrgId, err := uuid.Parse(chi.URLParam(r, "orgId"))
+	if err != nil {
+		http.Error(w, "Invalid organisation ID", http.StatusBadRequest)
+		return nil, false
+	}
+	orgService := organisation.NewService(*organisation.NewRepository(h.DB))
+	org, err := orgService.GetOrgByExternalId(externalOrgId)
+	if err != nil {
+		if errors.Is(err, organisation.ErrOrgNotFound) {
+			http.Error(w, "Organisation not found", http.StatusNotFound)
+			return nil, false
+		}
+		h.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
+		return nil, false
+	}
+	if !h.checkOrigin(w, r, org) {
+		return nil, false
+	}
+	return org, true
+}
@@ -0,0 +1,55 @@
+package widget
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHandlers_InvalidOrgId checks that malformed organisation IDs are rejected before the
+// database is queried, so no database is needed
+func TestHandlers_InvalidOrgId(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	handlers := New(testutil.NewMockDependencies(nil).ToSharedDependencies())
+
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		handler http.HandlerFunc
+	}{
+		{name: "release notes", method: http.MethodGet, handler: handlers.HandleReleaseNotesServe},
+		{name: "status", method: http.MethodGet, handler: handlers.HandleReleaseNotesStatusServe},
+		{name: "unread count", method: http.MethodGet, handler: handlers.HandleReleaseNotesUnreadCountServe},
+		{name: "widget config", method: http.MethodGet, handler: handlers.HandleWidgetConfigServe},
+		{name: "like state", method: http.MethodGet, handler: handlers.HandleGetReleaseNoteLikeState},
+		{name: "toggle like", method: http.MethodPost, body: `{"client_id":"client-1"}`, handler: handlers.HandleReleaseNoteToggleLike},
+		{name: "metrics", method: http.MethodPost, body: `{"client_id":"client-1"}`, handler: handlers.HandleReleaseNoteMetricCreate},
+		{name: "seen", method: http.MethodPost, body: `{"client_id":"client-1"}`, handler: handlers.HandleReleaseNotesMarkSeen},
+	}
+
+	for _, tt := range tests {
+		for _, orgId := range []string{"", "not-a-uuid"} {
+			t.Run(tt.name+" "+orgId, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, "/api/release-notes/"+orgId, strings.NewReader(tt.body))
+				rctx := chi.NewRouteContext()
+				rctx.URLParams.Add("orgId", orgId)
+				rctx.URLParams.Add("releaseNoteId", "b3c1c0de-6f0a-4c39-9d1e-2f0b8a4e7c11")
+				req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+				rr := httptest.NewRecorder()
+				tt.handler(rr, req)
+
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			})
+		}
+	}
+}
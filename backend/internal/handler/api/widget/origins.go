@@ -0,0 +1,103 @@
+package widget
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/devbydaniel/announcable/config"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	"github.com/devbydaniel/announcable/internal/memcache"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemCacheAllowedDomains holds the allowed domains of the organisations by external org ID
+var MemCacheAllowedDomains = memcache.New(5*time.Minute, 10*time.Minute)
+
+// InvalidateAllowedDomainsCache drops the cached allowed domains of all organisations
+func InvalidateAllowedDomainsCache() {
+	MemCacheAllowedDomains.Flush()
+}
+
+// IsOriginAllowed reports whether CORS requests from origin may access the widget API. Only
+// the organisation routes are restricted, to the allowed domains of the organisation in the path.
+func (h *Handlers) IsOriginAllowed(r *http.Request, origin string) bool {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 3 || segments[0] != "api" || (segments[1] != "release-notes" && segments[1] != "widget-config") {
+		return true
+	}
+	externalOrgId, err := uuid.Parse(segments[2])
+	if err != nil {
+		// the handlers reject the request
+		return true
+	}
+	domains, err := h.allowedDomains(externalOrgId)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting allowed domains")
+		return false
+	}
+	return isOriginAllowed(domains, origin)
+}
+
+// checkOrigin writes a 403 response and returns false if the organisation restricts the widget
+// to its allowed domains and the request comes from another site. The site is taken from the
+// Origin header, or from the Referer for requests without one.
+func (h *Handlers) checkOrigin(w http.ResponseWriter, r *http.Request, org *organisation.Organisation) bool {
+	domains, err := h.allowedDomains(org.ExternalID)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting allowed domains")
+		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		origin = r.Header.Get("Referer")
+	}
+	if !isOriginAllowed(domains, origin) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// allowedDomains returns the allowed domains of the organisation, none if all are allowed.
+// Unknown organisations and organisations without widget config allow all domains.
+func (h *Handlers) allowedDomains(externalOrgId uuid.UUID) ([]string, error) {
+	if cached, found := MemCacheAllowedDomains.Get(externalOrgId.String()); found {
+		return cached.([]string), nil
+	}
+	orgService := organisation.NewService(*organisation.NewRepository(h.DB))
+	widgetConfigService := widgetconfigs.NewService(*widgetconfigs.NewRepository(h.DB))
+
+	var domains []string
+	org, err := orgService.GetOrgByExternalId(externalOrgId)
+	if err == nil {
+		cfg, err := widgetConfigService.Get(org.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if cfg != nil {
+			domains = cfg.AllowedDomainList()
+		}
+	} else if !errors.Is(err, organisation.ErrOrgNotFound) {
+		return nil, err
+	}
+	MemCacheAllowedDomains.Set(externalOrgId.String(), domains, 5*time.Minute)
+	return domains, nil
+}
+
+// isOriginAllowed checks origin against the allowed domains. The app itself is always allowed,
+// so that the widget keeps working in its previews.
+func isOriginAllowed(domains []string, origin string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	if baseUrl, err := url.Parse(config.New().BaseURL); err == nil && baseUrl.Hostname() != "" {
+		domains = append(domains[:len(domains):len(domains)], strings.ToLower(baseUrl.Hostname()))
+	}
+	return widgetconfigs.IsOriginAllowed(domains, origin)
+}
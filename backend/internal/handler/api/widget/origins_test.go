@@ -0,0 +1,77 @@
+package widget
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHandlers_AllowedOrigins checks the origin checks against cached allowed domains, so no
+// database is needed
+func TestHandlers_AllowedOrigins(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+	defer InvalidateAllowedDomainsCache()
+
+	handlers := New(testutil.NewMockDependencies(nil).ToSharedDependencies())
+	restricted := uuid.New()
+	open := uuid.New()
+	MemCacheAllowedDomains.Set(restricted.String(), []string{"example.com", "*.example.org"}, 0)
+	MemCacheAllowedDomains.Set(open.String(), []string(nil), 0)
+
+	t.Run("cors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			path     string
+			origin   string
+			expected bool
+		}{
+			{name: "allowed domain", path: "/api/release-notes/" + restricted.String(), origin: "https://example.com", expected: true},
+			{name: "allowed subdomain", path: "/api/release-notes/" + restricted.String() + "/status", origin: "https://app.example.org", expected: true},
+			{name: "other domain", path: "/api/release-notes/" + restricted.String(), origin: "https://example.net", expected: false},
+			{name: "widget config", path: "/api/widget-config/" + restricted.String(), origin: "https://example.net", expected: false},
+			{name: "unrestricted organisation", path: "/api/widget-config/" + open.String(), origin: "https://example.net", expected: true},
+			{name: "route without organisation", path: "/api/img/logo.png", origin: "https://example.net", expected: true},
+			{name: "malformed organisation", path: "/api/release-notes/not-a-uuid", origin: "https://example.net", expected: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+				assert.Equal(t, tt.expected, handlers.IsOriginAllowed(req, tt.origin))
+			})
+		}
+	})
+
+	t.Run("handlers", func(t *testing.T) {
+		org := &organisation.Organisation{ExternalID: restricted}
+		tests := []struct {
+			name     string
+			headers  map[string]string
+			expected bool
+		}{
+			{name: "allowed origin", headers: map[string]string{"Origin": "https://example.com"}, expected: true},
+			{name: "allowed referer", headers: map[string]string{"Referer": "https://www.example.org/pricing"}, expected: true},
+			{name: "origin wins over referer", headers: map[string]string{"Origin": "https://example.net", "Referer": "https://example.com/"}, expected: false},
+			{name: "other origin", headers: map[string]string{"Origin": "https://example.net"}, expected: false},
+			{name: "no origin", headers: nil, expected: false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/api/release-notes/"+restricted.String(), nil)
+				for k, v := range tt.headers {
+					req.Header.Set(k, v)
+				}
+				rr := httptest.NewRecorder()
+				assert.Equal(t, tt.expected, handlers.checkOrigin(rr, req, org))
+				if !tt.expected {
+					assert.Equal(t, http.StatusForbidden, rr.Code)
+				}
+			})
+		}
+	})
+}
@@ -1,30 +1,57 @@
 package widget
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	"github.com/devbydaniel/announcable/internal/locale"
+	"github.com/devbydaniel/announcable/internal/markdown"
 	"github.com/devbydaniel/announcable/internal/util"
-	"github.com/go-chi/chi/v5"
+	"github.com/devbydaniel/announcable/internal/widgetcache"
 	"github.com/google/uuid"
 )
 
 type serveReleaseNotesWidgetResponseBodyReleaseNotes struct {
-	ID                 string `json:"id"`
-	Title              string `json:"title"`
-	Date               string `json:"date"`
-	ImageSrc           string `json:"imageSrc"`
-	MediaLink          string `json:"media_link"`
-	Text               string `json:"text"`
-	LastUpdateOn       string `json:"last_update_on"`
-	CtaLabelOverride   string `json:"cta_label_override"`
-	CtaHrefOverride    string `json:"cta_href_override"`
-	HideCta            bool   `json:"hide_cta"`
-	AttentionMechanism string `json:"attentionMechanism"`
+	ID                 string                                   `json:"id"`
+	Title              string                                   `json:"title"`
+	Date               string                                   `json:"date"`
+	ImageSrc           string                                   `json:"imageSrc"`
+	MediaLink          string                                   `json:"media_link"`
+	Text               string                                   `json:"text"`
+	TextHTML           string                                   `json:"text_html"`
+	LastUpdateOn       string                                   `json:"last_update_on"`
+	CtaLabelOverride   string                                   `json:"cta_label_override"`
+	CtaHrefOverride    string                                   `json:"cta_href_override"`
+	Permalink          string                                   `json:"permalink"`
+	HideCta            bool                                     `json:"hide_cta"`
+	AttentionMechanism string                                   `json:"attentionMechanism"`
+	Tags               []serveReleaseNotesWidgetResponseBodyTag `json:"tags"`
+	// Media holds all attachments in display order, ImageSrc and MediaLink only the first
+	// image and embed for older widget versions
+	Media []serveReleaseNotesWidgetResponseBodyMedia `json:"media"`
+	// IsPreview marks the note of a preview link, which might not be published
+	IsPreview bool `json:"is_preview,omitempty"`
+}
+
+type serveReleaseNotesWidgetResponseBodyTag struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type serveReleaseNotesWidgetResponseBodyMedia struct {
+	Kind string `json:"kind"`
+	Src  string `json:"src"`
+	Alt  string `json:"alt"`
+	// SrcSet, Width, Height and Placeholder are only set for images
+	SrcSet      string `json:"srcset,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	Placeholder string `json:"placeholder,omitempty"`
 }
 
 type serveReleaseNotesWidgetResponseBody struct {
@@ -34,8 +61,6 @@ type serveReleaseNotesWidgetResponseBody struct {
 // HandleReleaseNotesServe serves release notes for the widget
 func (h *Handlers) HandleReleaseNotesServe(w http.ResponseWriter, r *http.Request) {
 	h.Log.Trace().Msg("HandleReleaseNotesServe")
-	organisationService := organisation.NewService(*organisation.NewRepository(h.DB))
-	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
 	forWidgetOrWebsite := r.URL.Query().Get("for")
 	h.Log.Debug().Str("for", forWidgetOrWebsite).Msg("For widget or website")
 	h.Log.Debug().Msg("Getting page and pageSize")
@@ -59,17 +84,9 @@ func (h *Handlers) HandleReleaseNotesServe(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "Error getting release notes", http.StatusBadRequest)
 		return
 	}
-	externalOrgId := chi.URLParam(r, "orgId")
-	if externalOrgId == "" {
-		h.Log.Error().Msg("Org ID not found in URL")
-		http.Error(w, "Error getting release notes", http.StatusBadRequest)
-		return
-	}
 
-	org, err := organisationService.GetOrgByExternalId(uuid.MustParse(externalOrgId))
-	if err != nil {
-		h.Log.Error().Err(err).Msg("Error getting org ID")
-		http.Error(w, "Error getting widget config", http.StatusInternalServerError)
+	org, ok := h.getOrg(w, r)
+	if !ok {
 		return
 	}
 	filters := map[string]interface{}{
@@ -80,60 +97,193 @@ func (h *Handlers) HandleReleaseNotesServe(w http.ResponseWriter, r *http.Reques
 	}
 	if forWidgetOrWebsite == "website" {
 		filters["hide_on_release_page"] = false
+		filters[releasenotes.FilterUntargeted] = true
+	}
+	if tags := r.URL.Query()["tag"]; len(tags) > 0 {
+		filters[releasenotes.FilterTags] = tags
+	}
+	// the release page is public, it only lists notes without audience rules
+	if forWidgetOrWebsite != "website" {
+		attrs, err := h.audienceAttributes(r, org.ID)
+		if err != nil {
+			h.writeIdentityError(w, err)
+			return
+		}
+		filters[releasenotes.FilterAudience] = attrs
 	}
 
-	releaseNotes, err := releaseNotesService.GetAllWithImgUrl(org.ID.String(), pageInt, pageSizeInt, filters)
+	lang := locale.Negotiate(r.URL.Query().Get("locale"), r.Header.Get("Accept-Language"), org.DefaultLocale)
+	attrs, _ := filters[releasenotes.FilterAudience].(releasenotes.AudienceAttributes)
+	entry, err := widgetcache.GetOrLoad(org.ID, cacheKey(r, lang, attrs), func() (interface{}, error) {
+		return h.loadReleaseNotes(org.ID, pageInt, pageSizeInt, filters, lang)
+	})
 	if err != nil {
 		h.Log.Error().Err(err).Msg("Error getting release notes")
 		http.Error(w, "Error getting release notes", http.StatusInternalServerError)
 		return
 	}
+
+	res, lastModified := entry.Value, entry.LoadedAt
+	// preview links show a single, possibly unpublished note on top of the published ones.
+	// Drafts don't invalidate the cache, so the preview is added to the cached response.
+	if token := r.URL.Query().Get("preview"); token != "" {
+		withPreview, err := h.addPreview(res.(*serveReleaseNotesWidgetResponseBody), org.ID, token, lang, pageInt)
+		if err != nil {
+			if errors.Is(err, releasenotes.ErrPreviewNotFound) {
+				http.Error(w, "Preview not found", http.StatusNotFound)
+				return
+			}
+			h.Log.Error().Err(err).Msg("Error getting preview")
+			http.Error(w, "Error getting release notes", http.StatusInternalServerError)
+			return
+		}
+		res, lastModified = withPreview, time.Now()
+	}
+
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	h.writeJSON(w, r, res, lastModified)
+}
+
+// addPreview returns a copy of res with the note of the preview token on top of the first page.
+// A published note of the preview is moved to the top rather than listed twice.
+func (h *Handlers) addPreview(res *serveReleaseNotesWidgetResponseBody, orgId uuid.UUID, token, lang string, page int) (*serveReleaseNotesWidgetResponseBody, error) {
+	h.Log.Trace().Msg("addPreview")
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+	rn, _, err := releaseNotesService.GetByPreviewToken(orgId, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := releaseNotesService.Localize([]*releasenotes.ReleaseNote{rn}, lang); err != nil {
+		h.Log.Error().Err(err).Msg("Error translating release note")
+		return nil, err
+	}
+
+	preview := h.newWidgetReleaseNote(rn, "")
+	preview.IsPreview = true
+	withPreview := &serveReleaseNotesWidgetResponseBody{}
+	if page <= 1 {
+		withPreview.Data = append(withPreview.Data, preview)
+	}
+	for _, item := range res.Data {
+		if item.ID != preview.ID {
+			withPreview.Data = append(withPreview.Data, item)
+		}
+	}
+	return withPreview, nil
+}
+
+// loadReleaseNotes builds the widget response for the release notes matching filters
+func (h *Handlers) loadReleaseNotes(orgId uuid.UUID, page, pageSize int, filters map[string]interface{}, lang string) (*serveReleaseNotesWidgetResponseBody, error) {
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+	releaseNotes, err := releaseNotesService.GetAllWithImgUrl(orgId.String(), page, pageSize, filters)
+	if err != nil {
+		return nil, err
+	}
 	h.Log.Debug().Int("releaseNotes", len(releaseNotes.Items)).Msg("Number of release notes")
 
+	if err := releaseNotesService.Localize(releaseNotes.Items, lang); err != nil {
+		h.Log.Error().Err(err).Msg("Error translating release notes")
+		return nil, err
+	}
+
+	// permalinks point to the hosted release page, so they are left empty if the page
+	// is disabled or the widget links to a custom release page
+	releasePageUrl := h.hostedReleasePageUrl(orgId)
+
 	var res serveReleaseNotesWidgetResponseBody
 	for _, rn := range releaseNotes.Items {
 		if rn.IsPublished == false {
 			continue
 		}
-		var releaseDate string
-		if rn.ReleaseDate != nil {
-			parsedDate, err := time.Parse("2006-01-02", *rn.ReleaseDate)
-			if err != nil {
-				h.Log.Warn().Err(err).Msg("Error parsing date")
-			} else {
-				releaseDate = parsedDate.Format("02.01.2006")
-			}
+		res.Data = append(res.Data, h.newWidgetReleaseNote(rn, releasePageUrl))
+	}
+
+	h.Log.Debug().Int("dataLength", len(res.Data)).Msg("Response data length")
+	return &res, nil
+}
+
+// newWidgetReleaseNote converts a release note to its representation in the widget
+func (h *Handlers) newWidgetReleaseNote(rn *releasenotes.ReleaseNote, releasePageUrl string) serveReleaseNotesWidgetResponseBodyReleaseNotes {
+	var releaseDate string
+	if rn.ReleaseDate != nil {
+		parsedDate, err := time.Parse("2006-01-02", *rn.ReleaseDate)
+		if err != nil {
+			h.Log.Warn().Err(err).Msg("Error parsing date")
 		} else {
-			releaseDate = ""
+			releaseDate = parsedDate.Format("02.01.2006")
 		}
-		h.Log.Debug().Str("releaseDate", releaseDate).Msg("Release date")
-		if rn.MediaLink != "" {
-			rn.MediaLink = util.TransformMediaLink(rn.MediaLink)
+	} else {
+		releaseDate = ""
+	}
+	h.Log.Debug().Str("releaseDate", releaseDate).Msg("Release date")
+	if rn.MediaLink != "" {
+		rn.MediaLink = util.TransformMediaLink(rn.MediaLink)
+	}
+	tags := make([]serveReleaseNotesWidgetResponseBodyTag, len(rn.Tags))
+	for i, t := range rn.Tags {
+		tags[i] = serveReleaseNotesWidgetResponseBodyTag{Name: t.Name, Color: t.Color}
+	}
+	media := []serveReleaseNotesWidgetResponseBodyMedia{}
+	for _, m := range rn.Media {
+		if m.Url == "" {
+			continue
 		}
-		res.Data = append(res.Data, serveReleaseNotesWidgetResponseBodyReleaseNotes{
-			ID:                 rn.ID.String(),
-			Title:              rn.Title,
-			Date:               releaseDate,
-			ImageSrc:           rn.ImageUrl,
-			MediaLink:          rn.MediaLink,
-			Text:               rn.DescriptionShort,
-			LastUpdateOn:       rn.UpdatedAt.String(),
-			CtaLabelOverride:   rn.CtaLabelOverride,
-			CtaHrefOverride:    rn.CtaUrlOverride,
-			HideCta:            rn.HideCta,
-			AttentionMechanism: rn.AttentionMechanism.String(),
+		media = append(media, serveReleaseNotesWidgetResponseBodyMedia{
+			Kind:        m.Kind.String(),
+			Src:         m.Url,
+			Alt:         m.AltText,
+			SrcSet:      m.SrcSet,
+			Width:       m.Width,
+			Height:      m.Height,
+			Placeholder: m.Placeholder,
 		})
 	}
+	return serveReleaseNotesWidgetResponseBodyReleaseNotes{
+		ID:                 rn.ID.String(),
+		Title:              rn.Title,
+		Date:               releaseDate,
+		ImageSrc:           rn.ImageUrl,
+		MediaLink:          rn.MediaLink,
+		Text:               rn.DescriptionShort,
+		TextHTML:           markdown.ToHTML(rn.DescriptionShort),
+		LastUpdateOn:       rn.UpdatedAt.String(),
+		CtaLabelOverride:   rn.CtaLabelOverride,
+		CtaHrefOverride:    rn.CtaUrlOverride,
+		Permalink:          rn.Permalink(releasePageUrl),
+		HideCta:            rn.HideCta,
+		AttentionMechanism: rn.AttentionMechanism.String(),
+		Tags:               tags,
+		Media:              media,
+	}
+}
 
-	h.Log.Debug().Int("dataLength", len(res.Data)).Msg("Response data length")
+// hostedReleasePageUrl returns the URL of the organisation's hosted release page, or an empty
+// string if the page is disabled, the widget links to a custom release page or the lookup fails
+func (h *Handlers) hostedReleasePageUrl(orgId uuid.UUID) string {
+	widgetConfigService := widgetconfigs.NewService(*widgetconfigs.NewRepository(h.DB))
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.DB, h.ObjStore))
 
-	w.Header().Set("Content-Type", "application/json")
-	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(res); err != nil {
-		h.Log.Error().Err(err).Msg("Error encoding response")
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
-		return
+	widgetConfig, err := widgetConfigService.Get(orgId)
+	if err != nil {
+		h.Log.Warn().Err(err).Msg("Error getting widget config, omitting permalinks")
+		return ""
 	}
-
-	w.WriteHeader(http.StatusOK)
+	if widgetConfig.ReleasePageBaseUrl != nil {
+		return ""
+	}
+	releasePageConfig, err := releasePageConfigService.Get(orgId)
+	if err != nil {
+		h.Log.Warn().Err(err).Msg("Error getting release page config, omitting permalinks")
+		return ""
+	}
+	if releasePageConfig.DisableReleasePage {
+		return ""
+	}
+	releasePageUrl, err := releasePageConfigService.GetUrl(orgId)
+	if err != nil {
+		h.Log.Warn().Err(err).Msg("Error getting release page URL, omitting permalinks")
+		return ""
+	}
+	return releasePageUrl
 }
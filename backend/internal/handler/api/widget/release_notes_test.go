@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/devbydaniel/announcable/internal/domain/identity"
 	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
 	"github.com/devbydaniel/announcable/internal/testutil"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -28,8 +33,7 @@ func TestHandleReleaseNotesServe_Success(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{})
-	require.NoError(t, err)
+	migrateReleaseNoteTables(t, testDB.DB)
 
 	// Create test organization
 	testOrg, err := organisation.New("Test Org")
@@ -56,7 +60,7 @@ func TestHandleReleaseNotesServe_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	// Publish the release note
-	err = releaseNotesService.ChangePublishedStatus(releaseNoteID, true)
+	err = releaseNotesService.ChangePublishedStatus(releaseNoteID, true, testUserID)
 	require.NoError(t, err)
 
 	// Update testReleaseNote with the created ID for assertions
@@ -91,6 +95,7 @@ func TestHandleReleaseNotesServe_Success(t *testing.T) {
 	assert.Equal(t, releaseNoteID.String(), response.Data[0].ID)
 	assert.Equal(t, "Test Release Note", response.Data[0].Title)
 	assert.Equal(t, "This is a test release note", response.Data[0].Text)
+	assert.Equal(t, "<p>This is a test release note</p>\n", response.Data[0].TextHTML)
 	assert.Equal(t, "15.01.2024", response.Data[0].Date)
 }
 
@@ -106,8 +111,7 @@ func TestHandleReleaseNotesServe_WithPagination(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{})
-	require.NoError(t, err)
+	migrateReleaseNoteTables(t, testDB.DB)
 
 	// Create test organization
 	testOrg, err := organisation.New("Test Org")
@@ -134,7 +138,7 @@ func TestHandleReleaseNotesServe_WithPagination(t *testing.T) {
 		require.NoError(t, err)
 
 		// Publish the release note
-		err = releaseNotesService.ChangePublishedStatus(rnID, true)
+		err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
 		require.NoError(t, err)
 	}
 
@@ -177,8 +181,7 @@ func TestHandleReleaseNotesServe_WithForWidget(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{})
-	require.NoError(t, err)
+	migrateReleaseNoteTables(t, testDB.DB)
 
 	// Create test organization
 	testOrg, err := organisation.New("Test Org")
@@ -204,7 +207,7 @@ func TestHandleReleaseNotesServe_WithForWidget(t *testing.T) {
 	require.NoError(t, err)
 
 	// Publish and hide on widget
-	err = releaseNotesService.ChangePublishedStatus(rnID, true)
+	err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
 	require.NoError(t, err)
 
 	// Update to hide on widget
@@ -239,7 +242,7 @@ func TestHandleReleaseNotesServe_WithForWidget(t *testing.T) {
 	assert.Len(t, response.Data, 0)
 }
 
-func TestHandleReleaseNotesServe_WithForWebsite(t *testing.T) {
+func TestHandleReleaseNotesServe_WithTagFilter(t *testing.T) {
 	cleanup := testutil.SetupTest()
 	defer cleanup()
 
@@ -251,8 +254,391 @@ func TestHandleReleaseNotesServe_WithForWebsite(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{})
+	migrateReleaseNoteTables(t, testDB.DB)
+
+	// Create test organization
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	// Create tags
+	tagService := tag.NewService(*tag.NewRepository(testDB.DB))
+	fixTag, err := tagService.Create(testOrg.ID, "Fix", "#ef4444")
+	require.NoError(t, err)
+	newTag, err := tagService.Create(testOrg.ID, "New", "#22c55e")
+	require.NoError(t, err)
+
+	// Create one published release note per tag
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+	releaseDate := "2024-01-15"
+	for _, tg := range []*tag.Tag{fixTag, newTag} {
+		rn := &releasenotes.ReleaseNote{
+			OrganisationID:   testOrg.ID,
+			Title:            tg.Name + " Release Note",
+			DescriptionShort: "Description",
+			ReleaseDate:      &releaseDate,
+			CreatedBy:        testUserID,
+			LastUpdatedBy:    testUserID,
+			Tags:             []*tag.Tag{tg},
+		}
+		rnID, err := releaseNotesService.Create(rn, nil)
+		require.NoError(t, err)
+		err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+		require.NoError(t, err)
+	}
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+
+	// Create test request filtering by tag, matched case-insensitively
+	req := httptest.NewRequest(http.MethodGet, "/api/widget/"+testOrg.ExternalID.String()+"/release-notes?tag=fix", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	// Create response recorder
+	rr := httptest.NewRecorder()
+
+	// Execute handler
+	handlers.HandleReleaseNotesServe(rr, req)
+
+	// Assert response
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Parse response body
+	var response serveReleaseNotesWidgetResponseBody
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	require.NoError(t, err)
+
+	// Should only include the release note tagged "Fix", with its tag badge
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "Fix Release Note", response.Data[0].Title)
+	require.Len(t, response.Data[0].Tags, 1)
+	assert.Equal(t, "Fix", response.Data[0].Tags[0].Name)
+	assert.Equal(t, "#ef4444", response.Data[0].Tags[0].Color)
+}
+
+func TestHandleReleaseNotesServe_WithLocale(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB)
+
+	// Create test organization
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	// Create release note with a German translation
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+
+	rn := &releasenotes.ReleaseNote{
+		OrganisationID:   testOrg.ID,
+		Title:            "Dark mode",
+		DescriptionShort: "Switch to a dark theme",
+		DescriptionLong:  "Long description",
+		CreatedBy:        testUserID,
+		LastUpdatedBy:    testUserID,
+		Translations: []*releasenotes.ReleaseNoteTranslation{
+			{Locale: "de", Title: "Dunkelmodus", DescriptionShort: "Wechsle zu einem dunklen Design"},
+		},
+	}
+	rnID, err := releaseNotesService.Create(rn, nil)
+	require.NoError(t, err)
+	err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+	require.NoError(t, err)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		expectedLang   string
+		expectedTitle  string
+	}{
+		{name: "accept language", acceptLanguage: "de-DE,de;q=0.9,en;q=0.8", expectedLang: "de", expectedTitle: "Dunkelmodus"},
+		{name: "locale param wins", query: "?locale=en", acceptLanguage: "de", expectedLang: "en", expectedTitle: "Dark mode"},
+		{name: "untranslated language", query: "?locale=fr", expectedLang: "fr", expectedTitle: "Dark mode"},
+		{name: "default language", expectedLang: "en", expectedTitle: "Dark mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/widget/"+testOrg.ExternalID.String()+"/release-notes"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handlers.HandleReleaseNotesServe(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tt.expectedLang, rr.Header().Get("Content-Language"))
+
+			var response serveReleaseNotesWidgetResponseBody
+			err := json.NewDecoder(rr.Body).Decode(&response)
+			require.NoError(t, err)
+			require.Len(t, response.Data, 1)
+			assert.Equal(t, tt.expectedTitle, response.Data[0].Title)
+		})
+	}
+}
+
+func TestHandleReleaseNotesServe_WithAudience(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB)
+
+	// Create test organization
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	// Create a note for everyone and one for enterprise admins
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+
+	notes := []*releasenotes.ReleaseNote{
+		{
+			OrganisationID:   testOrg.ID,
+			Title:            "For everyone",
+			DescriptionShort: "Description",
+			CreatedBy:        testUserID,
+			LastUpdatedBy:    testUserID,
+		},
+		{
+			OrganisationID:   testOrg.ID,
+			Title:            "For enterprise admins",
+			DescriptionShort: "Description",
+			CreatedBy:        testUserID,
+			LastUpdatedBy:    testUserID,
+			AudienceRules: []*releasenotes.AudienceRule{
+				{Attribute: "plan", Operator: releasenotes.AudienceOperatorIn, Value: "enterprise"},
+				{Attribute: "role", Operator: releasenotes.AudienceOperatorIn, Value: "admin"},
+			},
+		},
+	}
+	for _, rn := range notes {
+		rnID, err := releaseNotesService.Create(rn, nil)
+		require.NoError(t, err)
+		err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+		require.NoError(t, err)
+	}
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedTitles []string
+	}{
+		{name: "no attributes", query: "?for=widget", expectedStatus: http.StatusOK, expectedTitles: []string{"For everyone"}},
+		{
+			name:           "matching attributes",
+			query:          "?for=widget&attributes=" + url.QueryEscape(`{"plan":"enterprise","role":"admin"}`),
+			expectedStatus: http.StatusOK,
+			expectedTitles: []string{"For everyone", "For enterprise admins"},
+		},
+		{
+			name:           "partially matching attributes",
+			query:          "?for=widget&attributes=" + url.QueryEscape(`{"plan":"enterprise","role":"member"}`),
+			expectedStatus: http.StatusOK,
+			expectedTitles: []string{"For everyone"},
+		},
+		{name: "release page leaves out targeted notes", query: "?for=website", expectedStatus: http.StatusOK, expectedTitles: []string{"For everyone"}},
+		{
+			name:           "release page ignores attributes",
+			query:          "?for=website&attributes=" + url.QueryEscape(`{"plan":"enterprise","role":"admin"}`),
+			expectedStatus: http.StatusOK,
+			expectedTitles: []string{"For everyone"},
+		},
+		{name: "invalid attributes", query: "?for=widget&attributes=" + url.QueryEscape(`{"plan":{}}`), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/widget/"+testOrg.ExternalID.String()+"/release-notes"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handlers.HandleReleaseNotesServe(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response serveReleaseNotesWidgetResponseBody
+			err := json.NewDecoder(rr.Body).Decode(&response)
+			require.NoError(t, err)
+			titles := make([]string, len(response.Data))
+			for i, rn := range response.Data {
+				titles[i] = rn.Title
+			}
+			assert.ElementsMatch(t, tt.expectedTitles, titles)
+		})
+	}
+}
+
+func TestHandleReleaseNotesServe_WithUserToken(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB)
+
+	// Create test organization with an identity secret
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	identityService := identity.NewService(*identity.NewRepository(testDB.DB))
+	err = identityService.RotateSecret(testOrg.ID)
+	require.NoError(t, err)
+	identityConfig, err := identityService.Get(testOrg.ID)
+	require.NoError(t, err)
+
+	// Create a note for everyone and one for enterprise customers
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+
+	notes := []*releasenotes.ReleaseNote{
+		{
+			OrganisationID:   testOrg.ID,
+			Title:            "For everyone",
+			DescriptionShort: "Description",
+			CreatedBy:        testUserID,
+			LastUpdatedBy:    testUserID,
+		},
+		{
+			OrganisationID:   testOrg.ID,
+			Title:            "For enterprise",
+			DescriptionShort: "Description",
+			CreatedBy:        testUserID,
+			LastUpdatedBy:    testUserID,
+			AudienceRules: []*releasenotes.AudienceRule{
+				{Attribute: "plan", Operator: releasenotes.AudienceOperatorIn, Value: "enterprise"},
+			},
+		},
+	}
+	for _, rn := range notes {
+		rnID, err := releaseNotesService.Create(rn, nil)
+		require.NoError(t, err)
+		err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+		require.NoError(t, err)
+	}
+
+	enterpriseToken, err := identity.Sign(identityConfig.Secret, "user-1", map[string]interface{}{"plan": "enterprise"}, nil)
+	require.NoError(t, err)
+	freeToken, err := identity.Sign(identityConfig.Secret, "user-2", map[string]interface{}{"plan": "free"}, nil)
 	require.NoError(t, err)
+	forgedToken, err := identity.Sign("idsec_forged", "user-1", map[string]interface{}{"plan": "enterprise"}, nil)
+	require.NoError(t, err)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+
+	enterpriseAttributes := "&attributes=" + url.QueryEscape(`{"plan":"enterprise"}`)
+	tests := []struct {
+		name           string
+		query          string
+		token          string
+		requireSigned  bool
+		expectedStatus int
+		expectedTitles []string
+	}{
+		{name: "signed attributes", token: enterpriseToken, expectedStatus: http.StatusOK, expectedTitles: []string{"For everyone", "For enterprise"}},
+		{name: "signed attributes replace unsigned ones", query: enterpriseAttributes, token: freeToken, expectedStatus: http.StatusOK, expectedTitles: []string{"For everyone"}},
+		{name: "forged token", token: forgedToken, expectedStatus: http.StatusUnauthorized},
+		{name: "unsigned attributes", query: enterpriseAttributes, expectedStatus: http.StatusOK, expectedTitles: []string{"For everyone", "For enterprise"}},
+		{name: "unsigned attributes ignored if signed identities are required", query: enterpriseAttributes, requireSigned: true, expectedStatus: http.StatusOK, expectedTitles: []string{"For everyone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := identityService.SetRequireSigned(testOrg.ID, tt.requireSigned)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/widget/"+testOrg.ExternalID.String()+"/release-notes?for=widget"+tt.query, nil)
+			if tt.token != "" {
+				req.Header.Set(HeaderUserToken, tt.token)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handlers.HandleReleaseNotesServe(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response serveReleaseNotesWidgetResponseBody
+			err = json.NewDecoder(rr.Body).Decode(&response)
+			require.NoError(t, err)
+			titles := make([]string, len(response.Data))
+			for i, rn := range response.Data {
+				titles[i] = rn.Title
+			}
+			assert.ElementsMatch(t, tt.expectedTitles, titles)
+		})
+	}
+}
+
+func TestHandleReleaseNotesServe_WithForWebsite(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB)
 
 	// Create test organization
 	testOrg, err := organisation.New("Test Org")
@@ -278,7 +664,7 @@ func TestHandleReleaseNotesServe_WithForWebsite(t *testing.T) {
 	require.NoError(t, err)
 
 	// Publish and hide on release page
-	err = releaseNotesService.ChangePublishedStatus(rnID, true)
+	err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
 	require.NoError(t, err)
 
 	// Update to hide on release page
@@ -325,7 +711,7 @@ func TestHandleReleaseNotesServe_InvalidPage(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{})
+	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &widgetconfigs.WidgetConfig{})
 	require.NoError(t, err)
 
 	// Create test organization
@@ -366,7 +752,7 @@ func TestHandleReleaseNotesServe_InvalidPageSize(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{})
+	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &widgetconfigs.WidgetConfig{})
 	require.NoError(t, err)
 
 	// Create test organization
@@ -438,7 +824,7 @@ func TestHandleReleaseNotesServe_OrganisationNotFound(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{})
+	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &widgetconfigs.WidgetConfig{})
 	require.NoError(t, err)
 
 	// Create handler
@@ -458,8 +844,8 @@ func TestHandleReleaseNotesServe_OrganisationNotFound(t *testing.T) {
 	handlers.HandleReleaseNotesServe(rr, req)
 
 	// Assert response
-	assert.Equal(t, http.StatusInternalServerError, rr.Code)
-	assert.Contains(t, rr.Body.String(), "Error getting widget config")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Organisation not found")
 }
 
 func TestHandleReleaseNotesServe_OnlyPublishedReleaseNotes(t *testing.T) {
@@ -474,8 +860,7 @@ func TestHandleReleaseNotesServe_OnlyPublishedReleaseNotes(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{})
-	require.NoError(t, err)
+	migrateReleaseNoteTables(t, testDB.DB)
 
 	// Create test organization
 	testOrg, err := organisation.New("Test Org")
@@ -500,7 +885,7 @@ func TestHandleReleaseNotesServe_OnlyPublishedReleaseNotes(t *testing.T) {
 	}
 	publishedRNID, err := releaseNotesService.Create(publishedRN, nil)
 	require.NoError(t, err)
-	err = releaseNotesService.ChangePublishedStatus(publishedRNID, true)
+	err = releaseNotesService.ChangePublishedStatus(publishedRNID, true, testUserID)
 	require.NoError(t, err)
 
 	// Create unpublished release note
@@ -544,7 +929,7 @@ func TestHandleReleaseNotesServe_OnlyPublishedReleaseNotes(t *testing.T) {
 	assert.Equal(t, "Published Release Note", response.Data[0].Title)
 }
 
-func TestHandleReleaseNotesServe_EmptyReleaseDate(t *testing.T) {
+func TestHandleReleaseNotesServe_WithPreview(t *testing.T) {
 	cleanup := testutil.SetupTest()
 	defer cleanup()
 
@@ -556,8 +941,98 @@ func TestHandleReleaseNotesServe_EmptyReleaseDate(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{})
+	migrateReleaseNoteTables(t, testDB.DB)
+
+	// Create test organization
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+	releaseDate := "2024-01-15"
+
+	// Create published release note
+	publishedRN := &releasenotes.ReleaseNote{
+		OrganisationID:   testOrg.ID,
+		Title:            "Published Release Note",
+		DescriptionShort: "Published description",
+		ReleaseDate:      &releaseDate,
+		CreatedBy:        testUserID,
+		LastUpdatedBy:    testUserID,
+	}
+	publishedRNID, err := releaseNotesService.Create(publishedRN, nil)
 	require.NoError(t, err)
+	err = releaseNotesService.ChangePublishedStatus(publishedRNID, true, testUserID)
+	require.NoError(t, err)
+
+	// Create draft with a preview link
+	draftRN := &releasenotes.ReleaseNote{
+		OrganisationID:   testOrg.ID,
+		Title:            "Draft Release Note",
+		DescriptionShort: "Draft description",
+		ReleaseDate:      &releaseDate,
+		CreatedBy:        testUserID,
+		LastUpdatedBy:    testUserID,
+	}
+	draftRNID, err := releaseNotesService.Create(draftRN, nil)
+	require.NoError(t, err)
+	token, _, err := releaseNotesService.CreatePreview(draftRNID, testOrg.ID, testUserID, 24*time.Hour)
+	require.NoError(t, err)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+
+	serve := func(preview string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/widget/"+testOrg.ExternalID.String()+"/release-notes?preview="+url.QueryEscape(preview), nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		rr := httptest.NewRecorder()
+		handlers.HandleReleaseNotesServe(rr, req)
+		return rr
+	}
+
+	// The draft is shown on top of the published notes
+	rr := serve(token)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response serveReleaseNotesWidgetResponseBody
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	require.NoError(t, err)
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, draftRNID.String(), response.Data[0].ID)
+	assert.True(t, response.Data[0].IsPreview)
+	assert.Equal(t, publishedRNID.String(), response.Data[1].ID)
+	assert.False(t, response.Data[1].IsPreview)
+
+	// Unknown tokens are rejected
+	rr = serve("unknown")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Revoked tokens stop working
+	previews, err := releaseNotesService.GetPreviews(draftRNID, testOrg.ID)
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	err = releaseNotesService.RevokePreview(previews[0].ID, draftRNID, testOrg.ID)
+	require.NoError(t, err)
+	rr = serve(token)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestHandleReleaseNotesServe_EmptyReleaseDate(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB)
 
 	// Create test organization
 	testOrg, err := organisation.New("Test Org")
@@ -582,7 +1057,7 @@ func TestHandleReleaseNotesServe_EmptyReleaseDate(t *testing.T) {
 	require.NoError(t, err)
 
 	// Publish the release note
-	err = releaseNotesService.ChangePublishedStatus(rnID, true)
+	err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
 	require.NoError(t, err)
 
 	// Create handler
@@ -613,7 +1088,7 @@ func TestHandleReleaseNotesServe_EmptyReleaseDate(t *testing.T) {
 	assert.Equal(t, "", response.Data[0].Date)
 }
 
-func TestHandleReleaseNotesServe_DefaultPagination(t *testing.T) {
+func TestHandleReleaseNotesServe_Media(t *testing.T) {
 	cleanup := testutil.SetupTest()
 	defer cleanup()
 
@@ -625,9 +1100,79 @@ func TestHandleReleaseNotesServe_DefaultPagination(t *testing.T) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{})
+	migrateReleaseNoteTables(t, testDB.DB)
+
+	// Create test organization
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	// Create release note with two videos
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+
+	rn := &releasenotes.ReleaseNote{
+		OrganisationID:   testOrg.ID,
+		Title:            "Test Release Note",
+		DescriptionShort: "Description",
+		CreatedBy:        testUserID,
+		LastUpdatedBy:    testUserID,
+	}
+	rnID, err := releaseNotesService.Create(rn, []*releasenotes.MediaInput{
+		{Kind: releasenotes.MediaKindEmbed, Link: "https://www.loom.com/share/abc", AltText: "Walkthrough"},
+		{Kind: releasenotes.MediaKindEmbed, Link: "https://www.youtube.com/watch?v=xyz"},
+	})
 	require.NoError(t, err)
 
+	err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+	require.NoError(t, err)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+
+	// Create test request
+	req := httptest.NewRequest(http.MethodGet, "/api/widget/"+testOrg.ExternalID.String()+"/release-notes", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	// Create response recorder
+	rr := httptest.NewRecorder()
+
+	// Execute handler
+	handlers.HandleReleaseNotesServe(rr, req)
+
+	// Assert response
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response serveReleaseNotesWidgetResponseBody
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	require.NoError(t, err)
+
+	// Verify all attachments are served in order, media_link only has the first one
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "https://www.loom.com/embed/abc", response.Data[0].MediaLink)
+	assert.Equal(t, []serveReleaseNotesWidgetResponseBodyMedia{
+		{Kind: "embed", Src: "https://www.loom.com/embed/abc", Alt: "Walkthrough"},
+		{Kind: "embed", Src: "https://www.youtube.com/embed/xyz", Alt: ""},
+	}, response.Data[0].Media)
+}
+
+func TestHandleReleaseNotesServe_DefaultPagination(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB)
+
 	// Create test organization
 	testOrg, err := organisation.New("Test Org")
 	require.NoError(t, err)
@@ -673,7 +1218,7 @@ func BenchmarkHandleReleaseNotesServe(b *testing.B) {
 	deps := testutil.NewMockDependencies(testDB.DB)
 
 	// Auto-migrate tables
-	testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &releasenotes.ReleaseNote{})
+	migrateReleaseNoteTables(b, testDB.DB)
 
 	// Create test organization
 	testOrg, _ := organisation.New("Bench Org")
@@ -695,7 +1240,7 @@ func BenchmarkHandleReleaseNotesServe(b *testing.B) {
 			LastUpdatedBy:    testUserID,
 		}
 		rnID, _ := releaseNotesService.Create(rn, nil)
-		releaseNotesService.ChangePublishedStatus(rnID, true)
+		releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
 	}
 
 	// Create handler
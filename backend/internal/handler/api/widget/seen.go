@@ -0,0 +1,77 @@
+package widget
+
+import (
+	"encoding/json"
+	"net/http"
+
+	releasenoteseen "github.com/devbydaniel/announcable/internal/domain/release-note-seen"
+	"github.com/google/uuid"
+)
+
+const (
+	// release notes marked seen in one request at most, the widget shows up to 100 notes
+	maxSeenReleaseNotes = 100
+	// request bodies are small, larger ones are rejected
+	maxSeenBodyBytes = 8 << 10
+)
+
+type markSeenRequest struct {
+	ReleaseNoteIDs []string `json:"release_note_ids"`
+	ClientID       string   `json:"client_id"`
+}
+
+// HandleReleaseNotesMarkSeen marks release notes as read by the end user
+func (h *Handlers) HandleReleaseNotesMarkSeen(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNotesMarkSeen")
+
+	org, ok := h.getOrg(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse request body
+	var req markSeenRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSeenBodyBytes)).Decode(&req); err != nil {
+		h.Log.Error().Err(err).Msg("Error decoding request body")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Attribute the seen state to the signed user identity if there is one
+	clientId, err := h.resolveClientId(r, org.ID, req.ClientID)
+	if err != nil {
+		h.writeIdentityError(w, err)
+		return
+	}
+	if clientId == "" {
+		h.Log.Error().Msg("Missing required fields")
+		http.Error(w, "Missing required fields", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.ReleaseNoteIDs) > maxSeenReleaseNotes {
+		http.Error(w, "Too many release notes", http.StatusBadRequest)
+		return
+	}
+
+	// Parse UUIDs
+	releaseNoteIds := make([]uuid.UUID, 0, len(req.ReleaseNoteIDs))
+	for _, id := range req.ReleaseNoteIDs {
+		releaseNoteUUID, err := uuid.Parse(id)
+		if err != nil {
+			h.Log.Error().Err(err).Msg("Invalid release note ID")
+			http.Error(w, "Invalid release note ID", http.StatusBadRequest)
+			return
+		}
+		releaseNoteIds = append(releaseNoteIds, releaseNoteUUID)
+	}
+
+	seenService := releasenoteseen.NewService(releasenoteseen.NewRepository(h.DB))
+	if err := seenService.MarkSeen(org.ID, clientId, releaseNoteIds); err != nil {
+		h.Log.Error().Err(err).Msg("Error marking release notes seen")
+		http.Error(w, "Error marking release notes seen", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
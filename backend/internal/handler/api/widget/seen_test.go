@@ -0,0 +1,236 @@
+package widget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devbydaniel/announcable/internal/domain/identity"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenoteseen "github.com/devbydaniel/announcable/internal/domain/release-note-seen"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleReleaseNotesMarkSeen(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB, &releasenoteseen.SeenReleaseNote{})
+
+	// Create test organization with two published notes
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+	var releaseNoteIds []uuid.UUID
+	for _, title := range []string{"Seen", "Unseen"} {
+		rnID, err := releaseNotesService.Create(&releasenotes.ReleaseNote{
+			OrganisationID:   testOrg.ID,
+			Title:            title,
+			DescriptionShort: "Description",
+			CreatedBy:        testUserID,
+			LastUpdatedBy:    testUserID,
+		}, nil)
+		require.NoError(t, err)
+		err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+		require.NoError(t, err)
+		releaseNoteIds = append(releaseNoteIds, rnID)
+	}
+	seenID, unseenID := releaseNoteIds[0], releaseNoteIds[1]
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+	newRequest := func(method, target, body string) *http.Request {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	// Mark the first note seen
+	rr := httptest.NewRecorder()
+	handlers.HandleReleaseNotesMarkSeen(rr, newRequest(http.MethodPost, "/api/release-notes/"+testOrg.ExternalID.String()+"/seen", `{"client_id":"client-1","release_note_ids":["`+seenID.String()+`"]}`))
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	// The status tells which notes are unread by the client
+	rr = httptest.NewRecorder()
+	handlers.HandleReleaseNotesStatusServe(rr, newRequest(http.MethodGet, "/api/release-notes/"+testOrg.ExternalID.String()+"/status?for=widget&clientId=client-1", ""))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var status releaseNotesStatusResponseBody
+	err = json.NewDecoder(rr.Body).Decode(&status)
+	require.NoError(t, err)
+	require.Len(t, status.Data, 2)
+	for _, rn := range status.Data {
+		require.NotNil(t, rn.IsUnread)
+		assert.Equal(t, rn.ID == unseenID.String(), *rn.IsUnread, rn.ID)
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedCount int
+	}{
+		{name: "client that has seen a note", query: "?for=widget&clientId=client-1", expectedCount: 1},
+		{name: "other client", query: "?for=widget&clientId=client-2", expectedCount: 2},
+		{name: "unknown client", query: "?for=widget", expectedCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handlers.HandleReleaseNotesUnreadCountServe(rr, newRequest(http.MethodGet, "/api/release-notes/"+testOrg.ExternalID.String()+"/unread-count"+tt.query, ""))
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			var response unreadCountResponseBody
+			err := json.NewDecoder(rr.Body).Decode(&response)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, response.Count)
+		})
+	}
+}
+
+func TestHandleReleaseNotesMarkSeen_InvalidRequest(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	err := testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &widgetconfigs.WidgetConfig{}, &identity.IdentityConfig{})
+	require.NoError(t, err)
+
+	// Create test organization
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed body", body: `{"client_id":`},
+		{name: "missing client ID", body: `{"release_note_ids":[]}`},
+		{name: "invalid release note ID", body: `{"client_id":"client-1","release_note_ids":["not-a-uuid"]}`},
+		{name: "too many release notes", body: `{"client_id":"client-1","release_note_ids":["` + strings.Repeat(uuid.NewString()+`","`, 100) + uuid.NewString() + `"]}`},
+		{name: "invalid client ID", body: `{"client_id":"client 1","release_note_ids":[]}`},
+		{name: "oversized body", body: `{"client_id":"` + strings.Repeat("a", 10<<10) + `","release_note_ids":[]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/release-notes/"+testOrg.ExternalID.String()+"/seen", strings.NewReader(tt.body))
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handlers.HandleReleaseNotesMarkSeen(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
+
+func TestHandleReleaseNotesMarkSeen_SignedClientId(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB, &releasenoteseen.SeenReleaseNote{})
+
+	// Create test organization with an identity secret and a published note
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	identityService := identity.NewService(*identity.NewRepository(testDB.DB))
+	err = identityService.RotateSecret(testOrg.ID)
+	require.NoError(t, err)
+	identityConfig, err := identityService.Get(testOrg.ID)
+	require.NoError(t, err)
+	token, err := identity.Sign(identityConfig.Secret, "user-1", nil, nil)
+	require.NoError(t, err)
+
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+	rnID, err := releaseNotesService.Create(&releasenotes.ReleaseNote{
+		OrganisationID:   testOrg.ID,
+		Title:            "Note",
+		DescriptionShort: "Description",
+		CreatedBy:        testUserID,
+		LastUpdatedBy:    testUserID,
+	}, nil)
+	require.NoError(t, err)
+	err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+	require.NoError(t, err)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+	newRequest := func(method, target, body, token string) *http.Request {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set(HeaderUserToken, token)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+	body := `{"client_id":"user:user-1","release_note_ids":["` + rnID.String() + `"]}`
+
+	// Anonymous visitors can't pass themselves off as the signed user
+	rr := httptest.NewRecorder()
+	handlers.HandleReleaseNotesMarkSeen(rr, newRequest(http.MethodPost, "/api/release-notes/"+testOrg.ExternalID.String()+"/seen", body, ""))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handlers.HandleReleaseNotesUnreadCountServe(rr, newRequest(http.MethodGet, "/api/release-notes/"+testOrg.ExternalID.String()+"/unread-count?for=widget&clientId=user:user-1", "", ""))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// The signed user's own requests are attributed to the prefixed user ID
+	rr = httptest.NewRecorder()
+	handlers.HandleReleaseNotesMarkSeen(rr, newRequest(http.MethodPost, "/api/release-notes/"+testOrg.ExternalID.String()+"/seen", body, token))
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	var seen []releasenoteseen.SeenReleaseNote
+	err = testDB.DB.Client.Find(&seen).Error
+	require.NoError(t, err)
+	require.Len(t, seen, 1)
+	assert.Equal(t, "user:user-1", seen[0].ClientID)
+}
@@ -1,84 +1,156 @@
 package widget
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/devbydaniel/announcable/internal/domain/identity"
 	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenoteseen "github.com/devbydaniel/announcable/internal/domain/release-note-seen"
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
-	"github.com/devbydaniel/announcable/internal/memcache"
-	"github.com/go-chi/chi/v5"
+	"github.com/devbydaniel/announcable/internal/widgetcache"
 	"github.com/google/uuid"
 )
 
 type releaseNotesStatusResponseBodyData struct {
+	ID                 string `json:"id"`
 	LastUpdateOn       string `json:"last_update_on"`
 	AttentionMechanism string `json:"attention_mechanism"`
+	// only set if the end user is known
+	IsUnread *bool `json:"is_unread,omitempty"`
 }
 
 type releaseNotesStatusResponseBody struct {
 	Data []releaseNotesStatusResponseBodyData `json:"data"`
 }
 
-var MemCacheReleaseNotesStatus = memcache.New(5*time.Minute, 10*time.Minute)
+type unreadCountResponseBody struct {
+	Count int `json:"count"`
+}
 
-// HandleReleaseNotesStatusServe serves release notes status for the widget
+// HandleReleaseNotesStatusServe serves release notes status for the widget. With a client ID
+// or a signed identity, every note also tells whether the end user has read it.
 func (h *Handlers) HandleReleaseNotesStatusServe(w http.ResponseWriter, r *http.Request) {
 	h.Log.Trace().Msg("HandleReleaseNotesStatusServe")
-	externalOrgId := chi.URLParam(r, "orgId")
-	if externalOrgId == "" {
-		h.Log.Error().Msg("Org ID not found in URL")
-		http.Error(w, "Error getting release notes status", http.StatusBadRequest)
+	org, entry, ok := h.releaseNotesStatus(w, r)
+	if !ok {
 		return
 	}
-
-	var releaseNotesStatus []*releasenotes.ReleaseNoteStatus
-	status, found := MemCacheReleaseNotesStatus.Get(externalOrgId)
-	if found {
-		h.Log.Trace().Msg("Found in cache")
-		releaseNotesStatus = status.([]*releasenotes.ReleaseNoteStatus)
-	} else {
-		organisationService := organisation.NewService(*organisation.NewRepository(h.DB))
-		releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
-		forWidgetOrWebsite := r.URL.Query().Get("for")
-		h.Log.Trace().Msg("Not found in cache")
-		org, err := organisationService.GetOrgByExternalId(uuid.MustParse(externalOrgId))
-		if err != nil {
-			h.Log.Error().Err(err).Msg("Error getting org ID")
-			http.Error(w, "Error getting release notes status", http.StatusInternalServerError)
-			return
-		}
-		filters := map[string]interface{}{}
-		if forWidgetOrWebsite == "widget" {
-			filters["hide_on_widget"] = false
-		} else if forWidgetOrWebsite == "website" {
-			filters["hide_on_release_page"] = false
-		}
-		status, err := releaseNotesService.GetStatus(org.ID.String(), filters)
-		if err != nil {
-			h.Log.Error().Err(err).Msg("Error getting release notes status")
-			http.Error(w, "Error getting release notes status", http.StatusInternalServerError)
-			return
-		}
-		MemCacheReleaseNotesStatus.Set(externalOrgId, status, 5*time.Minute)
-		releaseNotesStatus = status
+	releaseNotesStatus := entry.Value.([]*releasenotes.ReleaseNoteStatus)
+	unread, ok := h.unreadReleaseNotes(w, r, org.ID, releaseNotesStatus)
+	if !ok {
+		return
 	}
 
 	var res releaseNotesStatusResponseBody
 	for _, rn := range releaseNotesStatus {
-		res.Data = append(res.Data, releaseNotesStatusResponseBodyData{
+		data := releaseNotesStatusResponseBodyData{
+			ID:                 rn.ID.String(),
 			LastUpdateOn:       rn.UpdatedAt,
 			AttentionMechanism: rn.AttentionMechanism,
-		})
+		}
+		if unread != nil {
+			isUnread := unread[rn.ID]
+			data.IsUnread = &isUnread
+		}
+		res.Data = append(res.Data, data)
+	}
+	// the read state changes without the cached status changing
+	lastModified := entry.LoadedAt
+	if unread != nil {
+		lastModified = time.Time{}
 	}
-	w.Header().Set("Content-Type", "application/json")
-	encoder := json.NewEncoder(w)
-	if err := encoder.Encode(res); err != nil {
-		h.Log.Error().Err(err).Msg("Error encoding response")
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	h.writeJSON(w, r, res, lastModified)
+}
+
+// HandleReleaseNotesUnreadCountServe serves the number of release notes the end user hasn't
+// read. Without a client ID or signed identity all notes are unread.
+func (h *Handlers) HandleReleaseNotesUnreadCountServe(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleReleaseNotesUnreadCountServe")
+	org, entry, ok := h.releaseNotesStatus(w, r)
+	if !ok {
 		return
 	}
+	releaseNotesStatus := entry.Value.([]*releasenotes.ReleaseNoteStatus)
+	unread, ok := h.unreadReleaseNotes(w, r, org.ID, releaseNotesStatus)
+	if !ok {
+		return
+	}
+
+	res := unreadCountResponseBody{Count: len(releaseNotesStatus)}
+	lastModified := entry.LoadedAt
+	if unread != nil {
+		res.Count = len(unread)
+		lastModified = time.Time{}
+	}
+	h.writeJSON(w, r, res, lastModified)
+}
+
+// releaseNotesStatus returns the organisation of the request and the cached status of the release
+// notes shown to the end user. It writes the error response and returns false if that fails.
+func (h *Handlers) releaseNotesStatus(w http.ResponseWriter, r *http.Request) (*organisation.Organisation, *widgetcache.Entry, bool) {
+	org, ok := h.getOrg(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	forWidgetOrWebsite := r.URL.Query().Get("for")
+	filters := map[string]interface{}{}
+	if forWidgetOrWebsite == "widget" {
+		filters["hide_on_widget"] = false
+	} else if forWidgetOrWebsite == "website" {
+		filters["hide_on_release_page"] = false
+		filters[releasenotes.FilterUntargeted] = true
+	}
+	var attrs releasenotes.AudienceAttributes
+	if forWidgetOrWebsite != "website" {
+		var err error
+		attrs, err = h.audienceAttributes(r, org.ID)
+		if err != nil {
+			h.writeIdentityError(w, err)
+			return nil, nil, false
+		}
+		filters[releasenotes.FilterAudience] = attrs
+	}
+
+	entry, err := widgetcache.GetOrLoad(org.ID, cacheKey(r, "", attrs), func() (interface{}, error) {
+		releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+		return releaseNotesService.GetStatus(org.ID.String(), filters)
+	})
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting release notes status")
+		http.Error(w, "Error getting release notes status", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	return org, entry, true
+}
 
-	w.WriteHeader(http.StatusOK)
+// unreadReleaseNotes returns which of the release notes the end user hasn't read, or nil if the
+// end user is unknown. It writes the error response and returns false if that fails.
+func (h *Handlers) unreadReleaseNotes(w http.ResponseWriter, r *http.Request, orgId uuid.UUID, releaseNotesStatus []*releasenotes.ReleaseNoteStatus) (map[uuid.UUID]bool, bool) {
+	clientId, err := h.resolveClientId(r, orgId, r.URL.Query().Get("clientId"))
+	if errors.Is(err, identity.ErrIdentityRequired) {
+		return nil, true
+	}
+	if err != nil {
+		h.writeIdentityError(w, err)
+		return nil, false
+	}
+	if clientId == "" {
+		return nil, true
+	}
+
+	ids := make([]uuid.UUID, len(releaseNotesStatus))
+	for i, rn := range releaseNotesStatus {
+		ids[i] = rn.ID
+	}
+	seenService := releasenoteseen.NewService(releasenoteseen.NewRepository(h.DB))
+	unread, err := seenService.GetUnread(clientId, ids)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting unread release notes")
+		http.Error(w, "Error getting release notes status", http.StatusInternalServerError)
+		return nil, false
+	}
+	return unread, true
 }
@@ -0,0 +1,106 @@
+package widget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenoteseen "github.com/devbydaniel/announcable/internal/domain/release-note-seen"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleReleaseNotesStatusServe_Cache(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+
+	// Setup test database
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+
+	// Create test dependencies
+	deps := testutil.NewMockDependencies(testDB.DB)
+
+	// Auto-migrate tables
+	migrateReleaseNoteTables(t, testDB.DB, &releasenoteseen.SeenReleaseNote{})
+
+	// Create test organization
+	testOrg, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	err = testDB.DB.Client.Create(testOrg).Error
+	require.NoError(t, err)
+
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(testDB.DB, deps.ObjStore))
+	testUserID := uuid.New()
+	publish := func(title string, hideOnWidget bool) {
+		rnID, err := releaseNotesService.Create(&releasenotes.ReleaseNote{
+			OrganisationID:   testOrg.ID,
+			Title:            title,
+			DescriptionShort: "Description",
+			HideOnWidget:     hideOnWidget,
+			CreatedBy:        testUserID,
+			LastUpdatedBy:    testUserID,
+		}, nil)
+		require.NoError(t, err)
+		err = releaseNotesService.ChangePublishedStatus(rnID, true, testUserID)
+		require.NoError(t, err)
+	}
+	publish("On widget", false)
+	publish("Release page only", true)
+
+	// Create handler
+	handlers := New(deps.ToSharedDependencies())
+	get := func(query, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/release-notes/"+testOrg.ExternalID.String()+"/status"+query, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("orgId", testOrg.ExternalID.String())
+		rr := httptest.NewRecorder()
+		handlers.HandleReleaseNotesStatusServe(rr, req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)))
+		return rr
+	}
+	count := func(rr *httptest.ResponseRecorder) int {
+		var status releaseNotesStatusResponseBody
+		err := json.NewDecoder(rr.Body).Decode(&status)
+		require.NoError(t, err)
+		return len(status.Data)
+	}
+
+	// widget and website results are cached separately
+	rr := get("?for=widget", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, count(rr))
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.NotEmpty(t, rr.Header().Get("Last-Modified"))
+	rr = get("?for=website", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 2, count(rr))
+
+	// unchanged data is not sent again
+	rr = get("?for=widget", etag)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	// publishing a note invalidates the cache
+	publish("New", false)
+	rr = get("?for=widget", etag)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+	assert.Equal(t, 2, count(rr))
+
+	// responses with the read state of a client are revalidated by ETag only
+	rr = get("?for=widget&clientId=client-1", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+	assert.Empty(t, rr.Header().Get("Last-Modified"))
+}
@@ -2,55 +2,52 @@ package widget
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
-	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotelikes "github.com/devbydaniel/announcable/internal/domain/release-note-likes"
-	"github.com/go-chi/chi/v5"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// request bodies are small, larger ones are rejected
+const maxLikeBodyBytes = 4 << 10
+
 type toggleLikeRequest struct {
 	ReleaseNoteID string `json:"release_note_id"`
 	ClientID      string `json:"client_id"`
+	// optional, one of the reactions offered by the organisation; toggles a like if empty
+	Reaction string `json:"reaction"`
 }
 
-type toggleLikeResponse struct {
-	IsLiked bool `json:"is_liked"`
-	Count   int  `json:"count"`
-}
-
-// HandleReleaseNoteToggleLike toggles a like on a release note
+// HandleReleaseNoteToggleLike toggles a like or a reaction on a release note and responds with
+// the like state afterwards
 func (h *Handlers) HandleReleaseNoteToggleLike(w http.ResponseWriter, r *http.Request) {
 	h.Log.Trace().Msg("HandleReleaseNoteToggleLike")
-	orgService := organisation.NewService(*organisation.NewRepository(h.DB))
 
-	// Get external org ID from URL params
-	externalOrgId := chi.URLParam(r, "orgId")
-	if externalOrgId == "" {
-		h.Log.Error().Msg("Organisation ID not found in URL")
-		http.Error(w, "Organisation ID required", http.StatusBadRequest)
-		return
-	}
-
-	// Get org by external ID
-	org, err := orgService.GetOrgByExternalId(uuid.MustParse(externalOrgId))
-	if err != nil {
-		h.Log.Error().Err(err).Msg("Error getting organisation")
-		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
+	org, ok := h.getOrg(w, r)
+	if !ok {
 		return
 	}
 
 	// Parse request body
 	var req toggleLikeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLikeBodyBytes)).Decode(&req); err != nil {
 		h.Log.Error().Err(err).Msg("Error decoding request body")
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
+	// Attribute the event to the signed user identity if there is one
+	clientId, err := h.resolveClientId(r, org.ID, req.ClientID)
+	if err != nil {
+		h.writeIdentityError(w, err)
+		return
+	}
+
 	// Validate required fields
-	if req.ReleaseNoteID == "" || req.ClientID == "" {
+	if req.ReleaseNoteID == "" || clientId == "" {
 		h.Log.Error().Msg("Missing required fields")
 		http.Error(w, "Missing required fields", http.StatusBadRequest)
 		return
@@ -71,24 +68,34 @@ func (h *Handlers) HandleReleaseNoteToggleLike(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// Toggle like
-	likesService := releasenotelikes.NewService(releasenotelikes.NewRepository(h.DB))
-	isLiked, err := likesService.ToggleLike(releaseNoteUUID, orgUUID, req.ClientID)
-	if err != nil {
-		h.Log.Error().Err(err).Msg("Error toggling like")
-		http.Error(w, "Error toggling like", http.StatusInternalServerError)
+	// Only published notes of the organisation can be liked
+	if !h.checkPublishedReleaseNote(w, org, releaseNoteUUID) {
 		return
 	}
 
-	// Return response with updated state
-	response := toggleLikeResponse{
-		IsLiked: isLiked,
+	// Only the reactions offered by the organisation are accepted
+	if req.Reaction != "" {
+		widgetConfigService := widgetconfigs.NewService(*widgetconfigs.NewRepository(h.DB))
+		cfg, err := widgetConfigService.Get(org.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			h.Log.Error().Err(err).Msg("Error getting widget config")
+			http.Error(w, "Error toggling like", http.StatusInternalServerError)
+			return
+		}
+		if cfg == nil || !cfg.HasReaction(req.Reaction) {
+			http.Error(w, "Invalid reaction", http.StatusBadRequest)
+			return
+		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.Log.Error().Err(err).Msg("Error encoding response")
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	// Toggle like or reaction
+	likesService := releasenotelikes.NewService(releasenotelikes.NewRepository(h.DB))
+	if _, err := likesService.ToggleReaction(releaseNoteUUID, orgUUID, clientId, req.Reaction); err != nil {
+		h.Log.Error().Err(err).Msg("Error toggling like")
+		http.Error(w, "Error toggling like", http.StatusInternalServerError)
 		return
 	}
+
+	// Return response with updated state
+	h.writeLikeState(w, org, releaseNoteUUID, clientId)
 }
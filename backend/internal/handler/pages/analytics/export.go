@@ -0,0 +1,186 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	releasenotemetrics "github.com/devbydaniel/announcable/internal/domain/release-note-metrics"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+
+	exportDatasetMetrics = "metrics"
+	exportDatasetLikes   = "likes"
+
+	// rows written between flushes of the response
+	exportFlushRows = 1000
+)
+
+// HandleExport handles GET /analytics/export. It streams the metrics or likes of the
+// organisation as CSV or NDJSON, filtered like the analytics page.
+func (h *Handlers) HandleExport(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleExport")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	metricsService := releasenotemetrics.NewService(releasenotemetrics.NewRepository(h.deps.DB))
+	query := r.URL.Query()
+
+	dataset := query.Get("dataset")
+	if dataset != exportDatasetMetrics && dataset != exportDatasetLikes {
+		http.Error(w, "Invalid dataset", http.StatusBadRequest)
+		return
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if format != exportFormatCSV && format != exportFormatNDJSON {
+		http.Error(w, "Invalid format", http.StatusBadRequest)
+		return
+	}
+	from, to, err := parseDateRange(r, time.Now())
+	if err != nil {
+		http.Error(w, "Invalid date", http.StatusBadRequest)
+		return
+	}
+	filter := releasenotemetrics.ExportFilter{
+		From:       from,
+		To:         to,
+		MetricType: releasenotemetrics.MetricType(query.Get("metric_type")),
+	}
+	if id := query.Get("release_note"); id != "" {
+		releaseNoteId, err := uuid.Parse(id)
+		if err != nil {
+			http.Error(w, "Invalid release note ID", http.StatusBadRequest)
+			return
+		}
+		filter.ReleaseNoteID = &releaseNoteId
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s.%s", dataset, from.Format(dateFormat), to.Format(dateFormat), format)
+	var enc *exportEncoder
+	if dataset == exportDatasetMetrics {
+		enc = newExportEncoder(w, format, filename, releasenotemetrics.MetricExportColumns)
+		err = metricsService.ExportMetrics(uuid.MustParse(orgId), filter, func(m *releasenotemetrics.MetricExport) error {
+			return enc.write(m.Record(), m)
+		})
+	} else {
+		enc = newExportEncoder(w, format, filename, releasenotemetrics.LikeExportColumns)
+		err = metricsService.ExportLikes(uuid.MustParse(orgId), filter, func(l *releasenotemetrics.LikeExport) error {
+			return enc.write(l.Record(), l)
+		})
+	}
+	if err == nil {
+		err = enc.close()
+	}
+	if err != nil {
+		if enc.started {
+			// the status has been sent already, all we can do is cut the download short
+			h.deps.Log.Error().Err(err).Int("rows", enc.rows).Msg("Error streaming export")
+			return
+		}
+		if errors.Is(err, releasenotemetrics.ErrInvalidDateRange) {
+			http.Error(w, "The date range must end after it starts", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, releasenotemetrics.ErrInvalidMetricType) {
+			http.Error(w, "Invalid metric type", http.StatusBadRequest)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error exporting")
+		http.Error(w, "Error exporting", http.StatusInternalServerError)
+	}
+}
+
+// exportEncoder writes export rows to the response as CSV or NDJSON. The download headers
+// are sent with the first row, so errors before it can still be answered with an error status.
+type exportEncoder struct {
+	w        http.ResponseWriter
+	format   string
+	filename string
+	columns  []string
+	csv      *csv.Writer
+	json     *json.Encoder
+	started  bool
+	rows     int
+}
+
+func newExportEncoder(w http.ResponseWriter, format, filename string, columns []string) *exportEncoder {
+	return &exportEncoder{w: w, format: format, filename: filename, columns: columns}
+}
+
+// write writes one row, as the CSV record or as the JSON encoding of obj
+func (e *exportEncoder) write(record []string, obj interface{}) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	if e.format == exportFormatCSV {
+		if err := e.csv.Write(record); err != nil {
+			return err
+		}
+	} else if err := e.json.Encode(obj); err != nil {
+		return err
+	}
+	e.rows++
+	if e.rows%exportFlushRows == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// close writes the headers of an empty export and flushes the remaining rows
+func (e *exportEncoder) close() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+// start sends the download headers and, for CSV, the header row
+func (e *exportEncoder) start() error {
+	e.started = true
+	contentType := "application/x-ndjson"
+	if e.format == exportFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	e.w.Header().Set("Content-Type", contentType)
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+	e.w.Header().Set("Cache-Control", "no-store")
+	e.w.WriteHeader(http.StatusOK)
+	if e.format == exportFormatNDJSON {
+		e.json = json.NewEncoder(e.w)
+		return nil
+	}
+	e.csv = csv.NewWriter(e.w)
+	return e.csv.Write(e.columns)
+}
+
+// flush sends the buffered rows to the client
+func (e *exportEncoder) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
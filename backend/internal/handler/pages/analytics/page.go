@@ -0,0 +1,192 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	releasenotemetrics "github.com/devbydaniel/announcable/internal/domain/release-note-metrics"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/handler/shared"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/templates"
+	"github.com/google/uuid"
+)
+
+const (
+	dateFormat = "2006-01-02"
+	// days shown when no date range is selected
+	defaultRangeDays = 30
+)
+
+// Handlers holds dependencies for analytics handlers
+type Handlers struct {
+	deps *shared.Dependencies
+}
+
+// New creates a new Handlers instance
+func New(deps *shared.Dependencies) *Handlers {
+	return &Handlers{deps: deps}
+}
+
+// pageData represents the template data for the analytics page
+type pageData struct {
+	shared.BaseTemplateData
+	From             string
+	To               string
+	ReleaseNoteID    string
+	ReleaseNoteTitle string
+	Totals           releasenotemetrics.Counts
+	Charts           []*chart
+	ReleaseNotes     []*releasenotemetrics.ReleaseNoteMetrics
+}
+
+// chart is a daily bar chart of one metric
+type chart struct {
+	Title      string
+	Total      string
+	Bars       []*chartBar
+	FirstLabel string
+	LastLabel  string
+}
+
+// chartBar is the bar of one day
+type chartBar struct {
+	Label string
+	Value string
+	// percentage of the highest bar of the chart
+	Height float64
+}
+
+var pageTmpl = templates.Construct(
+	"analytics",
+	"layouts/root.html",
+	"layouts/appframe.html",
+	"pages/analytics.html",
+)
+
+// ServeAnalyticsPage handles GET /analytics/
+func (h *Handlers) ServeAnalyticsPage(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("ServeAnalyticsPage")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	metricsService := releasenotemetrics.NewService(releasenotemetrics.NewRepository(h.deps.DB))
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+
+	from, to, err := parseDateRange(r, time.Now())
+	if err != nil {
+		http.Error(w, "Invalid date", http.StatusBadRequest)
+		return
+	}
+	filter := releasenotemetrics.AnalyticsFilter{From: from, To: to}
+
+	data := pageData{
+		BaseTemplateData: shared.BaseTemplateData{
+			Title: "Analytics",
+		},
+	}
+
+	if id := r.URL.Query().Get("release_note"); id != "" {
+		if _, err := uuid.Parse(id); err != nil {
+			http.Error(w, "Invalid release note ID", http.StatusBadRequest)
+			return
+		}
+		rn, err := releaseNotesService.GetOne(id, orgId)
+		if err != nil {
+			if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+				http.Error(w, "Release note not found", http.StatusNotFound)
+				return
+			}
+			h.deps.Log.Error().Err(err).Msg("Error getting release note")
+			http.Error(w, "Error getting release note", http.StatusInternalServerError)
+			return
+		}
+		filter.ReleaseNoteID = &rn.ID
+		data.ReleaseNoteID = rn.ID.String()
+		data.ReleaseNoteTitle = rn.Title
+	}
+
+	analytics, err := metricsService.GetAnalytics(uuid.MustParse(orgId), filter)
+	if err != nil {
+		if errors.Is(err, releasenotemetrics.ErrInvalidDateRange) {
+			http.Error(w, "The date range must end after it starts and span at most a year", http.StatusBadRequest)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error getting analytics")
+		http.Error(w, "Error getting analytics", http.StatusInternalServerError)
+		return
+	}
+
+	data.From = analytics.From.Format(dateFormat)
+	data.To = analytics.To.Format(dateFormat)
+	data.Totals = analytics.Totals
+	data.Charts = buildCharts(analytics)
+	data.ReleaseNotes = analytics.ReleaseNotes
+
+	if err := pageTmpl.ExecuteTemplate(w, "root", data); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rendering page")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+	}
+}
+
+// parseDateRange reads the from and to query parameters, defaulting to the last 30 days
+func parseDateRange(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	to := now.UTC()
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse(dateFormat, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -(defaultRangeDays - 1))
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse(dateFormat, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = t
+	}
+	return from, to, nil
+}
+
+// buildCharts returns the daily charts of views, unique viewers, click-through rate and likes
+func buildCharts(analytics *releasenotemetrics.Analytics) []*chart {
+	count := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	percent := func(v float64) string { return fmt.Sprintf("%.1f%%", v) }
+	newChart := func(title, total string, value func(*releasenotemetrics.DailyMetrics) float64, format func(float64) string) *chart {
+		c := &chart{Title: title, Total: total}
+		max := 0.0
+		for _, day := range analytics.Days {
+			if v := value(day); v > max {
+				max = v
+			}
+		}
+		for _, day := range analytics.Days {
+			v := value(day)
+			bar := &chartBar{Label: day.Day.Format("Jan 2"), Value: format(v)}
+			if max > 0 {
+				bar.Height = v / max * 100
+			}
+			c.Bars = append(c.Bars, bar)
+		}
+		if len(c.Bars) > 0 {
+			c.FirstLabel = c.Bars[0].Label
+			c.LastLabel = c.Bars[len(c.Bars)-1].Label
+		}
+		return c
+	}
+
+	totals := analytics.Totals
+	return []*chart{
+		newChart("Views", count(float64(totals.Views)), func(d *releasenotemetrics.DailyMetrics) float64 { return float64(d.Views) }, count),
+		newChart("Unique viewers", count(float64(totals.UniqueViewers)), func(d *releasenotemetrics.DailyMetrics) float64 { return float64(d.UniqueViewers) }, count),
+		newChart("CTA click-through rate", percent(totals.ClickThroughRate()), func(d *releasenotemetrics.DailyMetrics) float64 { return d.ClickThroughRate() }, percent),
+		newChart("Likes", count(float64(totals.Likes)), func(d *releasenotemetrics.DailyMetrics) float64 { return float64(d.Likes) }, count),
+	}
+}
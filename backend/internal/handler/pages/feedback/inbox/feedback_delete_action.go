@@ -0,0 +1,36 @@
+package inbox
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/feedback"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// HandleFeedbackDelete handles DELETE /feedback/{id}
+func (h *Handlers) HandleFeedbackDelete(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleFeedbackDelete")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	feedbackService := feedback.NewService(*feedback.NewRepository(h.deps.DB))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid feedback ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := feedbackService.Delete(id, uuid.MustParse(orgId)); err != nil {
+		h.feedbackError(w, err, "Error deleting feedback")
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,56 @@
+package inbox
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/feedback"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// statusForm represents the form data for moving feedback to another status
+type statusForm struct {
+	Status string `schema:"status"`
+}
+
+// HandleFeedbackStatusUpdate handles PATCH /feedback/{id}
+func (h *Handlers) HandleFeedbackStatusUpdate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleFeedbackStatusUpdate")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	feedbackService := feedback.NewService(*feedback.NewRepository(h.deps.DB))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid feedback ID", http.StatusBadRequest)
+		return
+	}
+
+	// parse form
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error updating feedback", http.StatusBadRequest)
+		return
+	}
+
+	// decode form
+	var updateDTO statusForm
+	if err := h.deps.Decoder.Decode(&updateDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error updating feedback", http.StatusBadRequest)
+		return
+	}
+
+	if err := feedbackService.SetStatus(id, uuid.MustParse(orgId), feedback.Status(updateDTO.Status)); err != nil {
+		h.feedbackError(w, err, "Error updating feedback")
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
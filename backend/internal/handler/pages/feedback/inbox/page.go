@@ -0,0 +1,147 @@
+package inbox
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/devbydaniel/announcable/internal/domain/feedback"
+	"github.com/devbydaniel/announcable/internal/handler/shared"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/templates"
+	"github.com/google/uuid"
+)
+
+const pageSize = 20
+
+// Handlers holds dependencies for feedback inbox handlers
+type Handlers struct {
+	deps *shared.Dependencies
+}
+
+// New creates a new Handlers instance
+func New(deps *shared.Dependencies) *Handlers {
+	return &Handlers{deps: deps}
+}
+
+// statusTab links to the feedback with one status
+type statusTab struct {
+	Label    string
+	Url      string
+	Count    int
+	IsActive bool
+}
+
+// pageData represents the template data for the feedback inbox page
+type pageData struct {
+	shared.BaseTemplateData
+	Feedback     []*feedback.Feedback
+	Status       feedback.Status
+	Tabs         []statusTab
+	NextPageLink string
+	PrevPageLink string
+}
+
+var pageTmpl = templates.Construct(
+	"feedback",
+	"layouts/root.html",
+	"layouts/appframe.html",
+	"pages/feedback-list.html",
+)
+
+var statusLabels = map[feedback.Status]string{
+	feedback.StatusNew:      "Inbox",
+	feedback.StatusRead:     "Read",
+	feedback.StatusArchived: "Archived",
+}
+
+// ServeFeedbackPage handles GET /feedback/
+func (h *Handlers) ServeFeedbackPage(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("ServeFeedbackPage")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	feedbackService := feedback.NewService(*feedback.NewRepository(h.deps.DB))
+
+	status := feedback.Status(r.URL.Query().Get("status"))
+	if status == "" {
+		status = feedback.StatusNew
+	}
+	if !status.IsValid() {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+	page := 1
+	if p := r.URL.Query().Get("page"); p != "" {
+		var err error
+		if page, err = strconv.Atoi(p); err != nil {
+			h.deps.Log.Error().Err(err).Msg("Error parsing page")
+			http.Error(w, "Error getting feedback", http.StatusBadRequest)
+			return
+		}
+	}
+
+	items, err := feedbackService.GetInbox(uuid.MustParse(orgId), status, page, pageSize)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting feedback")
+		http.Error(w, "Error getting feedback", http.StatusInternalServerError)
+		return
+	}
+	counts, err := feedbackService.CountByStatus(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error counting feedback")
+		http.Error(w, "Error getting feedback", http.StatusInternalServerError)
+		return
+	}
+
+	var tabs []statusTab
+	for _, s := range feedback.Statuses {
+		tabs = append(tabs, statusTab{
+			Label:    statusLabels[s],
+			Url:      "/feedback?status=" + s.String(),
+			Count:    counts[s],
+			IsActive: s == status,
+		})
+	}
+
+	nextPageLink := ""
+	if items.Page < items.TotalPages {
+		nextPageLink = "/feedback?status=" + status.String() + "&page=" + strconv.Itoa(items.Page+1)
+	}
+	prevPageLink := ""
+	if items.Page > 1 {
+		prevPageLink = "/feedback?status=" + status.String() + "&page=" + strconv.Itoa(items.Page-1)
+	}
+
+	data := pageData{
+		BaseTemplateData: shared.BaseTemplateData{
+			Title: "Feedback",
+		},
+		Feedback:     items.Items,
+		Status:       status,
+		Tabs:         tabs,
+		NextPageLink: nextPageLink,
+		PrevPageLink: prevPageLink,
+	}
+
+	if err := pageTmpl.ExecuteTemplate(w, "root", data); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rendering page")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+	}
+}
+
+// feedbackError maps errors of the feedback service to a response
+func (h *Handlers) feedbackError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, feedback.ErrInvalidStatus):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, feedback.ErrFeedbackNotFound):
+		http.Error(w, "Feedback not found", http.StatusNotFound)
+	default:
+		h.deps.Log.Error().Err(err).Msg(msg)
+		http.Error(w, msg, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,233 @@
+package release_page
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devbydaniel/announcable/config"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
+	"github.com/devbydaniel/announcable/internal/feed"
+	"github.com/devbydaniel/announcable/internal/util"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// number of most recent release notes included in feeds
+const feedSize = 50
+
+// feedLinks holds the URLs of the feeds of a release page
+type feedLinks struct {
+	RSS  string
+	Atom string
+	JSON string
+}
+
+// newFeedLinks returns the feed URLs of a release page. linkLang selects the language of the
+// feeds and is empty for the default language.
+func newFeedLinks(slug, linkLang string) feedLinks {
+	baseUrl := config.New().BaseURL
+	return feedLinks{
+		RSS:  pageLink(util.BuildURL(baseUrl, "s", slug, "feed.rss"), linkLang),
+		Atom: pageLink(util.BuildURL(baseUrl, "s", slug, "feed.atom"), linkLang),
+		JSON: pageLink(util.BuildURL(baseUrl, "s", slug, "feed.json"), linkLang),
+	}
+}
+
+// ServeFeedRSS handles GET /s/{orgSlug}/feed.rss
+func (h *Handlers) ServeFeedRSS(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("ServeFeedRSS")
+	h.serveFeed(w, r, feed.ContentTypeRSS, func(f *feed.Feed, links feedLinks) ([]byte, error) {
+		return feed.RSS(f, links.RSS)
+	})
+}
+
+// ServeFeedAtom handles GET /s/{orgSlug}/feed.atom
+func (h *Handlers) ServeFeedAtom(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("ServeFeedAtom")
+	h.serveFeed(w, r, feed.ContentTypeAtom, func(f *feed.Feed, links feedLinks) ([]byte, error) {
+		return feed.Atom(f, links.Atom)
+	})
+}
+
+// ServeFeedJSON handles GET /s/{orgSlug}/feed.json
+func (h *Handlers) ServeFeedJSON(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("ServeFeedJSON")
+	h.serveFeed(w, r, feed.ContentTypeJSON, func(f *feed.Feed, links feedLinks) ([]byte, error) {
+		return feed.JSON(f, links.JSON)
+	})
+}
+
+// ServeFeedImage handles GET /s/{orgSlug}/images/{mediaId}. Feed readers keep items for a long
+// time, so feeds link images here and the redirect points to a freshly signed URL.
+func (h *Handlers) ServeFeedImage(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("ServeFeedImage")
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.DB, h.ObjStore))
+	rnService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	mediaId, err := uuid.Parse(chi.URLParam(r, "mediaId"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	cfg, err := releasePageConfigService.GetBySlug(chi.URLParam(r, "orgSlug"))
+	if err != nil || cfg.DisableReleasePage {
+		http.NotFound(w, r)
+		return
+	}
+
+	imgUrl, err := rnService.GetPublicImageUrl(cfg.OrganisationID, mediaId)
+	if err != nil {
+		if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error getting image")
+		http.Error(w, "Error getting image", http.StatusInternalServerError)
+		return
+	}
+
+	// well within the lifetime of the signed URL
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	http.Redirect(w, r, imgUrl, http.StatusFound)
+}
+
+// feedImageUrl returns the stable URL of an image attachment, see ServeFeedImage
+func feedImageUrl(slug string, mediaId uuid.UUID) string {
+	return util.BuildURL(config.New().BaseURL, "s", slug, "images", mediaId.String())
+}
+
+func (h *Handlers) serveFeed(w http.ResponseWriter, r *http.Request, contentType string, render func(*feed.Feed, feedLinks) ([]byte, error)) {
+	organisationService := organisation.NewService(*organisation.NewRepository(h.DB))
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.DB, h.ObjStore))
+	rnService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	orgSlug := chi.URLParam(r, "orgSlug")
+	cfg, err := releasePageConfigService.GetBySlug(orgSlug)
+	if err != nil {
+		h.Log.Debug().Err(err).Str("slug", orgSlug).Msg("Release page not found")
+		http.NotFound(w, r)
+		return
+	}
+	if cfg.DisableReleasePage {
+		http.NotFound(w, r)
+		return
+	}
+
+	org, err := organisationService.GetOrg(cfg.OrganisationID)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting release notes", http.StatusInternalServerError)
+		return
+	}
+
+	// same filters as the release page
+	filters := map[string]interface{}{
+		"is_published":                true,
+		"hide_on_release_page":        false,
+		releasenotes.FilterUntargeted: true,
+	}
+	if tags := r.URL.Query()["tag"]; len(tags) > 0 {
+		filters[releasenotes.FilterTags] = tags
+	}
+	rns, err := rnService.GetAllWithImgUrl(org.ID.String(), 1, feedSize, filters)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting release notes")
+		http.Error(w, "Error getting release notes", http.StatusInternalServerError)
+		return
+	}
+
+	lang, linkLang := pageLanguage(r, org)
+	if err := rnService.Localize(rns.Items, lang); err != nil {
+		h.Log.Error().Err(err).Msg("Error translating release notes")
+		http.Error(w, "Error getting release notes", http.StatusInternalServerError)
+		return
+	}
+
+	links := newFeedLinks(cfg.Slug, linkLang)
+	out, err := render(newReleaseNotesFeed(cfg, org.Name, rns.Items, linkLang), links)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error rendering feed")
+		http.Error(w, "Error rendering feed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write(out)
+}
+
+// newReleaseNotesFeed builds the feed of a release page
+func newReleaseNotesFeed(cfg *releasepageconfig.ReleasePageConfig, orgName string, rns []*releasenotes.ReleaseNote, linkLang string) *feed.Feed {
+	pageUrl := releasePageUrl(cfg.Slug)
+	title := cfg.Title
+	if title == "" {
+		title = orgName
+	}
+	f := &feed.Feed{
+		Title:       title,
+		Description: cfg.Description,
+		Link:        pageLink(pageUrl, linkLang),
+		ImageURL:    cfg.ImageUrl,
+	}
+	for _, rn := range rns {
+		published := rn.CreatedAt
+		if rn.ReleaseDate != nil {
+			if releaseDate, err := time.Parse("2006-01-02", *rn.ReleaseDate); err == nil {
+				published = releaseDate
+			}
+		}
+		if rn.UpdatedAt.After(f.Updated) {
+			f.Updated = rn.UpdatedAt
+		}
+		link := pageLink(rn.Permalink(pageUrl), linkLang)
+		if rn.Slug == "" {
+			link = pageLink(pageUrl, linkLang) + "#" + rn.ID.String()
+		}
+		f.Items = append(f.Items, &feed.Item{
+			ID:          "urn:uuid:" + rn.ID.String(),
+			Title:       rn.Title,
+			Link:        link,
+			Summary:     rn.DescriptionShort,
+			ContentHTML: releaseNoteContentHTML(rn, cfg.Slug),
+			ImageURL:    coverImageUrl(rn, cfg.Slug),
+			MediaURL:    rn.MediaLink,
+			Published:   published,
+			Updated:     rn.UpdatedAt,
+		})
+	}
+	return f
+}
+
+// coverImageUrl returns the stable URL of the first image of a release note, if any
+func coverImageUrl(rn *releasenotes.ReleaseNote, slug string) string {
+	for _, m := range rn.Media {
+		if m.IsImage() {
+			return feedImageUrl(slug, m.ID)
+		}
+	}
+	return ""
+}
+
+// releaseNoteContentHTML renders the body of a feed item: images, description and video links.
+// Images are linked by their stable URLs, the signed ones expire.
+func releaseNoteContentHTML(rn *releasenotes.ReleaseNote, slug string) string {
+	var b strings.Builder
+	for _, m := range rn.Media {
+		if m.IsImage() {
+			b.WriteString(`<p><img src="` + html.EscapeString(feedImageUrl(slug, m.ID)) + `" alt="` + html.EscapeString(m.AltText) + `"></p>`)
+		}
+	}
+	b.WriteString(string(rn.DescriptionHTML()))
+	for _, m := range rn.Media {
+		if !m.IsImage() {
+			link := html.EscapeString(m.Link)
+			b.WriteString(`<p><a href="` + link + `">` + link + `</a></p>`)
+		}
+	}
+	return b.String()
+}
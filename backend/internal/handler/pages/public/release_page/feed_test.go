@@ -0,0 +1,39 @@
+package release_page
+
+import (
+	"strings"
+	"testing"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
+	"github.com/google/uuid"
+)
+
+// TestFeedImageUrls checks that feeds link images by URLs that don't expire
+func TestFeedImageUrls(t *testing.T) {
+	t.Setenv("BASE_URL", "https://app.example.com")
+	signed := "https://app.example.com/api/img/release-notes/a.webp?X-Amz-Expires=86400&X-Amz-Signature=abc"
+	first, second := uuid.New(), uuid.New()
+	rn := &releasenotes.ReleaseNote{
+		Title:    "Images",
+		ImageUrl: signed,
+		Media: []*releasenotes.ReleaseNoteMedia{
+			{ID: uuid.New(), Kind: releasenotes.MediaKindEmbed, Link: "https://youtu.be/abc"},
+			{ID: first, Kind: releasenotes.MediaKindImage, Url: signed, AltText: "First"},
+			{ID: second, Kind: releasenotes.MediaKindImage, Url: signed, AltText: "Second"},
+		},
+	}
+	rn.ID = uuid.New()
+
+	f := newReleaseNotesFeed(&releasepageconfig.ReleasePageConfig{Slug: "acme"}, "Acme", []*releasenotes.ReleaseNote{rn}, "")
+	item := f.Items[0]
+	if want := "https://app.example.com/s/acme/images/" + first.String(); item.ImageURL != want {
+		t.Errorf("ImageURL = %q, want %q", item.ImageURL, want)
+	}
+	if strings.Contains(item.ContentHTML, "X-Amz") {
+		t.Errorf("content contains a signed URL: %s", item.ContentHTML)
+	}
+	if !strings.Contains(item.ContentHTML, "/s/acme/images/"+second.String()) {
+		t.Errorf("content doesn't link the second image: %s", item.ContentHTML)
+	}
+}
@@ -0,0 +1,127 @@
+package release_page
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/feedback"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
+	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// feedbackStatus is the result of a feedback submission, passed to the permalink page in the
+// feedback query parameter
+type feedbackStatus string
+
+const (
+	feedbackStatusSent    feedbackStatus = "sent"
+	feedbackStatusInvalid feedbackStatus = "invalid"
+	feedbackStatusError   feedbackStatus = "error"
+)
+
+// Message returns the text shown above the feedback form, empty if there is none
+func (s feedbackStatus) Message() string {
+	switch s {
+	case feedbackStatusSent:
+		return "Thanks for your feedback!"
+	case feedbackStatusInvalid:
+		return "Please enter up to 2000 characters with at most two links, and a valid email address if you add one."
+	case feedbackStatusError:
+		return "Your feedback couldn't be sent, please try again later."
+	}
+	return ""
+}
+
+// IsError reports whether the submission failed
+func (s feedbackStatus) IsError() bool {
+	return s == feedbackStatusInvalid || s == feedbackStatusError
+}
+
+// acceptsFeedback reports whether the organisation enabled feedback on release notes
+func (h *Handlers) acceptsFeedback(orgId uuid.UUID) bool {
+	widgetConfigService := widgetconfigs.NewService(*widgetconfigs.NewRepository(h.DB))
+	cfg, err := widgetConfigService.Get(orgId)
+	if err != nil {
+		return false
+	}
+	return cfg.EnableFeedback
+}
+
+// HandleFeedbackCreate stores feedback left with the form of a release note permalink page and
+// redirects back to the page. Duplicates and bots are redirected like successful submissions.
+func (h *Handlers) HandleFeedbackCreate(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("HandleFeedbackCreate")
+	organisationService := organisation.NewService(*organisation.NewRepository(h.DB))
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.DB, h.ObjStore))
+	rnService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	orgSlug := chi.URLParam(r, "orgSlug")
+	noteSlug := chi.URLParam(r, "noteSlug")
+
+	cfg, err := releasePageConfigService.GetBySlug(orgSlug)
+	if err != nil || cfg.DisableReleasePage {
+		http.NotFound(w, r)
+		return
+	}
+	rn, err := rnService.GetPublicBySlug(cfg.OrganisationID, noteSlug)
+	if err != nil {
+		if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error getting release note")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+	if !h.acceptsFeedback(cfg.OrganisationID) {
+		http.NotFound(w, r)
+		return
+	}
+	org, err := organisationService.GetOrg(cfg.OrganisationID)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+	_, linkLang := pageLanguage(r, org)
+
+	redirect := func(status feedbackStatus) {
+		target := pageLink(rn.Permalink(releasePageUrl(cfg.Slug)), linkLang, "feedback", string(status))
+		http.Redirect(w, r, target+"#feedback", http.StatusSeeOther)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 16<<10)
+	if err := r.ParseForm(); err != nil {
+		h.Log.Error().Err(err).Msg("Error parsing form")
+		redirect(feedbackStatusInvalid)
+		return
+	}
+	// honeypot, a hidden field only bots fill in
+	if r.PostForm.Get("website") != "" {
+		h.Log.Debug().Msg("Feedback honeypot filled in")
+		redirect(feedbackStatusSent)
+		return
+	}
+
+	feedbackService := feedback.NewService(*feedback.NewRepository(h.DB))
+	err = feedbackService.Submit(&feedback.Feedback{
+		OrganisationID: cfg.OrganisationID,
+		ReleaseNoteID:  rn.ID,
+		Source:         feedback.SourceReleasePage,
+		Text:           r.PostForm.Get("text"),
+		Email:          r.PostForm.Get("email"),
+	})
+	switch {
+	case errors.Is(err, feedback.ErrInvalidText), errors.Is(err, feedback.ErrInvalidEmail), errors.Is(err, feedback.ErrSpam):
+		redirect(feedbackStatusInvalid)
+	case err != nil && !errors.Is(err, feedback.ErrDuplicate):
+		h.Log.Error().Err(err).Msg("Error saving feedback")
+		redirect(feedbackStatusError)
+	default:
+		redirect(feedbackStatusSent)
+	}
+}
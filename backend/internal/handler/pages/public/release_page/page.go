@@ -1,15 +1,19 @@
 package release_page
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"time"
 
+	"github.com/devbydaniel/announcable/config"
 	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
 	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
 	"github.com/devbydaniel/announcable/internal/handler/shared"
+	"github.com/devbydaniel/announcable/internal/locale"
 	"github.com/devbydaniel/announcable/internal/util"
 	"github.com/devbydaniel/announcable/templates"
 	"github.com/go-chi/chi/v5"
@@ -27,8 +31,108 @@ func New(deps *shared.Dependencies) *Handlers {
 
 // ReleaseNotesWebsiteData represents the public release page template data
 type ReleaseNotesWebsiteData struct {
-	Cfg *releasepageconfig.ReleasePageConfig
-	Rns []*releasenotes.ReleaseNote
+	Cfg     *releasepageconfig.ReleasePageConfig
+	Rns     []*releasenotes.ReleaseNote
+	Feeds   feedLinks
+	Meta    pageMeta
+	PageUrl string
+	// ActiveTags are the tag names the list is filtered by
+	ActiveTags []string
+	// IsPermalink is set when the page shows a single release note
+	IsPermalink bool
+	// Lang is the language the page is shown in
+	Lang string
+	// Languages link to the page in the other languages release notes are available in
+	Languages []languageLink
+	// FeedbackUrl is the URL the feedback form of a permalink page posts to, empty if the
+	// organisation doesn't accept feedback
+	FeedbackUrl string
+	// FeedbackStatus is the result of the feedback submission the page was redirected from
+	FeedbackStatus feedbackStatus
+	// Preview is set when the page shows the note of a preview link
+	Preview *previewData
+	// linkLang is added to links within the page, empty for the default language
+	linkLang string
+}
+
+// languageLink links to a release page in one language
+type languageLink struct {
+	Code     string
+	Name     string
+	Url      string
+	IsActive bool
+}
+
+// Link returns a URL of the release page with the given query parameters (name, value pairs)
+// that keeps the language of the current page
+func (d ReleaseNotesWebsiteData) Link(base string, params ...string) string {
+	return pageLink(base, d.linkLang, params...)
+}
+
+// pageLink adds query parameters (name, value pairs) and the lang parameter, unless linkLang
+// is empty, to a release page URL
+func pageLink(base, linkLang string, params ...string) string {
+	query := url.Values{}
+	for i := 0; i+1 < len(params); i += 2 {
+		query.Add(params[i], params[i+1])
+	}
+	if linkLang != "" {
+		query.Set("lang", linkLang)
+	}
+	if len(query) == 0 {
+		return base
+	}
+	return base + "?" + query.Encode()
+}
+
+// pageMeta holds the document title, canonical URL and OpenGraph/Twitter card data of a page
+type pageMeta struct {
+	Title       string
+	Description string
+	ImageUrl    string
+	Url         string
+	Type        string
+}
+
+// releasePageUrl returns the absolute URL of a public release page
+func releasePageUrl(slug string) string {
+	return util.BuildURL(config.New().BaseURL, "s", slug)
+}
+
+// pageLanguage picks the language of a release page from the lang query parameter. It also
+// returns the value of the parameter for links within the page, empty for the default language.
+func pageLanguage(r *http.Request, org *organisation.Organisation) (lang, linkLang string) {
+	lang = locale.Negotiate(r.URL.Query().Get("lang"), "", org.DefaultLocale)
+	if lang != org.DefaultLocale {
+		linkLang = lang
+	}
+	return lang, linkLang
+}
+
+// newLanguageLinks returns links to a page in the default language and the given translation
+// languages, or nil if the page is only available in one language. urlFor returns the page
+// URL for a lang parameter, which is empty for the default language.
+func newLanguageLinks(org *organisation.Organisation, translated []string, lang string, urlFor func(linkLang string) string) []languageLink {
+	var links []languageLink
+	for _, l := range locale.Languages {
+		if l.Code != org.DefaultLocale && !slices.Contains(translated, l.Code) {
+			continue
+		}
+		linkLang := l.Code
+		if l.Code == org.DefaultLocale {
+			linkLang = ""
+		}
+		links = append(links, languageLink{
+			Code:     l.Code,
+			Name:     l.Name,
+			Url:      urlFor(linkLang),
+			IsActive: l.Code == lang,
+		})
+	}
+	if len(links) < 2 {
+		return nil
+	}
+	return links
 }
 
 var releaseNotesWebsiteTmpl = templates.Construct("release-notes-website", "pages/release-notes-website.html")
@@ -90,8 +194,13 @@ func (h *Handlers) ServeReleasePage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filters := map[string]interface{}{
-		"is_published":         true,
-		"hide_on_release_page": false,
+		"is_published":                true,
+		"hide_on_release_page":        false,
+		releasenotes.FilterUntargeted: true,
+	}
+	activeTags := r.URL.Query()["tag"]
+	if len(activeTags) > 0 {
+		filters[releasenotes.FilterTags] = activeTags
 	}
 	rns, err := rnService.GetAllWithImgUrl(org.ID.String(), pageInt, pageSizeInt, filters)
 	if err != nil {
@@ -100,25 +209,19 @@ func (h *Handlers) ServeReleasePage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// format release date and transform media links
-	for _, rn := range rns.Items {
-		if rn.ReleaseDate != nil {
-			releaseDate, err := time.Parse("2006-01-02", *rn.ReleaseDate)
-			if err != nil {
-				h.Log.Error().Err(err).Msg("Error parsing release date")
-				continue
-			}
-			rd := releaseDate.Format("02.01.2006")
-			rn.ReleaseDate = &rd
-		} else {
-			rd := ""
-			rn.ReleaseDate = &rd
-		}
+	lang, linkLang := pageLanguage(r, org)
+	if err := rnService.Localize(rns.Items, lang); err != nil {
+		h.Log.Error().Err(err).Msg("Error translating release notes")
+		http.Error(w, "Error getting release notes", http.StatusInternalServerError)
+		return
+	}
+	translated, err := rnService.GetLocales(org.ID)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting release note languages")
+	}
 
-		// Transform media link to embed URL
-		if rn.MediaLink != "" {
-			rn.MediaLink = util.TransformMediaLink(rn.MediaLink)
-		}
+	for _, rn := range rns.Items {
+		h.prepareReleaseNote(rn)
 	}
 
 	// adjust back link if there's query params
@@ -129,9 +232,34 @@ func (h *Handlers) ServeReleasePage(w http.ResponseWriter, r *http.Request) {
 		config.BackLinkLabel = url.QueryEscape(backLinkLabel)
 	}
 
+	pageUrl := releasePageUrl(config.Slug)
+	canonicalUrl := func(linkLang string) string {
+		var params []string
+		for _, t := range activeTags {
+			params = append(params, "tag", t)
+		}
+		if pageInt > 1 {
+			params = append(params, "page", strconv.Itoa(pageInt))
+		}
+		return pageLink(pageUrl, linkLang, params...)
+	}
+
 	data := ReleaseNotesWebsiteData{
-		Cfg: config,
-		Rns: rns.Items,
+		Cfg:        config,
+		Rns:        rns.Items,
+		Feeds:      newFeedLinks(config.Slug, linkLang),
+		PageUrl:    pageUrl,
+		ActiveTags: activeTags,
+		Lang:       lang,
+		Languages:  newLanguageLinks(org, translated, lang, canonicalUrl),
+		linkLang:   linkLang,
+		Meta: pageMeta{
+			Title:       config.Title,
+			Description: config.Description,
+			ImageUrl:    config.ImageUrl,
+			Url:         canonicalUrl(linkLang),
+			Type:        "website",
+		},
 	}
 
 	if err := releaseNotesWebsiteTmpl.ExecuteTemplate(w, "root", data); err != nil {
@@ -142,3 +270,106 @@ func (h *Handlers) ServeReleasePage(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// ServeReleaseNotePage renders the permalink page of a single release note
+func (h *Handlers) ServeReleaseNotePage(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("ServeReleaseNotePage")
+	organisationService := organisation.NewService(*organisation.NewRepository(h.DB))
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.DB, h.ObjStore))
+	rnService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	orgSlug := chi.URLParam(r, "orgSlug")
+	noteSlug := chi.URLParam(r, "noteSlug")
+
+	cfg, err := releasePageConfigService.GetBySlug(orgSlug)
+	if err != nil {
+		h.Log.Debug().Err(err).Str("slug", orgSlug).Msg("Release page not found")
+		http.NotFound(w, r)
+		return
+	}
+	if cfg.DisableReleasePage {
+		http.NotFound(w, r)
+		return
+	}
+
+	rn, err := rnService.GetPublicBySlug(cfg.OrganisationID, noteSlug)
+	if err != nil {
+		if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error getting release note")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+
+	org, err := organisationService.GetOrg(cfg.OrganisationID)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+	lang, linkLang := pageLanguage(r, org)
+	if err := rnService.Localize([]*releasenotes.ReleaseNote{rn}, lang); err != nil {
+		h.Log.Error().Err(err).Msg("Error translating release note")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+	h.prepareReleaseNote(rn)
+
+	pageUrl := releasePageUrl(cfg.Slug)
+	imageUrl := rn.ImageUrl
+	if imageUrl == "" {
+		imageUrl = cfg.ImageUrl
+	}
+
+	data := ReleaseNotesWebsiteData{
+		Cfg:         cfg,
+		Rns:         []*releasenotes.ReleaseNote{rn},
+		Feeds:       newFeedLinks(cfg.Slug, linkLang),
+		PageUrl:     pageUrl,
+		IsPermalink: true,
+		Lang:        lang,
+		linkLang:    linkLang,
+		Meta: pageMeta{
+			Title:       rn.Title,
+			Description: rn.DescriptionShort,
+			ImageUrl:    imageUrl,
+			Type:        "article",
+		},
+	}
+	data.Meta.Url = data.Link(rn.Permalink(pageUrl))
+	translated, err := rnService.GetLocales(org.ID)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting release note languages")
+	}
+	data.Languages = newLanguageLinks(org, translated, lang, func(linkLang string) string {
+		return pageLink(rn.Permalink(pageUrl), linkLang)
+	})
+	if permalink := rn.Permalink(pageUrl); permalink != "" && h.acceptsFeedback(org.ID) {
+		data.FeedbackUrl = data.Link(permalink + "/feedback")
+		data.FeedbackStatus = feedbackStatus(r.URL.Query().Get("feedback"))
+	}
+
+	if err := releaseNotesWebsiteTmpl.ExecuteTemplate(w, "root", data); err != nil {
+		h.Log.Error().Err(err).Msg("Error rendering page")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+		return
+	}
+}
+
+// prepareReleaseNote formats the release date
+func (h *Handlers) prepareReleaseNote(rn *releasenotes.ReleaseNote) {
+	if rn.ReleaseDate != nil {
+		releaseDate, err := time.Parse("2006-01-02", *rn.ReleaseDate)
+		if err != nil {
+			h.Log.Error().Err(err).Msg("Error parsing release date")
+			return
+		}
+		rd := releaseDate.Format("02.01.2006")
+		rn.ReleaseDate = &rd
+	} else {
+		rd := ""
+		rn.ReleaseDate = &rd
+	}
+}
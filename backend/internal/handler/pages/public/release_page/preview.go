@@ -0,0 +1,120 @@
+package release_page
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
+	"github.com/devbydaniel/announcable/internal/util"
+	"github.com/go-chi/chi/v5"
+)
+
+// previewData holds what the release page needs to show a preview link
+type previewData struct {
+	// ExpiresAt is the formatted expiry date of the link
+	ExpiresAt string
+	// WidgetInit configures the widget embedded in the page to show the previewed note
+	WidgetInit widgetInit
+}
+
+// widgetInit is the announcable_init config of the embedded widget
+type widgetInit struct {
+	OrgID        string `json:"org_id"`
+	Locale       string `json:"locale"`
+	PreviewToken string `json:"preview_token"`
+}
+
+// ServePreviewPage renders a single release note of a preview link the way its permalink page
+// and the widget will show it. The note is shown whether it's published or not, regardless of
+// its visibility and audience and even if the release page is disabled.
+func (h *Handlers) ServePreviewPage(w http.ResponseWriter, r *http.Request) {
+	h.Log.Trace().Msg("ServePreviewPage")
+	organisationService := organisation.NewService(*organisation.NewRepository(h.DB))
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.DB, h.ObjStore))
+	rnService := releasenotes.NewService(*releasenotes.NewRepository(h.DB, h.ObjStore))
+
+	orgSlug := chi.URLParam(r, "orgSlug")
+	token := chi.URLParam(r, "token")
+
+	cfg, err := releasePageConfigService.GetBySlug(orgSlug)
+	if err != nil {
+		h.Log.Debug().Err(err).Str("slug", orgSlug).Msg("Release page not found")
+		http.NotFound(w, r)
+		return
+	}
+
+	rn, preview, err := rnService.GetByPreviewToken(cfg.OrganisationID, token)
+	if err != nil {
+		if errors.Is(err, releasenotes.ErrPreviewNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		h.Log.Error().Err(err).Msg("Error getting preview")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+
+	org, err := organisationService.GetOrg(cfg.OrganisationID)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+	lang, linkLang := pageLanguage(r, org)
+	if err := rnService.Localize([]*releasenotes.ReleaseNote{rn}, lang); err != nil {
+		h.Log.Error().Err(err).Msg("Error translating release note")
+		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+	h.prepareReleaseNote(rn)
+
+	pageUrl := releasePageUrl(cfg.Slug)
+	previewUrl := util.BuildURL(pageUrl, "preview", token)
+	imageUrl := rn.ImageUrl
+	if imageUrl == "" {
+		imageUrl = cfg.ImageUrl
+	}
+
+	data := ReleaseNotesWebsiteData{
+		Cfg:         cfg,
+		Rns:         []*releasenotes.ReleaseNote{rn},
+		Feeds:       newFeedLinks(cfg.Slug, linkLang),
+		PageUrl:     pageUrl,
+		IsPermalink: true,
+		Lang:        lang,
+		linkLang:    linkLang,
+		Meta: pageMeta{
+			Title:       rn.Title,
+			Description: rn.DescriptionShort,
+			ImageUrl:    imageUrl,
+			Url:         pageLink(previewUrl, linkLang),
+			Type:        "article",
+		},
+		Preview: &previewData{
+			ExpiresAt: preview.ExpiresAt.Format("02.01.2006 15:04 MST"),
+			WidgetInit: widgetInit{
+				OrgID:        org.ExternalID.String(),
+				Locale:       lang,
+				PreviewToken: token,
+			},
+		},
+	}
+	translated, err := rnService.GetLocales(org.ID)
+	if err != nil {
+		h.Log.Error().Err(err).Msg("Error getting release note languages")
+	}
+	data.Languages = newLanguageLinks(org, translated, lang, func(linkLang string) string {
+		return pageLink(previewUrl, linkLang)
+	})
+
+	// previews must not end up in search engines or shared caches
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.Header().Set("Cache-Control", "private, no-store")
+	if err := releaseNotesWebsiteTmpl.ExecuteTemplate(w, "root", data); err != nil {
+		h.Log.Error().Err(err).Msg("Error rendering page")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+		return
+	}
+}
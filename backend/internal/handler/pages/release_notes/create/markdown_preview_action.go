@@ -0,0 +1,28 @@
+package create
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/devbydaniel/announcable/internal/markdown"
+)
+
+// HandleMarkdownPreview handles POST /release-notes/markdown-preview?field={name}. It renders
+// the form field with the given name the way it will show up on the release page.
+func (h *Handlers) HandleMarkdownPreview(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleMarkdownPreview")
+	field := r.URL.Query().Get("field")
+	if field == "" {
+		http.Error(w, "Missing field", http.StatusBadRequest)
+		return
+	}
+
+	// FormValue parses both url encoded and multipart forms
+	text := r.FormValue(field)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if strings.TrimSpace(text) == "" {
+		w.Write([]byte(`<p class="form__subtext">Nothing to preview yet.</p>`))
+		return
+	}
+	w.Write([]byte(markdown.ToHTML(text)))
+}
@@ -3,9 +3,14 @@ package create
 import (
 	"net/http"
 
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
 	"github.com/devbydaniel/announcable/internal/handler/shared"
+	"github.com/devbydaniel/announcable/internal/locale"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
 	"github.com/devbydaniel/announcable/templates"
+	"github.com/google/uuid"
 )
 
 // Handlers holds the dependencies for release note create handlers
@@ -27,6 +32,25 @@ type pageData struct {
 	HideCtaIsChecked             bool
 	CtaLabelOverrideIsChecked    bool
 	CtaUrlOverrideIsChecked      bool
+	Tags                         []*tag.Tag
+	SelectedTags                 map[uuid.UUID]bool
+	// DefaultLanguage is the name of the language the main content is written in
+	DefaultLanguage string
+	Translations    []*translationInput
+	// AudienceRules is the JSON encoded list of rules the audience editor starts with
+	AudienceRules     string
+	AudienceOperators []releasenotes.AudienceOperator
+	// Media is the JSON encoded list of attachments the media editor starts with
+	Media string
+}
+
+// translationInput holds the content of the note in one of the other languages
+type translationInput struct {
+	Code             string
+	Name             string
+	Title            string
+	DescriptionShort string
+	DescriptionLong  string
 }
 
 var pageTmpl = templates.Construct(
@@ -39,6 +63,33 @@ var pageTmpl = templates.Construct(
 // ServeReleaseNoteCreatePage handles GET /release-notes/new
 func (h *Handlers) ServeReleaseNoteCreatePage(w http.ResponseWriter, r *http.Request) {
 	h.deps.Log.Trace().Msg("ServeReleaseNoteCreatePage")
+	tagService := tag.NewService(*tag.NewRepository(h.deps.DB))
+	organisationService := organisation.NewService(*organisation.NewRepository(h.deps.DB))
+
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	tags, err := tagService.GetAll(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting tags")
+		http.Error(w, "Error getting tags", http.StatusInternalServerError)
+		return
+	}
+	org, err := organisationService.GetOrg(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
+		return
+	}
+	var translations []*translationInput
+	for _, l := range locale.Languages {
+		if l.Code != org.DefaultLocale {
+			translations = append(translations, &translationInput{Code: l.Code, Name: l.Name})
+		}
+	}
 
 	data := pageData{
 		BaseTemplateData: shared.BaseTemplateData{
@@ -50,6 +101,13 @@ func (h *Handlers) ServeReleaseNoteCreatePage(w http.ResponseWriter, r *http.Req
 		HideCtaIsChecked:             false,
 		CtaLabelOverrideIsChecked:    false,
 		CtaUrlOverrideIsChecked:      false,
+		Tags:                         tags,
+		SelectedTags:                 map[uuid.UUID]bool{},
+		DefaultLanguage:              locale.Name(org.DefaultLocale),
+		Translations:                 translations,
+		AudienceRules:                "[]",
+		AudienceOperators:            releasenotes.AudienceOperators,
+		Media:                        "[]",
 	}
 
 	if err := pageTmpl.ExecuteTemplate(w, "root", data); err != nil {
@@ -1,11 +1,13 @@
 package create
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
 	"github.com/devbydaniel/announcable/internal/imgUtil"
 	mw "github.com/devbydaniel/announcable/internal/middleware"
 	"github.com/go-playground/validator"
@@ -14,24 +16,47 @@ import (
 )
 
 type releaseNoteCreateForm struct {
-	// The request also contains a field for the image,
-	// but gorilla cannot parse it as it is a file in a multipart form.
-	Title               string `schema:"title" validate:"required"`
-	DescriptionShort    string `schema:"description_short" validate:"required"`
-	DeleteImage         bool   `schema:"delete_image"`
-	TextWebsiteOverride string `schema:"text_website_override"`
-	DescriptionLong     string `schema:"description_long"`
-	ReleaseDate         string `schema:"release_date"`
-	OverrideCtaLabel    bool   `schema:"override_cta_label"`
-	CtaLabelOverride    string `schema:"cta_label_override"`
-	OverrideCtaUrl      bool   `schema:"override_cta_url"`
-	CtaUrlOverride      string `schema:"cta_url_override"`
-	HideCta             bool   `schema:"hide_cta"`
-	AttentionMechanism  string `schema:"attention_mechanism"`
-	HideOnWidget        bool   `schema:"hide_on_widget"`
-	HideOnReleasePage   bool   `schema:"hide_on_release_page"`
-	MediaType           string `schema:"media_type"`
-	MediaLink           string `schema:"media_link"`
+	// The request also contains the files of new images,
+	// but gorilla cannot parse them as they are files in a multipart form.
+	Title               string             `schema:"title" validate:"required"`
+	DescriptionShort    string             `schema:"description_short" validate:"required"`
+	TextWebsiteOverride string             `schema:"text_website_override"`
+	DescriptionLong     string             `schema:"description_long"`
+	ReleaseDate         string             `schema:"release_date"`
+	OverrideCtaLabel    bool               `schema:"override_cta_label"`
+	CtaLabelOverride    string             `schema:"cta_label_override"`
+	OverrideCtaUrl      bool               `schema:"override_cta_url"`
+	CtaUrlOverride      string             `schema:"cta_url_override"`
+	HideCta             bool               `schema:"hide_cta"`
+	AttentionMechanism  string             `schema:"attention_mechanism"`
+	HideOnWidget        bool               `schema:"hide_on_widget"`
+	HideOnReleasePage   bool               `schema:"hide_on_release_page"`
+	PublishAt           string             `schema:"publish_at"`
+	UnpublishAt         string             `schema:"unpublish_at"`
+	TagIds              []string           `schema:"tag_ids"`
+	Translations        []translationForm  `schema:"translations"`
+	AudienceRules       []audienceRuleForm `schema:"audience_rules"`
+	Media               []mediaForm        `schema:"media"`
+}
+
+type translationForm struct {
+	Locale           string `schema:"locale"`
+	Title            string `schema:"title"`
+	DescriptionShort string `schema:"description_short"`
+	DescriptionLong  string `schema:"description_long"`
+}
+
+type audienceRuleForm struct {
+	Attribute string `schema:"attribute" json:"attribute"`
+	Operator  string `schema:"operator" json:"operator"`
+	Value     string `schema:"value" json:"value"`
+}
+
+// mediaForm is an attachment in the editor. The file of a new image is sent as media.{index}.file.
+type mediaForm struct {
+	Kind    string `schema:"kind"`
+	Link    string `schema:"link"`
+	AltText string `schema:"alt_text"`
 }
 
 // HandleReleaseNoteCreate handles POST /release-notes/
@@ -40,6 +65,7 @@ func (h *Handlers) HandleReleaseNoteCreate(w http.ResponseWriter, r *http.Reques
 
 	ctx := r.Context()
 	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+	tagService := tag.NewService(*tag.NewRepository(h.deps.DB))
 
 	// extract organisation ID from context
 	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
@@ -79,32 +105,20 @@ func (h *Handlers) HandleReleaseNoteCreate(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Prepare the image input based on media type
-	var imgInput *releasenotes.ImageInput
-	if createDTO.MediaType == "image" {
-		// Only process image if image type is selected
-		img, imgHeader, err := r.FormFile("image")
-		if err != nil {
-			h.deps.Log.Debug().Err(err).Msg("No image uploaded")
-		}
+	// parse schedule
+	publishAt, unpublishAt, err := releasenotes.ParseSchedule(createDTO.PublishAt, createDTO.UnpublishAt)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Invalid schedule")
+		http.Error(w, "Invalid publishing schedule", http.StatusBadRequest)
+		return
+	}
 
-		if img != nil {
-			ok := imgUtil.VerifyImageType(img)
-			if !ok {
-				h.deps.Log.Error().Msg("Invalid image type")
-				http.Error(w, "Error updating release note", http.StatusBadRequest)
-				return
-			}
-			imgInput = &releasenotes.ImageInput{
-				ShouldDeleteImage: false,
-				ImgData:           img,
-				Format:            imgHeader.Header.Get("Content-Type"),
-			}
-		} else if createDTO.DeleteImage {
-			imgInput = &releasenotes.ImageInput{
-				ShouldDeleteImage: true,
-			}
-		}
+	// read attachments
+	media, err := mediaInputs(r, createDTO.Media)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Invalid media")
+		http.Error(w, "Invalid media attachment", http.StatusBadRequest)
+		return
 	}
 
 	releaseNote := &releasenotes.ReleaseNote{
@@ -117,21 +131,8 @@ func (h *Handlers) HandleReleaseNoteCreate(w http.ResponseWriter, r *http.Reques
 		LastUpdatedBy:      uuid.MustParse(userId),
 		HideOnWidget:       createDTO.HideOnWidget,
 		HideOnReleasePage:  createDTO.HideOnReleasePage,
-	}
-
-	// Handle media type switching
-	switch createDTO.MediaType {
-	case "embed":
-		// If switching to embed, clear image path, set media link, and always delete any existing image
-		releaseNote.ImagePath = ""
-		releaseNote.MediaLink = createDTO.MediaLink
-		imgInput = &releasenotes.ImageInput{
-			ShouldDeleteImage: true,
-		}
-	case "image":
-		// If switching to image, clear media link
-		releaseNote.MediaLink = ""
-		// Image handling is done above with imgInput
+		PublishAt:          publishAt,
+		UnpublishAt:        unpublishAt,
 	}
 
 	if createDTO.TextWebsiteOverride == "on" {
@@ -155,10 +156,52 @@ func (h *Handlers) HandleReleaseNoteCreate(w http.ResponseWriter, r *http.Reques
 		releaseNote.ReleaseDate = nil
 	}
 
-	id, err := releaseNotesService.Create(releaseNote, imgInput)
+	tags, err := tagService.Resolve(uuid.MustParse(orgId), createDTO.TagIds)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting tags")
+		http.Error(w, "Error creating release note", http.StatusInternalServerError)
+		return
+	}
+	releaseNote.Tags = tags
+
+	releaseNote.Translations = []*releasenotes.ReleaseNoteTranslation{}
+	for _, t := range createDTO.Translations {
+		releaseNote.Translations = append(releaseNote.Translations, &releasenotes.ReleaseNoteTranslation{
+			Locale:           t.Locale,
+			Title:            t.Title,
+			DescriptionShort: t.DescriptionShort,
+			DescriptionLong:  t.DescriptionLong,
+		})
+	}
+	releaseNote.AudienceRules = []*releasenotes.AudienceRule{}
+	for _, rule := range createDTO.AudienceRules {
+		releaseNote.AudienceRules = append(releaseNote.AudienceRules, &releasenotes.AudienceRule{
+			Attribute: rule.Attribute,
+			Operator:  releasenotes.AudienceOperator(rule.Operator),
+			Value:     rule.Value,
+		})
+	}
+
+	id, err := releaseNotesService.Create(releaseNote, media)
 	if err != nil {
-		h.deps.Log.Error().Err(err).Msg("Error updating release note")
-		http.Error(w, "Error updating release note", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, releasenotes.ErrIncompleteTranslation):
+			http.Error(w, "Translations need a title and a description", http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrInvalidLocale):
+			http.Error(w, "Unsupported translation language", http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrInvalidAudienceRule):
+			http.Error(w, "Audience rules need an attribute and a value, comparisons a number", http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrTooManyMedia),
+			errors.Is(err, releasenotes.ErrInvalidMedia),
+			errors.Is(err, releasenotes.ErrInvalidMediaLink),
+			errors.Is(err, releasenotes.ErrAltTextTooLong):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrReviewRequired):
+			http.Error(w, "Release note needs an approved review before it can be scheduled", http.StatusConflict)
+		default:
+			h.deps.Log.Error().Err(err).Msg("Error updating release note")
+			http.Error(w, "Error updating release note", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -168,3 +211,27 @@ func (h *Handlers) HandleReleaseNoteCreate(w http.ResponseWriter, r *http.Reques
 	w.Header().Set("HX-Redirect", redirectURL)
 	w.WriteHeader(http.StatusCreated)
 }
+
+// mediaInputs reads the attachments of the editor in their submitted order
+func mediaInputs(r *http.Request, forms []mediaForm) ([]*releasenotes.MediaInput, error) {
+	media := make([]*releasenotes.MediaInput, 0, len(forms))
+	for i, f := range forms {
+		input := &releasenotes.MediaInput{
+			Kind:    releasenotes.MediaKind(f.Kind),
+			Link:    f.Link,
+			AltText: f.AltText,
+		}
+		if input.Kind == releasenotes.MediaKindImage {
+			img, _, err := r.FormFile(fmt.Sprintf("media.%d.file", i))
+			if err != nil {
+				return nil, fmt.Errorf("image %d: %w", i, err)
+			}
+			if ok := imgUtil.VerifyImageType(img); !ok {
+				return nil, fmt.Errorf("image %d: unsupported image type", i)
+			}
+			input.ImgData = img
+		}
+		media = append(media, input)
+	}
+	return media, nil
+}
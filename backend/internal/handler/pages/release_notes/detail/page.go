@@ -1,13 +1,21 @@
 package detail
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
 	"github.com/devbydaniel/announcable/internal/handler/shared"
+	"github.com/devbydaniel/announcable/internal/locale"
 	mw "github.com/devbydaniel/announcable/internal/middleware"
 	"github.com/devbydaniel/announcable/templates"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 // Handlers holds the dependencies for release note detail handlers
@@ -29,6 +37,74 @@ type pageData struct {
 	HideCtaIsChecked             bool
 	CtaLabelOverrideIsChecked    bool
 	CtaUrlOverrideIsChecked      bool
+	Revisions                    []*revisionItem
+	Tags                         []*tag.Tag
+	SelectedTags                 map[uuid.UUID]bool
+	// Permalink is set when the note can be viewed on the public release page
+	Permalink string
+	// DefaultLanguage is the name of the language the main content is written in
+	DefaultLanguage string
+	Translations    []*translationInput
+	// AudienceRules is the JSON encoded list of rules the audience editor starts with
+	AudienceRules     string
+	AudienceOperators []releasenotes.AudienceOperator
+	// Media is the JSON encoded list of attachments the media editor starts with
+	Media string
+	// Review is set when the organisation requires reviews before publishing
+	Review *reviewData
+	// ScheduleAwaitsApproval is set if the publish time only takes effect once the note is approved
+	ScheduleAwaitsApproval bool
+	// Previews are the preview links of the note that haven't expired yet
+	Previews []*previewItem
+}
+
+// previewItem is an active preview link; the link itself is only shown once
+type previewItem struct {
+	ID        string
+	Prefix    string
+	ExpiresAt string
+}
+
+// reviewData holds the review state of the note and the review log
+type reviewData struct {
+	Status string
+	// Reviewer is the email address of the assigned reviewer
+	Reviewer string
+	// IsReviewer is set if the current user is the assigned reviewer
+	IsReviewer bool
+	// Reviewers are the members that can be asked for a review
+	Reviewers []*reviewerOption
+	Items     []*reviewItem
+}
+
+type reviewerOption struct {
+	ID    string
+	Email string
+}
+
+// reviewItem is a single entry of the review log
+type reviewItem struct {
+	Action    string
+	Author    string
+	Comment   string
+	CreatedAt string
+}
+
+// translationInput holds the content of the note in one of the other languages
+type translationInput struct {
+	Code             string
+	Name             string
+	Title            string
+	DescriptionShort string
+	DescriptionLong  string
+}
+
+// revisionItem is a single entry of the release note history
+type revisionItem struct {
+	ID        string
+	Action    string
+	Author    string
+	CreatedAt string
 }
 
 var pageTmpl = templates.Construct(
@@ -56,7 +132,112 @@ func (h *Handlers) ServeReleaseNoteDetailPage(w http.ResponseWriter, r *http.Req
 	// get release note
 	rn, err := releaseNoteService.GetOne(id, orgId)
 	if err != nil {
+		if errors.Is(err, releasenotes.ErrReleaseNoteNotFound) {
+			http.Error(w, "Release note not found", http.StatusNotFound)
+			return
+		}
 		http.Error(w, "Error getting release note", http.StatusInternalServerError)
+		return
+	}
+
+	revisions, err := h.getRevisionItems(rn.ID, uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting revisions")
+		http.Error(w, "Error getting release note history", http.StatusInternalServerError)
+		return
+	}
+
+	tagService := tag.NewService(*tag.NewRepository(h.deps.DB))
+	tags, err := tagService.GetAll(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting tags")
+		http.Error(w, "Error getting tags", http.StatusInternalServerError)
+		return
+	}
+	selectedTags := make(map[uuid.UUID]bool, len(rn.Tags))
+	for _, t := range rn.Tags {
+		selectedTags[t.ID] = true
+	}
+
+	organisationService := organisation.NewService(*organisation.NewRepository(h.deps.DB))
+	org, err := organisationService.GetOrg(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
+		return
+	}
+	var translations []*translationInput
+	for _, l := range locale.Languages {
+		if l.Code == org.DefaultLocale {
+			continue
+		}
+		input := &translationInput{Code: l.Code, Name: l.Name}
+		for _, t := range rn.Translations {
+			if t.Locale == l.Code {
+				input.Title = t.Title
+				input.DescriptionShort = t.DescriptionShort
+				input.DescriptionLong = t.DescriptionLong
+			}
+		}
+		translations = append(translations, input)
+	}
+
+	audienceRules := make([]audienceRuleForm, len(rn.AudienceRules))
+	for i, rule := range rn.AudienceRules {
+		audienceRules[i] = audienceRuleForm{Attribute: rule.Attribute, Operator: rule.Operator.String(), Value: rule.Value}
+	}
+	audienceRulesJSON, err := json.Marshal(audienceRules)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error encoding audience rules")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+		return
+	}
+
+	media := make([]mediaForm, len(rn.Media))
+	for i, m := range rn.Media {
+		media[i] = mediaForm{ID: m.ID.String(), Kind: m.Kind.String(), Link: m.Link, AltText: m.AltText, Url: m.Url}
+	}
+	mediaJSON, err := json.Marshal(media)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error encoding media")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+		return
+	}
+
+	var review *reviewData
+	if org.RequireReview {
+		userId, ok := r.Context().Value(mw.UserIDKey).(string)
+		if !ok {
+			h.deps.Log.Error().Msg("User ID not found in context")
+			http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+			return
+		}
+		review, err = h.getReviewData(rn, uuid.MustParse(orgId), uuid.MustParse(userId))
+		if err != nil {
+			h.deps.Log.Error().Err(err).Msg("Error getting reviews")
+			http.Error(w, "Error getting reviews", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	previews, err := releaseNoteService.GetPreviews(rn.ID, uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting preview links")
+		http.Error(w, "Error getting preview links", http.StatusInternalServerError)
+		return
+	}
+	previewItems := make([]*previewItem, len(previews))
+	for i, p := range previews {
+		previewItems[i] = &previewItem{
+			ID:        p.ID.String(),
+			Prefix:    p.Prefix,
+			ExpiresAt: p.ExpiresAt.UTC().Format("02.01.2006 15:04") + " UTC",
+		}
+	}
+
+	var permalink string
+	if rn.IsPublished && !rn.HideOnReleasePage {
+		permalink = h.getPermalink(rn)
 	}
 
 	data := pageData{
@@ -69,6 +250,18 @@ func (h *Handlers) ServeReleaseNoteDetailPage(w http.ResponseWriter, r *http.Req
 		HideCtaIsChecked:             rn.HideCta,
 		CtaLabelOverrideIsChecked:    rn.CtaLabelOverride != "",
 		CtaUrlOverrideIsChecked:      rn.CtaUrlOverride != "",
+		Revisions:                    revisions,
+		Tags:                         tags,
+		SelectedTags:                 selectedTags,
+		Permalink:                    permalink,
+		DefaultLanguage:              locale.Name(org.DefaultLocale),
+		Translations:                 translations,
+		AudienceRules:                string(audienceRulesJSON),
+		AudienceOperators:            releasenotes.AudienceOperators,
+		Media:                        string(mediaJSON),
+		Review:                       review,
+		ScheduleAwaitsApproval:       rn.ScheduleAwaitsApproval(org.RequireReview),
+		Previews:                     previewItems,
 	}
 	h.deps.Log.Debug().Interface("data", data).Msg("Data")
 	if err := pageTmpl.ExecuteTemplate(w, "root", data); err != nil {
@@ -76,3 +269,106 @@ func (h *Handlers) ServeReleaseNoteDetailPage(w http.ResponseWriter, r *http.Req
 		http.Error(w, "Error rendering page", http.StatusInternalServerError)
 	}
 }
+
+// getRevisionItems loads the history of a release note with the authors resolved to email addresses
+func (h *Handlers) getRevisionItems(rnId, orgId uuid.UUID) ([]*revisionItem, error) {
+	releaseNoteService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+	organisationService := organisation.NewService(*organisation.NewRepository(h.deps.DB))
+
+	revisions, err := releaseNoteService.GetRevisions(rnId, orgId)
+	if err != nil {
+		return nil, err
+	}
+	orgUsers, err := organisationService.GetOrgUsers(orgId)
+	if err != nil {
+		return nil, err
+	}
+	emails := make(map[uuid.UUID]string, len(orgUsers))
+	for _, ou := range orgUsers {
+		emails[ou.UserID] = ou.User.Email
+	}
+
+	items := make([]*revisionItem, 0, len(revisions))
+	for _, rev := range revisions {
+		author := "Scheduler"
+		if rev.AuthorID != nil {
+			if email, ok := emails[*rev.AuthorID]; ok {
+				author = email
+			} else {
+				author = "Former member"
+			}
+		}
+		items = append(items, &revisionItem{
+			ID:        rev.ID.String(),
+			Action:    rev.Action.String(),
+			Author:    author,
+			CreatedAt: rev.CreatedAt.UTC().Format("02.01.2006 15:04") + " UTC",
+		})
+	}
+	return items, nil
+}
+
+// getReviewData loads the review log of a release note with the authors resolved to email addresses
+func (h *Handlers) getReviewData(rn *releasenotes.ReleaseNote, orgId, userId uuid.UUID) (*reviewData, error) {
+	releaseNoteService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+	organisationService := organisation.NewService(*organisation.NewRepository(h.deps.DB))
+
+	reviews, err := releaseNoteService.GetReviews(rn.ID, orgId)
+	if err != nil {
+		return nil, err
+	}
+	orgUsers, err := organisationService.GetOrgUsers(orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &reviewData{
+		Status:     rn.ReviewStatus.String(),
+		IsReviewer: rn.ReviewerID != nil && *rn.ReviewerID == userId,
+	}
+	emails := make(map[uuid.UUID]string, len(orgUsers))
+	for _, ou := range orgUsers {
+		emails[ou.UserID] = ou.User.Email
+		if ou.UserID != userId {
+			data.Reviewers = append(data.Reviewers, &reviewerOption{ID: ou.UserID.String(), Email: ou.User.Email})
+		}
+	}
+	if rn.ReviewerID != nil {
+		data.Reviewer = emails[*rn.ReviewerID]
+	}
+	for _, review := range reviews {
+		author := "Former member"
+		if review.AuthorID != nil {
+			if email, ok := emails[*review.AuthorID]; ok {
+				author = email
+			}
+		}
+		data.Items = append(data.Items, &reviewItem{
+			Action:    strings.ReplaceAll(review.Action.String(), "_", " "),
+			Author:    author,
+			Comment:   review.Comment,
+			CreatedAt: review.CreatedAt.UTC().Format("02.01.2006 15:04") + " UTC",
+		})
+	}
+	return data, nil
+}
+
+// getPermalink returns the URL of the note on the public release page, or an empty string if the page is disabled
+func (h *Handlers) getPermalink(rn *releasenotes.ReleaseNote) string {
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.deps.DB, h.deps.ObjStore))
+
+	cfg, err := releasePageConfigService.Get(rn.OrganisationID)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting release page config")
+		return ""
+	}
+	if cfg.DisableReleasePage {
+		return ""
+	}
+	releasePageUrl, err := releasePageConfigService.GetUrl(rn.OrganisationID)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting release page URL")
+		return ""
+	}
+	return rn.Permalink(releasePageUrl)
+}
@@ -0,0 +1,91 @@
+package detail
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/internal/util"
+	"github.com/devbydaniel/announcable/templates"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// previewCreateForm represents the form data for creating a preview link
+type previewCreateForm struct {
+	LifetimeDays int `schema:"lifetime_days"`
+}
+
+var previewLinkCreatedTmpl = templates.Construct("preview-link-created", "partials/hx-preview-link-created.html")
+
+// HandlePreviewCreate handles POST /release-notes/{id}/previews
+func (h *Handlers) HandlePreviewCreate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandlePreviewCreate")
+	ctx := r.Context()
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+	releasePageConfigService := releasepageconfig.NewService(*releasepageconfig.NewRepository(h.deps.DB, h.deps.ObjStore))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing ID")
+		http.Error(w, "Error creating preview link", http.StatusBadRequest)
+		return
+	}
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	userId, ok := ctx.Value(mw.UserIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("User ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error creating preview link", http.StatusBadRequest)
+		return
+	}
+	var createDTO previewCreateForm
+	if err := h.deps.Decoder.Decode(&createDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error creating preview link", http.StatusBadRequest)
+		return
+	}
+
+	// previews live on the hosted release page, which works without a custom URL
+	releasePageUrl, err := releasePageConfigService.GetUrl(uuid.MustParse(orgId))
+	if err != nil || releasePageUrl == "" {
+		h.deps.Log.Error().Err(err).Msg("Error getting release page URL")
+		http.Error(w, "Error creating preview link", http.StatusInternalServerError)
+		return
+	}
+
+	lifetime := time.Duration(createDTO.LifetimeDays) * 24 * time.Hour
+	token, _, err := releaseNotesService.CreatePreview(id, uuid.MustParse(orgId), uuid.MustParse(userId), lifetime)
+	if err != nil {
+		switch {
+		case errors.Is(err, releasenotes.ErrReleaseNoteNotFound):
+			http.Error(w, "Release note not found", http.StatusNotFound)
+		case errors.Is(err, releasenotes.ErrInvalidPreviewLifetime):
+			http.Error(w, "Preview links can be valid for one hour up to 30 days", http.StatusBadRequest)
+		default:
+			h.deps.Log.Error().Err(err).Msg("Error creating preview link")
+			http.Error(w, "Error creating preview link", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// the token is shown once, only its hash is stored
+	w.Header().Set("HX-Trigger", "custom:submit-success")
+	if err := previewLinkCreatedTmpl.ExecuteTemplate(w, "hx-preview-link-created", util.BuildURL(releasePageUrl, "preview", token)); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rendering template")
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,49 @@
+package detail
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// HandlePreviewRevoke handles DELETE /release-notes/{id}/previews/{previewId}
+func (h *Handlers) HandlePreviewRevoke(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandlePreviewRevoke")
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing ID")
+		http.Error(w, "Error revoking preview link", http.StatusBadRequest)
+		return
+	}
+	previewId, err := uuid.Parse(chi.URLParam(r, "previewId"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing preview ID")
+		http.Error(w, "Error revoking preview link", http.StatusBadRequest)
+		return
+	}
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+
+	if err := releaseNotesService.RevokePreview(previewId, id, uuid.MustParse(orgId)); err != nil {
+		if errors.Is(err, releasenotes.ErrPreviewNotFound) {
+			http.Error(w, "Preview link not found", http.StatusNotFound)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error revoking preview link")
+		http.Error(w, "Error revoking preview link", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
@@ -1,6 +1,7 @@
 package detail
 
 import (
+	"errors"
 	"net/http"
 
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
@@ -40,7 +41,11 @@ func (h *Handlers) HandleReleaseNotePublish(w http.ResponseWriter, r *http.Reque
 	shouldPublish := r.FormValue("publish") == "true"
 
 	h.deps.Log.Debug().Interface("publishDTO", shouldPublish).Msg("publishDTO")
-	if err := releaseNotesService.ChangePublishedStatus(id, shouldPublish); err != nil {
+	if err := releaseNotesService.ChangePublishedStatus(id, shouldPublish, uuid.MustParse(userId)); err != nil {
+		if errors.Is(err, releasenotes.ErrReviewRequired) {
+			http.Error(w, "Release note needs an approved review before it can be published", http.StatusConflict)
+			return
+		}
 		h.deps.Log.Error().Err(err).Msg("Error updating release note")
 		http.Error(w, "Error updating release note", http.StatusInternalServerError)
 		return
@@ -0,0 +1,97 @@
+package detail
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// reviewForm represents a review step. Action is one of "submit", "approve" and "request_changes",
+// ReviewerID is only used when submitting.
+type reviewForm struct {
+	Action     string `schema:"action"`
+	ReviewerID string `schema:"reviewer_id"`
+	Comment    string `schema:"comment"`
+}
+
+// HandleReleaseNoteReview handles POST /release-notes/{id}/reviews
+func (h *Handlers) HandleReleaseNoteReview(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleReleaseNoteReview")
+	ctx := r.Context()
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing ID")
+		http.Error(w, "Error saving review", http.StatusBadRequest)
+		return
+	}
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	userId, ok := ctx.Value(mw.UserIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("User ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error saving review", http.StatusBadRequest)
+		return
+	}
+	var reviewDTO reviewForm
+	if err := h.deps.Decoder.Decode(&reviewDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error saving review", http.StatusBadRequest)
+		return
+	}
+
+	switch reviewDTO.Action {
+	case "submit":
+		reviewerId, parseErr := uuid.Parse(reviewDTO.ReviewerID)
+		if parseErr != nil {
+			http.Error(w, "Please choose a reviewer", http.StatusBadRequest)
+			return
+		}
+		err = releaseNotesService.SubmitForReview(id, uuid.MustParse(orgId), uuid.MustParse(userId), reviewerId, reviewDTO.Comment)
+	case "approve":
+		err = releaseNotesService.Approve(id, uuid.MustParse(orgId), uuid.MustParse(userId), reviewDTO.Comment)
+	case "request_changes":
+		err = releaseNotesService.RequestChanges(id, uuid.MustParse(orgId), uuid.MustParse(userId), reviewDTO.Comment)
+	default:
+		http.Error(w, "Unknown review action", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, releasenotes.ErrReleaseNoteNotFound):
+			http.Error(w, "Release note not found", http.StatusNotFound)
+		case errors.Is(err, releasenotes.ErrNotReviewer):
+			http.Error(w, "Only the assigned reviewer can review this release note", http.StatusForbidden)
+		case errors.Is(err, releasenotes.ErrInvalidReviewTransition):
+			http.Error(w, "The review status has changed, please reload the page", http.StatusConflict)
+		case errors.Is(err, releasenotes.ErrInvalidReviewer):
+			http.Error(w, "Please choose another member of the organisation as reviewer", http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrCommentRequired):
+			http.Error(w, "Please describe the changes you request", http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrCommentTooLong):
+			http.Error(w, "Comments can have at most 2000 characters", http.StatusBadRequest)
+		default:
+			h.deps.Log.Error().Err(err).Msg("Error saving review")
+			http.Error(w, "Error saving review", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
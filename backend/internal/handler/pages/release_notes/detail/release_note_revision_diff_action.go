@@ -0,0 +1,55 @@
+package detail
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/templates"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var revisionDiffTmpl = templates.Construct("revision-diff", "partials/hx-revision-diff.html")
+
+// HandleRevisionDiff handles GET /release-notes/{id}/revisions/{revisionId}
+func (h *Handlers) HandleRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleRevisionDiff")
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing ID")
+		http.Error(w, "Error getting revision", http.StatusBadRequest)
+		return
+	}
+	revisionId, err := uuid.Parse(chi.URLParam(r, "revisionId"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing revision ID")
+		http.Error(w, "Error getting revision", http.StatusBadRequest)
+		return
+	}
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+
+	_, changes, err := releaseNotesService.GetRevisionChanges(id, revisionId, uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting revision changes")
+		if errors.Is(err, releasenotes.ErrRevisionNotFound) {
+			http.Error(w, "Revision not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error getting revision", http.StatusInternalServerError)
+		return
+	}
+
+	if err := revisionDiffTmpl.ExecuteTemplate(w, "hx-revision-diff", changes); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rendering template")
+		http.Error(w, "Error getting revision", http.StatusInternalServerError)
+	}
+}
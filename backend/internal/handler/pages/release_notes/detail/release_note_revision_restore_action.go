@@ -0,0 +1,56 @@
+package detail
+
+import (
+	"errors"
+	"net/http"
+
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// HandleRevisionRestore handles POST /release-notes/{id}/revisions/{revisionId}/restore
+func (h *Handlers) HandleRevisionRestore(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleRevisionRestore")
+	ctx := r.Context()
+	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing ID")
+		http.Error(w, "Error restoring revision", http.StatusBadRequest)
+		return
+	}
+	revisionId, err := uuid.Parse(chi.URLParam(r, "revisionId"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing revision ID")
+		http.Error(w, "Error restoring revision", http.StatusBadRequest)
+		return
+	}
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	userId, ok := ctx.Value(mw.UserIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("User ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+
+	if err := releaseNotesService.RestoreRevision(id, revisionId, uuid.MustParse(orgId), uuid.MustParse(userId)); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error restoring revision")
+		if errors.Is(err, releasenotes.ErrRevisionNotFound) {
+			http.Error(w, "Revision not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error restoring revision", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
@@ -1,9 +1,12 @@
 package detail
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/domain/tag"
 	"github.com/devbydaniel/announcable/internal/imgUtil"
 	mw "github.com/devbydaniel/announcable/internal/middleware"
 	"github.com/go-chi/chi/v5"
@@ -12,24 +15,51 @@ import (
 )
 
 type releaseNoteUpdateForm struct {
-	// The request also contains a field for the image,
-	// but gorilla cannot parse it as it is a file in a multipart form.
-	Title               string `schema:"title" validate:"required"`
-	ShouldDeleteImage   bool   `schema:"delete_image"`
-	DescriptionShort    string `schema:"description_short" validate:"required"`
-	TextWebsiteOverride string `schema:"text_website_override"`
-	DescriptionLong     string `schema:"description_long"`
-	ReleaseDate         string `schema:"release_date"`
-	HideCta             bool   `schema:"hide_cta"`
-	OverrideCtaLabel    bool   `schema:"override_cta_label"`
-	CtaLabelOverride    string `schema:"cta_label_override"`
-	OverrideCtaUrl      bool   `schema:"override_cta_url"`
-	CtaUrlOverride      string `schema:"cta_url_override"`
-	AttentionMechanism  string `schema:"attention_mechanism"`
-	HideOnWidget        string `schema:"hide_on_widget"`
-	HideOnReleasePage   string `schema:"hide_on_release_page"`
-	MediaType           string `schema:"media_type"`
-	MediaLink           string `schema:"media_link"`
+	// The request also contains the files of new images,
+	// but gorilla cannot parse them as they are files in a multipart form.
+	Title               string             `schema:"title" validate:"required"`
+	DescriptionShort    string             `schema:"description_short" validate:"required"`
+	TextWebsiteOverride string             `schema:"text_website_override"`
+	DescriptionLong     string             `schema:"description_long"`
+	ReleaseDate         string             `schema:"release_date"`
+	HideCta             bool               `schema:"hide_cta"`
+	OverrideCtaLabel    bool               `schema:"override_cta_label"`
+	CtaLabelOverride    string             `schema:"cta_label_override"`
+	OverrideCtaUrl      bool               `schema:"override_cta_url"`
+	CtaUrlOverride      string             `schema:"cta_url_override"`
+	AttentionMechanism  string             `schema:"attention_mechanism"`
+	HideOnWidget        string             `schema:"hide_on_widget"`
+	HideOnReleasePage   string             `schema:"hide_on_release_page"`
+	PublishAt           string             `schema:"publish_at"`
+	UnpublishAt         string             `schema:"unpublish_at"`
+	TagIds              []string           `schema:"tag_ids"`
+	Translations        []translationForm  `schema:"translations"`
+	AudienceRules       []audienceRuleForm `schema:"audience_rules"`
+	Media               []mediaForm        `schema:"media"`
+}
+
+type translationForm struct {
+	Locale           string `schema:"locale"`
+	Title            string `schema:"title"`
+	DescriptionShort string `schema:"description_short"`
+	DescriptionLong  string `schema:"description_long"`
+}
+
+type audienceRuleForm struct {
+	Attribute string `schema:"attribute" json:"attribute"`
+	Operator  string `schema:"operator" json:"operator"`
+	Value     string `schema:"value" json:"value"`
+}
+
+// mediaForm is an attachment in the editor. Existing attachments are sent with their ID,
+// the file of a new image as media.{index}.file.
+type mediaForm struct {
+	ID      string `schema:"id" json:"id"`
+	Kind    string `schema:"kind" json:"kind"`
+	Link    string `schema:"link" json:"link"`
+	AltText string `schema:"alt_text" json:"alt_text"`
+	// Url is only used to show the current attachments in the editor
+	Url string `schema:"-" json:"url"`
 }
 
 // HandleReleaseNoteUpdate handles PATCH /release-notes/{id}
@@ -37,6 +67,7 @@ func (h *Handlers) HandleReleaseNoteUpdate(w http.ResponseWriter, r *http.Reques
 	h.deps.Log.Trace().Msg("HandleReleaseNoteUpdate")
 	ctx := r.Context()
 	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
+	tagService := tag.NewService(*tag.NewRepository(h.deps.DB))
 
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -80,45 +111,22 @@ func (h *Handlers) HandleReleaseNoteUpdate(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// get image
-	img, imgHeader, err := r.FormFile("image")
+	// parse schedule
+	publishAt, unpublishAt, err := releasenotes.ParseSchedule(updateDTO.PublishAt, updateDTO.UnpublishAt)
 	if err != nil {
-		h.deps.Log.Debug().Err(err).Msg("No image uploaded")
+		h.deps.Log.Error().Err(err).Msg("Invalid schedule")
+		http.Error(w, "Invalid publishing schedule", http.StatusBadRequest)
+		return
 	}
-	h.deps.Log.Debug().Interface("img", img).Msg("img")
 
-	// prepare models
-	var imgInput *releasenotes.ImageInput
-	if updateDTO.MediaType == "image" {
-		if updateDTO.ShouldDeleteImage {
-			// If delete is requested, always delete regardless of new upload
-			imgInput = &releasenotes.ImageInput{
-				ShouldDeleteImage: true,
-			}
-		} else if img != nil {
-			// Only process new image if no deletion is requested
-			ok := imgUtil.VerifyImageType(img)
-			if !ok {
-				h.deps.Log.Error().Msg("Invalid image type")
-				http.Error(w, "Error updating release note", http.StatusBadRequest)
-				return
-			}
-			imgInput = &releasenotes.ImageInput{
-				ShouldDeleteImage: false,
-				ImgData:           img,
-				Format:            imgHeader.Header.Get("Content-Type"),
-			}
-		}
-	} else if updateDTO.MediaType == "embed" {
-		// If switching to embed, always delete any existing image
-		imgInput = &releasenotes.ImageInput{
-			ShouldDeleteImage: true,
-		}
+	// read attachments
+	media, err := mediaInputs(r, updateDTO.Media)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Invalid media")
+		http.Error(w, "Invalid media attachment", http.StatusBadRequest)
+		return
 	}
 
-	h.deps.Log.Debug().Interface("imgInput", imgInput).Msg("ImageInput")
-	h.deps.Log.Debug().Interface("updateDTO", updateDTO).Msg("updateDTO")
-
 	releaseNote := &releasenotes.ReleaseNote{
 		OrganisationID:     uuid.MustParse(orgId),
 		Title:              updateDTO.Title,
@@ -128,18 +136,8 @@ func (h *Handlers) HandleReleaseNoteUpdate(w http.ResponseWriter, r *http.Reques
 		LastUpdatedBy:      uuid.MustParse(userId),
 		HideOnWidget:       updateDTO.HideOnWidget == "on",
 		HideOnReleasePage:  updateDTO.HideOnReleasePage == "on",
-	}
-
-	// Handle media type switching
-	switch updateDTO.MediaType {
-	case "embed":
-		// If switching to embed, clear image path and set media link
-		releaseNote.ImagePath = ""
-		releaseNote.MediaLink = updateDTO.MediaLink
-	case "image":
-		// If switching to image, clear media link
-		releaseNote.MediaLink = ""
-		// Image handling is done above with imgInput
+		PublishAt:          publishAt,
+		UnpublishAt:        unpublishAt,
 	}
 
 	if updateDTO.TextWebsiteOverride == "on" {
@@ -162,14 +160,84 @@ func (h *Handlers) HandleReleaseNoteUpdate(w http.ResponseWriter, r *http.Reques
 	} else {
 		releaseNote.ReleaseDate = nil
 	}
+	tags, err := tagService.Resolve(uuid.MustParse(orgId), updateDTO.TagIds)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting tags")
+		http.Error(w, "Error updating release note", http.StatusInternalServerError)
+		return
+	}
+	releaseNote.Tags = tags
+	releaseNote.Translations = []*releasenotes.ReleaseNoteTranslation{}
+	for _, t := range updateDTO.Translations {
+		releaseNote.Translations = append(releaseNote.Translations, &releasenotes.ReleaseNoteTranslation{
+			Locale:           t.Locale,
+			Title:            t.Title,
+			DescriptionShort: t.DescriptionShort,
+			DescriptionLong:  t.DescriptionLong,
+		})
+	}
+	releaseNote.AudienceRules = []*releasenotes.AudienceRule{}
+	for _, rule := range updateDTO.AudienceRules {
+		releaseNote.AudienceRules = append(releaseNote.AudienceRules, &releasenotes.AudienceRule{
+			Attribute: rule.Attribute,
+			Operator:  releasenotes.AudienceOperator(rule.Operator),
+			Value:     rule.Value,
+		})
+	}
 	h.deps.Log.Debug().Interface("releaseNote", releaseNote).Msg("ReleaseNote to update")
 
-	if err := releaseNotesService.Update(id, releaseNote, imgInput); err != nil {
-		h.deps.Log.Error().Err(err).Msg("Error updating release note")
-		http.Error(w, "Error updating release note", http.StatusInternalServerError)
+	if err := releaseNotesService.Update(id, releaseNote, media); err != nil {
+		switch {
+		case errors.Is(err, releasenotes.ErrIncompleteTranslation):
+			http.Error(w, "Translations need a title and a description", http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrInvalidLocale):
+			http.Error(w, "Unsupported translation language", http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrInvalidAudienceRule):
+			http.Error(w, "Audience rules need an attribute and a value, comparisons a number", http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrTooManyMedia),
+			errors.Is(err, releasenotes.ErrInvalidMedia),
+			errors.Is(err, releasenotes.ErrInvalidMediaLink),
+			errors.Is(err, releasenotes.ErrAltTextTooLong):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, releasenotes.ErrReviewRequired):
+			http.Error(w, "Release note needs an approved review before it can be scheduled", http.StatusConflict)
+		default:
+			h.deps.Log.Error().Err(err).Msg("Error updating release note")
+			http.Error(w, "Error updating release note", http.StatusInternalServerError)
+		}
 		return
 	}
 
 	w.Header().Set("HX-Trigger", "custom:submit-success")
 	w.WriteHeader(http.StatusOK)
 }
+
+// mediaInputs reads the attachments of the editor in their submitted order
+func mediaInputs(r *http.Request, forms []mediaForm) ([]*releasenotes.MediaInput, error) {
+	media := make([]*releasenotes.MediaInput, 0, len(forms))
+	for i, f := range forms {
+		input := &releasenotes.MediaInput{
+			Kind:    releasenotes.MediaKind(f.Kind),
+			Link:    f.Link,
+			AltText: f.AltText,
+		}
+		if f.ID != "" {
+			id, err := uuid.Parse(f.ID)
+			if err != nil {
+				return nil, fmt.Errorf("media %d: %w", i, err)
+			}
+			input.ID = id
+		} else if input.Kind == releasenotes.MediaKindImage {
+			img, _, err := r.FormFile(fmt.Sprintf("media.%d.file", i))
+			if err != nil {
+				return nil, fmt.Errorf("image %d: %w", i, err)
+			}
+			if ok := imgUtil.VerifyImageType(img); !ok {
+				return nil, fmt.Errorf("image %d: unsupported image type", i)
+			}
+			input.ImgData = img
+		}
+		media = append(media, input)
+	}
+	return media, nil
+}
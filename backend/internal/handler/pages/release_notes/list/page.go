@@ -5,12 +5,14 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasenotelikes "github.com/devbydaniel/announcable/internal/domain/release-note-likes"
 	releasenotemetrics "github.com/devbydaniel/announcable/internal/domain/release-note-metrics"
 	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
 	"github.com/devbydaniel/announcable/internal/handler/shared"
 	mw "github.com/devbydaniel/announcable/internal/middleware"
 	"github.com/devbydaniel/announcable/templates"
+	"github.com/google/uuid"
 )
 
 // Handlers holds the dependencies for release notes list handlers
@@ -29,6 +31,8 @@ type ReleaseNoteWithMetrics struct {
 	ViewCount     int
 	LikeCount     int
 	CtaClickCount int
+	// AwaitsApproval is set for scheduled notes that are only published once approved
+	AwaitsApproval bool
 }
 
 // pageData holds the template data for the release notes list page
@@ -59,6 +63,7 @@ func (h *Handlers) ServeReleaseNotesListPage(w http.ResponseWriter, r *http.Requ
 	releaseNotesService := releasenotes.NewService(*releasenotes.NewRepository(h.deps.DB, h.deps.ObjStore))
 	metricsService := releasenotemetrics.NewService(releasenotemetrics.NewRepository(h.deps.DB))
 	likesService := releasenotelikes.NewService(releasenotelikes.NewRepository(h.deps.DB))
+	organisationService := organisation.NewService(*organisation.NewRepository(h.deps.DB))
 
 	page := r.URL.Query().Get("page")
 	if page == "" {
@@ -86,6 +91,12 @@ func (h *Handlers) ServeReleaseNotesListPage(w http.ResponseWriter, r *http.Requ
 		w.Write([]byte(err.Error()))
 		return
 	}
+	org, err := organisationService.GetOrg(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
+		return
+	}
 
 	// Create slice to hold release notes with metrics
 	releaseNotesWithMetrics := make([]*ReleaseNoteWithMetrics, len(releaseNotes.Items))
@@ -128,10 +139,11 @@ func (h *Handlers) ServeReleaseNotesListPage(w http.ResponseWriter, r *http.Requ
 		}
 
 		releaseNotesWithMetrics[i] = &ReleaseNoteWithMetrics{
-			ReleaseNote:   rn,
-			ViewCount:     viewCount,
-			LikeCount:     likeCount,
-			CtaClickCount: ctaClickCount,
+			ReleaseNote:    rn,
+			ViewCount:      viewCount,
+			LikeCount:      likeCount,
+			CtaClickCount:  ctaClickCount,
+			AwaitsApproval: rn.ScheduleAwaitsApproval(org.RequireReview),
 		}
 	}
 
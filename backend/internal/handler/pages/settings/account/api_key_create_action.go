@@ -0,0 +1,74 @@
+package account
+
+import (
+	"net/http"
+
+	apikey "github.com/devbydaniel/announcable/internal/domain/api-key"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/templates"
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+)
+
+// apiKeyCreateForm represents the form data for creating an API key
+type apiKeyCreateForm struct {
+	Name string `schema:"name" validate:"required,max=100"`
+}
+
+var apiKeyCreatedTmpl = templates.Construct("api-key-created", "partials/hx-api-key-created.html")
+
+// HandleApiKeyCreate handles POST /settings/api-keys
+func (h *Handlers) HandleApiKeyCreate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleApiKeyCreate")
+	ctx := r.Context()
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	userId, ok := ctx.Value(mw.UserIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("User ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	apiKeyService := apikey.NewService(*apikey.NewRepository(h.deps.DB))
+
+	// parse form
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error creating API key", http.StatusBadRequest)
+		return
+	}
+
+	// decode form
+	var createDTO apiKeyCreateForm
+	if err := h.deps.Decoder.Decode(&createDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error creating API key", http.StatusBadRequest)
+		return
+	}
+
+	// validate form
+	validate := validator.New()
+	if err := validate.Struct(createDTO); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Validation error")
+		http.Error(w, "Please enter a name of at most 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	plainKey, _, err := apiKeyService.Create(uuid.MustParse(orgId), uuid.MustParse(userId), createDTO.Name)
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error creating API key")
+		http.Error(w, "Error creating API key", http.StatusInternalServerError)
+		return
+	}
+
+	// the plain key is shown once, only its hash is stored
+	w.Header().Set("HX-Trigger", "custom:submit-success")
+	if err := apiKeyCreatedTmpl.ExecuteTemplate(w, "hx-api-key-created", plainKey); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rendering template")
+		http.Error(w, "Error rendering template", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,44 @@
+package account
+
+import (
+	"errors"
+	"net/http"
+
+	apikey "github.com/devbydaniel/announcable/internal/domain/api-key"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// HandleApiKeyRevoke handles DELETE /settings/api-keys/{id}
+func (h *Handlers) HandleApiKeyRevoke(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleApiKeyRevoke")
+	ctx := r.Context()
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	apiKeyService := apikey.NewService(*apikey.NewRepository(h.deps.DB))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Invalid API key ID")
+		http.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := apiKeyService.Revoke(id, uuid.MustParse(orgId)); err != nil {
+		if errors.Is(err, apikey.ErrKeyNotFound) {
+			http.Error(w, "API key not found", http.StatusNotFound)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error revoking API key")
+		http.Error(w, "Error revoking API key", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
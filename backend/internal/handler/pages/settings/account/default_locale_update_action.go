@@ -0,0 +1,55 @@
+package account
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// defaultLocaleUpdateForm represents the form data for updating the default language
+type defaultLocaleUpdateForm struct {
+	DefaultLocale string `schema:"default_locale"`
+}
+
+// HandleDefaultLocaleUpdate handles PATCH /settings/default-locale
+func (h *Handlers) HandleDefaultLocaleUpdate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleDefaultLocaleUpdate")
+	ctx := r.Context()
+	organisationService := organisation.NewService(*organisation.NewRepository(h.deps.DB))
+
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Error updating default language", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error updating default language", http.StatusBadRequest)
+		return
+	}
+
+	var updateDTO defaultLocaleUpdateForm
+	if err := h.deps.Decoder.Decode(&updateDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error updating default language", http.StatusBadRequest)
+		return
+	}
+
+	if err := organisationService.UpdateDefaultLocale(uuid.MustParse(orgId), updateDTO.DefaultLocale); err != nil {
+		if errors.Is(err, organisation.ErrUnsupportedLocale) {
+			http.Error(w, "Unsupported language", http.StatusBadRequest)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error updating default language")
+		http.Error(w, "Error updating default language", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "custom:submit-success")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,31 @@
+package account
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/identity"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// HandleIdentitySecretRotate handles PATCH /settings/identity-secret
+func (h *Handlers) HandleIdentitySecretRotate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleIdentitySecretRotate")
+	ctx := r.Context()
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	identityService := identity.NewService(*identity.NewRepository(h.deps.DB))
+
+	if err := identityService.RotateSecret(uuid.MustParse(orgId)); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rotating identity secret")
+		http.Error(w, "Error rotating identity secret", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,55 @@
+package account
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/identity"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// identityUpdateForm represents the form data for updating identity verification
+type identityUpdateForm struct {
+	RequireSigned bool `schema:"require_signed"`
+}
+
+// HandleIdentityUpdate handles PATCH /settings/identity
+func (h *Handlers) HandleIdentityUpdate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleIdentityUpdate")
+	ctx := r.Context()
+	identityService := identity.NewService(*identity.NewRepository(h.deps.DB))
+
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Error updating identity verification", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error updating identity verification", http.StatusBadRequest)
+		return
+	}
+
+	var updateDTO identityUpdateForm
+	if err := h.deps.Decoder.Decode(&updateDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error updating identity verification", http.StatusBadRequest)
+		return
+	}
+
+	if err := identityService.SetRequireSigned(uuid.MustParse(orgId), updateDTO.RequireSigned); err != nil {
+		if errors.Is(err, identity.ErrNoSecret) {
+			http.Error(w, "Generate a secret before requiring signed identities", http.StatusBadRequest)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error updating identity verification")
+		http.Error(w, "Error updating identity verification", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "custom:submit-success")
+	w.WriteHeader(http.StatusOK)
+}
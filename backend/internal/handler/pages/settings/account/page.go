@@ -2,11 +2,15 @@ package account
 
 import (
 	"net/http"
+	"time"
 
+	apikey "github.com/devbydaniel/announcable/internal/domain/api-key"
+	"github.com/devbydaniel/announcable/internal/domain/identity"
 	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	releasepageconfig "github.com/devbydaniel/announcable/internal/domain/release-page-configs"
 	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
 	"github.com/devbydaniel/announcable/internal/handler/shared"
+	"github.com/devbydaniel/announcable/internal/locale"
 	mw "github.com/devbydaniel/announcable/internal/middleware"
 	"github.com/devbydaniel/announcable/templates"
 	"github.com/google/uuid"
@@ -29,6 +33,21 @@ type pageData struct {
 	ReleasePageUrl     string
 	CustomUrl          *string
 	DisableReleasePage bool
+	ApiKeys            []*apiKeyItem
+	DefaultLocale      string
+	Languages          []locale.Language
+	IdentitySecret     string
+	RequireSigned      bool
+	RequireReview      bool
+}
+
+// apiKeyItem represents an API key in the settings page
+type apiKeyItem struct {
+	ID         string
+	Name       string
+	Prefix     string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
 }
 
 var pageTmpl = templates.Construct(
@@ -59,10 +78,10 @@ func (h *Handlers) ServeSettingsPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	externalId, err := organisationService.GetExternalId(uuid.MustParse(orgId))
+	org, err := organisationService.GetOrg(uuid.MustParse(orgId))
 	if err != nil {
-		h.deps.Log.Error().Err(err).Msg("Error getting external org ID")
-		http.Error(w, "Error getting external org ID", http.StatusInternalServerError)
+		h.deps.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
 		return
 	}
 
@@ -79,14 +98,44 @@ func (h *Handlers) ServeSettingsPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	apiKeyService := apikey.NewService(*apikey.NewRepository(h.deps.DB))
+	apiKeys, err := apiKeyService.GetAll(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting API keys")
+		http.Error(w, "Error getting API keys", http.StatusInternalServerError)
+		return
+	}
+
+	identityService := identity.NewService(*identity.NewRepository(h.deps.DB))
+	identityConfig, err := identityService.Get(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting identity config")
+		http.Error(w, "Error getting identity config", http.StatusInternalServerError)
+		return
+	}
+
 	orgName := ctx.Value(mw.OrgNameKey).(string)
 
 	data := pageData{
 		BaseTemplateData: shared.BaseTemplateData{
 			Title: "Settings for " + orgName,
 		},
-		WidgetID:           externalId.String(),
+		WidgetID:           org.ExternalID.String(),
 		DisableReleasePage: releasePageConfig.DisableReleasePage,
+		DefaultLocale:      org.DefaultLocale,
+		Languages:          locale.Languages,
+		IdentitySecret:     identityConfig.Secret,
+		RequireSigned:      identityConfig.RequireSigned,
+		RequireReview:      org.RequireReview,
+	}
+	for _, key := range apiKeys {
+		data.ApiKeys = append(data.ApiKeys, &apiKeyItem{
+			ID:         key.ID.String(),
+			Name:       key.Name,
+			Prefix:     key.Prefix,
+			CreatedAt:  key.CreatedAt,
+			LastUsedAt: key.LastUsedAt,
+		})
 	}
 	if releasePageUrl != "" {
 		data.ReleasePageUrl = releasePageUrl
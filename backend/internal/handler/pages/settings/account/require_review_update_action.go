@@ -0,0 +1,50 @@
+package account
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// requireReviewUpdateForm represents the form data for updating the review policy
+type requireReviewUpdateForm struct {
+	RequireReview bool `schema:"require_review"`
+}
+
+// HandleRequireReviewUpdate handles PATCH /settings/review
+func (h *Handlers) HandleRequireReviewUpdate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleRequireReviewUpdate")
+	ctx := r.Context()
+	organisationService := organisation.NewService(*organisation.NewRepository(h.deps.DB))
+
+	orgId, ok := ctx.Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Error updating review policy", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error updating review policy", http.StatusBadRequest)
+		return
+	}
+
+	var updateDTO requireReviewUpdateForm
+	if err := h.deps.Decoder.Decode(&updateDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error updating review policy", http.StatusBadRequest)
+		return
+	}
+
+	if err := organisationService.UpdateRequireReview(uuid.MustParse(orgId), updateDTO.RequireReview); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error updating review policy")
+		http.Error(w, "Error updating review policy", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "custom:submit-success")
+	w.WriteHeader(http.StatusOK)
+}
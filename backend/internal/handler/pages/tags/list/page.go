@@ -0,0 +1,81 @@
+package list
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	"github.com/devbydaniel/announcable/internal/handler/shared"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/templates"
+	"github.com/google/uuid"
+)
+
+// Handlers holds dependencies for tag handlers
+type Handlers struct {
+	deps *shared.Dependencies
+}
+
+// New creates a new Handlers instance
+func New(deps *shared.Dependencies) *Handlers {
+	return &Handlers{deps: deps}
+}
+
+// pageData represents the template data for the tags page
+type pageData struct {
+	shared.BaseTemplateData
+	Tags         []*tag.Tag
+	DefaultColor string
+}
+
+var pageTmpl = templates.Construct(
+	"tags",
+	"layouts/root.html",
+	"layouts/appframe.html",
+	"pages/tag-list.html",
+)
+
+// ServeTagsPage handles GET /tags/
+func (h *Handlers) ServeTagsPage(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("ServeTagsPage")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	tagService := tag.NewService(*tag.NewRepository(h.deps.DB))
+
+	tags, err := tagService.GetAll(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting tags")
+		http.Error(w, "Error getting tags", http.StatusInternalServerError)
+		return
+	}
+
+	data := pageData{
+		BaseTemplateData: shared.BaseTemplateData{
+			Title: "Tags",
+		},
+		Tags:         tags,
+		DefaultColor: tag.DefaultColor,
+	}
+
+	if err := pageTmpl.ExecuteTemplate(w, "root", data); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rendering page")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+	}
+}
+
+// tagError maps validation errors of the tag service to a response
+func (h *Handlers) tagError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, tag.ErrInvalidName), errors.Is(err, tag.ErrInvalidColor), errors.Is(err, tag.ErrNameTaken):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, tag.ErrTagNotFound):
+		http.Error(w, "Tag not found", http.StatusNotFound)
+	default:
+		h.deps.Log.Error().Err(err).Msg(msg)
+		http.Error(w, msg, http.StatusInternalServerError)
+	}
+}
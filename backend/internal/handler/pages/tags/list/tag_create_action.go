@@ -0,0 +1,50 @@
+package list
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// tagForm represents the form data for creating or updating a tag
+type tagForm struct {
+	Name  string `schema:"name"`
+	Color string `schema:"color"`
+}
+
+// HandleTagCreate handles POST /tags/
+func (h *Handlers) HandleTagCreate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleTagCreate")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	tagService := tag.NewService(*tag.NewRepository(h.deps.DB))
+
+	// parse form
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error creating tag", http.StatusBadRequest)
+		return
+	}
+
+	// decode form
+	var createDTO tagForm
+	if err := h.deps.Decoder.Decode(&createDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error creating tag", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := tagService.Create(uuid.MustParse(orgId), createDTO.Name, createDTO.Color); err != nil {
+		h.tagError(w, err, "Error creating tag")
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,36 @@
+package list
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// HandleTagDelete handles DELETE /tags/{id}
+func (h *Handlers) HandleTagDelete(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleTagDelete")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	tagService := tag.NewService(*tag.NewRepository(h.deps.DB))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := tagService.Delete(id, uuid.MustParse(orgId)); err != nil {
+		h.tagError(w, err, "Error deleting tag")
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,51 @@
+package list
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/tag"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// HandleTagUpdate handles PATCH /tags/{id}
+func (h *Handlers) HandleTagUpdate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleTagUpdate")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	tagService := tag.NewService(*tag.NewRepository(h.deps.DB))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	// parse form
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error updating tag", http.StatusBadRequest)
+		return
+	}
+
+	// decode form
+	var updateDTO tagForm
+	if err := h.deps.Decoder.Decode(&updateDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error updating tag", http.StatusBadRequest)
+		return
+	}
+
+	if err := tagService.Update(id, uuid.MustParse(orgId), updateDTO.Name, updateDTO.Color); err != nil {
+		h.tagError(w, err, "Error updating tag")
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "custom:submit-success")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,42 @@
+package detail
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// HandleDeliveryRetry handles POST /webhooks/{id}/deliveries/{deliveryId}/retry
+func (h *Handlers) HandleDeliveryRetry(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleDeliveryRetry")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	webhookService := webhook.NewService(*webhook.NewRepository(h.deps.DB))
+
+	deliveryId, err := uuid.Parse(chi.URLParam(r, "deliveryId"))
+	if err != nil {
+		http.Error(w, "Invalid delivery ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := webhookService.Redeliver(deliveryId, uuid.MustParse(orgId)); err != nil {
+		if errors.Is(err, webhook.ErrDeliveryNotFound) {
+			http.Error(w, "Delivery not found", http.StatusNotFound)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error retrying webhook delivery")
+		http.Error(w, "Error retrying delivery", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,143 @@
+package detail
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	"github.com/devbydaniel/announcable/internal/handler/shared"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/templates"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Handlers holds dependencies for webhook detail handlers
+type Handlers struct {
+	deps *shared.Dependencies
+}
+
+// New creates a new Handlers instance
+func New(deps *shared.Dependencies) *Handlers {
+	return &Handlers{deps: deps}
+}
+
+// eventOption represents an event checkbox of the endpoint form
+type eventOption struct {
+	Event      webhook.Event
+	Subscribed bool
+}
+
+// deliveryItem represents a delivery in the delivery log
+type deliveryItem struct {
+	ID             string
+	Event          string
+	Status         string
+	Attempts       int
+	CreatedAt      time.Time
+	LastAttemptAt  *time.Time
+	NextAttemptAt  *time.Time
+	ResponseStatus *int
+	ResponseBody   string
+	LastError      string
+	Payload        string
+}
+
+// pageData represents the template data for the webhook detail page
+type pageData struct {
+	shared.BaseTemplateData
+	ID         string
+	URL        string
+	Secret     string
+	IsActive   bool
+	Events     []*eventOption
+	Deliveries []*deliveryItem
+}
+
+var pageTmpl = templates.Construct(
+	"webhook-detail",
+	"layouts/root.html",
+	"layouts/appframe.html",
+	"pages/webhook-detail.html",
+)
+
+// ServeWebhookDetailPage handles GET /webhooks/{id}
+func (h *Handlers) ServeWebhookDetailPage(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("ServeWebhookDetailPage")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	webhookService := webhook.NewService(*webhook.NewRepository(h.deps.DB))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	endpoint, err := webhookService.GetEndpoint(id, uuid.MustParse(orgId))
+	if err != nil {
+		if errors.Is(err, webhook.ErrEndpointNotFound) {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error getting webhook endpoint")
+		http.Error(w, "Error getting webhook", http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := webhookService.GetDeliveries(id, uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting webhook deliveries")
+		http.Error(w, "Error getting webhook deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	data := pageData{
+		BaseTemplateData: shared.BaseTemplateData{
+			Title: "Webhook",
+		},
+		ID:       endpoint.ID.String(),
+		URL:      endpoint.URL,
+		Secret:   endpoint.Secret,
+		IsActive: endpoint.IsActive,
+	}
+	for _, ev := range webhook.Events {
+		data.Events = append(data.Events, &eventOption{Event: ev, Subscribed: endpoint.Subscribes(ev)})
+	}
+	for _, d := range deliveries {
+		data.Deliveries = append(data.Deliveries, &deliveryItem{
+			ID:             d.ID.String(),
+			Event:          d.Event.String(),
+			Status:         d.Status.String(),
+			Attempts:       d.Attempts,
+			CreatedAt:      d.CreatedAt,
+			LastAttemptAt:  d.LastAttemptAt,
+			NextAttemptAt:  d.NextAttemptAt,
+			ResponseStatus: d.ResponseStatus,
+			ResponseBody:   d.ResponseBody,
+			LastError:      d.LastError,
+			Payload:        indentJSON(d.Payload),
+		})
+	}
+
+	if err := pageTmpl.ExecuteTemplate(w, "root", data); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rendering page")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+	}
+}
+
+// indentJSON formats a JSON payload for display, falling back to the raw value
+func indentJSON(raw string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(raw), "", "  "); err != nil {
+		return raw
+	}
+	return buf.String()
+}
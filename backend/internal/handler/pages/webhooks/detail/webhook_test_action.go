@@ -0,0 +1,42 @@
+package detail
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// HandleWebhookTest handles POST /webhooks/{id}/test
+func (h *Handlers) HandleWebhookTest(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleWebhookTest")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	webhookService := webhook.NewService(*webhook.NewRepository(h.deps.DB))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := webhookService.SendTest(id, uuid.MustParse(orgId)); err != nil {
+		if errors.Is(err, webhook.ErrEndpointNotFound) {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error sending test webhook")
+		http.Error(w, "Error sending test webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,72 @@
+package detail
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// webhookUpdateForm represents the form data for updating a webhook endpoint
+type webhookUpdateForm struct {
+	URL      string   `schema:"url"`
+	Events   []string `schema:"events"`
+	IsActive bool     `schema:"is_active"`
+}
+
+// HandleWebhookUpdate handles PATCH /webhooks/{id}
+func (h *Handlers) HandleWebhookUpdate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleWebhookUpdate")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	webhookService := webhook.NewService(*webhook.NewRepository(h.deps.DB))
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	// parse form
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error updating webhook", http.StatusBadRequest)
+		return
+	}
+
+	// decode form
+	var updateDTO webhookUpdateForm
+	if err := h.deps.Decoder.Decode(&updateDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error updating webhook", http.StatusBadRequest)
+		return
+	}
+
+	events := make([]webhook.Event, len(updateDTO.Events))
+	for i, ev := range updateDTO.Events {
+		events[i] = webhook.Event(ev)
+	}
+
+	if err := webhookService.UpdateEndpoint(id, uuid.MustParse(orgId), updateDTO.URL, events, updateDTO.IsActive); err != nil {
+		switch {
+		case errors.Is(err, webhook.ErrEndpointNotFound):
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+		case errors.Is(err, webhook.ErrInvalidURL), errors.Is(err, webhook.ErrPrivateTarget), errors.Is(err, webhook.ErrInvalidEvents):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.deps.Log.Error().Err(err).Msg("Error updating webhook endpoint")
+			http.Error(w, "Error updating webhook", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "custom:submit-success")
+	w.WriteHeader(http.StatusOK)
+}
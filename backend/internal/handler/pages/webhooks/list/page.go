@@ -0,0 +1,82 @@
+package list
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	"github.com/devbydaniel/announcable/internal/handler/shared"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/devbydaniel/announcable/templates"
+	"github.com/google/uuid"
+)
+
+// Handlers holds dependencies for webhook list handlers
+type Handlers struct {
+	deps *shared.Dependencies
+}
+
+// New creates a new Handlers instance
+func New(deps *shared.Dependencies) *Handlers {
+	return &Handlers{deps: deps}
+}
+
+// endpointItem represents a webhook endpoint in the list
+type endpointItem struct {
+	ID       string
+	URL      string
+	Events   []webhook.Event
+	IsActive bool
+}
+
+// pageData represents the template data for the webhooks page
+type pageData struct {
+	shared.BaseTemplateData
+	Endpoints []*endpointItem
+	Events    []webhook.Event
+}
+
+var pageTmpl = templates.Construct(
+	"webhooks",
+	"layouts/root.html",
+	"layouts/appframe.html",
+	"pages/webhook-list.html",
+)
+
+// ServeWebhooksPage handles GET /webhooks/
+func (h *Handlers) ServeWebhooksPage(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("ServeWebhooksPage")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	webhookService := webhook.NewService(*webhook.NewRepository(h.deps.DB))
+
+	endpoints, err := webhookService.GetEndpoints(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting webhook endpoints")
+		http.Error(w, "Error getting webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	data := pageData{
+		BaseTemplateData: shared.BaseTemplateData{
+			Title: "Webhooks",
+		},
+		Events: webhook.Events,
+	}
+	for _, endpoint := range endpoints {
+		data.Endpoints = append(data.Endpoints, &endpointItem{
+			ID:       endpoint.ID.String(),
+			URL:      endpoint.URL,
+			Events:   endpoint.EventList(),
+			IsActive: endpoint.IsActive,
+		})
+	}
+
+	if err := pageTmpl.ExecuteTemplate(w, "root", data); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error rendering page")
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,62 @@
+package list
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
+	mw "github.com/devbydaniel/announcable/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// webhookCreateForm represents the form data for creating a webhook endpoint
+type webhookCreateForm struct {
+	URL    string   `schema:"url"`
+	Events []string `schema:"events"`
+}
+
+// HandleWebhookCreate handles POST /webhooks/
+func (h *Handlers) HandleWebhookCreate(w http.ResponseWriter, r *http.Request) {
+	h.deps.Log.Trace().Msg("HandleWebhookCreate")
+	orgId, ok := r.Context().Value(mw.OrgIDKey).(string)
+	if !ok {
+		h.deps.Log.Error().Msg("Organisation ID not found in context")
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	webhookService := webhook.NewService(*webhook.NewRepository(h.deps.DB))
+
+	// parse form
+	if err := r.ParseForm(); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error parsing form")
+		http.Error(w, "Error creating webhook", http.StatusBadRequest)
+		return
+	}
+
+	// decode form
+	var createDTO webhookCreateForm
+	if err := h.deps.Decoder.Decode(&createDTO, r.PostForm); err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error decoding form")
+		http.Error(w, "Error creating webhook", http.StatusBadRequest)
+		return
+	}
+
+	events := make([]webhook.Event, len(createDTO.Events))
+	for i, ev := range createDTO.Events {
+		events[i] = webhook.Event(ev)
+	}
+
+	endpoint, err := webhookService.CreateEndpoint(uuid.MustParse(orgId), createDTO.URL, events)
+	if err != nil {
+		if errors.Is(err, webhook.ErrInvalidURL) || errors.Is(err, webhook.ErrPrivateTarget) || errors.Is(err, webhook.ErrInvalidEvents) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error creating webhook endpoint")
+		http.Error(w, "Error creating webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("HX-Redirect", "/webhooks/"+endpoint.ID.String())
+	w.WriteHeader(http.StatusOK)
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
+	apiWidget "github.com/devbydaniel/announcable/internal/handler/api/widget"
 	mw "github.com/devbydaniel/announcable/internal/middleware"
 	"github.com/go-playground/validator"
 	"github.com/google/uuid"
@@ -11,23 +14,36 @@ import (
 
 // configUpdateForm represents the widget config update form data
 type configUpdateForm struct {
-	Title                   string `schema:"title" validate:"required"`
-	Description             string `schema:"description" validate:"required"`
-	WidgetType              string `schema:"widget_type" validate:"required"`
-	WidgetBorderRadius      int    `schema:"widget_border_radius" validate:"gte=0"`
-	WidgetBorderColor       string `schema:"widget_border_color" validate:"required"`
-	WidgetBorderWidth       int    `schema:"widget_border_width" validate:"gte=0"`
-	WidgetBgColor           string `schema:"widget_background_color" validate:"required"`
-	WidgetTextColor         string `schema:"widget_text_color" validate:"required"`
-	ReleaseNoteBorderRadius int    `schema:"release_note_border_radius" validate:"gte=0"`
-	ReleaseNoteBorderColor  string `schema:"release_note_border_color" validate:"required"`
-	ReleaseNoteBorderWidth  int    `schema:"release_note_border_width" validate:"gte=0"`
-	ReleaseNoteBgColor      string `schema:"release_note_background_color" validate:"required"`
-	ReleaseNoteTextColor    string `schema:"release_note_text_color" validate:"required"`
-	ReleaseNoteCtaText      string `schema:"release_note_cta_text" validate:"required"`
-	EnableLikes             string `schema:"enable_likes"`
-	LikeButtonText          string `schema:"like_button_text"`
-	UnlikeButtonText        string `schema:"unlike_button_text"`
+	Title                   string            `schema:"title" validate:"required"`
+	Description             string            `schema:"description" validate:"required"`
+	WidgetType              string            `schema:"widget_type" validate:"required"`
+	WidgetBorderRadius      int               `schema:"widget_border_radius" validate:"gte=0"`
+	WidgetBorderColor       string            `schema:"widget_border_color" validate:"required"`
+	WidgetBorderWidth       int               `schema:"widget_border_width" validate:"gte=0"`
+	WidgetBgColor           string            `schema:"widget_background_color" validate:"required"`
+	WidgetTextColor         string            `schema:"widget_text_color" validate:"required"`
+	ReleaseNoteBorderRadius int               `schema:"release_note_border_radius" validate:"gte=0"`
+	ReleaseNoteBorderColor  string            `schema:"release_note_border_color" validate:"required"`
+	ReleaseNoteBorderWidth  int               `schema:"release_note_border_width" validate:"gte=0"`
+	ReleaseNoteBgColor      string            `schema:"release_note_background_color" validate:"required"`
+	ReleaseNoteTextColor    string            `schema:"release_note_text_color" validate:"required"`
+	ReleaseNoteCtaText      string            `schema:"release_note_cta_text" validate:"required"`
+	EnableLikes             string            `schema:"enable_likes"`
+	LikeButtonText          string            `schema:"like_button_text"`
+	UnlikeButtonText        string            `schema:"unlike_button_text"`
+	Reactions               string            `schema:"reactions"`
+	EnableFeedback          string            `schema:"enable_feedback"`
+	AllowedDomains          string            `schema:"allowed_domains"`
+	Translations            []translationForm `schema:"translations"`
+}
+
+type translationForm struct {
+	Locale             string `schema:"locale"`
+	Title              string `schema:"title"`
+	Description        string `schema:"description"`
+	ReleaseNoteCtaText string `schema:"release_note_cta_text"`
+	LikeButtonText     string `schema:"like_button_text"`
+	UnlikeButtonText   string `schema:"unlike_button_text"`
 }
 
 // HandleConfigUpdate handles PATCH /widget-config/
@@ -90,6 +106,18 @@ func (h *Handlers) HandleConfigUpdate(w http.ResponseWriter, r *http.Request) {
 		updateDTO.UnlikeButtonText = ""
 	}
 
+	reactions, err := widgetconfigs.ParseReactions(updateDTO.Reactions)
+	if err != nil {
+		http.Error(w, "Reactions must be up to 8 emoji separated by spaces", http.StatusBadRequest)
+		return
+	}
+
+	allowedDomains, err := widgetconfigs.ParseAllowedDomains(updateDTO.AllowedDomains)
+	if err != nil {
+		http.Error(w, "Allowed domains must be domain names like example.com or *.example.com", http.StatusBadRequest)
+		return
+	}
+
 	widgetConfig := &widgetconfigs.WidgetConfig{
 		OrganisationID:          uuid.MustParse(orgId),
 		Title:                   updateDTO.Title,
@@ -109,6 +137,9 @@ func (h *Handlers) HandleConfigUpdate(w http.ResponseWriter, r *http.Request) {
 		EnableLikes:             isLikesEnabled,
 		LikeButtonText:          updateDTO.LikeButtonText,
 		UnlikeButtonText:        updateDTO.UnlikeButtonText,
+		Reactions:               strings.Join(reactions, " "),
+		EnableFeedback:          updateDTO.EnableFeedback == "on",
+		AllowedDomains:          strings.Join(allowedDomains, "\n"),
 	}
 	h.deps.Log.Debug().Interface("widget config", widgetConfig).Msg("Widget config to update")
 
@@ -117,6 +148,28 @@ func (h *Handlers) HandleConfigUpdate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error updating widget config", http.StatusInternalServerError)
 		return
 	}
+	apiWidget.InvalidateAllowedDomainsCache()
+
+	translations := make([]*widgetconfigs.WidgetConfigTranslation, len(updateDTO.Translations))
+	for i, t := range updateDTO.Translations {
+		translations[i] = &widgetconfigs.WidgetConfigTranslation{
+			Locale:             t.Locale,
+			Title:              t.Title,
+			Description:        t.Description,
+			ReleaseNoteCtaText: t.ReleaseNoteCtaText,
+			LikeButtonText:     t.LikeButtonText,
+			UnlikeButtonText:   t.UnlikeButtonText,
+		}
+	}
+	if err := widgetService.UpdateTranslations(uuid.MustParse(orgId), translations); err != nil {
+		if errors.Is(err, widgetconfigs.ErrInvalidLocale) {
+			http.Error(w, "Unsupported translation language", http.StatusBadRequest)
+			return
+		}
+		h.deps.Log.Error().Err(err).Msg("Error updating widget config translations")
+		http.Error(w, "Error updating widget config", http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("HX-Trigger", "custom:submit-success")
 	w.WriteHeader(http.StatusOK)
@@ -5,8 +5,10 @@ import (
 	"html"
 	"net/http"
 
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
 	widgetconfigs "github.com/devbydaniel/announcable/internal/domain/widget-configs"
 	"github.com/devbydaniel/announcable/internal/handler/shared"
+	"github.com/devbydaniel/announcable/internal/locale"
 	mw "github.com/devbydaniel/announcable/internal/middleware"
 	"github.com/devbydaniel/announcable/templates"
 	"github.com/google/uuid"
@@ -29,6 +31,15 @@ type pageData struct {
 	SafeTitle              string
 	SafeDescription        string
 	SafeReleaseNoteCtaText string
+	// DefaultLanguage is the name of the language of the texts above
+	DefaultLanguage string
+	Translations    []*translationInput
+}
+
+// translationInput holds the widget texts in one of the other languages
+type translationInput struct {
+	*widgetconfigs.WidgetConfigTranslation
+	Name string
 }
 
 var pageTmpl = templates.Construct(
@@ -69,6 +80,36 @@ func (h *Handlers) ServeWidgetConfigPage(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	organisationService := organisation.NewService(*organisation.NewRepository(h.deps.DB))
+	org, err := organisationService.GetOrg(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting organisation")
+		http.Error(w, "Error getting organisation", http.StatusInternalServerError)
+		return
+	}
+	existing, err := widgetService.GetTranslations(uuid.MustParse(orgId))
+	if err != nil {
+		h.deps.Log.Error().Err(err).Msg("Error getting widget config translations")
+		http.Error(w, "Error getting widget config", http.StatusInternalServerError)
+		return
+	}
+	var translations []*translationInput
+	for _, l := range locale.Languages {
+		if l.Code == org.DefaultLocale {
+			continue
+		}
+		input := &translationInput{
+			WidgetConfigTranslation: &widgetconfigs.WidgetConfigTranslation{Locale: l.Code},
+			Name:                    l.Name,
+		}
+		for _, t := range existing {
+			if t.Locale == l.Code {
+				input.WidgetConfigTranslation = t
+			}
+		}
+		translations = append(translations, input)
+	}
+
 	data := pageData{
 		BaseTemplateData: shared.BaseTemplateData{
 			Title: "Widget Config",
@@ -77,6 +118,8 @@ func (h *Handlers) ServeWidgetConfigPage(w http.ResponseWriter, r *http.Request)
 		SafeTitle:              html.EscapeString(cfg.Title),
 		SafeDescription:        html.EscapeString(cfg.Description),
 		SafeReleaseNoteCtaText: html.EscapeString(cfg.ReleaseNoteCtaText),
+		DefaultLanguage:        locale.Name(org.DefaultLocale),
+		Translations:           translations,
 	}
 
 	if err := pageTmpl.ExecuteTemplate(w, "root", data); err != nil {
@@ -2,15 +2,18 @@ package imgUtil
 
 import (
 	"bytes"
+	"cmp"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"image"
-	_ "image/gif"
+	"image/gif"
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/devbydaniel/announcable/internal/logger"
@@ -60,6 +63,33 @@ func (f SupportedFormat) ToEncodedFormat() EncodedFormat {
 type ImgProcessConfig struct {
 	MaxWidth uint
 	Quality  int
+	// VariantWidths are the widths of additional copies for responsive images. Widths at or above
+	// the width of the upload are skipped, as is the width of the main image. GIFs get no variants.
+	VariantWidths []uint
+	// Placeholder enables a tiny, blurry version of the image to show while it loads
+	Placeholder bool
+}
+
+// placeholderWidth is the width of placeholders, which browsers scale up with a blur-like smoothing
+const placeholderWidth = 16
+
+// Variant is a resized copy of a processed image
+type Variant struct {
+	Width uint
+	Data  *io.Reader
+}
+
+// ProcessedImage is an upload ready to be stored. Metadata like EXIF and GPS data is not carried over.
+type ProcessedImage struct {
+	Data   *io.Reader
+	Format EncodedFormat
+	// Width and Height of the main image
+	Width  uint
+	Height uint
+	// Variants in ascending order of width
+	Variants []Variant
+	// Placeholder is a data URI of a WebP, if enabled in the config
+	Placeholder string
 }
 
 func VerifyImageType(img multipart.File) bool {
@@ -144,44 +174,130 @@ func Encode(img image.Image, format SupportedFormat) (*io.Reader, error) {
 
 func DecodeProcessEncode(img io.Reader, config *ImgProcessConfig) (*io.Reader, EncodedFormat, error) {
 	log.Trace().Msg("DecodeProcessEncode")
+	processed, err := Process(img, config)
+	if err != nil {
+		return nil, "", err
+	}
+	return processed.Data, processed.Format, nil
+}
+
+// Process decodes an upload, turns it upright according to its EXIF orientation, resizes it to the
+// configured width and encodes it along with its variants and placeholder.
+// GIFs keep their frames and size to preserve animation, but are re-encoded to drop their metadata.
+func Process(img io.Reader, config *ImgProcessConfig) (*ProcessedImage, error) {
+	log.Trace().Msg("Process")
 
-	// Read all data into buffer first (needed to detect format and potentially pass through)
+	// Read all data into buffer first (needed to detect format and read the EXIF orientation)
 	imgData, err := io.ReadAll(img)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read image data: %w", err)
+		return nil, fmt.Errorf("failed to read image data: %w", err)
 	}
 
-	// Decode to get format (and image for non-GIF processing)
+	// Decode to get format (and the first frame of GIFs)
 	imgDecoded, format, err := Decode(bytes.NewReader(imgData))
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	// check if format is supported
 	if !isFormatSupported(format) {
-		return nil, "", fmt.Errorf("unsupported image format: %s", format)
+		return nil, fmt.Errorf("unsupported image format: %s", format)
 	}
 
-	// For GIFs, pass through original data to preserve animation
 	if SupportedFormat(format) == GIF {
-		log.Debug().Msg("GIF detected, passing through original data to preserve animation")
-		reader := io.Reader(bytes.NewReader(imgData))
-		return &reader, GIFEncoded, nil
+		return processGIF(imgData, imgDecoded, config)
+	}
+
+	if SupportedFormat(format) == JPEG {
+		imgDecoded = applyOrientation(imgDecoded, exifOrientation(imgData))
 	}
 
 	// Process the image (resize if needed)
 	imgProcessed, err := ProcessImage(imgDecoded, config.MaxWidth, config.Quality)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	// Encode the image
 	imgEncoded, err := Encode(imgProcessed, SupportedFormat(format))
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+	processed := &ProcessedImage{
+		Data:   imgEncoded,
+		Format: SupportedFormat(format).ToEncodedFormat(),
+		Width:  uint(imgProcessed.Bounds().Dx()),
+		Height: uint(imgProcessed.Bounds().Dy()),
 	}
 
-	return imgEncoded, SupportedFormat(format).ToEncodedFormat(), nil
+	originalWidth := uint(imgDecoded.Bounds().Dx())
+	for _, width := range config.VariantWidths {
+		if width >= originalWidth || width == processed.Width {
+			continue
+		}
+		log.Debug().Uint("width", width).Msg("Creating variant")
+		resized := resize.Resize(width, 0, imgDecoded, resize.Lanczos3)
+		data, err := Encode(resized, SupportedFormat(format))
+		if err != nil {
+			return nil, err
+		}
+		processed.Variants = append(processed.Variants, Variant{Width: width, Data: data})
+	}
+	slices.SortFunc(processed.Variants, func(a, b Variant) int {
+		return cmp.Compare(a.Width, b.Width)
+	})
+
+	if config.Placeholder {
+		processed.Placeholder, err = Placeholder(imgDecoded)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return processed, nil
+}
+
+// processGIF re-encodes all frames of a GIF, which keeps the animation but drops comments and
+// application data other than the loop count
+func processGIF(imgData []byte, firstFrame image.Image, config *ImgProcessConfig) (*ProcessedImage, error) {
+	log.Trace().Msg("processGIF")
+	g, err := gif.DecodeAll(bytes.NewReader(imgData))
+	if err != nil {
+		return nil, err
+	}
+	imgBuf := new(bytes.Buffer)
+	if err := gif.EncodeAll(imgBuf, g); err != nil {
+		return nil, err
+	}
+	reader := io.Reader(imgBuf)
+	processed := &ProcessedImage{
+		Data:   &reader,
+		Format: GIFEncoded,
+		Width:  uint(g.Config.Width),
+		Height: uint(g.Config.Height),
+	}
+	if config.Placeholder {
+		processed.Placeholder, err = Placeholder(firstFrame)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return processed, nil
+}
+
+// Placeholder encodes a tiny version of an image as a WebP data URI of a few hundred bytes,
+// small enough to be sent along with the image URL
+func Placeholder(img image.Image) (string, error) {
+	log.Trace().Msg("Placeholder")
+	small := resize.Resize(placeholderWidth, 0, img, resize.Bilinear)
+	options, err := encoder.NewLossyEncoderOptions(encoder.PresetPicture, 30)
+	if err != nil {
+		return "", err
+	}
+	imgBuf := new(bytes.Buffer)
+	if err := webp.Encode(imgBuf, small, options); err != nil {
+		return "", err
+	}
+	return "data:image/webp;base64," + base64.StdEncoding.EncodeToString(imgBuf.Bytes()), nil
 }
 
 func isFormatSupported(format string) bool {
@@ -0,0 +1,173 @@
+package imgUtil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// jpegWithOrientation encodes a JPEG with an APP1 segment carrying the given EXIF orientation
+func jpegWithOrientation(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// TIFF header with an IFD0 of one entry: orientation (SHORT, count 1)
+	tiff := []byte("MM\x00\x2a")
+	tiff = binary.BigEndian.AppendUint32(tiff, 8)
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	data := buf.Bytes()
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	out = binary.BigEndian.AppendUint16(out, uint16(len(segment)+2))
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func solidImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	return img
+}
+
+func TestExifOrientation(t *testing.T) {
+	img := solidImage(4, 2)
+	for _, orientation := range []uint16{1, 3, 6, 8} {
+		if got := exifOrientation(jpegWithOrientation(t, img, orientation)); got != int(orientation) {
+			t.Errorf("exifOrientation() = %d, want %d", got, orientation)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := exifOrientation(buf.Bytes()); got != 1 {
+		t.Errorf("exifOrientation() without EXIF = %d, want 1", got)
+	}
+	if got := exifOrientation([]byte("not a jpeg")); got != 1 {
+		t.Errorf("exifOrientation() of garbage = %d, want 1", got)
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// 2x1 image with a red left and a blue right pixel
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	img.Set(0, 0, red)
+	img.Set(1, 0, blue)
+
+	tests := []struct {
+		orientation int
+		width       int
+		height      int
+		red         image.Point
+	}{
+		{orientation: 1, width: 2, height: 1, red: image.Pt(0, 0)},
+		{orientation: 2, width: 2, height: 1, red: image.Pt(1, 0)},
+		{orientation: 3, width: 2, height: 1, red: image.Pt(1, 0)},
+		{orientation: 6, width: 1, height: 2, red: image.Pt(0, 0)},
+		{orientation: 8, width: 1, height: 2, red: image.Pt(0, 1)},
+	}
+	for _, tt := range tests {
+		got := applyOrientation(img, tt.orientation)
+		b := got.Bounds()
+		if b.Dx() != tt.width || b.Dy() != tt.height {
+			t.Errorf("orientation %d: size %dx%d, want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.width, tt.height)
+			continue
+		}
+		if r, _, _, _ := got.At(tt.red.X, tt.red.Y).RGBA(); r != 0xffff {
+			t.Errorf("orientation %d: red pixel not at %v", tt.orientation, tt.red)
+		}
+	}
+}
+
+func TestProcessVariants(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, solidImage(1200, 600)); err != nil {
+		t.Fatal(err)
+	}
+	config := &ImgProcessConfig{MaxWidth: 1000, VariantWidths: []uint{2000, 640, 320, 1000}, Placeholder: true}
+	processed, err := Process(&buf, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processed.Format != WebP || processed.Width != 1000 || processed.Height != 500 {
+		t.Errorf("got %s %dx%d, want webp 1000x500", processed.Format, processed.Width, processed.Height)
+	}
+	var widths []uint
+	for _, v := range processed.Variants {
+		widths = append(widths, v.Width)
+	}
+	if len(widths) != 2 || widths[0] != 320 || widths[1] != 640 {
+		t.Errorf("variant widths = %v, want [320 640]", widths)
+	}
+	if !strings.HasPrefix(processed.Placeholder, "data:image/webp;base64,") {
+		t.Errorf("placeholder = %q, want a WebP data URI", processed.Placeholder)
+	}
+}
+
+func TestProcessRotatesJPEG(t *testing.T) {
+	data := jpegWithOrientation(t, solidImage(40, 20), 6)
+	processed, err := Process(bytes.NewReader(data), &ImgProcessConfig{MaxWidth: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processed.Width != 20 || processed.Height != 40 {
+		t.Errorf("got %dx%d, want 20x40", processed.Width, processed.Height)
+	}
+	if processed.Placeholder != "" {
+		t.Errorf("placeholder = %q, want none", processed.Placeholder)
+	}
+}
+
+func TestProcessKeepsGIFAnimation(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{LoopCount: 0}
+	for i := 0; i < 3; i++ {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, 30, 10), palette))
+		anim.Delay = append(anim.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+
+	processed, err := Process(&buf, &ImgProcessConfig{MaxWidth: 20, VariantWidths: []uint{10}, Placeholder: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processed.Format != GIFEncoded || processed.Width != 30 || len(processed.Variants) != 0 {
+		t.Errorf("got %s, width %d, %d variants, want an unresized gif without variants", processed.Format, processed.Width, len(processed.Variants))
+	}
+	decoded, err := gif.DecodeAll(*processed.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Image) != 3 {
+		t.Errorf("got %d frames, want 3", len(decoded.Image))
+	}
+	if processed.Placeholder == "" {
+		t.Error("want a placeholder")
+	}
+}
@@ -0,0 +1,116 @@
+// Package locale lists the languages release notes can be translated into and picks the
+// language a visitor is served in.
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a supported content language
+type Language struct {
+	// Code is the ISO 639-1 code, e.g. "de"
+	Code string
+	// Name is the native name of the language, e.g. "Deutsch"
+	Name string
+}
+
+// Default is the language of organisations that have not chosen one
+const Default = "en"
+
+// Languages are the supported content languages, in the order they are offered in the UI
+var Languages = []Language{
+	{Code: "en", Name: "English"},
+	{Code: "de", Name: "Deutsch"},
+	{Code: "fr", Name: "Français"},
+	{Code: "es", Name: "Español"},
+	{Code: "it", Name: "Italiano"},
+	{Code: "nl", Name: "Nederlands"},
+	{Code: "pt", Name: "Português"},
+	{Code: "pl", Name: "Polski"},
+}
+
+// IsSupported reports whether code is one of Languages
+func IsSupported(code string) bool {
+	for _, l := range Languages {
+		if l.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the native name of a supported language, or the code itself
+func Name(code string) string {
+	for _, l := range Languages {
+		if l.Code == code {
+			return l.Name
+		}
+	}
+	return code
+}
+
+// Negotiate picks the language to serve. An explicitly requested language wins, then the
+// preferences of an Accept-Language header in order of their quality, then fallback.
+// Regional variants match their base language ("de-CH" is served "de").
+func Negotiate(requested, acceptLanguage, fallback string) string {
+	if code := normalize(requested); IsSupported(code) {
+		return code
+	}
+	for _, code := range parseAcceptLanguage(acceptLanguage) {
+		if IsSupported(code) {
+			return code
+		}
+	}
+	if IsSupported(fallback) {
+		return fallback
+	}
+	return Default
+}
+
+// normalize reduces a language tag to its lowercase primary subtag
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// parseAcceptLanguage returns the languages of an Accept-Language header, most preferred
+// first. Wildcards and languages with a quality of 0 are left out.
+func parseAcceptLanguage(header string) []string {
+	type preference struct {
+		code    string
+		quality float64
+	}
+	var prefs []preference
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		code := normalize(tag)
+		if code == "" || code == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		prefs = append(prefs, preference{code: code, quality: quality})
+	}
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].quality > prefs[j].quality
+	})
+	codes := make([]string, len(prefs))
+	for i, p := range prefs {
+		codes[i] = p.code
+	}
+	return codes
+}
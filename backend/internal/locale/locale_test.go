@@ -0,0 +1,33 @@
+package locale
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		requested      string
+		acceptLanguage string
+		fallback       string
+		expected       string
+	}{
+		{name: "requested language", requested: "fr", acceptLanguage: "de", fallback: "en", expected: "fr"},
+		{name: "requested regional variant", requested: "de-AT", fallback: "en", expected: "de"},
+		{name: "requested uppercase with underscore", requested: "PT_br", fallback: "en", expected: "pt"},
+		{name: "unsupported request uses header", requested: "xx", acceptLanguage: "nl", fallback: "en", expected: "nl"},
+		{name: "header order", acceptLanguage: "fr-CH, fr;q=0.9, en;q=0.8", fallback: "de", expected: "fr"},
+		{name: "header quality", acceptLanguage: "en;q=0.5, de;q=0.9", fallback: "fr", expected: "de"},
+		{name: "header skips unsupported", acceptLanguage: "ja, zh;q=0.9, it;q=0.1", fallback: "en", expected: "it"},
+		{name: "header quality zero", acceptLanguage: "de;q=0, *;q=0.5", fallback: "fr", expected: "fr"},
+		{name: "malformed quality", acceptLanguage: "de;q=abc, es;q=0.2", fallback: "en", expected: "es"},
+		{name: "fallback", fallback: "de", expected: "de"},
+		{name: "unsupported fallback", fallback: "xx", expected: Default},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.requested, tt.acceptLanguage, tt.fallback); got != tt.expected {
+				t.Errorf("Negotiate(%q, %q, %q) = %q, want %q", tt.requested, tt.acceptLanguage, tt.fallback, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Package markdown renders the Markdown of release notes to sanitized HTML. It supports
+// CommonMark with the GitHub extensions for tables, task lists, strikethrough and autolinks.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// renderer converts Markdown to HTML. Raw HTML in the source is dropped, and single line
+// breaks are kept as the descriptions were plain text before.
+var renderer = goldmark.New(
+	// GFM, with table alignment as attributes as styles are stripped
+	goldmark.WithExtensions(
+		extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		extension.Strikethrough,
+		extension.Linkify,
+		extension.TaskList,
+	),
+	goldmark.WithRendererOptions(goldmarkhtml.WithHardWraps()),
+)
+
+// policy allows the elements Markdown produces and strips everything else, such as scripts,
+// event handlers, styles and javascript: URLs
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// task list items render a disabled checkbox
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	// table columns can be aligned
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	// fenced code blocks name their language
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// ToHTML renders Markdown to sanitized HTML that is safe to embed in a page
+func ToHTML(src string) string {
+	if src == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(src), &buf); err != nil {
+		// rendering to a buffer doesn't fail, fall back to the escaped source regardless
+		return "<p>" + html.EscapeString(src) + "</p>"
+	}
+	return policy.SanitizeReader(&buf).String()
+}
@@ -0,0 +1,157 @@
+package markdown
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "empty", input: "", expected: ""},
+		{name: "emphasis", input: "**bold** and _italic_", expected: "<p><strong>bold</strong> and <em>italic</em></p>\n"},
+		{name: "line breaks are kept", input: "first\nsecond", expected: "<p>first<br>\nsecond</p>\n"},
+		{name: "paragraphs", input: "first\n\nsecond", expected: "<p>first</p>\n<p>second</p>\n"},
+		{name: "list", input: "- one\n- two", expected: "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n"},
+		{
+			name:     "task list",
+			input:    "- [x] done\n- [ ] open",
+			expected: "<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> done</li>\n<li><input disabled=\"\" type=\"checkbox\"> open</li>\n</ul>\n",
+		},
+		{
+			name:     "table with alignment",
+			input:    "| a | b |\n|:--|--:|\n| 1 | 2 |",
+			expected: "<table>\n<thead>\n<tr>\n<th align=\"left\">a</th>\n<th align=\"right\">b</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td align=\"left\">1</td>\n<td align=\"right\">2</td>\n</tr>\n</tbody>\n</table>\n",
+		},
+		{name: "inline code is escaped", input: "`<b>`", expected: "<p><code>&lt;b&gt;</code></p>\n"},
+		{name: "code block", input: "```go\nx := 1\n```", expected: "<pre><code class=\"language-go\">x := 1\n</code></pre>\n"},
+		{name: "strikethrough", input: "~~old~~", expected: "<p><del>old</del></p>\n"},
+		{
+			name:     "link",
+			input:    "[docs](https://example.com/docs)",
+			expected: "<p><a href=\"https://example.com/docs\" rel=\"nofollow noopener\" target=\"_blank\">docs</a></p>\n",
+		},
+		{
+			name:     "autolink",
+			input:    "see https://example.com",
+			expected: "<p>see <a href=\"https://example.com\" rel=\"nofollow noopener\" target=\"_blank\">https://example.com</a></p>\n",
+		},
+		{name: "relative link", input: "[pricing](/pricing)", expected: "<p><a href=\"/pricing\" rel=\"nofollow\">pricing</a></p>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToHTML(tt.input); got != tt.expected {
+				t.Errorf("ToHTML(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToHTMLSanitizes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "script tag", input: "<script>alert(1)</script>"},
+		{name: "script tag in block", input: "<div>\n<script>alert(1)</script>\n</div>"},
+		{name: "inline event handler", input: "<img src=x onerror=alert(1)>"},
+		{name: "event handler on link", input: `<a href="https://example.com" onclick="alert(1)">x</a>`},
+		{name: "javascript link", input: "[x](javascript:alert(1))"},
+		{name: "mixed case javascript link", input: "[x](JaVaScRiPt:alert(1))"},
+		{name: "entity encoded javascript link", input: "[x](&#106;avascript:alert(1))"},
+		{name: "whitespace in javascript link", input: "[x](java\tscript:alert(1))"},
+		{name: "vbscript link", input: "[x](vbscript:msgbox(1))"},
+		{name: "data link", input: "[x](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)"},
+		{name: "javascript autolink", input: "<javascript:alert(1)>"},
+		{name: "javascript reference link", input: "[x][1]\n\n[1]: javascript:alert(1)"},
+		{name: "javascript image", input: "![x](javascript:alert(1))"},
+		{name: "image title breaking out", input: `![x](https://example.com/a.png "a\" onerror=\"alert(1)")`},
+		{name: "link title breaking out", input: `[x](https://example.com "a\" onmouseover=\"alert(1)")`},
+		{name: "iframe", input: `<iframe src="https://example.com"></iframe>`},
+		{name: "iframe srcdoc", input: `<iframe srcdoc="<script>alert(1)</script>"></iframe>`},
+		{name: "object and embed", input: `<object data="x.swf"></object><embed src="x.swf">`},
+		{name: "style tag", input: "<style>body{display:none}</style>"},
+		{name: "style attribute", input: `<p style="position:fixed">x</p>`},
+		{name: "form", input: `<form action="https://example.com"><button formaction="javascript:alert(1)">x</button></form>`},
+		{name: "svg", input: `<svg onload="alert(1)"><circle r="1"/></svg>`},
+		{name: "math", input: `<math><mtext><script>alert(1)</script></mtext></math>`},
+		{name: "meta refresh", input: `<meta http-equiv="refresh" content="0;url=https://example.com">`},
+		{name: "base and link", input: `<base href="https://example.com"><link rel="stylesheet" href="https://example.com/x.css">`},
+		{name: "unclosed tag", input: "<img src=x onerror=alert(1)//"},
+		{name: "html comment", input: "<!--><script>alert(1)</script>-->"},
+		{name: "code fence language", input: "```\" onmouseover=\"alert(1)\nx\n```"},
+		{name: "table cell", input: "| a |\n|---|\n| <img src=x onerror=alert(1)> |"},
+		{name: "task list", input: "- [ ] <script>alert(1)</script>"},
+		{name: "nested in emphasis", input: "**<script>alert(1)</script>**"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToHTML(tt.input)
+			if err := checkSafe(got); err != nil {
+				t.Errorf("ToHTML(%q) = %q: %v", tt.input, got, err)
+			}
+		})
+	}
+}
+
+// safeElements are the elements Markdown renders to
+var safeElements = map[string]bool{
+	"p": true, "br": true, "strong": true, "em": true, "del": true, "code": true, "pre": true,
+	"blockquote": true, "ul": true, "ol": true, "li": true, "input": true, "a": true, "img": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "hr": true,
+	"table": true, "thead": true, "tbody": true, "tr": true, "th": true, "td": true,
+}
+
+// checkSafe parses fragment and returns an error for elements other than safeElements, event
+// handler and style attributes, and links or sources with a scheme other than http(s) or mailto
+func checkSafe(fragment string) error {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return err
+	}
+	var check func(n *html.Node) error
+	check = func(n *html.Node) error {
+		if n.Type == html.ElementNode {
+			if !safeElements[n.Data] {
+				return fmt.Errorf("unsafe element <%s>", n.Data)
+			}
+			for _, a := range n.Attr {
+				key := strings.ToLower(a.Key)
+				if strings.HasPrefix(key, "on") || key == "style" || key == "srcdoc" || key == "formaction" {
+					return fmt.Errorf("unsafe attribute %s on <%s>", a.Key, n.Data)
+				}
+				if key == "href" || key == "src" {
+					u, err := url.Parse(strings.TrimSpace(a.Val))
+					if err != nil {
+						return fmt.Errorf("invalid URL %q", a.Val)
+					}
+					if scheme := strings.ToLower(u.Scheme); scheme != "" && scheme != "http" && scheme != "https" && scheme != "mailto" {
+						return fmt.Errorf("unsafe URL %q", a.Val)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if err := check(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, n := range nodes {
+		if err := check(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -9,7 +9,9 @@ import (
 type Cacher interface {
 	Get(key string) (interface{}, bool)
 	Set(key string, value interface{}, expiresIn time.Duration)
+	Delete(key string)
 	Flush()
+	ItemCount() int
 }
 
 func New(defaultExpiration, cleanupInterval time.Duration) Cacher {
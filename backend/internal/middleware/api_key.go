@@ -0,0 +1,51 @@
+package mw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	apikey "github.com/devbydaniel/announcable/internal/domain/api-key"
+)
+
+// AuthenticateApiKey authenticates requests with an organisation API key
+// sent as "Authorization: Bearer <key>"
+func (h *Handler) AuthenticateApiKey(next http.Handler) http.Handler {
+	apiKeyService := apikey.NewService(*apikey.NewRepository(h.DB))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.log.Trace().Msg("mw AuthenticateApiKey")
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			http.Error(w, "Missing API key", http.StatusUnauthorized)
+			return
+		}
+
+		key, err := apiKeyService.Authenticate(strings.TrimSpace(token))
+		if err != nil {
+			if errors.Is(err, apikey.ErrInvalidKey) {
+				h.log.Warn().Msg("Invalid API key")
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+				http.Error(w, "Invalid API key", http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, "Error validating API key", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := r.Context()
+		ctx = context.WithValue(ctx, ApiKeyIDKey, key.ID.String())
+		ctx = context.WithValue(ctx, UserIDKey, key.CreatedBy.String())
+		ctx = context.WithValue(ctx, OrgIDKey, key.OrganisationID.String())
+
+		r = r.WithContext(ctx)
+		h.log.Trace().
+			Str("apiKeyId", key.ID.String()).
+			Str("orgId", key.OrganisationID.String()).
+			Msg("Authenticated with API key")
+
+		next.ServeHTTP(w, r)
+	})
+}
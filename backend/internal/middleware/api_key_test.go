@@ -0,0 +1,80 @@
+package mw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apikey "github.com/devbydaniel/announcable/internal/domain/api-key"
+	"github.com/devbydaniel/announcable/internal/domain/organisation"
+	"github.com/devbydaniel/announcable/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateApiKey(t *testing.T) {
+	cleanup := testutil.SetupTest()
+	defer cleanup()
+	testDB := testutil.SetupTestDB(t)
+	defer testDB.Cleanup(t)
+	require.NoError(t, testDB.DB.Client.AutoMigrate(&organisation.Organisation{}, &apikey.ApiKey{}))
+
+	org, err := organisation.New("Test Org")
+	require.NoError(t, err)
+	require.NoError(t, testDB.DB.Client.Create(org).Error)
+	apiKeyService := apikey.NewService(*apikey.NewRepository(testDB.DB))
+	userId := uuid.New()
+	plainKey, key, err := apiKeyService.Create(org.ID, userId, "CI")
+	require.NoError(t, err)
+	revokedKey, revoked, err := apiKeyService.Create(org.ID, userId, "Old")
+	require.NoError(t, err)
+	require.NoError(t, apiKeyService.Revoke(revoked.ID, org.ID))
+
+	var gotOrgId, gotUserId, gotKeyId interface{}
+	handler := NewHandler(testDB.DB).AuthenticateApiKey(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotOrgId = r.Context().Value(OrgIDKey)
+		gotUserId = r.Context().Value(UserIDKey)
+		gotKeyId = r.Context().Value(ApiKeyIDKey)
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/release-notes", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{name: "missing header"},
+		{name: "empty bearer", authorization: "Bearer "},
+		{name: "other scheme", authorization: "Basic " + plainKey},
+		{name: "key without scheme", authorization: plainKey},
+		{name: "malformed key", authorization: "Bearer not-a-key"},
+		{name: "unknown key", authorization: "Bearer ann_" + uuid.NewString()},
+		{name: "revoked key", authorization: "Bearer " + revokedKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOrgId = nil
+			rr := serve(tt.authorization)
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+			assert.Contains(t, rr.Header().Get("WWW-Authenticate"), "Bearer")
+			assert.Nil(t, gotOrgId, "next handler was called")
+		})
+	}
+
+	t.Run("valid key", func(t *testing.T) {
+		rr := serve("Bearer " + plainKey)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, org.ID.String(), gotOrgId)
+		assert.Equal(t, userId.String(), gotUserId)
+		assert.Equal(t, key.ID.String(), gotKeyId)
+	})
+}
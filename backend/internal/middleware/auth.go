@@ -22,6 +22,7 @@ const (
 	OrgIDKey         contextKey = "orgId"
 	OrgNameKey       contextKey = "orgName"
 	EmailVerifiedKey contextKey = "emailVerified"
+	ApiKeyIDKey      contextKey = "apiKeyId"
 )
 
 func (h *Handler) Authenticate(next http.Handler) http.Handler {
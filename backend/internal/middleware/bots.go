@@ -0,0 +1,21 @@
+package mw
+
+import (
+	"net/http"
+
+	"github.com/devbydaniel/announcable/internal/util"
+)
+
+// IgnoreBots answers requests of crawlers and other automated clients with 204 No Content
+// without passing them on, so that bot traffic isn't recorded as metrics, likes or read state
+func (h *Handler) IgnoreBots(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.log.Trace().Msg("mw IgnoreBots")
+		if util.IsBot(r.UserAgent()) {
+			h.log.Debug().Str("userAgent", r.UserAgent()).Msg("Ignoring bot request")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,9 +1,11 @@
 package mw
 
 import (
+	"net"
 	"net/http"
 
 	"github.com/devbydaniel/announcable/internal/ratelimit"
+	"github.com/go-chi/chi/v5"
 )
 
 const (
@@ -26,3 +28,73 @@ func (h *Handler) RateLimit(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// limits of the public widget endpoints that serve release notes and record metrics, likes
+// and read state, per minute.
+// The IP limit is generous as offices share an IP, the organisation limit caps abuse spread
+// over many IPs.
+var (
+	widgetIPRateLimiter  = ratelimit.New(60, 300)
+	widgetOrgRateLimiter = ratelimit.New(60, 6000)
+)
+
+// RateLimitWidget limits requests per client IP and per organisation (the orgId URL param).
+// Behind a reverse proxy the client IP is only known if TRUST_PROXY_HEADERS is set.
+func (h *Handler) RateLimitWidget(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.log.Trace().Msg("mw RateLimitWidget")
+		ip := clientIP(r)
+		if err := widgetIPRateLimiter.Deduct(ip, 1); err != nil {
+			h.log.Warn().Str("ip", ip).Err(err).Msg("Widget rate limit reached")
+			http.Error(w, "Rate limit reached", http.StatusTooManyRequests)
+			return
+		}
+		orgId := chi.URLParam(r, "orgId")
+		if err := widgetOrgRateLimiter.Deduct(orgId, 1); err != nil {
+			h.log.Warn().Str("orgId", orgId).Err(err).Msg("Widget organisation rate limit reached")
+			http.Error(w, "Rate limit reached", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limits of feedback submissions, which end up in the organisation's inbox and emails. A visitor
+// can leave 5 comments per 10 minutes, an organisation receives at most 100 per hour.
+var (
+	feedbackIPRateLimiter  = ratelimit.New(600, 5)
+	feedbackOrgRateLimiter = ratelimit.New(3600, 100)
+)
+
+// RateLimitFeedback limits feedback submissions per client IP and per organisation (the orgId
+// URL param of the widget API or the orgSlug URL param of the release page)
+func (h *Handler) RateLimitFeedback(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.log.Trace().Msg("mw RateLimitFeedback")
+		ip := clientIP(r)
+		if err := feedbackIPRateLimiter.Deduct(ip, 1); err != nil {
+			h.log.Warn().Str("ip", ip).Err(err).Msg("Feedback rate limit reached")
+			http.Error(w, "Rate limit reached", http.StatusTooManyRequests)
+			return
+		}
+		org := chi.URLParam(r, "orgId")
+		if org == "" {
+			org = chi.URLParam(r, "orgSlug")
+		}
+		if err := feedbackOrgRateLimiter.Deduct(org, 1); err != nil {
+			h.log.Warn().Str("org", org).Err(err).Msg("Feedback organisation rate limit reached")
+			http.Error(w, "Rate limit reached", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP of the client without the port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -40,23 +40,46 @@ func (b *Bucket) consume(cost, maxValue float64, refillIntervalMillis int64) boo
 	return false
 }
 
+// number of buckets from which on idle buckets are removed, so that limiters keyed by IP or
+// client don't grow without bounds
+const pruneThreshold = 10000
+
 type TokenBucketRateLimit struct {
 	refillIntervalMillis int64
 	maxValue             float64
 	buckets              map[string]*Bucket
+	// guards buckets, which is written by concurrent requests
+	mu sync.Mutex
 }
 
 func (tbr *TokenBucketRateLimit) Deduct(id string, cost float64) error {
 	now := time.Now().UTC().UnixMilli()
 	// check if userId is already part of the map
+	tbr.mu.Lock()
 	bucket, ok := tbr.buckets[id]
 	if !ok {
-		bucket := Bucket{count: tbr.maxValue, refilledAt: now}
-		tbr.buckets[id] = &bucket
-		return tbr.Deduct(id, cost)
+		if len(tbr.buckets) >= pruneThreshold {
+			tbr.prune(now)
+		}
+		bucket = &Bucket{count: tbr.maxValue, refilledAt: now}
+		tbr.buckets[id] = bucket
 	}
+	tbr.mu.Unlock()
 	if ok := bucket.consume(cost, tbr.maxValue, tbr.refillIntervalMillis); !ok {
 		return errors.New("rate limit reached")
 	}
 	return nil
 }
+
+// prune removes the buckets that have been refilled completely since they were last used,
+// they are the same as new ones. Must be called with tbr.mu held.
+func (tbr *TokenBucketRateLimit) prune(now int64) {
+	for id, bucket := range tbr.buckets {
+		bucket.mu.Lock()
+		idle := now-bucket.refilledAt >= tbr.refillIntervalMillis
+		bucket.mu.Unlock()
+		if idle {
+			delete(tbr.buckets, id)
+		}
+	}
+}
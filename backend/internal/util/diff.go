@@ -0,0 +1,95 @@
+package util
+
+import "regexp"
+
+// DiffOp is the kind of change a DiffSegment represents
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffSegment is a run of text that is unchanged, inserted or deleted
+type DiffSegment struct {
+	Op   DiffOp
+	Text string
+}
+
+// maxDiffCells caps the size of the LCS table; larger inputs are diffed as a whole replacement
+const maxDiffCells = 1_000_000
+
+var diffTokenRegex = regexp.MustCompile(`\S+\s*|\s+`)
+
+// DiffWords computes a word-level diff between two texts.
+// Whitespace stays attached to the preceding word, so joining the equal and deleted
+// segments yields a and joining the equal and inserted segments yields b.
+func DiffWords(a, b string) []DiffSegment {
+	if a == b {
+		if a == "" {
+			return nil
+		}
+		return []DiffSegment{{Op: DiffEqual, Text: a}}
+	}
+	aTokens := diffTokenRegex.FindAllString(a, -1)
+	bTokens := diffTokenRegex.FindAllString(b, -1)
+	n, m := len(aTokens), len(bTokens)
+
+	if n*m > maxDiffCells {
+		var segments []DiffSegment
+		segments = appendSegment(segments, DiffDelete, a)
+		return appendSegment(segments, DiffInsert, b)
+	}
+
+	// lcs[i][j] holds the length of the longest common subsequence of aTokens[i:] and bTokens[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if aTokens[i] == bTokens[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var segments []DiffSegment
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case aTokens[i] == bTokens[j]:
+			segments = appendSegment(segments, DiffEqual, aTokens[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			segments = appendSegment(segments, DiffDelete, aTokens[i])
+			i++
+		default:
+			segments = appendSegment(segments, DiffInsert, bTokens[j])
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		segments = appendSegment(segments, DiffDelete, aTokens[i])
+	}
+	for ; j < m; j++ {
+		segments = appendSegment(segments, DiffInsert, bTokens[j])
+	}
+	return segments
+}
+
+// appendSegment adds text to the last segment if it has the same op
+func appendSegment(segments []DiffSegment, op DiffOp, text string) []DiffSegment {
+	if text == "" {
+		return segments
+	}
+	if len(segments) > 0 && segments[len(segments)-1].Op == op {
+		segments[len(segments)-1].Text += text
+		return segments
+	}
+	return append(segments, DiffSegment{Op: op, Text: text})
+}
@@ -0,0 +1,92 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDiffWords tests the word-level diff used for release note revisions
+func TestDiffWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected []DiffSegment
+	}{
+		{
+			name:     "both empty",
+			a:        "",
+			b:        "",
+			expected: nil,
+		},
+		{
+			name:     "unchanged",
+			a:        "New dashboard",
+			b:        "New dashboard",
+			expected: []DiffSegment{{Op: DiffEqual, Text: "New dashboard"}},
+		},
+		{
+			name:     "added text",
+			a:        "",
+			b:        "New dashboard",
+			expected: []DiffSegment{{Op: DiffInsert, Text: "New dashboard"}},
+		},
+		{
+			name:     "removed text",
+			a:        "New dashboard",
+			b:        "",
+			expected: []DiffSegment{{Op: DiffDelete, Text: "New dashboard"}},
+		},
+		{
+			name: "replaced word",
+			a:    "The new dashboard is live",
+			b:    "The redesigned dashboard is live",
+			expected: []DiffSegment{
+				{Op: DiffEqual, Text: "The "},
+				{Op: DiffDelete, Text: "new "},
+				{Op: DiffInsert, Text: "redesigned "},
+				{Op: DiffEqual, Text: "dashboard is live"},
+			},
+		},
+		{
+			name: "appended words",
+			a:    "Faster exports",
+			b:    "Faster exports for everyone",
+			expected: []DiffSegment{
+				{Op: DiffEqual, Text: "Faster "},
+				{Op: DiffDelete, Text: "exports"},
+				{Op: DiffInsert, Text: "exports for everyone"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DiffWords(tt.a, tt.b)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("DiffWords(%q, %q) = %v, want %v", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDiffWords_Reconstructs tests that the segments reproduce both inputs
+func TestDiffWords_Reconstructs(t *testing.T) {
+	a := "Line one\nline two  with spacing\n"
+	b := "Line one\nline 2 with spacing\nline three"
+	var gotA, gotB string
+	for _, s := range DiffWords(a, b) {
+		if s.Op != DiffInsert {
+			gotA += s.Text
+		}
+		if s.Op != DiffDelete {
+			gotB += s.Text
+		}
+	}
+	if gotA != a {
+		t.Errorf("old text = %q, want %q", gotA, a)
+	}
+	if gotB != b {
+		t.Errorf("new text = %q, want %q", gotB, b)
+	}
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"strings"
+)
+
+// maximum length of a slug generated by Slugify
+const maxSlugLength = 80
+
+// Slugify turns a title into a lowercase, URL-safe slug of ASCII letters, digits and dashes.
+// Returns an empty string if the title contains no usable characters.
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
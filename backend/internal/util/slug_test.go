@@ -0,0 +1,32 @@
+package util
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "simple title", input: "Dark mode", expected: "dark-mode"},
+		{name: "punctuation", input: "New: CSV export (beta)!", expected: "new-csv-export-beta"},
+		{name: "surrounding whitespace", input: "  Faster search  ", expected: "faster-search"},
+		{name: "repeated separators", input: "v2.0 -- release", expected: "v2-0-release"},
+		{name: "non-ascii characters", input: "Überarbeitete Ansicht", expected: "berarbeitete-ansicht"},
+		{name: "no usable characters", input: "🎉 !!", expected: ""},
+		{name: "empty", input: "", expected: ""},
+		{
+			name:     "truncated without trailing dash",
+			input:    "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa bbb",
+			expected: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.input); got != tt.expected {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
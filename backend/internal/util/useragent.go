@@ -0,0 +1,28 @@
+package util
+
+import "strings"
+
+// botUserAgentTokens are lowercase substrings of the user agents of crawlers, link previews,
+// uptime monitors, headless browsers and HTTP libraries
+var botUserAgentTokens = []string{
+	"bot", "crawl", "spider", "slurp", "scrape", "archiver",
+	"facebookexternalhit", "embedly", "bingpreview", "mediapartners-google",
+	"headlesschrome", "phantomjs", "puppeteer", "playwright", "selenium", "lighthouse",
+	"pingdom", "uptime", "monitor",
+	"curl/", "wget/", "python-requests", "python-urllib", "go-http-client", "java/", "okhttp", "axios/", "node-fetch",
+}
+
+// IsBot reports whether a user agent belongs to automated traffic rather than a person using a
+// browser. Requests without a user agent count as bots.
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, token := range botUserAgentTokens {
+		if strings.Contains(ua, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package util
+
+import "testing"
+
+func TestIsBot(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		expected  bool
+	}{
+		{name: "chrome", userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36", expected: false},
+		{name: "safari on iphone", userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1", expected: false},
+		{name: "firefox", userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0", expected: false},
+		{name: "googlebot", userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", expected: true},
+		{name: "bingbot", userAgent: "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm) Chrome/116.0.1938.76 Safari/537.36", expected: true},
+		{name: "yahoo slurp", userAgent: "Mozilla/5.0 (compatible; Yahoo! Slurp; http://help.yahoo.com/help/us/ysearch/slurp)", expected: true},
+		{name: "link preview", userAgent: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", expected: true},
+		{name: "headless chrome", userAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/126.0.0.0 Safari/537.36", expected: true},
+		{name: "curl", userAgent: "curl/8.7.1", expected: true},
+		{name: "python", userAgent: "python-requests/2.32.3", expected: true},
+		{name: "go", userAgent: "Go-http-client/1.1", expected: true},
+		{name: "empty", userAgent: "", expected: true},
+		{name: "whitespace", userAgent: "  ", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBot(tt.userAgent); got != tt.expected {
+				t.Errorf("IsBot(%q) = %v, want %v", tt.userAgent, got, tt.expected)
+			}
+		})
+	}
+}
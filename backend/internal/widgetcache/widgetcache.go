@@ -0,0 +1,112 @@
+// Package widgetcache caches the data served by the public widget API per organisation.
+// Entries are stored under the organisation's current version, so that services can
+// invalidate all cached data of an organisation at once by bumping the version after writes.
+package widgetcache
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/devbydaniel/announcable/internal/logger"
+	"github.com/devbydaniel/announcable/internal/memcache"
+	"github.com/google/uuid"
+)
+
+var log = logger.Get()
+
+// ttl bounds how long data changed outside of the services (e.g. in the database) stays stale
+const ttl = 5 * time.Minute
+
+// maxEntriesPerOrg and maxEntries bound the memory used by requests with many distinct
+// parameters. Results beyond them are served but not cached.
+const (
+	maxEntriesPerOrg = 500
+	maxEntries       = 20000
+)
+
+var store = memcache.New(ttl, 2*ttl)
+
+var (
+	versionsMu sync.Mutex
+	versions   = map[uuid.UUID]uint64{}
+	// expiry of the entries cached under the current version of each organisation
+	entries = map[uuid.UUID]map[string]time.Time{}
+)
+
+// Entry is a cached value with the time it was loaded
+type Entry struct {
+	Value    interface{}
+	LoadedAt time.Time
+}
+
+// GetOrLoad returns the entry cached for the organisation under key, calling load and caching
+// its result if there is none. Results loaded while the organisation was invalidated are
+// returned but not served to later requests.
+func GetOrLoad(orgId uuid.UUID, key string, load func() (interface{}, error)) (*Entry, error) {
+	version := currentVersion(orgId)
+	storeKey := orgId.String() + "|" + strconv.FormatUint(version, 10) + "|" + key
+	if cached, found := store.Get(storeKey); found {
+		log.Trace().Str("key", key).Msg("Found in widget cache")
+		return cached.(*Entry), nil
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	entry := &Entry{Value: value, LoadedAt: time.Now().UTC().Truncate(time.Second)}
+	if reserve(orgId, version, storeKey) {
+		store.Set(storeKey, entry, ttl)
+	}
+	return entry, nil
+}
+
+// reserve counts storeKey towards the entries of the organisation and returns false if
+// the result must not be cached, because the version is outdated or a limit is reached
+func reserve(orgId uuid.UUID, version uint64, storeKey string) bool {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+	if versions[orgId] != version {
+		return false
+	}
+	now := time.Now()
+	keys := entries[orgId]
+	if keys == nil {
+		keys = map[string]time.Time{}
+		entries[orgId] = keys
+	}
+	if len(keys) >= maxEntriesPerOrg {
+		for key, expiresAt := range keys {
+			if now.After(expiresAt) {
+				delete(keys, key)
+			}
+		}
+	}
+	if len(keys) >= maxEntriesPerOrg {
+		log.Warn().Str("orgId", orgId.String()).Msg("Widget cache entry limit of organisation reached")
+		return false
+	}
+	if store.ItemCount() >= maxEntries {
+		log.Warn().Msg("Widget cache entry limit reached")
+		return false
+	}
+	keys[storeKey] = now.Add(ttl)
+	return true
+}
+
+// Invalidate drops all cached data of the organisation. Services call it after writes
+// that change what the widget shows.
+func Invalidate(orgId uuid.UUID) {
+	log.Trace().Str("orgId", orgId.String()).Msg("Invalidate")
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+	versions[orgId]++
+	// entries of the previous version are no longer served and expire on their own
+	delete(entries, orgId)
+}
+
+func currentVersion(orgId uuid.UUID) uint64 {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+	return versions[orgId]
+}
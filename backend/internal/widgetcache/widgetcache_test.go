@@ -0,0 +1,108 @@
+package widgetcache
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	orgId, otherOrgId := uuid.New(), uuid.New()
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+	get := func(orgId uuid.UUID, key string) int {
+		t.Helper()
+		entry, err := GetOrLoad(orgId, key, load)
+		if err != nil {
+			t.Fatalf("GetOrLoad() error = %v", err)
+		}
+		return entry.Value.(int)
+	}
+
+	if got := get(orgId, "status?for=widget"); got != 1 {
+		t.Fatalf("first load = %d, want 1", got)
+	}
+	if got := get(orgId, "status?for=widget"); got != 1 {
+		t.Errorf("cached value = %d, want 1", got)
+	}
+	if got := get(orgId, "status?for=website"); got != 2 {
+		t.Errorf("value of other key = %d, want 2", got)
+	}
+	if got := get(otherOrgId, "status?for=widget"); got != 3 {
+		t.Errorf("value of other organisation = %d, want 3", got)
+	}
+
+	Invalidate(orgId)
+	if got := get(orgId, "status?for=widget"); got != 4 {
+		t.Errorf("value after Invalidate() = %d, want 4", got)
+	}
+	if got := get(otherOrgId, "status?for=widget"); got != 3 {
+		t.Errorf("value of other organisation after Invalidate() = %d, want 3", got)
+	}
+
+	// invalidating while loading doesn't cache the stale result
+	get(orgId, "notes")
+	stale := func() (interface{}, error) {
+		Invalidate(orgId)
+		return -1, nil
+	}
+	Invalidate(orgId)
+	if entry, _ := GetOrLoad(orgId, "notes", stale); entry.Value != -1 {
+		t.Fatalf("GetOrLoad() = %v, want -1", entry.Value)
+	}
+	if got := get(orgId, "notes"); got == -1 {
+		t.Errorf("result loaded during invalidation was cached")
+	}
+
+	// errors are not cached
+	errLoad := errors.New("load failed")
+	if _, err := GetOrLoad(orgId, "config", func() (interface{}, error) { return nil, errLoad }); !errors.Is(err, errLoad) {
+		t.Errorf("GetOrLoad() error = %v, want %v", err, errLoad)
+	}
+	if got := get(orgId, "config"); got < 1 {
+		t.Errorf("value after failed load = %d", got)
+	}
+}
+
+func TestGetOrLoadEntryLimit(t *testing.T) {
+	orgId, otherOrgId := uuid.New(), uuid.New()
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+	for i := 0; i < maxEntriesPerOrg; i++ {
+		if _, err := GetOrLoad(orgId, "notes?page="+strconv.Itoa(i), load); err != nil {
+			t.Fatalf("GetOrLoad() error = %v", err)
+		}
+	}
+
+	// results beyond the limit are served but not cached
+	for i := 0; i < 2; i++ {
+		if _, err := GetOrLoad(orgId, "notes?page=overflow", load); err != nil {
+			t.Fatalf("GetOrLoad() error = %v", err)
+		}
+	}
+	if loads != maxEntriesPerOrg+2 {
+		t.Errorf("loads = %d, want %d", loads, maxEntriesPerOrg+2)
+	}
+	if entry, _ := GetOrLoad(orgId, "notes?page=0", load); entry.Value != 1 {
+		t.Errorf("cached value = %v, want 1", entry.Value)
+	}
+
+	// the limit applies per organisation and is reset by invalidation
+	before := loads
+	GetOrLoad(otherOrgId, "notes?page=overflow", load)
+	GetOrLoad(otherOrgId, "notes?page=overflow", load)
+	Invalidate(orgId)
+	GetOrLoad(orgId, "notes?page=overflow", load)
+	GetOrLoad(orgId, "notes?page=overflow", load)
+	if loads != before+2 {
+		t.Errorf("loads = %d, want %d", loads, before+2)
+	}
+}
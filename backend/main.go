@@ -6,16 +6,21 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/devbydaniel/announcable/config"
 	"github.com/devbydaniel/announcable/internal/database"
 	"github.com/devbydaniel/announcable/internal/domain/rbac"
+	releasenotes "github.com/devbydaniel/announcable/internal/domain/release-notes"
+	"github.com/devbydaniel/announcable/internal/domain/webhook"
 	apiShared "github.com/devbydaniel/announcable/internal/handler/api/shared"
+	apiV1 "github.com/devbydaniel/announcable/internal/handler/api/v1"
 	apiWidget "github.com/devbydaniel/announcable/internal/handler/api/widget"
 	"github.com/devbydaniel/announcable/internal/handler/pages/admin/dashboard"
 	"github.com/devbydaniel/announcable/internal/handler/pages/admin/organisation"
+	"github.com/devbydaniel/announcable/internal/handler/pages/analytics"
 	"github.com/devbydaniel/announcable/internal/handler/pages/auth/invite_accept"
 	"github.com/devbydaniel/announcable/internal/handler/pages/auth/login"
 	"github.com/devbydaniel/announcable/internal/handler/pages/auth/logout"
@@ -23,6 +28,7 @@ import (
 	"github.com/devbydaniel/announcable/internal/handler/pages/auth/password_reset"
 	"github.com/devbydaniel/announcable/internal/handler/pages/auth/register"
 	"github.com/devbydaniel/announcable/internal/handler/pages/auth/verify_email"
+	feedbackInboxHandler "github.com/devbydaniel/announcable/internal/handler/pages/feedback/inbox"
 	"github.com/devbydaniel/announcable/internal/handler/pages/public/home"
 	"github.com/devbydaniel/announcable/internal/handler/pages/public/release_page"
 	"github.com/devbydaniel/announcable/internal/handler/pages/public/widget_script"
@@ -31,7 +37,10 @@ import (
 	rnListHandler "github.com/devbydaniel/announcable/internal/handler/pages/release_notes/list"
 	releasePageConfigHandler "github.com/devbydaniel/announcable/internal/handler/pages/release_page/config"
 	"github.com/devbydaniel/announcable/internal/handler/pages/settings/account"
+	tagListHandler "github.com/devbydaniel/announcable/internal/handler/pages/tags/list"
 	"github.com/devbydaniel/announcable/internal/handler/pages/users"
+	webhookDetailHandler "github.com/devbydaniel/announcable/internal/handler/pages/webhooks/detail"
+	webhookListHandler "github.com/devbydaniel/announcable/internal/handler/pages/webhooks/list"
 	widgetConfigHandler "github.com/devbydaniel/announcable/internal/handler/pages/widget/config"
 	"github.com/devbydaniel/announcable/internal/handler/shared"
 	"github.com/devbydaniel/announcable/internal/logger"
@@ -48,6 +57,12 @@ var (
 	cfg = config.New()
 )
 
+// how often the scheduler checks for release notes to publish or unpublish
+const releaseNoteScheduleInterval = 30 * time.Second
+
+// how often the dispatcher checks for webhook deliveries that are due
+const webhookDispatchInterval = 5 * time.Second
+
 func main() {
 	log.Info().Msg("Starting application")
 	if cfg.Env == "production" {
@@ -80,11 +95,18 @@ func main() {
 	rnListHandler := rnListHandler.New(deps)
 	rnCreateHandler := rnCreateHandler.New(deps)
 	rnDetailHandler := rnDetailHandler.New(deps)
+	tagListHandler := tagListHandler.New(deps)
+	analyticsHandler := analytics.New(deps)
+	feedbackInboxHandler := feedbackInboxHandler.New(deps)
 
 	// Config handlers
 	widgetHandler := widgetConfigHandler.New(deps)
 	releasePageHandler := releasePageConfigHandler.New(deps)
 
+	// Webhook handlers
+	webhookListHandler := webhookListHandler.New(deps)
+	webhookDetailHandler := webhookDetailHandler.New(deps)
+
 	// Admin handlers
 	adminDashboardHandler := dashboard.New(deps)
 	adminOrgHandler := organisation.New(deps)
@@ -97,8 +119,13 @@ func main() {
 	// API handlers
 	widgetAPIHandler := apiWidget.New(deps)
 	sharedAPIHandler := apiShared.New(deps)
+	v1APIHandler := apiV1.New(deps)
 
 	r := chi.NewRouter()
+	if cfg.TrustProxyHeaders {
+		// take the client IP from X-Forwarded-For / X-Real-IP, only safe behind a reverse proxy
+		r.Use(middleware.RealIP)
+	}
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
@@ -165,10 +192,16 @@ func main() {
 		r.Get("/", rnListHandler.ServeReleaseNotesListPage)
 		r.Post("/", rnCreateHandler.HandleReleaseNoteCreate)
 		r.Get("/new", rnCreateHandler.ServeReleaseNoteCreatePage)
+		r.Post("/markdown-preview", rnCreateHandler.HandleMarkdownPreview)
 		r.Get("/{id}", rnDetailHandler.ServeReleaseNoteDetailPage)
 		r.Patch("/{id}", rnDetailHandler.HandleReleaseNoteUpdate)
 		r.Delete("/{id}", rnDetailHandler.HandleReleaseNoteDelete)
 		r.Patch("/{id}/publish", rnDetailHandler.HandleReleaseNotePublish)
+		r.Post("/{id}/reviews", rnDetailHandler.HandleReleaseNoteReview)
+		r.Post("/{id}/previews", rnDetailHandler.HandlePreviewCreate)
+		r.Delete("/{id}/previews/{previewId}", rnDetailHandler.HandlePreviewRevoke)
+		r.Get("/{id}/revisions/{revisionId}", rnDetailHandler.HandleRevisionDiff)
+		r.Post("/{id}/revisions/{revisionId}/restore", rnDetailHandler.HandleRevisionRestore)
 	})
 
 	// WIDGET
@@ -191,6 +224,55 @@ func main() {
 		r.Patch("/", releasePageHandler.HandleConfigUpdate)
 	})
 
+	// WEBHOOKS
+
+	r.With(
+		mwHandler.Authenticate,
+		mwHandler.Authorize(rbac.PermissionManageAccess),
+	).Route("/webhooks", func(r chi.Router) {
+		r.Get("/", webhookListHandler.ServeWebhooksPage)
+		r.Post("/", webhookListHandler.HandleWebhookCreate)
+		r.Get("/{id}", webhookDetailHandler.ServeWebhookDetailPage)
+		r.Patch("/{id}", webhookDetailHandler.HandleWebhookUpdate)
+		r.Delete("/{id}", webhookDetailHandler.HandleWebhookDelete)
+		r.Patch("/{id}/secret", webhookDetailHandler.HandleWebhookSecretRotate)
+		r.Post("/{id}/test", webhookDetailHandler.HandleWebhookTest)
+		r.Post("/{id}/deliveries/{deliveryId}/retry", webhookDetailHandler.HandleDeliveryRetry)
+	})
+
+	// ANALYTICS
+
+	r.With(
+		mwHandler.Authenticate,
+		mwHandler.Authorize(rbac.PermissionManageReleaseNote),
+	).Route("/analytics", func(r chi.Router) {
+		r.Get("/", analyticsHandler.ServeAnalyticsPage)
+		r.Get("/export", analyticsHandler.HandleExport)
+	})
+
+	// FEEDBACK
+
+	r.With(
+		mwHandler.Authenticate,
+		mwHandler.Authorize(rbac.PermissionManageReleaseNote),
+	).Route("/feedback", func(r chi.Router) {
+		r.Get("/", feedbackInboxHandler.ServeFeedbackPage)
+		r.Patch("/{id}", feedbackInboxHandler.HandleFeedbackStatusUpdate)
+		r.Delete("/{id}", feedbackInboxHandler.HandleFeedbackDelete)
+	})
+
+	// TAGS
+
+	r.With(
+		mwHandler.Authenticate,
+		mwHandler.Authorize(rbac.PermissionManageReleaseNote),
+	).Route("/tags", func(r chi.Router) {
+		r.Get("/", tagListHandler.ServeTagsPage)
+		r.Post("/", tagListHandler.HandleTagCreate)
+		r.Patch("/{id}", tagListHandler.HandleTagUpdate)
+		r.Delete("/{id}", tagListHandler.HandleTagDelete)
+	})
+
 	// SETTINGS
 
 	r.With(
@@ -201,6 +283,12 @@ func main() {
 		r.Patch("/password", settingsHandler.HandlePasswordUpdate)
 		r.Patch("/widget-id", settingsHandler.HandleWidgetIdRegenerate)
 		r.Patch("/release-page-url", settingsHandler.HandleReleasePageUrlUpdate)
+		r.Patch("/default-locale", settingsHandler.HandleDefaultLocaleUpdate)
+		r.Patch("/review", settingsHandler.HandleRequireReviewUpdate)
+		r.Patch("/identity-secret", settingsHandler.HandleIdentitySecretRotate)
+		r.Patch("/identity", settingsHandler.HandleIdentityUpdate)
+		r.Post("/api-keys", settingsHandler.HandleApiKeyCreate)
+		r.Delete("/api-keys/{id}", settingsHandler.HandleApiKeyRevoke)
 	})
 
 	// ADMIN DASHBOARD
@@ -220,26 +308,51 @@ func main() {
 	// !! this route path is hardcoded in the widget script
 	r.Route("/api", func(r chi.Router) {
 		r.Use(cors.Handler(cors.Options{
-			AllowedOrigins:   []string{"*"},
-			AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
-			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+			// organisations can restrict the widget to their own domains
+			AllowOriginFunc: widgetAPIHandler.IsOriginAllowed,
+			// PATCH and DELETE are used by the v1 API
+			AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", apiWidget.HeaderUserToken},
 			ExposedHeaders:   []string{"Link"},
 			AllowCredentials: false,
 			MaxAge:           300, // Maximum value not ignored by any of major browsers
 		}))
-		r.Get("/release-notes/{orgId}", widgetAPIHandler.HandleReleaseNotesServe)
-		r.Get("/release-notes/{orgId}/status", widgetAPIHandler.HandleReleaseNotesStatusServe)
-		r.Post("/release-notes/{orgId}/metrics", widgetAPIHandler.HandleReleaseNoteMetricCreate)
+		// reads are cached per query, the limit keeps clients from cycling through parameters
+		r.With(mwHandler.RateLimitWidget).Group(func(r chi.Router) {
+			r.Get("/release-notes/{orgId}", widgetAPIHandler.HandleReleaseNotesServe)
+			r.Get("/release-notes/{orgId}/status", widgetAPIHandler.HandleReleaseNotesStatusServe)
+			r.Get("/release-notes/{orgId}/unread-count", widgetAPIHandler.HandleReleaseNotesUnreadCountServe)
+		})
 		r.Get("/release-notes/{orgId}/{releaseNoteId}/like", widgetAPIHandler.HandleGetReleaseNoteLikeState)
-		r.Post("/release-notes/{orgId}/{releaseNoteId}/like", widgetAPIHandler.HandleReleaseNoteToggleLike)
+		// endpoints recording engagement, bots are dropped before they count
+		r.With(mwHandler.IgnoreBots, mwHandler.RateLimitWidget).Group(func(r chi.Router) {
+			r.Post("/release-notes/{orgId}/seen", widgetAPIHandler.HandleReleaseNotesMarkSeen)
+			r.Post("/release-notes/{orgId}/metrics", widgetAPIHandler.HandleReleaseNoteMetricCreate)
+			r.Post("/release-notes/{orgId}/{releaseNoteId}/like", widgetAPIHandler.HandleReleaseNoteToggleLike)
+			r.With(mwHandler.RateLimitFeedback).Post("/release-notes/{orgId}/{releaseNoteId}/feedback", widgetAPIHandler.HandleReleaseNoteFeedbackCreate)
+		})
+		// live updates for open widgets, the stream stays open until the client disconnects
+		r.With(mwHandler.IgnoreBots, mwHandler.RateLimitWidget).Get("/release-notes/{orgId}/events", widgetAPIHandler.HandleReleaseNotesEvents)
 		r.Get("/widget-config/{orgId}", widgetAPIHandler.HandleWidgetConfigServe)
 		r.Get("/img/*", sharedAPIHandler.HandleObjStore)
+
+		// public REST API, authenticated with organisation API keys
+		r.With(mwHandler.AuthenticateApiKey).Route("/v1/release-notes", func(r chi.Router) {
+			r.Get("/", v1APIHandler.HandleReleaseNotesList)
+			r.Post("/", v1APIHandler.HandleReleaseNoteCreate)
+			r.Get("/{id}", v1APIHandler.HandleReleaseNoteGet)
+			r.Patch("/{id}", v1APIHandler.HandleReleaseNoteUpdate)
+			r.Delete("/{id}", v1APIHandler.HandleReleaseNoteDelete)
+			r.Post("/{id}/publish", v1APIHandler.HandleReleaseNotePublish)
+			r.Post("/{id}/unpublish", v1APIHandler.HandleReleaseNoteUnpublish)
+		})
 	})
 
 	// WIDGET SCRIPT
 
 	r.Route("/widget", func(r chi.Router) {
 		r.Use(cors.Handler(cors.Options{
+			// the script holds no organisation data, the API enforces the allowed domains
 			AllowedOrigins:   []string{"*"},
 			AllowedMethods:   []string{"GET", "OPTIONS"},
 			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
@@ -265,6 +378,13 @@ func main() {
 
 		}))
 		r.Get("/{orgSlug}", releasePagePublicHandler.ServeReleasePage)
+		r.Get("/{orgSlug}/feed.rss", releasePagePublicHandler.ServeFeedRSS)
+		r.Get("/{orgSlug}/feed.atom", releasePagePublicHandler.ServeFeedAtom)
+		r.Get("/{orgSlug}/feed.json", releasePagePublicHandler.ServeFeedJSON)
+		r.Get("/{orgSlug}/images/{mediaId}", releasePagePublicHandler.ServeFeedImage)
+		r.Get("/{orgSlug}/{noteSlug}", releasePagePublicHandler.ServeReleaseNotePage)
+		r.Get("/{orgSlug}/preview/{token}", releasePagePublicHandler.ServePreviewPage)
+		r.With(mwHandler.IgnoreBots, mwHandler.RateLimitFeedback).Post("/{orgSlug}/{noteSlug}/feedback", releasePagePublicHandler.HandleFeedbackCreate)
 	})
 
 	// STATIC
@@ -285,6 +405,33 @@ func main() {
 		Addr:    ":" + strconv.Itoa(cfg.Port),
 		Handler: r,
 	}
+	// end open event streams, as Shutdown waits for them otherwise
+	srv.RegisterOnShutdown(releasenotes.Events.Close)
+
+	// Start the background workers
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	rnScheduler := releasenotes.NewScheduler(
+		releasenotes.NewService(*releasenotes.NewRepository(db, objStore)),
+		releaseNoteScheduleInterval,
+	)
+	webhookDispatcher := webhook.NewDispatcher(
+		webhook.NewService(*webhook.NewRepository(db)),
+		webhookDispatchInterval,
+	)
+	workers.Add(3)
+	go func() {
+		defer workers.Done()
+		rnScheduler.Run(workersCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		webhookDispatcher.Run(workersCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		widgetAPIHandler.RunMetricsIngester(workersCtx)
+	}()
 
 	// Channel to listen for errors coming from the listener.
 	serverErrors := make(chan error, 1)
@@ -318,6 +465,9 @@ func main() {
 			}
 		}
 	}
+
+	stopWorkers()
+	workers.Wait()
 }
 
 func initDb() *database.DB {
@@ -1,12 +1,14 @@
 import { LitElement, html, css } from 'lit';
-import { customElement, property } from 'lit/decorators.js';
+import { customElement, property, state } from 'lit/decorators.js';
 import { TaskStatus } from '@lit/task';
-import type { WidgetInit } from './lib/types';
+import type { ReleaseNote, WidgetConfig, WidgetInit } from './lib/types';
 import { WidgetToggleController } from './controllers/widget-toggle';
 import { AnchorsController } from './controllers/anchors';
+import { ReleaseNoteEventsController } from './controllers/release-note-events';
 import { ReleaseNotesTask } from './tasks/release-notes';
 import { WidgetConfigTask } from './tasks/widget-config';
-import { ReleaseNoteStatusTask } from './tasks/release-note-status';
+import { ReleaseNoteStatusTask, type ReleaseNoteStatus } from './tasks/release-note-status';
+import { markReleaseNotesSeen } from './tasks/release-note-seen';
 import './components/ui/button';
 import './components/ui/indicator';
 import './components/ui/error-panel';
@@ -25,6 +27,9 @@ export class AnnouncableApp extends LitElement {
   private toggleController!: WidgetToggleController;
   private anchorsController!: AnchorsController;
   private statusTask!: ReleaseNoteStatusTask;
+  private markedSeen?: ReleaseNoteStatus[];
+  // bumped on every release note event so that open widget content refetches the notes
+  @state() private notesRevision = 0;
 
   static styles = css`
     :host {
@@ -64,7 +69,19 @@ export class AnnouncableApp extends LitElement {
       this.init.anchor_query_selector
     );
 
-    this.statusTask = new ReleaseNoteStatusTask(this, this.init.org_id);
+    this.statusTask = new ReleaseNoteStatusTask(this, this.init.org_id, this.init.user_attributes);
+
+    new ReleaseNoteEventsController(this, this.init.org_id, () => {
+      this.statusTask.task.run();
+      this.notesRevision++;
+    });
+  }
+
+  firstUpdated() {
+    // Preview links show the widget right away
+    if (this.init.preview_token) {
+      this.toggleController.setIsOpen(true);
+    }
   }
 
   updated() {
@@ -77,6 +94,23 @@ export class AnnouncableApp extends LitElement {
       });
     }
 
+    // Store the read state on the server once the user has opened the widget,
+    // previewing doesn't count as reading
+    const releaseNoteStatus = this.statusTask.task.value;
+    if (
+      !this.init.preview_token &&
+      this.toggleController.isOpen &&
+      this.statusTask.task.status === TaskStatus.COMPLETE &&
+      releaseNoteStatus &&
+      this.markedSeen !== releaseNoteStatus
+    ) {
+      this.markedSeen = releaseNoteStatus;
+      markReleaseNotesSeen(
+        this.init.org_id,
+        releaseNoteStatus.map((note) => note.id)
+      );
+    }
+
     // Handle instant open
     if (
       this.shouldInstantOpen() &&
@@ -94,34 +128,35 @@ export class AnnouncableApp extends LitElement {
     if (this.statusTask.task.status !== TaskStatus.COMPLETE) return false;
 
     const releaseNoteStatus = this.statusTask.task.value;
-    const lastOpened = this.toggleController.lastOpened;
-
     if (!releaseNoteStatus) return false;
-    if (!lastOpened) return true;
 
-    const lastOpenedTimestamp = parseInt(lastOpened);
-    return releaseNoteStatus.some((note) => {
-      if (!note.last_update_on) return false;
-      const noteTimestamp = new Date(note.last_update_on).getTime();
-      return noteTimestamp > lastOpenedTimestamp;
-    });
+    return releaseNoteStatus.some((note) => this.isUnseen(note));
   }
 
   private shouldInstantOpen(): boolean {
     if (this.statusTask.task.status !== TaskStatus.COMPLETE) return false;
 
     const releaseNoteStatus = this.statusTask.task.value;
-    const lastOpened = this.toggleController.lastOpened;
-
     if (!releaseNoteStatus) return false;
+
+    return releaseNoteStatus.some(
+      (note) => note.attention_mechanism === 'instant_open' && this.isUnseen(note)
+    );
+  }
+
+  /**
+   * A note is unseen unless the server knows the user has read it (on any device)
+   * or the widget was opened in this browser since the note's last update
+   */
+  private isUnseen(note: ReleaseNoteStatus): boolean {
+    if (note.is_unread === false) return false;
+
+    const lastOpened = this.toggleController.lastOpened;
     if (!lastOpened) return true;
+    if (!note.last_update_on) return false;
 
-    const lastOpenedTimestamp = parseInt(lastOpened);
-    return releaseNoteStatus.some((note) => {
-      if (!note.last_update_on || note.attention_mechanism !== 'instant_open') return false;
-      const noteTimestamp = new Date(note.last_update_on).getTime();
-      return noteTimestamp > lastOpenedTimestamp;
-    });
+    const noteTimestamp = new Date(note.last_update_on).getTime();
+    return noteTimestamp > parseInt(lastOpened);
   }
 
   private updateIndicatorDataset(
@@ -177,6 +212,7 @@ export class AnnouncableApp extends LitElement {
             <widget-content
               .init=${this.init}
               .isOpen=${this.toggleController.isOpen}
+              .notesRevision=${this.notesRevision}
               @close=${() => this.toggleController.setIsOpen(false)}
             ></widget-content>
           `
@@ -193,6 +229,7 @@ export class AnnouncableApp extends LitElement {
 export class WidgetContent extends LitElement {
   @property({ type: Object }) init!: WidgetInit;
   @property({ type: Boolean }) isOpen = false;
+  @property({ type: Number }) notesRevision = 0;
 
   private notesTask!: ReleaseNotesTask;
   private configTask!: WidgetConfigTask;
@@ -210,8 +247,21 @@ export class WidgetContent extends LitElement {
   connectedCallback() {
     super.connectedCallback();
 
-    this.notesTask = new ReleaseNotesTask(this, this.init.org_id);
-    this.configTask = new WidgetConfigTask(this, this.init.org_id);
+    this.notesTask = new ReleaseNotesTask(
+      this,
+      this.init.org_id,
+      this.init.locale,
+      this.init.user_attributes,
+      this.init.preview_token
+    );
+    this.configTask = new WidgetConfigTask(this, this.init.org_id, this.init.locale);
+  }
+
+  updated(changedProperties: Map<string, unknown>) {
+    // refetch the notes after a release note event, the initial fetch runs on its own
+    if (changedProperties.has('notesRevision') && changedProperties.get('notesRevision') !== undefined) {
+      this.notesTask.task.run();
+    }
   }
 
   private handleClose() {
@@ -245,23 +295,16 @@ export class WidgetContent extends LitElement {
           ${notesError || configError
             ? html`<ui-error-panel></ui-error-panel>`
             : this.notesTask.task.render({
-                pending: () => html`
-                  <release-notes-list>
-                    <release-note-skeleton .config=${widgetConfig}></release-note-skeleton>
-                  </release-notes-list>
-                `,
-                complete: (releaseNotes) => html`
-                  <release-notes-list>
-                    ${releaseNotes.map(
-                      (releaseNote) => html`
-                        <release-note-entry
-                          .config=${widgetConfig}
-                          .releaseNote=${releaseNote}
-                        ></release-note-entry>
-                      `
-                    )}
-                  </release-notes-list>
-                `,
+                // keep showing the current notes while refetching after an event
+                pending: () =>
+                  this.notesTask.task.value
+                    ? this.renderReleaseNotes(this.notesTask.task.value, widgetConfig)
+                    : html`
+                        <release-notes-list>
+                          <release-note-skeleton .config=${widgetConfig}></release-note-skeleton>
+                        </release-notes-list>
+                      `,
+                complete: (releaseNotes) => this.renderReleaseNotes(releaseNotes, widgetConfig),
                 error: (e) => {
                   console.error('Error loading release notes:', e);
                   return html`<ui-error-panel></ui-error-panel>`;
@@ -271,4 +314,19 @@ export class WidgetContent extends LitElement {
       </div>
     `;
   }
+
+  private renderReleaseNotes(releaseNotes: ReleaseNote[], widgetConfig: WidgetConfig) {
+    return html`
+      <release-notes-list>
+        ${releaseNotes.map(
+          (releaseNote) => html`
+            <release-note-entry
+              .config=${widgetConfig}
+              .releaseNote=${releaseNote}
+            ></release-note-entry>
+          `
+        )}
+      </release-notes-list>
+    `;
+  }
 }
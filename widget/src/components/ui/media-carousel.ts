@@ -0,0 +1,206 @@
+import { LitElement, html, css } from 'lit';
+import { customElement, property, state, query } from 'lit/decorators.js';
+import { ifDefined } from 'lit/directives/if-defined.js';
+import type { Media } from '@/lib/types';
+
+/**
+ * Horizontally scrolling carousel of the images and videos of a release note
+ */
+@customElement('ui-media-carousel')
+export class MediaCarousel extends LitElement {
+  @property({ type: Array }) media: Media[] = [];
+  // used as iframe title for videos without alt text
+  @property({ type: String }) label = '';
+
+  @state() private current = 0;
+  @query('.track') private track!: HTMLElement;
+
+  static styles = css`
+    :host {
+      display: block;
+    }
+
+    .carousel {
+      position: relative;
+    }
+
+    .track {
+      display: flex;
+      overflow-x: auto;
+      scroll-snap-type: x mandatory;
+      scroll-behavior: smooth;
+      scrollbar-width: none;
+    }
+
+    .track::-webkit-scrollbar {
+      display: none;
+    }
+
+    .slide {
+      position: relative;
+      flex: 0 0 100%;
+      margin: 0;
+      aspect-ratio: 16 / 9;
+      scroll-snap-align: start;
+    }
+
+    .slide img {
+      width: 100%;
+      height: 100%;
+      object-fit: contain;
+      background-size: cover;
+      background-repeat: no-repeat;
+    }
+
+    .slide iframe {
+      position: absolute;
+      top: 0;
+      left: 0;
+      width: 100%;
+      height: 100%;
+      border: none;
+    }
+
+    .nav {
+      position: absolute;
+      top: 50%;
+      transform: translateY(-50%);
+      display: flex;
+      align-items: center;
+      justify-content: center;
+      width: 1.75rem;
+      height: 1.75rem;
+      padding: 0;
+      border: none;
+      border-radius: 50%;
+      background: rgba(0, 0, 0, 0.5);
+      color: #fff;
+      font: inherit;
+      cursor: pointer;
+    }
+
+    .nav:disabled {
+      display: none;
+    }
+
+    .nav--prev {
+      left: 0.5rem;
+    }
+
+    .nav--next {
+      right: 0.5rem;
+    }
+
+    .dots {
+      display: flex;
+      justify-content: center;
+      gap: 0.375rem;
+      margin-top: 0.5rem;
+    }
+
+    .dot {
+      width: 0.5rem;
+      height: 0.5rem;
+      padding: 0;
+      border: 1px solid currentColor;
+      border-radius: 50%;
+      background: none;
+      opacity: 0.5;
+      cursor: pointer;
+    }
+
+    .dot--active {
+      background: currentColor;
+      opacity: 1;
+    }
+  `;
+
+  private scrollToSlide(i: number) {
+    this.track.scrollTo({ left: i * this.track.clientWidth });
+  }
+
+  private handleScroll() {
+    this.current = Math.round(this.track.scrollLeft / this.track.clientWidth);
+  }
+
+  // placeholders are removed once the image has loaded, so that they don't show through transparent images
+  private handleImageLoad(e: Event) {
+    (e.target as HTMLImageElement).style.backgroundImage = '';
+  }
+
+  private handleImageError(e: Event) {
+    const img = e.target as HTMLImageElement;
+    console.error('Image failed to load', img.src, e);
+    img.style.visibility = 'hidden';
+  }
+
+  private renderSlide(item: Media) {
+    if (item.kind === 'embed') {
+      return html`
+        <figure class="slide">
+          <iframe
+            src="${item.src}"
+            allow="fullscreen"
+            allowfullscreen
+            loading="lazy"
+            referrerpolicy="no-referrer"
+            sandbox="allow-scripts allow-presentation allow-same-origin"
+            title="${item.alt || this.label}"
+          ></iframe>
+        </figure>
+      `;
+    }
+    return html`
+      <figure class="slide">
+        <img
+          src="${item.src}"
+          srcset=${ifDefined(item.srcset || undefined)}
+          sizes=${ifDefined(item.srcset ? '(max-width: 640px) 100vw, 640px' : undefined)}
+          width=${ifDefined(item.width || undefined)}
+          height=${ifDefined(item.height || undefined)}
+          style=${item.placeholder ? `background-image: url(${item.placeholder})` : ''}
+          alt="${item.alt}"
+          loading="lazy"
+          @load=${this.handleImageLoad}
+          @error=${this.handleImageError}
+        />
+      </figure>
+    `;
+  }
+
+  render() {
+    const count = this.media.length;
+    return html`
+      <div class="carousel">
+        <div class="track" @scroll=${this.handleScroll}>
+          ${this.media.map((item) => this.renderSlide(item))}
+        </div>
+        ${count > 1 ? html`
+          <button
+            class="nav nav--prev"
+            aria-label="Previous"
+            ?disabled=${this.current === 0}
+            @click=${() => this.scrollToSlide(this.current - 1)}
+          >&#8249;</button>
+          <button
+            class="nav nav--next"
+            aria-label="Next"
+            ?disabled=${this.current === count - 1}
+            @click=${() => this.scrollToSlide(this.current + 1)}
+          >&#8250;</button>
+        ` : ''}
+      </div>
+      ${count > 1 ? html`
+        <div class="dots">
+          ${this.media.map((_, i) => html`
+            <button
+              class="dot ${i === this.current ? 'dot--active' : ''}"
+              aria-label="Show media ${i + 1} of ${count}"
+              @click=${() => this.scrollToSlide(i)}
+            ></button>
+          `)}
+        </div>
+      ` : ''}
+    `;
+  }
+}
@@ -1,12 +1,15 @@
 import { LitElement, html, css } from 'lit';
 import { customElement, property } from 'lit/decorators.js';
 import { ref, createRef, Ref } from 'lit/directives/ref.js';
+import { unsafeHTML } from 'lit/directives/unsafe-html.js';
 import type { ReleaseNote, WidgetConfig } from '@/lib/types';
 import { ReleaseNoteMetricsController } from '@/tasks/release-note-metrics';
 import { ReleaseNoteLikesController } from '@/tasks/release-note-likes';
+import { ReleaseNoteFeedbackController } from '@/tasks/release-note-feedback';
 import { getOrCreateClientId } from '@/lib/clientId';
 import './card';
 import './skeleton';
+import './media-carousel';
 import '../icons/thumbs-up';
 import '../icons/external-link';
 
@@ -46,6 +49,7 @@ export class ReleaseNoteEntry extends LitElement {
 
   private metricsController!: ReleaseNoteMetricsController;
   private likesController!: ReleaseNoteLikesController;
+  private feedbackController!: ReleaseNoteFeedbackController;
   private cardRef: Ref<HTMLElement> = createRef();
 
   static styles = css`
@@ -83,6 +87,111 @@ export class ReleaseNoteEntry extends LitElement {
       white-space: pre-wrap;
     }
 
+    .markdown {
+      white-space: normal;
+      overflow-wrap: anywhere;
+    }
+
+    .markdown > :first-child {
+      margin-top: 0;
+    }
+
+    .markdown > :last-child {
+      margin-bottom: 0;
+    }
+
+    .markdown p,
+    .markdown ul,
+    .markdown ol,
+    .markdown pre,
+    .markdown blockquote,
+    .markdown table {
+      margin: 0 0 0.75rem;
+    }
+
+    .markdown h1,
+    .markdown h2,
+    .markdown h3,
+    .markdown h4,
+    .markdown h5,
+    .markdown h6 {
+      margin: 1rem 0 0.5rem;
+      font-size: 1em;
+      font-weight: 600;
+    }
+
+    .markdown ul,
+    .markdown ol {
+      padding-left: 1.25rem;
+    }
+
+    .markdown ul {
+      list-style: disc;
+    }
+
+    .markdown ol {
+      list-style: decimal;
+    }
+
+    .markdown a {
+      color: inherit;
+      text-decoration: underline;
+    }
+
+    .markdown code {
+      padding: 0.1em 0.3em;
+      border-radius: 0.25rem;
+      background: rgba(127, 127, 127, 0.15);
+      font-size: 0.875em;
+    }
+
+    .markdown pre {
+      padding: 0.5rem 0.75rem;
+      border-radius: 0.375rem;
+      background: rgba(127, 127, 127, 0.15);
+      overflow-x: auto;
+    }
+
+    .markdown pre code {
+      padding: 0;
+      background: none;
+    }
+
+    .markdown blockquote {
+      padding-left: 0.75rem;
+      border-left: 3px solid currentColor;
+      opacity: 0.8;
+    }
+
+    .markdown table {
+      border-collapse: collapse;
+    }
+
+    .markdown th,
+    .markdown td {
+      padding: 0.25rem 0.5rem;
+      border: 1px solid rgba(127, 127, 127, 0.4);
+    }
+
+    .markdown img {
+      max-width: 100%;
+    }
+
+    .tags {
+      display: flex;
+      flex-wrap: wrap;
+      gap: 0.25rem;
+      margin-top: 0.5rem;
+    }
+
+    .tag {
+      padding: 0.125rem 0.5rem;
+      border: 1px solid currentColor;
+      border-radius: 9999px;
+      font-size: 0.75rem;
+      line-height: 1rem;
+    }
+
     .actions {
       width: 100%;
       display: flex;
@@ -118,6 +227,112 @@ export class ReleaseNoteEntry extends LitElement {
       font-size: 0.875rem;
     }
 
+    .reactions {
+      width: 100%;
+      display: flex;
+      justify-content: center;
+      flex-wrap: wrap;
+      gap: 0.5rem;
+    }
+
+    .reaction-button {
+      display: inline-flex;
+      align-items: center;
+      gap: 0.25rem;
+      background: none;
+      border: 1px solid transparent;
+      border-radius: 999px;
+      cursor: pointer;
+      padding: 0.125rem 0.5rem;
+      font: inherit;
+      font-size: 0.875rem;
+      color: inherit;
+    }
+
+    .reaction-button--active {
+      border-color: currentColor;
+    }
+
+    .reaction-button:disabled {
+      opacity: 0.5;
+      cursor: not-allowed;
+    }
+
+    .feedback {
+      width: 100%;
+      display: flex;
+      flex-direction: column;
+      align-items: center;
+      gap: 0.5rem;
+      font-size: 0.875rem;
+    }
+
+    .feedback-toggle {
+      background: none;
+      border: none;
+      cursor: pointer;
+      padding: 0;
+      font: inherit;
+      color: inherit;
+      text-decoration: underline;
+    }
+
+    .feedback-form {
+      width: 100%;
+      display: flex;
+      flex-direction: column;
+      gap: 0.5rem;
+    }
+
+    .feedback-form textarea,
+    .feedback-form input {
+      box-sizing: border-box;
+      width: 100%;
+      padding: 0.5rem;
+      border: 1px solid currentColor;
+      border-radius: 0.375rem;
+      background: transparent;
+      font: inherit;
+      color: inherit;
+    }
+
+    .feedback-form .feedback-hp {
+      position: absolute;
+      left: -9999px;
+      width: 1px;
+      height: 1px;
+    }
+
+    .feedback-form button {
+      align-self: flex-end;
+      padding: 0.25rem 0.75rem;
+      border: 1px solid currentColor;
+      border-radius: 0.375rem;
+      background: none;
+      cursor: pointer;
+      font: inherit;
+      color: inherit;
+    }
+
+    .feedback-form button:disabled {
+      opacity: 0.5;
+      cursor: not-allowed;
+    }
+
+    .feedback-error {
+      opacity: 0.8;
+    }
+
+    .preview-badge {
+      align-self: flex-start;
+      margin-bottom: 0.5rem;
+      padding: 0.125rem 0.5rem;
+      border: 1px dashed currentColor;
+      border-radius: 9999px;
+      font-size: 0.75rem;
+      line-height: 1rem;
+    }
+
     .cta-link {
       text-decoration: none;
       color: inherit;
@@ -151,11 +366,18 @@ export class ReleaseNoteEntry extends LitElement {
       orgId: this.config.org_id,
       clientId,
     });
+
+    // Setup feedback controller
+    this.feedbackController = new ReleaseNoteFeedbackController(this, {
+      releaseNoteId: this.releaseNote.id,
+      orgId: this.config.org_id,
+      clientId,
+    });
   }
 
   updated() {
-    // Set element for metrics tracking after render
-    if (this.cardRef.value) {
+    // Set element for metrics tracking after render, previews aren't tracked
+    if (this.cardRef.value && !this.releaseNote.is_preview) {
       this.metricsController.setElement(this.cardRef.value as HTMLElement);
     }
   }
@@ -170,12 +392,76 @@ export class ReleaseNoteEntry extends LitElement {
     img.style.display = 'none';
   }
 
+  private handleFeedbackSubmit(e: SubmitEvent) {
+    e.preventDefault();
+    const form = e.target as HTMLFormElement;
+    const data = new FormData(form);
+    this.feedbackController.submit(
+      String(data.get('text') ?? ''),
+      String(data.get('email') ?? ''),
+      String(data.get('website') ?? '')
+    );
+  }
+
+  private renderFeedback(clientId: string) {
+    if (this.feedbackController.isSent) {
+      return html`<div class="feedback">Thanks for your feedback!</div>`;
+    }
+    return html`
+      <div class="feedback">
+        <button
+          class="feedback-toggle"
+          @click=${() => this.feedbackController.toggle()}
+          ?disabled=${!clientId}
+        >
+          Leave feedback
+        </button>
+        ${this.feedbackController.isOpen ? html`
+          <form class="feedback-form" @submit=${this.handleFeedbackSubmit}>
+            <textarea
+              name="text"
+              rows="3"
+              maxlength="2000"
+              required
+              placeholder="What do you think about this update?"
+            ></textarea>
+            <input
+              type="email"
+              name="email"
+              maxlength="254"
+              placeholder="Email (optional, if you'd like a reply)"
+            />
+            <input
+              type="text"
+              name="website"
+              class="feedback-hp"
+              tabindex="-1"
+              autocomplete="off"
+              aria-hidden="true"
+            />
+            ${this.feedbackController.error ? html`
+              <span class="feedback-error">${this.feedbackController.error}</span>
+            ` : ''}
+            <button type="submit" ?disabled=${this.feedbackController.isPending}>
+              Send
+            </button>
+          </form>
+        ` : ''}
+      </div>
+    `;
+  }
+
   render() {
     const ctaLabel = this.releaseNote.cta_label_override || this.config.cta_text;
     const baseUrl = this.config.release_page_baseurl;
-    const ctaHref = this.releaseNote.cta_href_override || `${baseUrl}#${this.releaseNote.id}`;
+    const ctaHref =
+      this.releaseNote.cta_href_override ||
+      this.releaseNote.permalink ||
+      `${baseUrl}#${this.releaseNote.id}`;
 
     const clientId = getOrCreateClientId();
+    // previews can't be liked, reacted to or commented on
+    const isPreview = !!this.releaseNote.is_preview;
 
     return html`
       <ui-card
@@ -189,14 +475,27 @@ export class ReleaseNoteEntry extends LitElement {
         "
       >
         <ui-card-header style="padding-bottom: 1rem;">
+          ${isPreview ? html`<span class="preview-badge">Preview</span>` : ''}
           <ui-card-title>${this.releaseNote.title}</ui-card-title>
           <ui-card-description style="color: ${this.config.release_note_font_color}">
             ${this.releaseNote.date || ''}
           </ui-card-description>
+          ${this.releaseNote.tags?.length ? html`
+            <div class="tags">
+              ${this.releaseNote.tags.map((tag) => html`
+                <span class="tag" style="color: ${tag.color}">${tag.name}</span>
+              `)}
+            </div>
+          ` : ''}
         </ui-card-header>
         <ui-card-content>
           <div class="content">
-            ${this.releaseNote.media_link ? html`
+            ${this.releaseNote.media?.length ? html`
+              <ui-media-carousel
+                .media=${this.releaseNote.media}
+                .label=${this.releaseNote.title}
+              ></ui-media-carousel>
+            ` : this.releaseNote.media_link ? html`
               <div class="media-container">
                 <iframe
                   src="${this.releaseNote.media_link}"
@@ -218,13 +517,16 @@ export class ReleaseNoteEntry extends LitElement {
               </div>
             ` : ''}
 
-            ${this.releaseNote.text ? html`
+            ${this.releaseNote.text_html ? html`
+              <!-- text_html is sanitized by the server -->
+              <div class="text markdown">${unsafeHTML(this.releaseNote.text_html)}</div>
+            ` : this.releaseNote.text ? html`
               <div class="text">${this.releaseNote.text}</div>
             ` : ''}
 
-            ${(this.config.enable_likes || !this.releaseNote.hide_cta) ? html`
+            ${((this.config.enable_likes && !isPreview) || (this.config.reactions?.length && !isPreview) || !this.releaseNote.hide_cta) ? html`
               <div class="actions">
-                ${this.config.enable_likes ? html`
+                ${this.config.enable_likes && !isPreview ? html`
                   <div class="action-wrapper">
                     <button
                       class="like-button"
@@ -235,6 +537,7 @@ export class ReleaseNoteEntry extends LitElement {
                         ${this.likesController.isLiked
                           ? this.config.unlike_button_text
                           : this.config.like_button_text}
+                        ${this.likesController.likeCount > 0 ? html`(${this.likesController.likeCount})` : ''}
                       </span>
                       <icon-thumbs-up
                         class="icon-small"
@@ -245,13 +548,28 @@ export class ReleaseNoteEntry extends LitElement {
                   </div>
                 ` : ''}
 
+                ${this.config.reactions?.length && !isPreview ? html`
+                  <div class="reactions">
+                    ${this.likesController.reactions.map((reaction) => html`
+                      <button
+                        class="reaction-button ${reaction.reacted ? 'reaction-button--active' : ''}"
+                        @click=${() => this.likesController.toggleReaction(reaction.emoji)}
+                        ?disabled=${!clientId}
+                      >
+                        <span>${reaction.emoji}</span>
+                        ${reaction.count > 0 ? html`<span>${reaction.count}</span>` : ''}
+                      </button>
+                    `)}
+                  </div>
+                ` : ''}
+
                 ${!this.releaseNote.hide_cta && !(this.config.disable_release_page && !this.releaseNote.cta_href_override) ? html`
                   <div class="action-wrapper">
                     <a
                       class="cta-link"
                       href="${ctaHref}"
                       target="_blank"
-                      @click=${this.metricsController.trackCtaClick}
+                      @click=${isPreview ? undefined : this.metricsController.trackCtaClick}
                     >
                       <span class="cta-content">
                         ${ctaLabel}
@@ -262,6 +580,8 @@ export class ReleaseNoteEntry extends LitElement {
                 ` : ''}
               </div>
             ` : ''}
+
+            ${this.config.enable_feedback && !isPreview ? this.renderFeedback(clientId) : ''}
           </div>
         </ui-card-content>
       </ui-card>
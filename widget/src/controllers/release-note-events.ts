@@ -0,0 +1,53 @@
+import { ReactiveController, ReactiveControllerHost } from 'lit';
+import { backendUrl } from '@/lib/config';
+
+export type ReleaseNoteEventType = 'published' | 'updated' | 'unpublished' | 'deleted';
+
+const EVENT_TYPES: ReleaseNoteEventType[] = ['published', 'updated', 'unpublished', 'deleted'];
+
+/**
+ * Controller for live release note updates
+ *
+ * Features:
+ * - Subscribes to the organisation's release note events via Server-Sent Events
+ * - Calls onEvent whenever a note is published, updated, unpublished or deleted
+ * - Reconnects automatically (handled by EventSource)
+ * - Closes the stream on disconnect
+ */
+export class ReleaseNoteEventsController implements ReactiveController {
+  host: ReactiveControllerHost;
+
+  private orgId: string;
+  private onEvent: (type: ReleaseNoteEventType) => void;
+  private eventSource: EventSource | null = null;
+
+  constructor(
+    host: ReactiveControllerHost,
+    orgId: string,
+    onEvent: (type: ReleaseNoteEventType) => void
+  ) {
+    this.host = host;
+    this.orgId = orgId;
+    this.onEvent = onEvent;
+    host.addController(this);
+  }
+
+  hostConnected() {
+    if (typeof EventSource === 'undefined' || this.eventSource) return;
+
+    try {
+      this.eventSource = new EventSource(`${backendUrl}/api/release-notes/${this.orgId}/events`);
+      EVENT_TYPES.forEach((type) => {
+        this.eventSource!.addEventListener(type, () => this.onEvent(type));
+      });
+    } catch (error) {
+      console.error('[Announcable] Error subscribing to release note events:', error);
+      this.eventSource = null;
+    }
+  }
+
+  hostDisconnected() {
+    this.eventSource?.close();
+    this.eventSource = null;
+  }
+}
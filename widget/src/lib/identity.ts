@@ -0,0 +1,25 @@
+/**
+ * Signed end-user identity
+ *
+ * The token is created by the host application's backend and signs the user's
+ * ID and attributes with the organisation's identity secret. When set, the
+ * backend attributes likes and views to the user instead of the client ID.
+ */
+
+const USER_TOKEN_HEADER = 'X-User-Token';
+
+let userToken: string | undefined;
+
+/**
+ * Set the identity token sent with all API requests
+ */
+export function setUserToken(token?: string) {
+  userToken = token || undefined;
+}
+
+/**
+ * Headers identifying the signed in user, empty for anonymous visitors
+ */
+export function identityHeaders(): Record<string, string> {
+  return userToken ? { [USER_TOKEN_HEADER]: userToken } : {};
+}
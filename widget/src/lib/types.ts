@@ -1,15 +1,41 @@
+export interface Tag {
+  name: string;
+  color: string;
+}
+
+export interface Media {
+  kind: "image" | "embed";
+  // signed image URL or embed URL of the video
+  src: string;
+  alt: string;
+  // resized copies of images for the srcset attribute
+  srcset?: string;
+  width?: number;
+  height?: number;
+  // data URI of a tiny version of the image, shown while it loads
+  placeholder?: string;
+}
+
 export interface ReleaseNote {
   id: string;
   title: string;
   date?: string;
   imageSrc?: string;
   media_link?: string;
+  // all images and videos in display order, imageSrc and media_link are only the first ones
+  media?: Media[];
   text?: string;
+  // text rendered from Markdown to sanitized HTML by the server
+  text_html?: string;
   last_update_on: Date;
   cta_label_override?: string;
   cta_href_override?: string;
+  permalink?: string;
+  tags?: Tag[];
   hide_cta?: boolean;
   attention_mechanism?: null | "show_indicator" | "instant_open";
+  // set on the note of a preview link, which might not be published yet
+  is_preview?: boolean;
 }
 
 export interface WidgetConfig {
@@ -20,6 +46,10 @@ export interface WidgetConfig {
   enable_likes?: boolean;
   like_button_text?: string;
   unlike_button_text?: string;
+  // emoji readers can react with besides liking
+  reactions?: string[];
+  // readers can leave feedback on release notes
+  enable_feedback?: boolean;
   widget_type: "popover" | "modal" | "sidebar";
   widget_border_radius: number;
   widget_border_color: string;
@@ -40,4 +70,16 @@ export type WidgetInit = {
   anchor_query_selector?: string;
   hide_indicator?: boolean;
   font_family?: string[];
+  // language of the texts, e.g. "de"; defaults to the browser's Accept-Language
+  locale?: string;
+  // attributes of the signed in user, matched against the audience rules of release notes
+  user_attributes?: UserAttributes;
+  // identity token signed by your backend, takes precedence over user_attributes
+  user_token?: string;
+  // token of a preview link; shows its note on top and opens the widget right away
+  preview_token?: string;
 };
+
+type UserAttributeValue = string | number | boolean;
+
+export type UserAttributes = Record<string, UserAttributeValue | UserAttributeValue[]>;
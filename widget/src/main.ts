@@ -1,6 +1,7 @@
 import { html, render } from 'lit';
 import './index.css'; // CSS will be injected by vite-plugin-css-injected-by-js
 import type { WidgetInit } from './lib/types';
+import { setUserToken } from './lib/identity';
 import './app';
 
 declare global {
@@ -64,6 +65,8 @@ function initialize(init: WidgetInit) {
     return;
   }
 
+  setUserToken(init.user_token);
+
   try {
     // Create widget root element
     const widgetRoot = document.createElement('div');
@@ -0,0 +1,94 @@
+import { ReactiveController, ReactiveControllerHost } from 'lit';
+import { backendUrl } from '@/lib/config';
+import { identityHeaders } from '@/lib/identity';
+
+interface FeedbackOptions {
+  releaseNoteId: string;
+  orgId: string;
+  clientId: string;
+}
+
+/**
+ * Controller for leaving feedback on a release note
+ */
+export class ReleaseNoteFeedbackController implements ReactiveController {
+  host: ReactiveControllerHost;
+
+  private releaseNoteId: string;
+  private orgId: string;
+  private clientId: string;
+
+  isOpen = false;
+  isPending = false;
+  isSent = false;
+  error: string | null = null;
+
+  constructor(host: ReactiveControllerHost, options: FeedbackOptions) {
+    this.host = host;
+    this.releaseNoteId = options.releaseNoteId;
+    this.orgId = options.orgId;
+    this.clientId = options.clientId;
+    host.addController(this);
+  }
+
+  hostConnected() {}
+  hostDisconnected() {}
+
+  /**
+   * Show or hide the feedback form
+   */
+  toggle(): void {
+    this.isOpen = !this.isOpen;
+    this.error = null;
+    this.host.requestUpdate();
+  }
+
+  /**
+   * Send feedback, website is a honeypot field that is only filled in by bots
+   */
+  async submit(text: string, email: string, website: string): Promise<void> {
+    if (!this.clientId || this.isPending) {
+      return;
+    }
+
+    this.isPending = true;
+    this.error = null;
+    this.host.requestUpdate();
+
+    try {
+      const response = await fetch(
+        `${backendUrl}/api/release-notes/${this.orgId}/${this.releaseNoteId}/feedback`,
+        {
+          method: 'POST',
+          headers: {
+            'Content-Type': 'application/json',
+            ...identityHeaders(),
+          },
+          body: JSON.stringify({
+            client_id: this.clientId,
+            text,
+            email,
+            website,
+          }),
+        }
+      );
+
+      if (response.status === 400) {
+        this.error = (await response.text()).trim();
+        return;
+      }
+      if (!response.ok) {
+        throw new Error('Failed to send feedback');
+      }
+
+      this.isSent = true;
+      this.isOpen = false;
+    } catch (error) {
+      this.error = 'Your feedback couldn\'t be sent, please try again later.';
+      console.error('Failed to send feedback:', error);
+    } finally {
+      this.isPending = false;
+      this.host.requestUpdate();
+    }
+  }
+}
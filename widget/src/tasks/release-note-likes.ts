@@ -1,9 +1,18 @@
 import { Task, TaskStatus } from '@lit/task';
 import { ReactiveController, ReactiveControllerHost } from 'lit';
 import { backendUrl } from '@/lib/config';
+import { identityHeaders } from '@/lib/identity';
+
+type ReactionState = {
+  emoji: string;
+  count: number;
+  reacted: boolean;
+};
 
 type LikeState = {
   is_liked: boolean;
+  count: number;
+  reactions: ReactionState[];
 };
 
 interface LikesTaskOptions {
@@ -38,13 +47,9 @@ export class ReleaseNoteLikesController implements ReactiveController {
     this.task = new Task(
       host,
       async ([releaseNoteId, orgId, clientId]) => {
-        if (!clientId) {
-          return { is_liked: false };
-        }
-        
         const response = await fetch(
           `${backendUrl}/api/release-notes/${orgId}/${releaseNoteId}/like?clientId=${clientId}`,
-          { method: 'GET' }
+          { method: 'GET', headers: identityHeaders() }
         );
         
         if (!response.ok) {
@@ -64,6 +69,13 @@ export class ReleaseNoteLikesController implements ReactiveController {
    * Toggle like state for this release note
    */
   async toggleLike(): Promise<void> {
+    return this.toggleReaction('');
+  }
+
+  /**
+   * Toggle a reaction of the organisation on this release note, an empty reaction toggles the like
+   */
+  async toggleReaction(reaction: string): Promise<void> {
     if (!this.clientId) {
       return;
     }
@@ -79,10 +91,12 @@ export class ReleaseNoteLikesController implements ReactiveController {
           method: 'POST',
           headers: {
             'Content-Type': 'application/json',
+            ...identityHeaders(),
           },
           body: JSON.stringify({
             release_note_id: this.releaseNoteId,
             client_id: this.clientId,
+            reaction,
           }),
         }
       );
@@ -111,4 +125,24 @@ export class ReleaseNoteLikesController implements ReactiveController {
     }
     return false;
   }
+
+  /**
+   * Get the number of likes
+   */
+  get likeCount(): number {
+    if (this.task.status === TaskStatus.COMPLETE && this.task.value) {
+      return this.task.value.count;
+    }
+    return 0;
+  }
+
+  /**
+   * Get the reactions offered by the organisation with their counts
+   */
+  get reactions(): ReactionState[] {
+    if (this.task.status === TaskStatus.COMPLETE && this.task.value) {
+      return this.task.value.reactions;
+    }
+    return [];
+  }
 }
@@ -1,6 +1,7 @@
 import { ReactiveController, ReactiveControllerHost } from 'lit';
 import { backendUrl } from '@/lib/config';
 import { getOrCreateClientId } from '@/lib/clientId';
+import { identityHeaders } from '@/lib/identity';
 
 type MetricType = 'view' | 'cta_click';
 
@@ -16,6 +17,7 @@ interface MetricsOptions {
  * - IntersectionObserver for view tracking (50% visible threshold)
  * - Automatic cleanup on component disconnect
  * - Error handling and retry logic
+ * - Events are batched and sent every few seconds or when the page is hidden
  * - Debounced setup for mounted elements
  * - Multiple viewport support (scroll areas and window)
  * 
@@ -176,36 +178,93 @@ export class ReleaseNoteMetricsController implements ReactiveController {
   };
 
   /**
-   * Send metric to backend with error handling and retry
-   * Fails silently to not disrupt user experience
+   * Queue metric for the next batch sent to the backend
    */
-  private async sendMetric(type: MetricType) {
-    try {
-      const clientId = getOrCreateClientId();
-      
-      const response = await fetch(`${backendUrl}/api/release-notes/${this.orgId}/metrics`, {
-        method: 'POST',
-        headers: {
-          'Content-Type': 'application/json',
-        },
-        body: JSON.stringify({
-          release_note_id: this.releaseNoteId,
-          metric_type: type,
-          client_id: clientId,
-        }),
-      });
-      
-      if (!response.ok) {
-        throw new Error(`HTTP ${response.status}: ${response.statusText}`);
-      }
-      
-      console.debug(`[Announcable] Tracked ${type} for release note ${this.releaseNoteId}`);
-    } catch (error) {
-      // Log but don't throw - metrics are not critical
-      console.error(`[Announcable] Failed to send ${type} metric:`, error);
-      
-      // Could implement retry queue here if metrics are critical
-      // For now, we fail silently to not disrupt user experience
+  private sendMetric(type: MetricType) {
+    queueMetric(this.orgId, { release_note_id: this.releaseNoteId, metric_type: type });
+  }
+}
+
+interface MetricEvent {
+  release_note_id: string;
+  metric_type: MetricType;
+}
+
+const FLUSH_DELAY = 2000; // ms to collect events before sending a batch
+const MAX_BATCH_SIZE = 50; // events accepted by the backend per request
+
+const queues = new Map<string, MetricEvent[]>();
+let flushTimeoutId: number | null = null;
+
+/**
+ * Queue a metric event and schedule sending the batch
+ * Pending events are sent right away when the page is hidden
+ */
+function queueMetric(orgId: string, event: MetricEvent) {
+  const queue = queues.get(orgId) ?? [];
+  queue.push(event);
+  queues.set(orgId, queue);
+
+  if (queue.length >= MAX_BATCH_SIZE) {
+    flushMetrics();
+    return;
+  }
+  if (flushTimeoutId === null) {
+    flushTimeoutId = window.setTimeout(flushMetrics, FLUSH_DELAY);
+  }
+}
+
+/**
+ * Send all queued events, one request per organisation and batch
+ */
+function flushMetrics() {
+  if (flushTimeoutId !== null) {
+    clearTimeout(flushTimeoutId);
+    flushTimeoutId = null;
+  }
+  queues.forEach((events, orgId) => {
+    for (let i = 0; i < events.length; i += MAX_BATCH_SIZE) {
+      sendMetrics(orgId, events.slice(i, i + MAX_BATCH_SIZE));
     }
+  });
+  queues.clear();
+}
+
+/**
+ * Send a batch of metrics to the backend
+ * Uses keepalive so batches sent while the page unloads still arrive
+ * Fails silently to not disrupt user experience
+ */
+async function sendMetrics(orgId: string, events: MetricEvent[]) {
+  try {
+    const response = await fetch(`${backendUrl}/api/release-notes/${orgId}/metrics`, {
+      method: 'POST',
+      keepalive: true,
+      headers: {
+        'Content-Type': 'application/json',
+        ...identityHeaders(),
+      },
+      body: JSON.stringify({
+        client_id: getOrCreateClientId(),
+        events,
+      }),
+    });
+
+    if (!response.ok) {
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+
+    console.debug(`[Announcable] Tracked ${events.length} metrics`);
+  } catch (error) {
+    // Log but don't throw - metrics are not critical
+    console.error('[Announcable] Failed to send metrics:', error);
   }
 }
+
+if (typeof document !== 'undefined') {
+  document.addEventListener('visibilitychange', () => {
+    if (document.visibilityState === 'hidden') {
+      flushMetrics();
+    }
+  });
+}
@@ -0,0 +1,34 @@
+import { backendUrl } from '@/lib/config';
+import { getOrCreateClientId } from '@/lib/clientId';
+import { identityHeaders } from '@/lib/identity';
+
+/**
+ * Mark release notes as read by the current user
+ * Stores the read state on the server so the unread indicator is the same on all devices
+ * Fails silently to not disrupt user experience
+ */
+export async function markReleaseNotesSeen(orgId: string, releaseNoteIds: string[]) {
+  if (releaseNoteIds.length === 0) {
+    return;
+  }
+
+  try {
+    const response = await fetch(`${backendUrl}/api/release-notes/${orgId}/seen`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+        ...identityHeaders(),
+      },
+      body: JSON.stringify({
+        release_note_ids: releaseNoteIds,
+        client_id: getOrCreateClientId(),
+      }),
+    });
+
+    if (!response.ok) {
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+  } catch (error) {
+    console.error('[Announcable] Failed to mark release notes as seen:', error);
+  }
+}
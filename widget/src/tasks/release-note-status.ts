@@ -1,10 +1,16 @@
 import { Task } from '@lit/task';
 import { ReactiveController, ReactiveControllerHost } from 'lit';
 import { backendUrl } from '@/lib/config';
+import { identityHeaders } from '@/lib/identity';
+import { getOrCreateClientId } from '@/lib/clientId';
+import type { UserAttributes } from '@/lib/types';
 
 export interface ReleaseNoteStatus {
+  id: string;
   last_update_on: string;
   attention_mechanism?: string;
+  // whether the user has read the note on any device
+  is_unread?: boolean;
 }
 
 /**
@@ -14,19 +20,24 @@ export interface ReleaseNoteStatus {
 export class ReleaseNoteStatusTask implements ReactiveController {
   host: ReactiveControllerHost;
   private orgId: string;
+  private userAttributes?: UserAttributes;
   
   task: Task<[string], ReleaseNoteStatus[]>;
 
-  constructor(host: ReactiveControllerHost, orgId: string) {
+  constructor(host: ReactiveControllerHost, orgId: string, userAttributes?: UserAttributes) {
     this.host = host;
     this.orgId = orgId;
+    this.userAttributes = userAttributes;
     host.addController(this);
     
     this.task = new Task(
       host,
       async ([orgId]) => {
-        const url = `${backendUrl}/api/release-notes/${orgId}/status?for=widget`;
-        const response = await fetch(url);
+        let url = `${backendUrl}/api/release-notes/${orgId}/status?for=widget&clientId=${getOrCreateClientId()}`;
+        if (this.userAttributes) {
+          url += `&attributes=${encodeURIComponent(JSON.stringify(this.userAttributes))}`;
+        }
+        const response = await fetch(url, { headers: identityHeaders() });
         
         if (!response.ok) {
           throw new Error('Failed to fetch release note status');
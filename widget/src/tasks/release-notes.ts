@@ -1,7 +1,8 @@
 import { Task } from '@lit/task';
 import { ReactiveController, ReactiveControllerHost } from 'lit';
-import type { ReleaseNote } from '@/lib/types';
+import type { ReleaseNote, UserAttributes } from '@/lib/types';
 import { backendUrl } from '@/lib/config';
+import { identityHeaders } from '@/lib/identity';
 
 /**
  * Task for fetching release notes
@@ -10,22 +11,38 @@ import { backendUrl } from '@/lib/config';
 export class ReleaseNotesTask implements ReactiveController {
   host: ReactiveControllerHost;
   private orgId: string;
+  private locale?: string;
+  private userAttributes?: UserAttributes;
+  private previewToken?: string;
   
   task: Task<[string], ReleaseNote[]>;
 
-  constructor(host: ReactiveControllerHost, orgId: string) {
+  constructor(host: ReactiveControllerHost, orgId: string, locale?: string, userAttributes?: UserAttributes, previewToken?: string) {
     this.host = host;
     this.orgId = orgId;
+    this.locale = locale;
+    this.userAttributes = userAttributes;
+    this.previewToken = previewToken;
     host.addController(this);
     
     this.task = new Task(
       host,
       async ([orgId]) => {
-        const url = `${backendUrl}/api/release-notes/${orgId}?for=widget`;
+        let url = `${backendUrl}/api/release-notes/${orgId}?for=widget`;
+        if (this.locale) {
+          url += `&locale=${encodeURIComponent(this.locale)}`;
+        }
+        if (this.userAttributes) {
+          url += `&attributes=${encodeURIComponent(JSON.stringify(this.userAttributes))}`;
+        }
+        if (this.previewToken) {
+          url += `&preview=${encodeURIComponent(this.previewToken)}`;
+        }
         const res = await fetch(url, {
           method: 'GET',
           headers: {
             'Content-Type': 'application/json',
+            ...identityHeaders(),
           },
         });
         
@@ -10,18 +10,23 @@ import { backendUrl } from '@/lib/config';
 export class WidgetConfigTask implements ReactiveController {
   host: ReactiveControllerHost;
   private orgId: string;
+  private locale?: string;
   
   task: Task<[string], WidgetConfig>;
 
-  constructor(host: ReactiveControllerHost, orgId: string) {
+  constructor(host: ReactiveControllerHost, orgId: string, locale?: string) {
     this.host = host;
     this.orgId = orgId;
+    this.locale = locale;
     host.addController(this);
     
     this.task = new Task(
       host,
       async ([orgId]) => {
-        const url = `${backendUrl}/api/widget-config/${orgId}`;
+        let url = `${backendUrl}/api/widget-config/${orgId}`;
+        if (this.locale) {
+          url += `?locale=${encodeURIComponent(this.locale)}`;
+        }
         const res = await fetch(url, {
           method: 'GET',
           headers: {